@@ -35,6 +35,7 @@ import (
 	"github.com/googleapis/genai-toolbox/internal/auth"
 	"github.com/googleapis/genai-toolbox/internal/log"
 	"github.com/googleapis/genai-toolbox/internal/prebuiltconfigs"
+	"github.com/googleapis/genai-toolbox/internal/secrets"
 	"github.com/googleapis/genai-toolbox/internal/server"
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/telemetry"
@@ -55,14 +56,24 @@ import (
 	_ "github.com/googleapis/genai-toolbox/internal/tools/alloydbainl"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigqueryanalyzecontribution"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigqueryconversationalanalytics"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigquerydescribeerror"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigqueryexecutesql"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigqueryforecast"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigquerygenerateembedding"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigquerygetdatasetinfo"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigquerygettableinfo"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigquerylistcolumns"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigquerylistdatasetids"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigquerylisttableids"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigqueryloadfromgcs"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigquerymlpredict"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigqueryprofiletable"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigqueryqueryexternal"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigqueryrunsavedquery"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigquerysearchcatalog"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigquerysql"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigquerytablestorage"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigqueryvectorsearch"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/bigtable"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/clickhouse/clickhouseexecutesql"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/clickhouse/clickhouselistdatabases"
@@ -146,6 +157,9 @@ import (
 	_ "github.com/googleapis/genai-toolbox/internal/tools/tidb/tidbsql"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/trino/trinoexecutesql"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/trino/trinosql"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/utility/branch"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/utility/connectivitycheck"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/utility/sequence"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/utility/wait"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/valkey"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/yugabytedbsql"
@@ -196,6 +210,10 @@ var (
 	commitSha string
 )
 
+// defaultMaxRequestBodySize is the default limit of a tool invocation request
+// body, 10 MiB.
+const defaultMaxRequestBodySize = 10 << 20
+
 func init() {
 	versionString = semanticVersion()
 }
@@ -292,6 +310,13 @@ func NewCommand(opts ...Option) *Command {
 	flags.BoolVar(&cmd.cfg.Stdio, "stdio", false, "Listens via MCP STDIO instead of acting as a remote HTTP server.")
 	flags.BoolVar(&cmd.cfg.DisableReload, "disable-reload", false, "Disables dynamic reloading of tools file.")
 	flags.BoolVar(&cmd.cfg.UI, "ui", false, "Launches the Toolbox UI web server.")
+	flags.StringVar(&cmd.cfg.DefaultLocale, "default-locale", "", "Locale used for tool descriptions when a request does not set a supported Accept-Language (e.g. 'fr').")
+	flags.StringVar(&cmd.cfg.UserAgentSuffix, "user-agent-suffix", "", "Appended to the user agent sent to downstream services, to identify traffic from this deployment.")
+	flags.StringVar(&cmd.cfg.EmptyResultMessage, "empty-result-message", "", "Message returned by tools whose result has no data, unless the tool sets its own 'emptyResultMessage'.")
+	flags.BoolVar(&cmd.cfg.PrettyPrint, "pretty-print", false, "Indent the JSON results of every tool, for human readers. Tools can also set 'prettyPrint' individually.")
+	flags.Int64Var(&cmd.cfg.MaxRequestBodySize, "max-request-body-size", defaultMaxRequestBodySize, "Maximum size in bytes of a tool invocation request body. Set to 0 for no limit.")
+	flags.StringSliceVar(&cmd.cfg.DefaultAuthRequired, "default-auth-required", nil, "Auth services required by every tool that doesn't set 'authRequired'. Tools opt out with 'authRequired: []'.")
+	flags.IntVar(&cmd.cfg.MaxStatementLength, "max-statement-length", tools.DefaultMaxStatementLength, "Maximum length in characters of the statement of every tool that takes one, such as execute-sql tools. Tools can also set 'maxStatementLength' individually. Set to 0 for no limit.")
 
 	// wrap RunE command so that we have access to original Command object
 	cmd.RunE = func(*cobra.Command, []string) error { return run(cmd) }
@@ -308,9 +333,11 @@ type ToolsFile struct {
 }
 
 // parseEnv replaces environment variables ${ENV_NAME} with their values.
-// also support ${ENV_NAME:default_value}.
+// also support ${ENV_NAME:default_value}, which uses the default if the
+// variable is unset, and ${ENV_NAME:-default_value}, which also uses it if the
+// variable is empty.
 func parseEnv(input string) (string, error) {
-	re := regexp.MustCompile(`\$\{(\w+)(:(\w*))?\}`)
+	re := regexp.MustCompile(`\$\{(\w+)(:(-)?([^}]*))?\}`)
 
 	var err error
 	output := re.ReplaceAllStringFunc(input, func(match string) string {
@@ -318,11 +345,12 @@ func parseEnv(input string) (string, error) {
 
 		// extract the variable name
 		variableName := parts[1]
-		if value, found := os.LookupEnv(variableName); found {
+		value, found := os.LookupEnv(variableName)
+		if found && (value != "" || parts[3] == "") {
 			return value
 		}
 		if parts[2] != "" {
-			return parts[3]
+			return parts[4]
 		}
 		err = fmt.Errorf("environment variable not found: %q", variableName)
 		return ""
@@ -340,6 +368,12 @@ func parseToolsFile(ctx context.Context, raw []byte) (ToolsFile, error) {
 	}
 	raw = []byte(output)
 
+	// Replace secret references with their values
+	raw, err = secrets.ResolveYAML(ctx, raw)
+	if err != nil {
+		return toolsFile, fmt.Errorf("error resolving secrets: %w", err)
+	}
+
 	// Parse contents
 	err = yaml.UnmarshalContext(ctx, raw, &toolsFile, yaml.Strict())
 	if err != nil {
@@ -354,6 +388,7 @@ func parseToolsFile(ctx context.Context, raw []byte) (ToolsFile, error) {
 func mergeToolsFiles(files ...ToolsFile) (ToolsFile, error) {
 	merged := ToolsFile{
 		Sources:      make(server.SourceConfigs),
+		AuthSources:  make(server.AuthServiceConfigs),
 		AuthServices: make(server.AuthServiceConfigs),
 		Tools:        make(server.ToolConfigs),
 		Toolsets:     make(server.ToolsetConfigs),
@@ -491,11 +526,39 @@ func handleDynamicReload(ctx context.Context, toolsFile ToolsFile, s *server.Ser
 		return err
 	}
 
+	oldSources, oldTools, oldToolsets := s.ResourceMgr.GetSourcesMap(), s.ResourceMgr.GetToolsMap(), s.ResourceMgr.GetToolsetsMap()
+
+	// In-flight requests keep the resources they already looked up, so they
+	// complete against the previous config.
 	s.ResourceMgr.SetResources(sourcesMap, authServicesMap, toolsMap, toolsetsMap)
 
+	// the changes are logged once the new resources are in use
+	logReloadChanges(ctx, logger, "sources", oldSources, sourcesMap)
+	logReloadChanges(ctx, logger, "tools", oldTools, toolsMap)
+	logReloadChanges(ctx, logger, "toolsets", oldToolsets, toolsetsMap)
+
 	return nil
 }
 
+// logReloadChanges logs which resources of the given type were added or
+// removed by a reload.
+func logReloadChanges[T any](ctx context.Context, logger log.Logger, resourceType string, before, after map[string]T) {
+	var added, removed []string
+	for name := range after {
+		if _, ok := before[name]; !ok {
+			added = append(added, name)
+		}
+	}
+	for name := range before {
+		if _, ok := after[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+	slices.Sort(added)
+	slices.Sort(removed)
+	logger.InfoContext(ctx, fmt.Sprintf("Reloaded %d %s (added: %v, removed: %v).", len(after), resourceType, added, removed))
+}
+
 // validateReloadEdits checks that the reloaded tools file configs can initialized without failing
 func validateReloadEdits(
 	ctx context.Context, toolsFile ToolsFile,
@@ -738,6 +801,9 @@ func run(cmd *Command) error {
 	}
 
 	ctx = util.WithLogger(ctx, cmd.logger)
+	// tools files are parsed with the default authRequired list, including
+	// on dynamic reloads
+	ctx = util.WithDefaultAuthRequired(ctx, cmd.cfg.DefaultAuthRequired)
 
 	// Set up OpenTelemetry
 	otelShutdown, err := telemetry.SetupOTel(ctx, cmd.cfg.Version, cmd.cfg.TelemetryOTLP, cmd.cfg.TelemetryGCP, cmd.cfg.TelemetryServiceName)
@@ -20,6 +20,8 @@ import (
 	_ "embed"
 	"fmt"
 	"io"
+	nethttp "net/http"
+	"net/http/httptest"
 	"os"
 	"path"
 	"path/filepath"
@@ -41,6 +43,7 @@ import (
 	"github.com/googleapis/genai-toolbox/internal/testutils"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/tools/http"
+	"github.com/googleapis/genai-toolbox/internal/tools/postgres/postgresexecutesql"
 	"github.com/googleapis/genai-toolbox/internal/tools/postgres/postgressql"
 	"github.com/googleapis/genai-toolbox/internal/util"
 	"github.com/spf13/cobra"
@@ -60,6 +63,12 @@ func withDefaults(c server.ServerConfig) server.ServerConfig {
 	if c.TelemetryServiceName == "" {
 		c.TelemetryServiceName = "toolbox"
 	}
+	if c.MaxRequestBodySize == 0 {
+		c.MaxRequestBodySize = 10 << 20
+	}
+	if c.MaxStatementLength == 0 {
+		c.MaxStatementLength = 100000
+	}
 	return c
 }
 
@@ -191,6 +200,48 @@ func TestServerConfigFlags(t *testing.T) {
 				DisableReload: true,
 			}),
 		},
+		{
+			desc: "user agent suffix",
+			args: []string{"--user-agent-suffix", "my-deployment/1.0"},
+			want: withDefaults(server.ServerConfig{
+				UserAgentSuffix: "my-deployment/1.0",
+			}),
+		},
+		{
+			desc: "empty result message",
+			args: []string{"--empty-result-message", "No data found."},
+			want: withDefaults(server.ServerConfig{
+				EmptyResultMessage: "No data found.",
+			}),
+		},
+		{
+			desc: "pretty print",
+			args: []string{"--pretty-print"},
+			want: withDefaults(server.ServerConfig{
+				PrettyPrint: true,
+			}),
+		},
+		{
+			desc: "max request body size",
+			args: []string{"--max-request-body-size", "1024"},
+			want: withDefaults(server.ServerConfig{
+				MaxRequestBodySize: 1024,
+			}),
+		},
+		{
+			desc: "max statement length",
+			args: []string{"--max-statement-length", "5000"},
+			want: withDefaults(server.ServerConfig{
+				MaxStatementLength: 5000,
+			}),
+		},
+		{
+			desc: "default auth required",
+			args: []string{"--default-auth-required", "my-google-auth,other-auth"},
+			want: withDefaults(server.ServerConfig{
+				DefaultAuthRequired: []string{"my-google-auth", "other-auth"},
+			}),
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -248,6 +299,40 @@ func TestParseEnv(t *testing.T) {
 			in:   "${FOO:bar}",
 			want: "hello",
 		},
+		{
+			desc: "with dash default",
+			in:   "${FOO:-bar}",
+			want: "bar",
+		},
+		{
+			desc: "with dash default with env",
+			env: map[string]string{
+				"FOO": "hello",
+			},
+			in:   "${FOO:-bar}",
+			want: "hello",
+		},
+		{
+			desc: "with dash default with empty env",
+			env: map[string]string{
+				"FOO": "",
+			},
+			in:   "${FOO:-bar}",
+			want: "bar",
+		},
+		{
+			desc: "with default with empty env",
+			env: map[string]string{
+				"FOO": "",
+			},
+			in:   "${FOO:bar}",
+			want: "",
+		},
+		{
+			desc: "with path default",
+			in:   "${DB_PATH:-/tmp/my-db.sqlite}",
+			want: "/tmp/my-db.sqlite",
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -551,6 +636,460 @@ func TestParseToolFile(t *testing.T) {
 
 }
 
+func TestLoadAndMergeToolsFolder(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	sourcesFile := `
+	sources:
+		my-pg-instance:
+			kind: cloud-sql-postgres
+			project: my-project
+			region: my-region
+			instance: my-instance
+			database: my_db
+			user: my_user
+			password: my_pass
+	`
+	toolsFile := `
+	tools:
+		example_tool:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: some description
+			statement: |
+				SELECT * FROM SQL_STATEMENT;
+			parameters:
+				- name: country
+					type: string
+					description: some description
+	toolsets:
+		example_toolset:
+			- example_tool
+	`
+	duplicateToolFile := `
+	tools:
+		example_tool:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: another description
+			statement: |
+				SELECT 1;
+	`
+
+	writeFiles := func(t *testing.T, files map[string]string) string {
+		dir := t.TempDir()
+		for name, content := range files {
+			if err := os.WriteFile(filepath.Join(dir, name), testutils.FormatYaml(content), 0o644); err != nil {
+				t.Fatalf("unable to write %s: %s", name, err)
+			}
+		}
+		return dir
+	}
+
+	t.Run("sources and tools in separate files", func(t *testing.T) {
+		dir := writeFiles(t, map[string]string{
+			"sources.yaml": sourcesFile,
+			"tools.yml":    toolsFile,
+			"notes.txt":    "not a tools file",
+		})
+		want := ToolsFile{
+			Sources: server.SourceConfigs{
+				"my-pg-instance": cloudsqlpgsrc.Config{
+					Name:     "my-pg-instance",
+					Kind:     cloudsqlpgsrc.SourceKind,
+					Project:  "my-project",
+					Region:   "my-region",
+					Instance: "my-instance",
+					IPType:   "public",
+					Database: "my_db",
+					User:     "my_user",
+					Password: "my_pass",
+				},
+			},
+			AuthSources:  server.AuthServiceConfigs{},
+			AuthServices: server.AuthServiceConfigs{},
+			Tools: server.ToolConfigs{
+				"example_tool": postgressql.Config{
+					Name:         "example_tool",
+					Kind:         "postgres-sql",
+					Source:       "my-pg-instance",
+					Description:  "some description",
+					Statement:    "SELECT * FROM SQL_STATEMENT;\n",
+					Parameters:   []tools.Parameter{tools.NewStringParameter("country", "some description")},
+					AuthRequired: []string{},
+				},
+			},
+			Toolsets: server.ToolsetConfigs{
+				"example_toolset": tools.ToolsetConfig{
+					Name:      "example_toolset",
+					ToolNames: []string{"example_tool"},
+				},
+			},
+		}
+		got, err := loadAndMergeToolsFolder(ctx, dir)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Fatalf("incorrect merged tools file: diff %v", diff)
+		}
+	})
+
+	t.Run("duplicate tool name", func(t *testing.T) {
+		dir := writeFiles(t, map[string]string{
+			"a_sources.yaml":   sourcesFile,
+			"b_tools.yaml":     toolsFile,
+			"c_duplicate.yaml": duplicateToolFile,
+		})
+		_, err := loadAndMergeToolsFolder(ctx, dir)
+		if err == nil {
+			t.Fatalf("expected an error for a duplicate tool name")
+		}
+		wantErr := "tool 'example_tool'"
+		if !strings.Contains(err.Error(), wantErr) {
+			t.Fatalf("unexpected error: got %q, want to contain %q", err.Error(), wantErr)
+		}
+	})
+
+	t.Run("no yaml files", func(t *testing.T) {
+		dir := writeFiles(t, map[string]string{"notes.txt": "not a tools file"})
+		_, err := loadAndMergeToolsFolder(ctx, dir)
+		if err == nil {
+			t.Fatalf("expected an error for a folder without YAML files")
+		}
+	})
+}
+
+func TestParseToolFileWithExamples(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	tools:
+		example_tool:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: some description
+			statement: |
+				SELECT * FROM SQL_STATEMENT;
+			parameters:
+				- name: country
+					type: string
+					description: some description
+				- name: limit
+					type: integer
+					description: some description
+			examples:
+				- description: flights to France
+					args:
+						country: France
+						limit: 10
+				- description: flights to Japan
+					args:
+						country: Japan
+						limit: 5
+	`
+	want := server.ToolConfigs{
+		"example_tool": tools.ExampleConfig{
+			ToolConfig: postgressql.Config{
+				Name:        "example_tool",
+				Kind:        "postgres-sql",
+				Source:      "my-pg-instance",
+				Description: "some description",
+				Statement:   "SELECT * FROM SQL_STATEMENT;\n",
+				Parameters: []tools.Parameter{
+					tools.NewStringParameter("country", "some description"),
+					tools.NewIntParameter("limit", "some description"),
+				},
+				AuthRequired: []string{},
+			},
+			Option: tools.ExampleOption{
+				Examples: []tools.Example{
+					{Description: "flights to France", Args: map[string]any{"country": "France", "limit": uint64(10)}},
+					{Description: "flights to Japan", Args: map[string]any{"country": "Japan", "limit": uint64(5)}},
+				},
+			},
+		},
+	}
+	toolsFile, err := parseToolsFile(ctx, testutils.FormatYaml(in))
+	if err != nil {
+		t.Fatalf("failed to parse input: %v", err)
+	}
+	if diff := cmp.Diff(want, toolsFile.Tools); diff != "" {
+		t.Fatalf("incorrect tools parse: diff %v", diff)
+	}
+}
+
+func TestParseToolFileWithInvalidExamples(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		description string
+		args        string
+		errStr      string
+	}{
+		{
+			description: "wrong type",
+			args: `
+						country: France
+						limit: ten`,
+			errStr: `invalid "examples" field for tool "example_tool": example 0: parameter "limit": expected a value of type "integer", got string`,
+		},
+		{
+			description: "unknown parameter",
+			args: `
+						country: France
+						limit: 10
+						city: Paris`,
+			errStr: `invalid "examples" field for tool "example_tool": example 0: unknown parameter "city"`,
+		},
+		{
+			description: "missing required parameter",
+			args: `
+						country: France`,
+			errStr: `invalid "examples" field for tool "example_tool": example 0: missing required parameter "limit"`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.description, func(t *testing.T) {
+			in := `
+	tools:
+		example_tool:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: some description
+			statement: |
+				SELECT * FROM SQL_STATEMENT;
+			parameters:
+				- name: country
+					type: string
+					description: some description
+				- name: limit
+					type: integer
+					description: some description
+			examples:
+				- description: an example
+					args:` + tc.args + "\n"
+			_, err := parseToolsFile(ctx, testutils.FormatYaml(in))
+			if err == nil {
+				t.Fatalf("expected parsing to fail")
+			}
+			if !strings.Contains(err.Error(), tc.errStr) {
+				t.Fatalf("unexpected error: got %q, want to contain %q", err.Error(), tc.errStr)
+			}
+		})
+	}
+}
+
+func TestParseToolFileWithAliases(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	tools:
+		example_tool:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: some description
+			statement: |
+				SELECT * FROM SQL_STATEMENT;
+			aliases:
+				- example-tool
+				- old_example_tool
+	`
+	want := server.ToolConfigs{
+		"example_tool": postgressql.Config{
+			Name:         "example_tool",
+			Kind:         "postgres-sql",
+			Source:       "my-pg-instance",
+			Description:  "some description",
+			Statement:    "SELECT * FROM SQL_STATEMENT;\n",
+			AuthRequired: []string{},
+		},
+		"example-tool":     tools.AliasConfig{Name: "example-tool", Tool: "example_tool"},
+		"old_example_tool": tools.AliasConfig{Name: "old_example_tool", Tool: "example_tool"},
+	}
+	toolsFile, err := parseToolsFile(ctx, testutils.FormatYaml(in))
+	if err != nil {
+		t.Fatalf("failed to parse input: %v", err)
+	}
+	if diff := cmp.Diff(want, toolsFile.Tools); diff != "" {
+		t.Fatalf("incorrect tools parse: diff %v", diff)
+	}
+
+	// an alias can't take the name of another tool
+	in = `
+	tools:
+		example_tool:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: some description
+			statement: |
+				SELECT * FROM SQL_STATEMENT;
+			aliases:
+				- other_tool
+		other_tool:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: some description
+			statement: |
+				SELECT * FROM SQL_STATEMENT;
+	`
+	_, err = parseToolsFile(ctx, testutils.FormatYaml(in))
+	if err == nil || !strings.Contains(err.Error(), `alias "other_tool" of tool "example_tool" is already the name of a tool`) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestParseToolFileWithMaxStatementLength(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	tools:
+		execute_sql:
+			kind: postgres-execute-sql
+			source: my-pg-instance
+			description: some description
+		short_sql:
+			kind: postgres-execute-sql
+			source: my-pg-instance
+			description: some description
+			maxStatementLength: 5000
+		example_tool:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: some description
+			statement: |
+				SELECT * FROM SQL_STATEMENT;
+	`
+	want := server.ToolConfigs{
+		// tools that take a statement are marked for the server default
+		"execute_sql": tools.StatementLengthConfig{
+			ToolConfig: postgresexecutesql.Config{
+				Name:         "execute_sql",
+				Kind:         "postgres-execute-sql",
+				Source:       "my-pg-instance",
+				Description:  "some description",
+				AuthRequired: []string{},
+			},
+		},
+		"short_sql": tools.StatementLengthConfig{
+			ToolConfig: postgresexecutesql.Config{
+				Name:         "short_sql",
+				Kind:         "postgres-execute-sql",
+				Source:       "my-pg-instance",
+				Description:  "some description",
+				AuthRequired: []string{},
+			},
+			Option: tools.StatementLengthOption{MaxLength: 5000},
+		},
+		"example_tool": postgressql.Config{
+			Name:         "example_tool",
+			Kind:         "postgres-sql",
+			Source:       "my-pg-instance",
+			Description:  "some description",
+			Statement:    "SELECT * FROM SQL_STATEMENT;\n",
+			AuthRequired: []string{},
+		},
+	}
+	toolsFile, err := parseToolsFile(ctx, testutils.FormatYaml(in))
+	if err != nil {
+		t.Fatalf("failed to parse input: %v", err)
+	}
+	if diff := cmp.Diff(want, toolsFile.Tools); diff != "" {
+		t.Fatalf("incorrect tools parse: diff %v", diff)
+	}
+
+	// only tools that take a statement can be limited
+	in = `
+	tools:
+		example_tool:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: some description
+			statement: |
+				SELECT * FROM SQL_STATEMENT;
+			maxStatementLength: 5000
+	`
+	_, err = parseToolsFile(ctx, testutils.FormatYaml(in))
+	if err == nil || !strings.Contains(err.Error(), `"maxStatementLength" of tool "example_tool" is only supported by tools that take a statement`) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestParseToolFileWithDefaultAuthRequired(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	ctx = util.WithDefaultAuthRequired(ctx, []string{"my-google-service"})
+	in := `
+	tools:
+		default_tool:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: some description
+			statement: |
+				SELECT * FROM SQL_STATEMENT;
+		opt_out_tool:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: some description
+			statement: |
+				SELECT * FROM SQL_STATEMENT;
+			authRequired: []
+		own_auth_tool:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: some description
+			statement: |
+				SELECT * FROM SQL_STATEMENT;
+			authRequired:
+				- other-auth-service
+	`
+	want := server.ToolConfigs{
+		"default_tool": postgressql.Config{
+			Name:         "default_tool",
+			Kind:         "postgres-sql",
+			Source:       "my-pg-instance",
+			Description:  "some description",
+			Statement:    "SELECT * FROM SQL_STATEMENT;\n",
+			AuthRequired: []string{"my-google-service"},
+		},
+		"opt_out_tool": postgressql.Config{
+			Name:         "opt_out_tool",
+			Kind:         "postgres-sql",
+			Source:       "my-pg-instance",
+			Description:  "some description",
+			Statement:    "SELECT * FROM SQL_STATEMENT;\n",
+			AuthRequired: []string{},
+		},
+		"own_auth_tool": postgressql.Config{
+			Name:         "own_auth_tool",
+			Kind:         "postgres-sql",
+			Source:       "my-pg-instance",
+			Description:  "some description",
+			Statement:    "SELECT * FROM SQL_STATEMENT;\n",
+			AuthRequired: []string{"other-auth-service"},
+		},
+	}
+	toolsFile, err := parseToolsFile(ctx, testutils.FormatYaml(in))
+	if err != nil {
+		t.Fatalf("failed to parse input: %v", err)
+	}
+	if diff := cmp.Diff(want, toolsFile.Tools); diff != "" {
+		t.Fatalf("incorrect tools parse: diff %v", diff)
+	}
+}
+
 func TestParseToolFileWithAuth(t *testing.T) {
 	ctx, err := testutils.ContextWithNewLogger()
 	if err != nil {
@@ -894,6 +1433,7 @@ func TestEnvVarReplacement(t *testing.T) {
 	t.Setenv("cat_string", "cat")
 	t.Setenv("food_string", "food")
 	t.Setenv("TestHeader", "ACTUAL_HEADER")
+	t.Setenv("PROJECT_ID", "my-project")
 
 	if err != nil {
 		t.Fatalf("unexpected error: %s", err)
@@ -1025,6 +1565,35 @@ func TestEnvVarReplacement(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "source fields with env vars and defaults",
+			in: `
+			sources:
+				my-pg-instance:
+					kind: cloud-sql-postgres
+					project: ${PROJECT_ID}
+					region: ${REGION:-us-central1}
+					instance: my-instance
+					database: ${DATABASE_NAME:-my_db}
+					user: my_user
+					password: my_pass
+			`,
+			wantToolsFile: ToolsFile{
+				Sources: server.SourceConfigs{
+					"my-pg-instance": cloudsqlpgsrc.Config{
+						Name:     "my-pg-instance",
+						Kind:     cloudsqlpgsrc.SourceKind,
+						Project:  "my-project",
+						Region:   "us-central1",
+						Instance: "my-instance",
+						IPType:   "public",
+						Database: "my_db",
+						User:     "my_user",
+						Password: "my_pass",
+					},
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.description, func(t *testing.T) {
@@ -1227,6 +1796,109 @@ func TestSingleEdit(t *testing.T) {
 	}
 }
 
+func TestReloadAddsTool(t *testing.T) {
+	ctx, cancelCtx := context.WithTimeout(context.Background(), time.Minute)
+	defer cancelCtx()
+
+	pr, pw := io.Pipe()
+	defer pw.Close()
+	defer pr.Close()
+
+	logger, err := log.NewStdLogger(pw, pw, "DEBUG")
+	if err != nil {
+		t.Fatalf("failed to setup logger %s", err)
+	}
+	ctx = util.WithLogger(ctx, logger)
+
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation(versionString)
+	if err != nil {
+		t.Fatalf("failed to setup instrumentation %s", err)
+	}
+	ctx = util.WithInstrumentation(ctx, instrumentation)
+
+	ts := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		fmt.Fprintf(w, `{"path": %q}`, r.URL.Path)
+	}))
+	defer ts.Close()
+
+	toolsFileContent := func(toolNames ...string) []byte {
+		content := fmt.Sprintf(`
+	sources:
+		my-http-instance:
+			kind: http
+			baseUrl: %s
+			timeout: 10s
+	tools:`, ts.URL)
+		for _, name := range toolNames {
+			content += fmt.Sprintf(`
+		%s:
+			kind: http
+			source: my-http-instance
+			method: GET
+			path: /%s
+			description: some description`, name, name)
+		}
+		return testutils.FormatYaml(content + "\n")
+	}
+
+	fileToWatch := filepath.Join(t.TempDir(), "tools.yaml")
+	if err := os.WriteFile(fileToWatch, toolsFileContent("initial_tool"), 0o644); err != nil {
+		t.Fatalf("error writing tools file: %s", err)
+	}
+
+	// load the initial config without logging to the pipe, which isn't read yet
+	discardLogger, err := log.NewStdLogger(io.Discard, io.Discard, "DEBUG")
+	if err != nil {
+		t.Fatalf("failed to setup logger %s", err)
+	}
+	setupCtx := util.WithLogger(ctx, discardLogger)
+	toolsFile, err := loadAndMergeToolsFiles(setupCtx, []string{fileToWatch})
+	if err != nil {
+		t.Fatalf("unable to load tools file: %s", err)
+	}
+	sourcesMap, authServicesMap, toolsMap, toolsetsMap, err := validateReloadEdits(setupCtx, toolsFile)
+	if err != nil {
+		t.Fatalf("unable to initialize tools file: %s", err)
+	}
+	s := &server.Server{ResourceMgr: server.NewResourceManager(sourcesMap, authServicesMap, toolsMap, toolsetsMap)}
+
+	watchDir := filepath.Dir(fileToWatch)
+	go watchChanges(ctx, map[string]bool{watchDir: true}, map[string]bool{fileToWatch: true}, s)
+
+	begunWatchingDir := regexp.MustCompile(`Added directory .* to watcher.`)
+	if _, err := testutils.WaitForString(ctx, begunWatchingDir, pr); err != nil {
+		t.Fatalf("timeout or error waiting for watcher to start: %s", err)
+	}
+
+	if err := os.WriteFile(fileToWatch, toolsFileContent("initial_tool", "new_tool"), 0o644); err != nil {
+		t.Fatalf("error writing tools file: %s", err)
+	}
+
+	reloaded := regexp.MustCompile(`Reloaded 2 tools \(added: \[new_tool\], removed: \[\]\)`)
+	if _, err := testutils.WaitForString(ctx, reloaded, pr); err != nil {
+		t.Fatalf("timeout or error waiting for tools file to reload: %s", err)
+	}
+	// keep draining the logs so the watcher isn't blocked on writes
+	go func() { _, _ = io.Copy(io.Discard, pr) }()
+
+	tool, ok := s.ResourceMgr.GetTool("new_tool")
+	if !ok {
+		t.Fatalf("new_tool was not loaded after reload")
+	}
+	params, err := tool.ParseParams(map[string]any{}, nil)
+	if err != nil {
+		t.Fatalf("unable to parse params: %s", err)
+	}
+	got, err := tool.Invoke(ctx, params, "")
+	if err != nil {
+		t.Fatalf("unable to invoke new_tool: %s", err)
+	}
+	want := map[string]any{"path": "/new_tool"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected result from new_tool: diff %v", diff)
+	}
+}
+
 func TestPrebuiltTools(t *testing.T) {
 	// Get prebuilt configs
 	alloydb_admin_config, _ := prebuiltconfigs.Get("alloydb-postgres-admin")
@@ -1645,3 +2317,77 @@ func TestUpdateLogLevel(t *testing.T) {
 		})
 	}
 }
+
+func TestReloadDropsCachedResults(t *testing.T) {
+	ctx := context.Background()
+	logger, err := log.NewStdLogger(io.Discard, io.Discard, "DEBUG")
+	if err != nil {
+		t.Fatalf("failed to setup logger %s", err)
+	}
+	ctx = util.WithLogger(ctx, logger)
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation(versionString)
+	if err != nil {
+		t.Fatalf("failed to setup instrumentation %s", err)
+	}
+	ctx = util.WithInstrumentation(ctx, instrumentation)
+
+	ts := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		fmt.Fprintf(w, `{"path": %q}`, r.URL.Path)
+	}))
+	defer ts.Close()
+
+	// the tool keeps its name across reloads, but the path it calls changes
+	toolsFile := func(path string) ToolsFile {
+		content := fmt.Sprintf(`
+	sources:
+		my-http-instance:
+			kind: http
+			baseUrl: %s
+			timeout: 10s
+	tools:
+		cached_tool:
+			kind: http
+			source: my-http-instance
+			method: GET
+			path: /%s
+			description: some description
+			cacheTTL: 1h
+`, ts.URL, path)
+		file, err := parseToolsFile(ctx, testutils.FormatYaml(content))
+		if err != nil {
+			t.Fatalf("unable to parse tools file: %s", err)
+		}
+		return file
+	}
+	invoke := func(s *server.Server) any {
+		tool, ok := s.ResourceMgr.GetTool("cached_tool")
+		if !ok {
+			t.Fatalf("cached_tool was not loaded")
+		}
+		params, err := tool.ParseParams(map[string]any{}, nil)
+		if err != nil {
+			t.Fatalf("unable to parse params: %s", err)
+		}
+		got, err := tool.Invoke(ctx, params, "")
+		if err != nil {
+			t.Fatalf("unable to invoke cached_tool: %s", err)
+		}
+		return got
+	}
+
+	sourcesMap, authServicesMap, toolsMap, toolsetsMap, err := validateReloadEdits(ctx, toolsFile("old"))
+	if err != nil {
+		t.Fatalf("unable to initialize tools file: %s", err)
+	}
+	s := &server.Server{ResourceMgr: server.NewResourceManager(sourcesMap, authServicesMap, toolsMap, toolsetsMap)}
+	if diff := cmp.Diff(map[string]any{"path": "/old"}, invoke(s)); diff != "" {
+		t.Fatalf("unexpected result before reload: diff %v", diff)
+	}
+
+	if err := handleDynamicReload(ctx, toolsFile("new"), s); err != nil {
+		t.Fatalf("unable to reload tools file: %s", err)
+	}
+	if diff := cmp.Diff(map[string]any{"path": "/new"}, invoke(s)); diff != "" {
+		t.Fatalf("reloaded tool returned a result cached before the reload: diff %v", diff)
+	}
+}
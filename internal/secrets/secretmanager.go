@@ -0,0 +1,66 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sync"
+
+	secretmanager "google.golang.org/api/secretmanager/v1"
+)
+
+// SecretManagerScheme is the scheme of references to Google Secret Manager
+// secret versions, e.g.
+// `secret://projects/my-project/secrets/my-secret/versions/latest`.
+const SecretManagerScheme = "secret"
+
+func init() {
+	if !Register(&secretManagerProvider{}) {
+		panic(fmt.Sprintf("secret provider %q already registered", SecretManagerScheme))
+	}
+}
+
+// secretManagerProvider fetches secrets from Google Secret Manager using
+// Application Default Credentials. The client is created on first use, so
+// tools files without references do not need credentials.
+type secretManagerProvider struct {
+	once    sync.Once
+	service *secretmanager.Service
+	err     error
+}
+
+func (p *secretManagerProvider) Scheme() string {
+	return SecretManagerScheme
+}
+
+func (p *secretManagerProvider) Get(ctx context.Context, name string) (string, error) {
+	p.once.Do(func() {
+		p.service, p.err = secretmanager.NewService(ctx)
+	})
+	if p.err != nil {
+		return "", fmt.Errorf("unable to create Secret Manager client: %w", p.err)
+	}
+	resp, err := p.service.Projects.Secrets.Versions.Access(name).Context(ctx).Do()
+	if err != nil {
+		return "", fmt.Errorf("unable to access secret version: %w", err)
+	}
+	data, err := base64.StdEncoding.DecodeString(resp.Payload.Data)
+	if err != nil {
+		return "", fmt.Errorf("unable to decode secret payload: %w", err)
+	}
+	return string(data), nil
+}
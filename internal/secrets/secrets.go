@@ -0,0 +1,196 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package secrets resolves references to secrets in tools files, so that
+// sensitive values such as passwords do not have to be stored in plain text.
+// A reference is a string value of the form `<scheme>://<name>`, where the
+// scheme selects the Provider that fetches the secret.
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+	"github.com/goccy/go-yaml/token"
+)
+
+// Provider fetches secrets from a secret store.
+type Provider interface {
+	// Scheme is the prefix of the references handled by the provider,
+	// without `://`.
+	Scheme() string
+	// Get returns the value of the secret with the given name, which is the
+	// reference without the scheme prefix.
+	Get(ctx context.Context, name string) (string, error)
+}
+
+var (
+	mu        sync.RWMutex
+	providers = make(map[string]Provider)
+)
+
+// Register makes a provider available for resolving references. It returns
+// false if a provider with the same scheme was already registered.
+func Register(p Provider) bool {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, exists := providers[p.Scheme()]; exists {
+		return false
+	}
+	providers[p.Scheme()] = p
+	return true
+}
+
+// lookup returns the provider and secret name for s, if the whole of s is a
+// reference.
+func lookup(s string) (Provider, string, bool) {
+	scheme, name, ok := strings.Cut(s, "://")
+	if !ok || name == "" || strings.ContainsFunc(s, unicode.IsSpace) {
+		return nil, "", false
+	}
+	mu.RLock()
+	defer mu.RUnlock()
+	p, ok := providers[scheme]
+	return p, name, ok
+}
+
+// ResolveYAML replaces every scalar value of raw that is a secret reference
+// with the value of the secret, written as a double-quoted YAML string. Every
+// other byte of raw, including comments, anchors and aliases, is kept as is. raw
+// is returned unchanged if it does not contain any references.
+func ResolveYAML(ctx context.Context, raw []byte) ([]byte, error) {
+	if !hasReference(raw) {
+		return raw, nil
+	}
+	file, err := parser.ParseBytes(raw, 0)
+	if err != nil {
+		return nil, err
+	}
+	var refs referenceFinder
+	for _, doc := range file.Docs {
+		ast.Walk(&refs, doc)
+	}
+	lines := lineStarts(raw)
+	for i, r := range refs {
+		start, ok := byteOffset(raw, lines, r.pos)
+		if !ok || !bytes.HasPrefix(raw[start:], []byte(r.text)) {
+			return nil, fmt.Errorf("unable to locate secret reference %q at line %d", r.value, r.pos.Line)
+		}
+		refs[i].start = start
+	}
+	// splice the secrets in from the end, so that the offsets of the
+	// remaining references stay valid
+	sort.Slice(refs, func(i, j int) bool { return refs[i].start > refs[j].start })
+	out := bytes.Clone(raw)
+	for _, r := range refs {
+		secret, err := r.provider.Get(ctx, r.name)
+		if err != nil {
+			return nil, fmt.Errorf("unable to resolve secret %q: %w", r.value, err)
+		}
+		end := r.start + len(r.text)
+		out = append(out[:r.start], append([]byte(strconv.Quote(secret)), out[end:]...)...)
+	}
+	return out, nil
+}
+
+// reference is a scalar of a YAML document whose value is a secret reference.
+type reference struct {
+	provider Provider
+	name     string
+	value    string
+	// text is the scalar as written, including any quotes
+	text string
+	pos  *token.Position
+	// start is the byte offset of text in the document
+	start int
+}
+
+// referenceFinder collects the scalar values, but not the keys, of a YAML
+// document that are secret references.
+type referenceFinder []reference
+
+func (f *referenceFinder) Visit(node ast.Node) ast.Visitor {
+	switch n := node.(type) {
+	case *ast.MappingValueNode:
+		if n.Value != nil {
+			ast.Walk(f, n.Value)
+		}
+		return nil
+	case *ast.StringNode:
+		p, name, ok := lookup(n.Value)
+		if !ok {
+			return nil
+		}
+		tk := n.GetToken()
+		*f = append(*f, reference{
+			provider: p,
+			name:     name,
+			value:    n.Value,
+			text:     strings.TrimSpace(tk.Origin),
+			pos:      tk.Position,
+		})
+		return nil
+	}
+	return f
+}
+
+// lineStarts returns the byte offsets at which the lines of raw start.
+func lineStarts(raw []byte) []int {
+	starts := []int{0}
+	for i, b := range raw {
+		if b == '\n' {
+			starts = append(starts, i+1)
+		}
+	}
+	return starts
+}
+
+// byteOffset converts a token position, whose columns count runes, to a byte
+// offset in raw.
+func byteOffset(raw []byte, lines []int, pos *token.Position) (int, bool) {
+	if pos.Line < 1 || pos.Line > len(lines) || pos.Column < 1 {
+		return 0, false
+	}
+	offset := lines[pos.Line-1]
+	for col := 1; col < pos.Column; col++ {
+		if offset >= len(raw) {
+			return 0, false
+		}
+		_, size := utf8.DecodeRune(raw[offset:])
+		offset += size
+	}
+	return offset, true
+}
+
+// hasReference reports whether raw may contain a reference to a registered
+// provider.
+func hasReference(raw []byte) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	for scheme := range providers {
+		if bytes.Contains(raw, []byte(scheme+"://")) {
+			return true
+		}
+	}
+	return false
+}
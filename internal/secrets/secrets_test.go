@@ -0,0 +1,211 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/googleapis/genai-toolbox/internal/secrets"
+)
+
+// fakeProvider serves secrets from a map and records the names it was asked
+// for.
+type fakeProvider struct {
+	secrets map[string]string
+	fetched []string
+}
+
+func (p *fakeProvider) Scheme() string {
+	return "fake"
+}
+
+func (p *fakeProvider) Get(_ context.Context, name string) (string, error) {
+	p.fetched = append(p.fetched, name)
+	v, ok := p.secrets[name]
+	if !ok {
+		return "", fmt.Errorf("secret %q not found", name)
+	}
+	return v, nil
+}
+
+var fake = &fakeProvider{
+	secrets: map[string]string{
+		"projects/my-project/secrets/db-password/versions/latest": "p@ss: #word",
+		"projects/my-project/secrets/api-key/versions/1":          "my-api-key",
+	},
+}
+
+func init() {
+	if !secrets.Register(fake) {
+		panic("fake secret provider already registered")
+	}
+}
+
+func TestResolveYAML(t *testing.T) {
+	tcs := []struct {
+		desc    string
+		in      string
+		want    map[string]any
+		fetched []string
+		err     string
+	}{
+		{
+			desc: "secrets substituted",
+			in: `
+sources:
+  my-pg-instance:
+    kind: postgres
+    user: my_user
+    password: fake://projects/my-project/secrets/db-password/versions/latest
+  my-http-instance:
+    kind: http
+    baseUrl: https://example.com/
+    queryParams:
+      api-key: fake://projects/my-project/secrets/api-key/versions/1
+`,
+			want: map[string]any{
+				"sources": map[string]any{
+					"my-pg-instance": map[string]any{
+						"kind":     "postgres",
+						"user":     "my_user",
+						"password": "p@ss: #word",
+					},
+					"my-http-instance": map[string]any{
+						"kind":        "http",
+						"baseUrl":     "https://example.com/",
+						"queryParams": map[string]any{"api-key": "my-api-key"},
+					},
+				},
+			},
+			fetched: []string{
+				"projects/my-project/secrets/api-key/versions/1",
+				"projects/my-project/secrets/db-password/versions/latest",
+			},
+		},
+		{
+			desc: "no references",
+			in: `
+sources:
+  my-pg-instance:
+    kind: postgres
+    password: my_pass
+`,
+			want: map[string]any{
+				"sources": map[string]any{
+					"my-pg-instance": map[string]any{"kind": "postgres", "password": "my_pass"},
+				},
+			},
+		},
+		{
+			desc: "anchors and aliases",
+			in: `
+sources:
+  my-pg-instance: &pg
+    kind: postgres
+    user: my_user
+    password: "fake://projects/my-project/secrets/db-password/versions/latest"
+  my-other-pg-instance:
+    <<: *pg
+    user: my_other_user
+`,
+			want: map[string]any{
+				"sources": map[string]any{
+					"my-pg-instance": map[string]any{
+						"kind":     "postgres",
+						"user":     "my_user",
+						"password": "p@ss: #word",
+					},
+					"my-other-pg-instance": map[string]any{
+						"kind":     "postgres",
+						"user":     "my_other_user",
+						"password": "p@ss: #word",
+					},
+				},
+			},
+			fetched: []string{"projects/my-project/secrets/db-password/versions/latest"},
+		},
+		{
+			desc: "missing secret",
+			in: `
+sources:
+  my-pg-instance:
+    kind: postgres
+    password: fake://projects/my-project/secrets/imaginary/versions/latest
+`,
+			fetched: []string{"projects/my-project/secrets/imaginary/versions/latest"},
+			err:     `unable to resolve secret "fake://projects/my-project/secrets/imaginary/versions/latest"`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			fake.fetched = nil
+			raw, err := secrets.ResolveYAML(context.Background(), []byte(tc.in))
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("unexpected error: got %v, want it to contain %q", err, tc.err)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				var got map[string]any
+				if err := yaml.Unmarshal(raw, &got); err != nil {
+					t.Fatalf("unable to unmarshal resolved yaml: %s", err)
+				}
+				if diff := cmp.Diff(tc.want, got); diff != "" {
+					t.Fatalf("incorrect resolved yaml: diff %v", diff)
+				}
+			}
+			sortStrings := cmpopts.SortSlices(func(a, b string) bool { return a < b })
+			if diff := cmp.Diff(tc.fetched, fake.fetched, sortStrings); diff != "" {
+				t.Fatalf("unexpected secrets fetched: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestResolveYAMLKeepsSource(t *testing.T) {
+	in := `# the database
+sources:
+  my-pg-instance:
+    kind: postgres # managed
+    password: 'fake://projects/my-project/secrets/db-password/versions/latest'
+    description: fake://projects/my-project/secrets/api-key/versions/1 is the key
+    fake://projects/my-project/secrets/api-key/versions/1: key
+    apiKeys: [fake://projects/my-project/secrets/api-key/versions/1, other]
+`
+	want := `# the database
+sources:
+  my-pg-instance:
+    kind: postgres # managed
+    password: "p@ss: #word"
+    description: fake://projects/my-project/secrets/api-key/versions/1 is the key
+    fake://projects/my-project/secrets/api-key/versions/1: key
+    apiKeys: ["my-api-key", other]
+`
+	got, err := secrets.ResolveYAML(context.Background(), []byte(in))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Fatalf("incorrect resolved yaml: diff %v", diff)
+	}
+}
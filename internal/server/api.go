@@ -15,11 +15,15 @@
 package server
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -44,6 +48,7 @@ func apiRouter(s *Server) (chi.Router, error) {
 
 	r.Route("/tool/{toolName}", func(r chi.Router) {
 		r.Get("/", func(w http.ResponseWriter, r *http.Request) { toolGetHandler(s, w, r) })
+		r.Get("/schema", func(w http.ResponseWriter, r *http.Request) { toolSchemaHandler(s, w, r) })
 		r.Post("/invoke", func(w http.ResponseWriter, r *http.Request) { toolInvokeHandler(s, w, r) })
 	})
 
@@ -84,6 +89,7 @@ func toolsetHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		_ = render.Render(w, r, newErrResponse(err, http.StatusNotFound))
 		return
 	}
+	toolset = s.localizeToolset(toolset, s.requestLocales(r.Header))
 	render.JSON(w, r, toolset.Manifest)
 }
 
@@ -124,13 +130,43 @@ func toolGetHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 	m := tools.ToolsetManifest{
 		ServerVersion: s.version,
 		ToolsManifest: map[string]tools.Manifest{
-			toolName: tool.Manifest(),
+			toolName: tools.LocalizeManifest(tool, s.requestLocales(r.Header)),
 		},
 	}
 
 	render.JSON(w, r, m)
 }
 
+// toolSchemaHandler handles requests for the JSON Schema of a Tool's parameters.
+func toolSchemaHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	ctx, span := s.instrumentation.Tracer.Start(r.Context(), "toolbox/server/tool/schema")
+	r = r.WithContext(ctx)
+
+	toolName := chi.URLParam(r, "toolName")
+	s.logger.DebugContext(ctx, fmt.Sprintf("tool name: %s", toolName))
+	span.SetAttributes(attribute.String("tool_name", toolName))
+	var err error
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+	tool, ok := s.ResourceMgr.GetTool(toolName)
+	if !ok {
+		err = fmt.Errorf("invalid tool name: tool with name %q does not exist", toolName)
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusNotFound))
+		return
+	}
+	manifest := tools.LocalizeManifest(tool, s.requestLocales(r.Header))
+	schema := tools.NewJSONSchema(manifest.Parameters)
+	schema.Title = toolName
+	schema.Description = manifest.Description
+
+	render.JSON(w, r, schema)
+}
+
 // toolInvokeHandler handles the API request to invoke a specific Tool.
 func toolInvokeHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 	ctx, span := s.instrumentation.Tracer.Start(r.Context(), "toolbox/server/tool/invoke")
@@ -167,6 +203,19 @@ func toolInvokeHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// as_file writes the result to a file and returns a URL to download it
+	// from, instead of inlining the result in the response.
+	asFile := false
+	if v := r.URL.Query().Get("as_file"); v != "" {
+		asFile, err = strconv.ParseBool(v)
+		if err != nil {
+			err = fmt.Errorf("invalid value for as_file: %w", err)
+			s.logger.DebugContext(ctx, err.Error())
+			_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+			return
+		}
+	}
+
 	// Extract OAuth access token from the "Authorization" header (currently for
 	// BigQuery end-user credentials usage only)
 	accessToken := tools.AccessToken(r.Header.Get("Authorization"))
@@ -215,15 +264,19 @@ func toolInvokeHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 	}
 	s.logger.DebugContext(ctx, "tool invocation authorized")
 
-	var data map[string]any
-	if err = util.DecodeJSON(r.Body, &data); err != nil {
-		render.Status(r, http.StatusBadRequest)
-		err = fmt.Errorf("request body was invalid JSON: %w", err)
+	if s.maxBodySize > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, s.maxBodySize)
+	}
+	data, status, err := decodeInvokeBody(r.Body)
+	if err != nil {
+		render.Status(r, status)
 		s.logger.DebugContext(ctx, err.Error())
-		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+		_ = render.Render(w, r, newErrResponse(err, status))
 		return
 	}
 
+	data = tools.ApplyHeaderParams(tool.Manifest(), data, r.Header)
+
 	params, err := tool.ParseParams(data, claimsFromAuth)
 	if err != nil {
 		// If auth error, return 401
@@ -239,6 +292,13 @@ func toolInvokeHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 	}
 	s.logger.DebugContext(ctx, fmt.Sprintf("invocation params: %s", params))
 
+	if tools.CacheBypassRequested(r.Header) {
+		ctx = tools.WithCacheBypass(ctx)
+	}
+	// results downloaded as files are always JSON
+	if tools.ArrowStreamAccepted(r.Header) && !asFile {
+		ctx = tools.WithArrowStream(ctx)
+	}
 	res, err := tool.Invoke(ctx, params, accessToken)
 
 	// Determine what error to return to the users.
@@ -246,6 +306,17 @@ func toolInvokeHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		errStr := err.Error()
 		var statusCode int
 
+		if errors.Is(err, tools.ErrConcurrencyLimit) {
+			s.logger.DebugContext(ctx, err.Error())
+			_ = render.Render(w, r, newErrResponse(err, http.StatusTooManyRequests))
+			return
+		}
+		if errors.Is(err, tools.ErrToolTimeout) {
+			s.logger.DebugContext(ctx, err.Error())
+			_ = render.Render(w, r, newErrResponse(err, http.StatusGatewayTimeout))
+			return
+		}
+
 		// Upstream API auth error propagation
 		switch {
 		case strings.Contains(errStr, "Error 401"):
@@ -273,7 +344,14 @@ func toolInvokeHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resMarshal, err := json.Marshal(res)
+	res, indent := tools.UnwrapPrettyResult(res)
+	if stream, ok := res.(tools.ArrowStream); ok {
+		w.Header().Set("Content-Type", tools.ArrowStreamMediaType)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(stream)
+		return
+	}
+	resMarshal, err := tools.MarshalResult(res, indent)
 	if err != nil {
 		err = fmt.Errorf("unable to marshal result: %w", err)
 		s.logger.DebugContext(ctx, err.Error())
@@ -281,9 +359,68 @@ func toolInvokeHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if asFile {
+		var id string
+		var expiresAt time.Time
+		id, expiresAt, err = s.downloads.add(fmt.Sprintf("%s-result.json", toolName), resMarshal)
+		if err != nil {
+			s.logger.DebugContext(ctx, err.Error())
+			_ = render.Render(w, r, newErrResponse(err, http.StatusInternalServerError))
+			return
+		}
+		scheme := "http"
+		if r.TLS != nil {
+			scheme = "https"
+		}
+		resMarshal, err = json.Marshal(map[string]any{
+			"downloadUrl": fmt.Sprintf("%s://%s/download/%s", scheme, r.Host, id),
+			"expiresAt":   expiresAt.UTC().Format(time.RFC3339),
+		})
+		if err != nil {
+			err = fmt.Errorf("unable to marshal result: %w", err)
+			s.logger.DebugContext(ctx, err.Error())
+			_ = render.Render(w, r, newErrResponse(err, http.StatusInternalServerError))
+			return
+		}
+	}
+
 	_ = render.Render(w, r, &resultResponse{Result: string(resMarshal)})
 }
 
+// decodeInvokeBody reads the parameters of a tool invocation. Its errors tell
+// an empty body, a body over the size limit and invalid JSON apart, and come
+// with the status code to respond with.
+func decodeInvokeBody(body io.Reader) (map[string]any, int, error) {
+	b, err := io.ReadAll(body)
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return nil, http.StatusRequestEntityTooLarge, fmt.Errorf("request body exceeds the maximum size of %d bytes", maxErr.Limit)
+		}
+		return nil, http.StatusBadRequest, fmt.Errorf("unable to read request body: %w", err)
+	}
+	if len(bytes.TrimSpace(b)) == 0 {
+		return nil, http.StatusBadRequest, fmt.Errorf("request body is empty: send a JSON object of parameters, e.g. {} for a tool without parameters")
+	}
+
+	var data map[string]any
+	if err := util.DecodeJSON(bytes.NewReader(b), &data); err != nil {
+		var syntaxErr *json.SyntaxError
+		var typeErr *json.UnmarshalTypeError
+		switch {
+		case errors.As(err, &syntaxErr):
+			return nil, http.StatusBadRequest, fmt.Errorf("request body is not valid JSON: %s (at byte %d)", syntaxErr, syntaxErr.Offset)
+		case errors.Is(err, io.ErrUnexpectedEOF):
+			return nil, http.StatusBadRequest, fmt.Errorf("request body is not valid JSON: unexpected end of input")
+		case errors.As(err, &typeErr):
+			return nil, http.StatusBadRequest, fmt.Errorf("request body must be a JSON object of parameters, got a JSON %s", typeErr.Value)
+		default:
+			return nil, http.StatusBadRequest, fmt.Errorf("request body is not valid JSON: %w", err)
+		}
+	}
+	return data, http.StatusOK, nil
+}
+
 var _ render.Renderer = &resultResponse{} // Renderer interface for managing response payloads.
 
 // resultResponse is the response sent back when the tool was invocated successfully.
@@ -16,13 +16,16 @@ package server
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 )
 
@@ -211,6 +214,170 @@ func TestToolGetEndpoint(t *testing.T) {
 	}
 }
 
+func TestToolGetEndpointLocalized(t *testing.T) {
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, tool3})
+	toolsMap[tool3.Name] = tools.LocalizedTool{
+		Tool: tool3,
+		Localizations: tools.Localizations{
+			"fr": {
+				Description: "une description",
+				Parameters:  map[string]string{"my_array": "ce paramètre est un tableau de chaînes"},
+			},
+			"es": {Description: "una descripción"},
+		},
+	}
+	r, shutdown := setUpServer(t, "api", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	testCases := []struct {
+		name           string
+		acceptLanguage string
+		wantDesc       string
+		wantParamDesc  string
+	}{
+		{
+			name:          "no locale",
+			wantDesc:      "some description",
+			wantParamDesc: "this param is an array of strings",
+		},
+		{
+			name:           "french",
+			acceptLanguage: "fr",
+			wantDesc:       "une description",
+			wantParamDesc:  "ce paramètre est un tableau de chaînes",
+		},
+		{
+			name:           "spanish region falls back to language",
+			acceptLanguage: "es-MX, en;q=0.8",
+			wantDesc:       "una descripción",
+			wantParamDesc:  "this param is an array of strings",
+		},
+		{
+			name:           "preferred locale by weight",
+			acceptLanguage: "es;q=0.5, fr;q=0.9",
+			wantDesc:       "une description",
+			wantParamDesc:  "ce paramètre est un tableau de chaînes",
+		},
+		{
+			name:           "unsupported locale",
+			acceptLanguage: "de",
+			wantDesc:       "some description",
+			wantParamDesc:  "this param is an array of strings",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			header := map[string]string{}
+			if tc.acceptLanguage != "" {
+				header["Accept-Language"] = tc.acceptLanguage
+			}
+			for _, path := range []string{fmt.Sprintf("/tool/%s", tool3.Name), "/toolset/"} {
+				resp, body, err := runRequest(ts, http.MethodGet, path, nil, header)
+				if err != nil {
+					t.Fatalf("unexpected error during request: %s", err)
+				}
+				if resp.StatusCode != http.StatusOK {
+					t.Fatalf("unexpected status code for %s: want %d, got %d: %s", path, http.StatusOK, resp.StatusCode, body)
+				}
+				var m tools.ToolsetManifest
+				if err := json.Unmarshal(body, &m); err != nil {
+					t.Fatalf("unable to parse ToolsetManifest: %s", err)
+				}
+				got, ok := m.ToolsManifest[tool3.Name]
+				if !ok {
+					t.Fatalf("%q tool not found in manifest for %s", tool3.Name, path)
+				}
+				if got.Description != tc.wantDesc {
+					t.Errorf("unexpected description for %s: want %q, got %q", path, tc.wantDesc, got.Description)
+				}
+				if got.Parameters[0].Description != tc.wantParamDesc {
+					t.Errorf("unexpected parameter description for %s: want %q, got %q", path, tc.wantParamDesc, got.Parameters[0].Description)
+				}
+			}
+		})
+	}
+}
+
+func TestToolSchemaEndpoint(t *testing.T) {
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, tool3})
+	r, shutdown := setUpServer(t, "api", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	testCases := []struct {
+		name       string
+		toolName   string
+		want       tools.JSONSchema
+		isErr      bool
+		wantStatus int
+	}{
+		{
+			name:     "tool without parameters",
+			toolName: tool1.Name,
+			want: tools.JSONSchema{
+				Schema:               tools.JSONSchemaDraft,
+				Title:                tool1.Name,
+				Type:                 "object",
+				AdditionalProperties: false,
+			},
+		},
+		{
+			name:     "tool with an array parameter",
+			toolName: tool3.Name,
+			want: tools.JSONSchema{
+				Schema:      tools.JSONSchemaDraft,
+				Title:       tool3.Name,
+				Type:        "object",
+				Description: "some description",
+				Properties: map[string]*tools.JSONSchema{
+					"my_array": {
+						Type:        "array",
+						Description: "this param is an array of strings",
+						Items:       &tools.JSONSchema{Type: "string", Description: "string item"},
+					},
+				},
+				Required:             []string{"my_array"},
+				AdditionalProperties: false,
+			},
+		},
+		{
+			name:       "nonexistent tool",
+			toolName:   "non_existent_tool",
+			isErr:      true,
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, body, err := runRequest(ts, http.MethodGet, fmt.Sprintf("/tool/%s/schema", tc.toolName), nil, nil)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if tc.isErr {
+				if resp.StatusCode != tc.wantStatus {
+					t.Fatalf("unexpected status code: want %d, got %d", tc.wantStatus, resp.StatusCode)
+				}
+				return
+			}
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("unexpected status code: want %d, got %d: %s", http.StatusOK, resp.StatusCode, body)
+			}
+			var got tools.JSONSchema
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("unable to parse JSON schema: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("unexpected schema (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestToolInvokeEndpoint(t *testing.T) {
 	mockTools := []MockTool{tool1, tool2, tool4, tool5}
 	toolsMap, toolsets := setUpResources(t, mockTools)
@@ -295,3 +462,304 @@ func TestToolInvokeEndpoint(t *testing.T) {
 		})
 	}
 }
+
+func TestToolInvokeEndpointAllowedValues(t *testing.T) {
+	allowlisted := MockTool{
+		Name: "allowlisted_tool",
+		Params: tools.Parameters{
+			tools.NewStringParameterWithAllowedValues("tableName", "The table to read.", []string{"hotels", "flights"}),
+		},
+	}
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, tool2, allowlisted})
+	r, shutdown := setUpServer(t, "api", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	testCases := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{
+			name:       "allowed value",
+			body:       `{"tableName": "hotels"}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "disallowed value",
+			body:       `{"tableName": "users"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, body, err := runRequest(ts, http.MethodPost, fmt.Sprintf("/tool/%s/invoke", allowlisted.Name), bytes.NewBuffer([]byte(tc.body)), nil)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != tc.wantStatus {
+				t.Fatalf("unexpected status code: got %d, want %d: %s", resp.StatusCode, tc.wantStatus, string(body))
+			}
+		})
+	}
+}
+
+// slowTool blocks until the test ends, ignoring cancellation like a driver
+// that doesn't honor its context.
+type slowTool struct {
+	MockTool
+	release chan struct{}
+}
+
+func (t slowTool) Invoke(context.Context, tools.ParamValues, tools.AccessToken) (any, error) {
+	<-t.release
+	return []any{t.Name}, nil
+}
+
+func TestToolInvokeEndpointTimeout(t *testing.T) {
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, tool2})
+	release := make(chan struct{})
+	defer close(release)
+	slow := slowTool{MockTool: MockTool{Name: "slow_tool", Params: []tools.Parameter{}}, release: release}
+	toolsMap[slow.Name] = tools.NewTimeoutTool(slow, 50*time.Millisecond)
+
+	r, shutdown := setUpServer(t, "api", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	start := time.Now()
+	resp, body, err := runRequest(ts, http.MethodPost, fmt.Sprintf("/tool/%s/invoke", slow.Name), bytes.NewBuffer([]byte(`{}`)), nil)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("handler did not return promptly after the timeout, took %s", elapsed)
+	}
+	if resp.StatusCode != http.StatusGatewayTimeout {
+		t.Fatalf("unexpected status code: got %d, want %d: %s", resp.StatusCode, http.StatusGatewayTimeout, string(body))
+	}
+	if !strings.Contains(string(body), tools.ErrToolTimeout.Error()) {
+		t.Fatalf("unexpected response body: %s", string(body))
+	}
+}
+
+func TestToolInvokeEndpointMalformedBody(t *testing.T) {
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, tool2})
+	toolsMap["strict_params"] = tools.NewDisallowUnknownTool(tool2)
+
+	r, shutdown := setUpServer(t, "api", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	tcs := []struct {
+		name       string
+		toolName   string
+		body       string
+		wantStatus int
+		wantErr    string
+	}{
+		{
+			name:       "empty body",
+			toolName:   tool2.Name,
+			body:       "",
+			wantStatus: http.StatusBadRequest,
+			wantErr:    "request body is empty",
+		},
+		{
+			name:       "whitespace body",
+			toolName:   tool2.Name,
+			body:       " \n",
+			wantStatus: http.StatusBadRequest,
+			wantErr:    "request body is empty",
+		},
+		{
+			name:       "invalid JSON",
+			toolName:   tool2.Name,
+			body:       `{"param1": 1,}`,
+			wantStatus: http.StatusBadRequest,
+			wantErr:    "request body is not valid JSON: invalid character '}' looking for beginning of object key string (at byte 14)",
+		},
+		{
+			name:       "truncated JSON",
+			toolName:   tool2.Name,
+			body:       `{"param1": 1`,
+			wantStatus: http.StatusBadRequest,
+			wantErr:    "request body is not valid JSON: unexpected end of input",
+		},
+		{
+			name:       "not an object",
+			toolName:   tool2.Name,
+			body:       `[1, 2]`,
+			wantStatus: http.StatusBadRequest,
+			wantErr:    "request body must be a JSON object of parameters, got a JSON array",
+		},
+		{
+			name:       "too large",
+			toolName:   tool2.Name,
+			body:       fmt.Sprintf(`{"param1": 1, "padding": %q}`, strings.Repeat("a", testMaxBodySize)),
+			wantStatus: http.StatusRequestEntityTooLarge,
+			wantErr:    fmt.Sprintf("request body exceeds the maximum size of %d bytes", testMaxBodySize),
+		},
+		{
+			name:       "unknown parameter",
+			toolName:   "strict_params",
+			body:       `{"param1": 1, "param3": 3}`,
+			wantStatus: http.StatusBadRequest,
+			wantErr:    `unknown parameter \"param3\"`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, body, err := runRequest(ts, http.MethodPost, fmt.Sprintf("/tool/%s/invoke", tc.toolName), bytes.NewBuffer([]byte(tc.body)), nil)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != tc.wantStatus {
+				t.Fatalf("unexpected status code: got %d, want %d: %s", resp.StatusCode, tc.wantStatus, string(body))
+			}
+			if !strings.Contains(string(body), tc.wantErr) {
+				t.Fatalf("unexpected response body: got %s, want it to contain %q", string(body), tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestToolInvokeEndpointPrettyPrint(t *testing.T) {
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, tool2})
+	toolsMap["pretty_tool"] = tools.NewPrettyPrintTool(tool1)
+
+	r, shutdown := setUpServer(t, "api", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	tcs := []struct {
+		name     string
+		toolName string
+		want     string
+	}{
+		{
+			name:     "compact by default",
+			toolName: tool1.Name,
+			want:     `["no_params"]`,
+		},
+		{
+			name:     "pretty print",
+			toolName: "pretty_tool",
+			want:     "[\n  \"no_params\"\n]",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, body, err := runRequest(ts, http.MethodPost, fmt.Sprintf("/tool/%s/invoke", tc.toolName), bytes.NewBuffer([]byte(`{}`)), nil)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("unexpected status code: got %d: %s", resp.StatusCode, string(body))
+			}
+			var got map[string]string
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("unable to parse response body: %s", err)
+			}
+			if got["result"] != tc.want {
+				t.Fatalf("unexpected result: got %q, want %q", got["result"], tc.want)
+			}
+		})
+	}
+}
+
+func TestToolInvokeEndpointArrow(t *testing.T) {
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, tool2})
+	r, shutdown := setUpServer(t, "api", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	tcs := []struct {
+		name            string
+		path            string
+		header          map[string]string
+		wantContentType string
+		wantArrow       bool
+	}{
+		{
+			name:            "json by default",
+			path:            fmt.Sprintf("/tool/%s/invoke", tool1.Name),
+			wantContentType: "application/json",
+		},
+		{
+			name:            "arrow accepted",
+			path:            fmt.Sprintf("/tool/%s/invoke", tool1.Name),
+			header:          map[string]string{"Accept": tools.ArrowStreamMediaType},
+			wantContentType: tools.ArrowStreamMediaType,
+			wantArrow:       true,
+		},
+		{
+			name:            "arrow accepted among other types",
+			path:            fmt.Sprintf("/tool/%s/invoke", tool1.Name),
+			header:          map[string]string{"Accept": "application/json;q=0.5, " + tools.ArrowStreamMediaType},
+			wantContentType: tools.ArrowStreamMediaType,
+			wantArrow:       true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, body, err := runRequest(ts, http.MethodPost, tc.path, bytes.NewBuffer([]byte(`{}`)), tc.header)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("unexpected status code: got %d: %s", resp.StatusCode, string(body))
+			}
+			if got := resp.Header.Get("Content-Type"); !strings.HasPrefix(got, tc.wantContentType) {
+				t.Fatalf("unexpected content type: got %q, want %q", got, tc.wantContentType)
+			}
+			if tc.wantArrow {
+				if got := string(body); got != tool1.Name {
+					t.Fatalf("unexpected body: got %q, want the stream returned by the tool %q", got, tool1.Name)
+				}
+				return
+			}
+			var got map[string]string
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("unable to parse response body: %s", err)
+			}
+			if want := `["no_params"]`; got["result"] != want {
+				t.Fatalf("unexpected result: got %q, want %q", got["result"], want)
+			}
+		})
+	}
+}
+
+func TestToolInvokeEndpointAlias(t *testing.T) {
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, tool2})
+	toolsMap["old_name"] = toolsMap[tool1.Name]
+
+	r, shutdown := setUpServer(t, "api", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	for _, name := range []string{tool1.Name, "old_name"} {
+		t.Run(name, func(t *testing.T) {
+			resp, body, err := runRequest(ts, http.MethodPost, fmt.Sprintf("/tool/%s/invoke", name), bytes.NewBuffer([]byte(`{}`)), nil)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("unexpected status code: got %d: %s", resp.StatusCode, string(body))
+			}
+			var got map[string]string
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("unable to parse response body: %s", err)
+			}
+			if want := `["no_params"]`; got["result"] != want {
+				t.Fatalf("unexpected result: got %q, want %q", got["result"], want)
+			}
+		})
+	}
+}
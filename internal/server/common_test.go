@@ -32,6 +32,9 @@ import (
 // fakeVersionString is used as a temporary version string in tests
 const fakeVersionString = "0.0.0"
 
+// testMaxBodySize is the request body size limit of servers in tests
+const testMaxBodySize = 1 << 10
+
 var _ tools.Tool = &MockTool{}
 
 // MockTool is used to mock tools in tests
@@ -42,9 +45,18 @@ type MockTool struct {
 	manifest                     tools.Manifest
 	unauthorized                 bool
 	requiresClientAuthrorization bool
+	// progress is reported before the result, like a long-running tool
+	progress []string
 }
 
-func (t MockTool) Invoke(context.Context, tools.ParamValues, tools.AccessToken) (any, error) {
+func (t MockTool) Invoke(ctx context.Context, _ tools.ParamValues, _ tools.AccessToken) (any, error) {
+	if tools.ArrowStreamRequested(ctx) {
+		// stands in for an Arrow IPC stream
+		return tools.ArrowStream(t.Name), nil
+	}
+	for _, message := range t.progress {
+		tools.ReportProgress(ctx, message)
+	}
 	mock := []any{t.Name}
 	return mock, nil
 }
@@ -175,6 +187,11 @@ func setUpServer(t *testing.T, router string, tools map[string]tools.Tool, tools
 
 	sseManager := newSseManager(ctx)
 
+	downloads, err := newDownloadStore(ctx, downloadTTL)
+	if err != nil {
+		t.Fatalf("unable to create download store: %s", err)
+	}
+
 	resourceManager := NewResourceManager(nil, nil, tools, toolsets)
 
 	server := Server{
@@ -182,6 +199,8 @@ func setUpServer(t *testing.T, router string, tools map[string]tools.Tool, tools
 		logger:          testLogger,
 		instrumentation: instrumentation,
 		sseManager:      sseManager,
+		downloads:       downloads,
+		maxBodySize:     testMaxBodySize,
 		ResourceMgr:     resourceManager,
 	}
 
@@ -197,6 +216,19 @@ func setUpServer(t *testing.T, router string, tools map[string]tools.Tool, tools
 		if err != nil {
 			t.Fatalf("unable to initialize mcp router: %s", err)
 		}
+	case "root":
+		// mounts the routers that serve tool invocations and their downloads
+		r = chi.NewRouter()
+		apiR, err := apiRouter(&server)
+		if err != nil {
+			t.Fatalf("unable to initialize api router: %s", err)
+		}
+		r.Mount("/api", apiR)
+		downloadR, err := downloadRouter(&server)
+		if err != nil {
+			t.Fatalf("unable to initialize download router: %s", err)
+		}
+		r.Mount("/download", downloadR)
 	default:
 		t.Fatalf("unknown router")
 	}
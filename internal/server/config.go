@@ -16,6 +16,7 @@ package server
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 
 	yaml "github.com/goccy/go-yaml"
@@ -57,6 +58,27 @@ type ServerConfig struct {
 	DisableReload bool
 	// UI indicates if Toolbox UI endpoints (/ui) are available
 	UI bool
+	// DefaultLocale is the locale used for tool descriptions when a request
+	// does not ask for a supported one.
+	DefaultLocale string
+	// UserAgentSuffix is appended to the user agent sources send to
+	// downstream services.
+	UserAgentSuffix string
+	// EmptyResultMessage is returned by every tool whose result has no data,
+	// unless the tool sets its own. Results are left as is if it is empty.
+	EmptyResultMessage string
+	// MaxRequestBodySize is the maximum size in bytes of a tool invocation
+	// request body. Zero means no limit.
+	MaxRequestBodySize int64
+	// PrettyPrint indents the JSON results of every tool.
+	PrettyPrint bool
+	// MaxStatementLength is the maximum length in characters of the statement
+	// of every tool that takes one, such as execute-sql tools, unless the tool
+	// sets its own. Zero means no limit.
+	MaxStatementLength int
+	// DefaultAuthRequired is the list of auth services required by every tool
+	// that doesn't set `authRequired`. Tools opt out with an empty list.
+	DefaultAuthRequired []string
 }
 
 type logFormat string
@@ -223,9 +245,79 @@ func (c *ToolConfigs) UnmarshalYAML(ctx context.Context, unmarshal func(interfac
 			return fmt.Errorf("`authRequired` and `useClientOAuth` are mutually exclusive. Choose only one authentication method")
 		}
 
-		// Make `authRequired` an empty list instead of nil for Tool manifest
+		// Tools that don't set `authRequired` inherit the server default. An
+		// explicit empty list opts out of it.
 		if v["authRequired"] == nil {
-			v["authRequired"] = []string{}
+			defaultAuth := util.DefaultAuthRequiredFromContext(ctx)
+			if len(defaultAuth) > 0 && v["useClientOAuth"] != true {
+				v["authRequired"] = slices.Clone(defaultAuth)
+			} else {
+				// Make `authRequired` an empty list instead of nil for Tool manifest
+				v["authRequired"] = []string{}
+			}
+		}
+
+		// Translated descriptions, concurrency limits, result caching,
+		// parameter coercion and validation, empty result messages, invoke
+		// timeouts, pretty-printing, key casing, result aggregation,
+		// examples, statement length limits, resolved statements and aliases
+		// are handled for every kind, so remove them before the tool config
+		// is strictly decoded.
+		localizations, err := tools.ExtractLocalizations(name, v)
+		if err != nil {
+			return err
+		}
+		limit, err := tools.ExtractConcurrencyLimit(name, v)
+		if err != nil {
+			return err
+		}
+		cacheOpts, err := tools.ExtractCacheOptions(name, v)
+		if err != nil {
+			return err
+		}
+		coerce, err := tools.ExtractParamCoercion(name, v)
+		if err != nil {
+			return err
+		}
+		emptyMsg, err := tools.ExtractEmptyResultMessage(name, v)
+		if err != nil {
+			return err
+		}
+		timeout, err := tools.ExtractInvokeTimeout(name, v)
+		if err != nil {
+			return err
+		}
+		disallowUnknown, err := tools.ExtractDisallowUnknownFields(name, v)
+		if err != nil {
+			return err
+		}
+		pretty, err := tools.ExtractPrettyPrint(name, v)
+		if err != nil {
+			return err
+		}
+		keyCase, err := tools.ExtractKeyCase(name, v)
+		if err != nil {
+			return err
+		}
+		aggregate, err := tools.ExtractAggregate(name, v)
+		if err != nil {
+			return err
+		}
+		examples, err := tools.ExtractExamples(ctx, name, v)
+		if err != nil {
+			return err
+		}
+		maxStatementLength, err := tools.ExtractMaxStatementLength(name, v)
+		if err != nil {
+			return err
+		}
+		aliases, err := tools.ExtractAliases(name, v)
+		if err != nil {
+			return err
+		}
+		includeStatement, err := tools.ExtractIncludeResolvedStatement(name, v)
+		if err != nil {
+			return err
 		}
 
 		kindVal, ok := v["kind"]
@@ -246,7 +338,81 @@ func (c *ToolConfigs) UnmarshalYAML(ctx context.Context, unmarshal func(interfac
 		if err != nil {
 			return err
 		}
+		_, takesStatement := toolCfg.(tools.StatementConfig)
+		if maxStatementLength > 0 && !takesStatement {
+			return fmt.Errorf("%q of tool %q is only supported by tools that take a statement, such as execute-sql tools", "maxStatementLength", name)
+		}
+		// The per-tool options wrap the config of the tool kind. From the
+		// innermost wrapper to the outermost one:
+		//   - includeResolvedStatement, so that the tool kind can resolve the
+		//     statement
+		//   - invokeTimeout, which doesn't count time spent waiting for a
+		//     concurrency slot
+		//   - maxConcurrency and concurrencyPolicy
+		//   - coerceParameters
+		//   - disallowUnknownFields
+		//   - keyCase, inside the aggregation, which only converts lists of
+		//     rows, and inside the cache, so that cached rows are already
+		//     converted
+		//   - aggregateAs and aggregateKey
+		//   - emptyResultMessage
+		//   - cacheTTL and cacheTags, so that cached results are returned
+		//     without waiting for a concurrency slot
+		//   - prettyPrint
+		//   - examples
+		//   - descriptions
+		//   - maxStatementLength, so that the server default can tell which
+		//     tools take a statement and whether their limit is set
+		if includeStatement {
+			toolCfg = tools.ResolvedStatementConfig{ToolConfig: toolCfg}
+		}
+		if timeout > 0 {
+			toolCfg = tools.TimeoutConfig{ToolConfig: toolCfg, Option: tools.TimeoutOption{Timeout: timeout}}
+		}
+		if limit != nil {
+			toolCfg = tools.LimitedConfig{ToolConfig: toolCfg, Option: *limit}
+		}
+		if coerce {
+			toolCfg = tools.CoercingConfig{ToolConfig: toolCfg}
+		}
+		if disallowUnknown {
+			toolCfg = tools.DisallowUnknownConfig{ToolConfig: toolCfg}
+		}
+		if keyCase != "" && keyCase != tools.KeyCaseNone {
+			toolCfg = tools.KeyCaseConfig{ToolConfig: toolCfg, Option: tools.KeyCaseOption{Case: keyCase}}
+		}
+		if aggregate != nil && aggregate.As != tools.AggregateArray {
+			toolCfg = tools.AggregateConfig{ToolConfig: toolCfg, Option: *aggregate}
+		}
+		if emptyMsg != "" {
+			toolCfg = tools.EmptyResultConfig{ToolConfig: toolCfg, Option: tools.EmptyResultOption{Message: emptyMsg}}
+		}
+		if cacheOpts != nil {
+			toolCfg = tools.CachedConfig{ToolConfig: toolCfg, Option: tools.CacheOption{Name: name, Options: *cacheOpts}}
+		}
+		if pretty {
+			toolCfg = tools.PrettyPrintConfig{ToolConfig: toolCfg}
+		}
+		if examples != nil {
+			toolCfg = tools.ExampleConfig{ToolConfig: toolCfg, Option: tools.ExampleOption{Examples: examples}}
+		}
+		if localizations != nil {
+			toolCfg = tools.LocalizedConfig{ToolConfig: toolCfg, Option: localizations}
+		}
+		if takesStatement {
+			toolCfg = tools.StatementLengthConfig{ToolConfig: toolCfg, Option: tools.StatementLengthOption{MaxLength: maxStatementLength}}
+		}
 		(*c)[name] = toolCfg
+
+		for _, alias := range aliases {
+			if _, ok := raw[alias]; ok {
+				return fmt.Errorf("alias %q of tool %q is already the name of a tool", alias, name)
+			}
+			if other, ok := (*c)[alias].(tools.AliasConfig); ok {
+				return fmt.Errorf("alias %q of tool %q is already an alias of tool %q", alias, name, other.Tool)
+			}
+			(*c)[alias] = tools.AliasConfig{Name: alias, Tool: name}
+		}
 	}
 	return nil
 }
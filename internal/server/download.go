@@ -0,0 +1,138 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// downloadTTL is how long a tool result written to a file can be downloaded.
+const downloadTTL = 15 * time.Minute
+
+// downloadFile is a tool result that was written to a temporary file.
+type downloadFile struct {
+	path      string
+	name      string
+	expiresAt time.Time
+}
+
+// downloadStore keeps tool results written to temporary files, so that large
+// results can be downloaded instead of being inlined in a response.
+type downloadStore struct {
+	dir string
+	ttl time.Duration
+
+	mu    sync.Mutex
+	files map[string]downloadFile
+}
+
+func newDownloadStore(ctx context.Context, ttl time.Duration) (*downloadStore, error) {
+	dir, err := os.MkdirTemp("", "toolbox-downloads-")
+	if err != nil {
+		return nil, fmt.Errorf("unable to create download directory: %w", err)
+	}
+	d := &downloadStore{
+		dir:   dir,
+		ttl:   ttl,
+		files: make(map[string]downloadFile),
+	}
+	go d.cleanupRoutine(ctx)
+	return d, nil
+}
+
+// add writes data to a new file, which is named name when downloaded, and
+// returns the id to download it with and when it expires.
+func (d *downloadStore) add(name string, data []byte) (string, time.Time, error) {
+	id := uuid.New().String()
+	path := filepath.Join(d.dir, id)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return "", time.Time{}, fmt.Errorf("unable to write download file: %w", err)
+	}
+	expiresAt := time.Now().Add(d.ttl)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.files[id] = downloadFile{path: path, name: name, expiresAt: expiresAt}
+	return id, expiresAt, nil
+}
+
+// get returns the file with the given id, unless it does not exist or has
+// expired.
+func (d *downloadStore) get(id string) (downloadFile, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	f, ok := d.files[id]
+	if !ok || time.Now().After(f.expiresAt) {
+		return downloadFile{}, false
+	}
+	return f, true
+}
+
+// removeExpired deletes the files that expired before now.
+func (d *downloadStore) removeExpired(now time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for id, f := range d.files {
+		if now.After(f.expiresAt) {
+			_ = os.Remove(f.path)
+			delete(d.files, id)
+		}
+	}
+}
+
+func (d *downloadStore) cleanupRoutine(ctx context.Context) {
+	ticker := time.NewTicker(d.ttl)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			_ = os.RemoveAll(d.dir)
+			return
+		case <-ticker.C:
+			d.removeExpired(time.Now())
+		}
+	}
+}
+
+// downloadRouter creates a router that represents the routes under /download
+func downloadRouter(s *Server) (chi.Router, error) {
+	r := chi.NewRouter()
+	r.Get("/{id}", func(w http.ResponseWriter, r *http.Request) { downloadHandler(s, w, r) })
+	return r, nil
+}
+
+// downloadHandler serves a tool result that was written to a file.
+func downloadHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	f, ok := s.downloads.get(id)
+	if !ok {
+		s.logger.DebugContext(r.Context(), fmt.Sprintf("download %q not found or expired", id))
+		http.Error(w, "download not found or expired", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", f.name))
+	http.ServeFile(w, r, f.path)
+}
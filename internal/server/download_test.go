@@ -0,0 +1,118 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestToolInvokeAsFile(t *testing.T) {
+	mockTools := []MockTool{tool1, tool2}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	r, shutdown := setUpServer(t, "root", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	resp, body, err := runRequest(ts, http.MethodPost, fmt.Sprintf("/api/tool/%s/invoke?as_file=true", tool1.Name), bytes.NewBuffer([]byte(`{}`)), nil)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("response status code is not 200, got %d, %s", resp.StatusCode, string(body))
+	}
+
+	var res resultResponse
+	if err := json.Unmarshal(body, &res); err != nil {
+		t.Fatalf("unable to parse response: %s", err)
+	}
+	var download struct {
+		DownloadURL string `json:"downloadUrl"`
+		ExpiresAt   string `json:"expiresAt"`
+	}
+	if err := json.Unmarshal([]byte(res.Result), &download); err != nil {
+		t.Fatalf("unable to parse result %q: %s", res.Result, err)
+	}
+	if !strings.HasPrefix(download.DownloadURL, ts.URL+"/download/") {
+		t.Fatalf("unexpected download url: got %q, want prefix %q", download.DownloadURL, ts.URL+"/download/")
+	}
+	if _, err := time.Parse(time.RFC3339, download.ExpiresAt); err != nil {
+		t.Fatalf("unable to parse expiry %q: %s", download.ExpiresAt, err)
+	}
+
+	resp, body, err = runRequest(ts, http.MethodGet, strings.TrimPrefix(download.DownloadURL, ts.URL), nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error during download: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("download status code is not 200, got %d, %s", resp.StatusCode, string(body))
+	}
+	if got, want := string(body), `["no_params"]`; got != want {
+		t.Fatalf("unexpected file contents: got %q, want %q", got, want)
+	}
+	if got := resp.Header.Get("Content-Disposition"); !strings.Contains(got, "no_params-result.json") {
+		t.Fatalf("unexpected Content-Disposition header: %q", got)
+	}
+
+	resp, body, err = runRequest(ts, http.MethodGet, "/download/some-imaginary-id", nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error during download: %s", err)
+	}
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown download, got %d, %s", resp.StatusCode, string(body))
+	}
+
+	resp, body, err = runRequest(ts, http.MethodPost, fmt.Sprintf("/api/tool/%s/invoke?as_file=maybe", tool1.Name), bytes.NewBuffer([]byte(`{}`)), nil)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid as_file, got %d, %s", resp.StatusCode, string(body))
+	}
+}
+
+func TestDownloadStoreExpiry(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	d, err := newDownloadStore(ctx, time.Minute)
+	if err != nil {
+		t.Fatalf("unable to create download store: %s", err)
+	}
+
+	id, expiresAt, err := d.add("result.json", []byte(`[]`))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	f, ok := d.get(id)
+	if !ok {
+		t.Fatalf("expected download %q to exist", id)
+	}
+
+	d.removeExpired(expiresAt.Add(time.Second))
+	if _, ok := d.get(id); ok {
+		t.Fatalf("expected download %q to be removed after it expired", id)
+	}
+	if _, err := os.Stat(f.path); !os.IsNotExist(err) {
+		t.Fatalf("expected file %q to be deleted, got %v", f.path, err)
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-chi/render"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+)
+
+// readinessTimeout bounds how long the readiness check waits for sources.
+const readinessTimeout = 5 * time.Second
+
+// healthResponse is the body of the health and readiness endpoints.
+type healthResponse struct {
+	Status string `json:"status"`
+	// Sources maps the name of each source that failed its check to the error.
+	Sources map[string]string `json:"sources,omitempty"`
+}
+
+// healthzHandler reports that the server is running. It doesn't check sources,
+// so that a struggling database doesn't get the server restarted.
+func healthzHandler(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, healthResponse{Status: "ok"})
+}
+
+// readyzHandler reports whether the server is ready to serve tools, i.e. every
+// source that implements sources.Pinger can be reached. It responds with 503
+// and the failing sources otherwise.
+func readyzHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+	defer cancel()
+
+	failed := pingSources(ctx, s.ResourceMgr.GetSourcesMap())
+	if len(failed) > 0 {
+		for name, err := range failed {
+			s.logger.WarnContext(ctx, fmt.Sprintf("source %q is not ready: %s", name, err))
+		}
+		render.Status(r, http.StatusServiceUnavailable)
+		render.JSON(w, r, healthResponse{Status: "unavailable", Sources: failed})
+		return
+	}
+	render.JSON(w, r, healthResponse{Status: "ok"})
+}
+
+// pingSources pings all sources concurrently, and returns the errors of the
+// ones that failed by source name.
+func pingSources(ctx context.Context, srcs map[string]sources.Source) map[string]string {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	failed := make(map[string]string)
+	for name, src := range srcs {
+		p, ok := src.(sources.Pinger)
+		if !ok {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := p.Ping(ctx); err != nil {
+				mu.Lock()
+				defer mu.Unlock()
+				failed[name] = err.Error()
+			}
+		}()
+	}
+	wg.Wait()
+	return failed
+}
@@ -0,0 +1,122 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+)
+
+// pingSource is a source whose Ping returns err.
+type pingSource struct {
+	err error
+}
+
+func (s pingSource) SourceKind() string {
+	return "ping-source"
+}
+
+func (s pingSource) Ping(context.Context) error {
+	return s.err
+}
+
+// plainSource is a source that can't be pinged.
+type plainSource struct{}
+
+func (s plainSource) SourceKind() string {
+	return "plain-source"
+}
+
+func TestHealthz(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	w := httptest.NewRecorder()
+	healthzHandler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("response status code is not 200, got %d, %s", w.Code, w.Body.String())
+	}
+}
+
+func TestReadyz(t *testing.T) {
+	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "info")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+
+	tcs := []struct {
+		name       string
+		srcs       map[string]sources.Source
+		wantStatus int
+		want       healthResponse
+	}{
+		{
+			name:       "no sources",
+			wantStatus: http.StatusOK,
+			want:       healthResponse{Status: "ok"},
+		},
+		{
+			name: "all sources healthy",
+			srcs: map[string]sources.Source{
+				"my-db":    pingSource{},
+				"my-other": plainSource{},
+			},
+			wantStatus: http.StatusOK,
+			want:       healthResponse{Status: "ok"},
+		},
+		{
+			name: "unhealthy source",
+			srcs: map[string]sources.Source{
+				"my-db":     pingSource{},
+				"my-broken": pingSource{err: errors.New("connection refused")},
+			},
+			wantStatus: http.StatusServiceUnavailable,
+			want: healthResponse{
+				Status:  "unavailable",
+				Sources: map[string]string{"my-broken": "connection refused"},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &Server{
+				logger:      testLogger,
+				ResourceMgr: NewResourceManager(tc.srcs, nil, nil, nil),
+			}
+			req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+			w := httptest.NewRecorder()
+			readyzHandler(s, w, req)
+
+			if w.Code != tc.wantStatus {
+				t.Fatalf("unexpected status code: got %d, want %d, %s", w.Code, tc.wantStatus, w.Body.String())
+			}
+			var got healthResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatalf("unable to parse response: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("unexpected response: diff %v", diff)
+			}
+		})
+	}
+}
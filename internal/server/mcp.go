@@ -57,6 +57,19 @@ type sseManager struct {
 	sseSessions map[string]*sseSession
 }
 
+// queue queues a message to be sent to the client as an sse event
+func (s *sseSession) queue(ctx context.Context, srv *Server, message any) {
+	eventData, _ := json.Marshal(message)
+	select {
+	case s.eventQueue <- fmt.Sprintf("event: message\ndata: %s\n\n", eventData):
+		srv.logger.DebugContext(ctx, "event queue successful")
+	case <-s.done:
+		srv.logger.DebugContext(ctx, "session is close")
+	default:
+		srv.logger.DebugContext(ctx, "unable to add to event queue")
+	}
+}
+
 func (m *sseManager) get(id string) (*sseSession, bool) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -144,7 +157,12 @@ func (s *stdioSession) readInputStream(ctx context.Context) error {
 			}
 			return err
 		}
-		v, res, err := processMcpMessage(ctx, []byte(line), s.server, s.protocol, "", nil)
+		notify := func(n any) {
+			if err := s.write(ctx, n); err != nil {
+				s.server.logger.DebugContext(ctx, fmt.Sprintf("unable to write notification: %s", err))
+			}
+		}
+		v, res, err := processMcpMessage(ctx, []byte(line), s.server, s.protocol, "", nil, notify)
 		if err != nil {
 			// errors during the processing of message will generate a valid MCP Error response.
 			// server can continue to run.
@@ -406,7 +424,15 @@ func httpHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	v, res, err := processMcpMessage(ctx, body, s, protocolVersion, toolsetName, r.Header)
+	// progress notifications can only be sent through an sse session
+	var notify func(any)
+	if session != nil {
+		notify = func(n any) {
+			session.queue(ctx, s, n)
+		}
+	}
+
+	v, res, err := processMcpMessage(ctx, body, s, protocolVersion, toolsetName, r.Header, notify)
 	if err != nil {
 		s.logger.DebugContext(ctx, fmt.Errorf("error processing message: %w", err).Error())
 	}
@@ -426,16 +452,7 @@ func httpHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 	}
 
 	if session != nil {
-		// queue sse event
-		eventData, _ := json.Marshal(res)
-		select {
-		case session.eventQueue <- fmt.Sprintf("event: message\ndata: %s\n\n", eventData):
-			s.logger.DebugContext(ctx, "event queue successful")
-		case <-session.done:
-			s.logger.DebugContext(ctx, "session is close")
-		default:
-			s.logger.DebugContext(ctx, "unable to add to event queue")
-		}
+		session.queue(ctx, s, res)
 	}
 	if rpcResponse, ok := res.(jsonrpc.JSONRPCError); ok {
 		code := rpcResponse.Error.Code
@@ -458,8 +475,52 @@ func httpHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 	render.JSON(w, r, res)
 }
 
-// processMcpMessage process the messages received from clients
-func processMcpMessage(ctx context.Context, body []byte, s *Server, protocolVersion string, toolsetName string, header http.Header) (string, any, error) {
+// progressNotifier sends progress notifications for a request with a
+// progress token. Progress reported after the request completed is dropped,
+// so that no notification follows the response.
+type progressNotifier struct {
+	mu       sync.Mutex
+	token    jsonrpc.ProgressToken
+	notify   func(any)
+	progress int
+	done     bool
+}
+
+func (p *progressNotifier) report(message string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.done {
+		return
+	}
+	p.progress++
+	p.notify(mcputil.NewProgressNotification(p.token, float64(p.progress), message))
+}
+
+func (p *progressNotifier) close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done = true
+}
+
+// withProgress returns a context that reports the progress of invocations
+// through notify, if the request has a progress token. The returned func must
+// be called once the request completed.
+func withProgress(ctx context.Context, body []byte, notify func(any)) (context.Context, func()) {
+	if notify == nil {
+		return ctx, func() {}
+	}
+	var req jsonrpc.Request
+	if err := json.Unmarshal(body, &req); err != nil || req.Params.Meta.ProgressToken == nil {
+		return ctx, func() {}
+	}
+	p := &progressNotifier{token: req.Params.Meta.ProgressToken, notify: notify}
+	return tools.WithProgress(ctx, p.report), p.close
+}
+
+// processMcpMessage process the messages received from clients. notify sends
+// notifications, e.g. the progress of a tool call, to the client; it is nil
+// if the transport can't send them.
+func processMcpMessage(ctx context.Context, body []byte, s *Server, protocolVersion string, toolsetName string, header http.Header, notify func(any)) (string, any, error) {
 	logger, err := util.LoggerFromContext(ctx)
 	if err != nil {
 		return "", jsonrpc.NewError("", jsonrpc.INTERNAL_ERROR, err.Error(), nil), err
@@ -514,6 +575,9 @@ func processMcpMessage(ctx context.Context, body []byte, s *Server, protocolVers
 			err = fmt.Errorf("toolset does not exist")
 			return "", jsonrpc.NewError(baseMessage.Id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
 		}
+		toolset = s.localizeToolset(toolset, s.requestLocales(header))
+		ctx, done := withProgress(ctx, body, notify)
+		defer done()
 		res, err := mcp.ProcessMethod(ctx, protocolVersion, baseMessage.Id, baseMessage.Method, toolset, s.ResourceMgr.GetToolsMap(), s.ResourceMgr.GetAuthServiceMap(), body, header)
 		return "", res, err
 	}
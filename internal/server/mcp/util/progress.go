@@ -0,0 +1,57 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"github.com/googleapis/genai-toolbox/internal/server/mcp/jsonrpc"
+)
+
+// PROGRESS_NOTIFICATION is the method of progress notifications.
+const PROGRESS_NOTIFICATION = "notifications/progress"
+
+/* Progress */
+
+// ProgressNotificationParams describes the progress of a request.
+type ProgressNotificationParams struct {
+	// The progress token given in the request.
+	ProgressToken jsonrpc.ProgressToken `json:"progressToken"`
+	// The progress thus far. This increases every time progress is made,
+	// even if the total is unknown.
+	Progress float64 `json:"progress"`
+	// A message describing the current progress.
+	Message string `json:"message,omitempty"`
+}
+
+// ProgressNotification is sent to inform the client of the progress of a
+// long-running request that set a progress token.
+type ProgressNotification struct {
+	Jsonrpc string                     `json:"jsonrpc"`
+	Method  string                     `json:"method"`
+	Params  ProgressNotificationParams `json:"params"`
+}
+
+// NewProgressNotification returns the notification of the given progress
+// of the request with the progress token.
+func NewProgressNotification(token jsonrpc.ProgressToken, progress float64, message string) ProgressNotification {
+	return ProgressNotification{
+		Jsonrpc: jsonrpc.JSONRPC_VERSION,
+		Method:  PROGRESS_NOTIFICATION,
+		Params: ProgressNotificationParams{
+			ProgressToken: token,
+			Progress:      progress,
+			Message:       message,
+		},
+	}
+}
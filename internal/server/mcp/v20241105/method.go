@@ -152,6 +152,9 @@ func toolsCallHandler(ctx context.Context, id jsonrpc.RequestId, toolsMap map[st
 	}
 	logger.DebugContext(ctx, "tool invocation authorized")
 
+	// with stdio, header is nil and parameters aren't taken from headers
+	data = tools.ApplyHeaderParams(tool.Manifest(), data, header)
+
 	params, err := tool.ParseParams(data, claimsFromAuth)
 	if err != nil {
 		err = fmt.Errorf("provided parameters were invalid: %w", err)
@@ -160,6 +163,9 @@ func toolsCallHandler(ctx context.Context, id jsonrpc.RequestId, toolsMap map[st
 	logger.DebugContext(ctx, fmt.Sprintf("invocation params: %s", params))
 
 	// run tool invocation and generate response.
+	if tools.CacheBypassRequested(header) {
+		ctx = tools.WithCacheBypass(ctx)
+	}
 	results, err := tool.Invoke(ctx, params, accessToken)
 	if err != nil {
 		errStr := err.Error()
@@ -190,6 +196,7 @@ func toolsCallHandler(ctx context.Context, id jsonrpc.RequestId, toolsMap map[st
 
 	content := make([]TextContent, 0)
 
+	results, indent := tools.UnwrapPrettyResult(results)
 	sliceRes, ok := results.([]any)
 	if !ok {
 		sliceRes = []any{results}
@@ -197,7 +204,7 @@ func toolsCallHandler(ctx context.Context, id jsonrpc.RequestId, toolsMap map[st
 
 	for _, d := range sliceRes {
 		text := TextContent{Type: "text"}
-		dM, err := json.Marshal(d)
+		dM, err := tools.MarshalResult(d, indent)
 		if err != nil {
 			text.Text = fmt.Sprintf("fail to marshal: %s, result: %s", err, d)
 		} else {
@@ -31,6 +31,7 @@ import (
 	"github.com/googleapis/genai-toolbox/internal/server/mcp/jsonrpc"
 	"github.com/googleapis/genai-toolbox/internal/telemetry"
 	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
 )
 
 const jsonrpcVersion = "2.0"
@@ -999,3 +1000,90 @@ func TestStdioSession(t *testing.T) {
 		t.Fatalf("unexpected read: got %s, want %s", read, want)
 	}
 }
+
+func TestStdioSessionProgress(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	slowTool := MockTool{
+		Name:     "slow_tool",
+		Params:   []tools.Parameter{},
+		progress: []string{"job submitted", "job complete"},
+	}
+	toolsMap, toolsets := setUpResources(t, []MockTool{slowTool, tool1})
+
+	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "warn")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	ctx = util.WithLogger(ctx, testLogger)
+
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation(fakeVersionString)
+	if err != nil {
+		t.Fatalf("unable to create custom metrics: %s", err)
+	}
+
+	server := &Server{
+		version:         fakeVersionString,
+		logger:          testLogger,
+		instrumentation: instrumentation,
+		sseManager:      newSseManager(ctx),
+		ResourceMgr:     NewResourceManager(nil, nil, toolsMap, toolsets),
+	}
+
+	inR, inW, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("error with Pipe: %s", err)
+	}
+	outR, outW, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("error with Pipe: %s", err)
+	}
+	go func() {
+		_ = NewStdioSession(server, inR, outW).Start(ctx)
+	}()
+
+	request := `{"jsonrpc":"2.0","id":"call","method":"tools/call","params":{"name":"slow_tool","arguments":{},"_meta":{"progressToken":"tok"}}}`
+	if _, err := fmt.Fprintf(inW, "%s\n", request); err != nil {
+		t.Fatalf("error writing into pipe: %s", err)
+	}
+
+	out := bufio.NewReader(outR)
+	var messages []map[string]any
+	for {
+		line, err := out.ReadString('\n')
+		if err != nil {
+			t.Fatalf("error reading: %s", err)
+		}
+		var m map[string]any
+		if err := json.Unmarshal([]byte(line), &m); err != nil {
+			t.Fatalf("unable to parse message %q: %s", line, err)
+		}
+		messages = append(messages, m)
+		if _, ok := m["id"]; ok {
+			break
+		}
+	}
+
+	want := []map[string]any{
+		{
+			"jsonrpc": "2.0",
+			"method":  "notifications/progress",
+			"params":  map[string]any{"progressToken": "tok", "progress": 1.0, "message": "job submitted"},
+		},
+		{
+			"jsonrpc": "2.0",
+			"method":  "notifications/progress",
+			"params":  map[string]any{"progressToken": "tok", "progress": 2.0, "message": "job complete"},
+		},
+	}
+	if len(messages) != len(want)+1 {
+		t.Fatalf("expected %d progress notifications before the result, got messages %v", len(want), messages)
+	}
+	if !reflect.DeepEqual(messages[:len(want)], want) {
+		t.Fatalf("unexpected progress notifications: got %v, want %v", messages[:len(want)], want)
+	}
+	if _, ok := messages[len(want)]["result"]; !ok {
+		t.Fatalf("expected the tool result after the progress notifications, got %v", messages[len(want)])
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"sort"
 	"strconv"
 	"sync"
 	"time"
@@ -46,6 +47,9 @@ type Server struct {
 	logger          log.Logger
 	instrumentation *telemetry.Instrumentation
 	sseManager      *sseManager
+	downloads       *downloadStore
+	defaultLocale   string
+	maxBodySize     int64
 	ResourceMgr     *ResourceManager
 }
 
@@ -111,6 +115,12 @@ func (r *ResourceManager) SetResources(sourcesMap map[string]sources.Source, aut
 	r.toolsets = toolsetsMap
 }
 
+func (r *ResourceManager) GetSourcesMap() map[string]sources.Source {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.sources
+}
+
 func (r *ResourceManager) GetAuthServiceMap() map[string]auth.AuthService {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -123,6 +133,12 @@ func (r *ResourceManager) GetToolsMap() map[string]tools.Tool {
 	return r.tools
 }
 
+func (r *ResourceManager) GetToolsetsMap() map[string]tools.Toolset {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.toolsets
+}
+
 func InitializeConfigs(ctx context.Context, cfg ServerConfig) (
 	map[string]sources.Source,
 	map[string]auth.AuthService,
@@ -130,7 +146,7 @@ func InitializeConfigs(ctx context.Context, cfg ServerConfig) (
 	map[string]tools.Toolset,
 	error,
 ) {
-	ctx = util.WithUserAgent(ctx, cfg.Version)
+	ctx = util.WithUserAgent(ctx, cfg.Version, cfg.UserAgentSuffix)
 	instrumentation, err := util.InstrumentationFromContext(ctx)
 	if err != nil {
 		panic(err)
@@ -190,8 +206,13 @@ func InitializeConfigs(ctx context.Context, cfg ServerConfig) (
 	l.InfoContext(ctx, fmt.Sprintf("Initialized %d authServices.", len(authServicesMap)))
 
 	// initialize and validate the tools from configs
+	toolOrder, err := toolInitOrder(cfg.ToolConfigs)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
 	toolsMap := make(map[string]tools.Tool)
-	for name, tc := range cfg.ToolConfigs {
+	for _, name := range toolOrder {
+		tc := cfg.ToolConfigs[name]
 		t, err := func() (tools.Tool, error) {
 			_, span := instrumentation.Tracer.Start(
 				ctx,
@@ -200,7 +221,7 @@ func InitializeConfigs(ctx context.Context, cfg ServerConfig) (
 				trace.WithAttributes(attribute.String("tool_name", name)),
 			)
 			defer span.End()
-			t, err := tc.Initialize(sourcesMap)
+			t, err := tools.InitializeWithTools(tc, sourcesMap, toolsMap)
 			if err != nil {
 				return nil, fmt.Errorf("unable to initialize tool %q: %w", name, err)
 			}
@@ -211,12 +232,45 @@ func InitializeConfigs(ctx context.Context, cfg ServerConfig) (
 		}
 		toolsMap[name] = t
 	}
+	// The server default is applied once every tool is initialized, so that
+	// tools built on top of other tools still see results without data.
+	if cfg.EmptyResultMessage != "" {
+		for name, t := range toolsMap {
+			toolsMap[name] = tools.NewEmptyResultTool(t, cfg.EmptyResultMessage)
+		}
+	}
+	if cfg.PrettyPrint {
+		for name, t := range toolsMap {
+			toolsMap[name] = tools.NewPrettyPrintTool(t)
+		}
+	}
+	if cfg.MaxStatementLength > 0 {
+		for name, t := range toolsMap {
+			// only tools that take a statement are limited, and their own
+			// limit takes precedence
+			if c, ok := cfg.ToolConfigs[name].(tools.StatementLengthConfig); ok && c.Option.MaxLength == 0 {
+				toolsMap[name] = tools.NewStatementLengthTool(t, cfg.MaxStatementLength)
+			}
+		}
+	}
+	// Aliases are resolved last, so that they are the very same tool as the
+	// one they refer to, including the server defaults.
+	aliases := make(map[string]bool)
+	for name, tc := range cfg.ToolConfigs {
+		if a, ok := tc.(tools.AliasConfig); ok {
+			toolsMap[name] = toolsMap[a.Tool]
+			aliases[name] = true
+		}
+	}
 	l.InfoContext(ctx, fmt.Sprintf("Initialized %d tools.", len(toolsMap)))
 
-	// create a default toolset that contains all tools
+	// create a default toolset that contains all tools, listed once under
+	// their own names
 	allToolNames := make([]string, 0, len(toolsMap))
 	for name := range toolsMap {
-		allToolNames = append(allToolNames, name)
+		if !aliases[name] {
+			allToolNames = append(allToolNames, name)
+		}
 	}
 	if cfg.ToolsetConfigs == nil {
 		cfg.ToolsetConfigs = make(ToolsetConfigs)
@@ -249,6 +303,50 @@ func InitializeConfigs(ctx context.Context, cfg ServerConfig) (
 	return sourcesMap, authServicesMap, toolsMap, toolsetsMap, nil
 }
 
+// toolInitOrder returns the names of the configured tools, ordered so that
+// every tool comes after the tools it references.
+func toolInitOrder(toolConfigs ToolConfigs) ([]string, error) {
+	names := make([]string, 0, len(toolConfigs))
+	for name := range toolConfigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	const (
+		visiting = iota + 1
+		visited
+	)
+	state := make(map[string]int, len(toolConfigs))
+	order := make([]string, 0, len(toolConfigs))
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("tool %q references itself through other tools", name)
+		}
+		state[name] = visiting
+		for _, ref := range tools.ReferencedTools(toolConfigs[name]) {
+			if _, ok := toolConfigs[ref]; !ok {
+				return fmt.Errorf("tool %q references unknown tool %q", name, ref)
+			}
+			if err := visit(ref); err != nil {
+				return err
+			}
+		}
+		state[name] = visited
+		order = append(order, name)
+		return nil
+	}
+	for _, name := range names {
+		if err := visit(name); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
 // NewServer returns a Server object based on provided Config.
 func NewServer(ctx context.Context, cfg ServerConfig) (*Server, error) {
 	instrumentation, err := util.InstrumentationFromContext(ctx)
@@ -308,6 +406,11 @@ func NewServer(ctx context.Context, cfg ServerConfig) (*Server, error) {
 
 	sseManager := newSseManager(ctx)
 
+	downloads, err := newDownloadStore(ctx, downloadTTL)
+	if err != nil {
+		return nil, err
+	}
+
 	resourceManager := NewResourceManager(sourcesMap, authServicesMap, toolsMap, toolsetsMap)
 
 	s := &Server{
@@ -317,6 +420,9 @@ func NewServer(ctx context.Context, cfg ServerConfig) (*Server, error) {
 		logger:          l,
 		instrumentation: instrumentation,
 		sseManager:      sseManager,
+		downloads:       downloads,
+		defaultLocale:   cfg.DefaultLocale,
+		maxBodySize:     cfg.MaxRequestBodySize,
 		ResourceMgr:     resourceManager,
 	}
 	// control plane
@@ -330,6 +436,11 @@ func NewServer(ctx context.Context, cfg ServerConfig) (*Server, error) {
 		return nil, err
 	}
 	r.Mount("/mcp", mcpR)
+	downloadR, err := downloadRouter(s)
+	if err != nil {
+		return nil, err
+	}
+	r.Mount("/download", downloadR)
 	if cfg.UI {
 		webR, err := webRouter()
 		if err != nil {
@@ -337,6 +448,9 @@ func NewServer(ctx context.Context, cfg ServerConfig) (*Server, error) {
 		}
 		r.Mount("/ui", webR)
 	}
+	// liveness and readiness checks, e.g. for Kubernetes probes
+	r.Get("/healthz", healthzHandler)
+	r.Get("/readyz", func(w http.ResponseWriter, r *http.Request) { readyzHandler(s, w, r) })
 	// default endpoint for validating server is running
 	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
 		_, _ = w.Write([]byte("🧰 Hello, World! 🧰"))
@@ -380,3 +494,39 @@ func (s *Server) Shutdown(ctx context.Context) error {
 	s.logger.DebugContext(ctx, "shutting down the server.")
 	return s.srv.Shutdown(ctx)
 }
+
+// requestLocales returns the locales to use for tool descriptions, in order of
+// preference: those from the Accept-Language header, then the server default.
+func (s *Server) requestLocales(header http.Header) []string {
+	locales := tools.ParseAcceptLanguage(header.Get("Accept-Language"))
+	if s.defaultLocale != "" {
+		locales = append(locales, s.defaultLocale)
+	}
+	return locales
+}
+
+// localizeToolset returns a copy of the toolset with its manifests localized
+// to the first supported locale in locales.
+func (s *Server) localizeToolset(toolset tools.Toolset, locales []string) tools.Toolset {
+	if len(locales) == 0 {
+		return toolset
+	}
+	manifests := make(map[string]tools.Manifest, len(toolset.Manifest.ToolsManifest))
+	for name, m := range toolset.Manifest.ToolsManifest {
+		if tool, ok := s.ResourceMgr.GetTool(name); ok {
+			m = tools.LocalizeManifest(tool, locales)
+		}
+		manifests[name] = m
+	}
+	toolset.Manifest.ToolsManifest = manifests
+
+	mcpManifests := make([]tools.McpManifest, 0, len(toolset.McpManifest))
+	for _, m := range toolset.McpManifest {
+		if tool, ok := s.ResourceMgr.GetTool(m.Name); ok {
+			m = tools.LocalizeMcpManifest(tool, locales)
+		}
+		mcpManifests = append(mcpManifests, m)
+	}
+	toolset.McpManifest = mcpManifests
+	return toolset
+}
@@ -33,6 +33,7 @@ import (
 	"github.com/googleapis/genai-toolbox/internal/testutils"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/util"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func TestServe(t *testing.T) {
@@ -172,3 +173,235 @@ func TestUpdateServer(t *testing.T) {
 		t.Errorf("error updating server, toolset (-want +got):\n%s", diff)
 	}
 }
+
+// userAgentSourceConfig is a source config that records the user agent its
+// source is initialized with.
+type userAgentSourceConfig struct {
+	userAgent *string
+}
+
+func (c userAgentSourceConfig) SourceConfigKind() string {
+	return "user-agent-source"
+}
+
+func (c userAgentSourceConfig) Initialize(ctx context.Context, tracer trace.Tracer) (sources.Source, error) {
+	userAgent, err := util.UserAgentFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	*c.userAgent = userAgent
+	return &alloydbpg.Source{Name: "user-agent-source", Kind: "alloydb-postgres"}, nil
+}
+
+func TestInitializeConfigsUserAgentSuffix(t *testing.T) {
+	tcs := []struct {
+		desc   string
+		suffix string
+		want   string
+	}{
+		{
+			desc: "no suffix",
+			want: "genai-toolbox/0.0.0",
+		},
+		{
+			desc:   "with suffix",
+			suffix: "my-deployment/1.0",
+			want:   "genai-toolbox/0.0.0 my-deployment/1.0",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx, err := testutils.ContextWithNewLogger()
+			if err != nil {
+				t.Fatalf("error setting up logger: %s", err)
+			}
+			instrumentation, err := telemetry.CreateTelemetryInstrumentation("0.0.0")
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			ctx = util.WithInstrumentation(ctx, instrumentation)
+
+			var got string
+			cfg := server.ServerConfig{
+				Version:         "0.0.0",
+				UserAgentSuffix: tc.suffix,
+				SourceConfigs: server.SourceConfigs{
+					"my-source": userAgentSourceConfig{userAgent: &got},
+				},
+			}
+			if _, _, _, _, err := server.InitializeConfigs(ctx, cfg); err != nil {
+				t.Fatalf("unable to initialize configs: %s", err)
+			}
+			if got != tc.want {
+				t.Fatalf("unexpected user agent: got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+// refToolConfig is a tool config that can only be initialized once the tools
+// it references are.
+type refToolConfig struct {
+	refs []string
+}
+
+type refTool struct {
+	tools.Tool
+}
+
+func (t refTool) Manifest() tools.Manifest {
+	return tools.Manifest{}
+}
+
+func (t refTool) McpManifest() tools.McpManifest {
+	return tools.McpManifest{}
+}
+
+func (c refToolConfig) ToolConfigKind() string {
+	return "ref"
+}
+
+func (c refToolConfig) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	return c.InitializeWithTools(srcs, nil)
+}
+
+func (c refToolConfig) ReferencedTools() []string {
+	return c.refs
+}
+
+func (c refToolConfig) InitializeWithTools(_ map[string]sources.Source, tls map[string]tools.Tool) (tools.Tool, error) {
+	for _, ref := range c.refs {
+		if _, ok := tls[ref]; !ok {
+			return nil, fmt.Errorf("tool %q is not initialized yet", ref)
+		}
+	}
+	return refTool{}, nil
+}
+
+func TestInitializeConfigsToolReferences(t *testing.T) {
+	tcs := []struct {
+		desc  string
+		tools server.ToolConfigs
+		err   string
+	}{
+		{
+			desc: "references initialized first",
+			tools: server.ToolConfigs{
+				"a": refToolConfig{refs: []string{"b", "c"}},
+				"b": refToolConfig{refs: []string{"c"}},
+				"c": refToolConfig{},
+			},
+		},
+		{
+			desc: "unknown reference",
+			tools: server.ToolConfigs{
+				"a": refToolConfig{refs: []string{"b"}},
+			},
+			err: `tool "a" references unknown tool "b"`,
+		},
+		{
+			desc: "cycle",
+			tools: server.ToolConfigs{
+				"a": refToolConfig{refs: []string{"b"}},
+				"b": refToolConfig{refs: []string{"a"}},
+			},
+			err: "references itself through other tools",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx, err := testutils.ContextWithNewLogger()
+			if err != nil {
+				t.Fatalf("error setting up logger: %s", err)
+			}
+			instrumentation, err := telemetry.CreateTelemetryInstrumentation("0.0.0")
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			ctx = util.WithInstrumentation(ctx, instrumentation)
+
+			cfg := server.ServerConfig{Version: "0.0.0", ToolConfigs: tc.tools}
+			_, _, toolsMap, _, err := server.InitializeConfigs(ctx, cfg)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("unexpected error: got %v, want it to contain %q", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unable to initialize configs: %s", err)
+			}
+			if len(toolsMap) != len(tc.tools) {
+				t.Fatalf("unexpected number of tools: got %d, want %d", len(toolsMap), len(tc.tools))
+			}
+		})
+	}
+}
+
+func TestInitializeConfigsAliases(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("error setting up logger: %s", err)
+	}
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation("0.0.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	ctx = util.WithInstrumentation(ctx, instrumentation)
+
+	cfg := server.ServerConfig{
+		Version: "0.0.0",
+		ToolConfigs: server.ToolConfigs{
+			"list_tables": refToolConfig{},
+			"list-tables": tools.AliasConfig{Name: "list-tables", Tool: "list_tables"},
+		},
+		// the alias is wrapped along with the tool it refers to
+		PrettyPrint: true,
+	}
+	_, _, toolsMap, toolsets, err := server.InitializeConfigs(ctx, cfg)
+	if err != nil {
+		t.Fatalf("unable to initialize configs: %s", err)
+	}
+	if toolsMap["list-tables"] != toolsMap["list_tables"] {
+		t.Fatalf("alias should be the same tool as the one it refers to: got %v and %v", toolsMap["list-tables"], toolsMap["list_tables"])
+	}
+	got := toolsets[""].Manifest.ToolsManifest
+	if _, ok := got["list-tables"]; ok || len(got) != 1 {
+		t.Fatalf("the default toolset should only list the tool under its own name, got %v", got)
+	}
+}
+
+func TestInitializeConfigsMaxStatementLength(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("error setting up logger: %s", err)
+	}
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation("0.0.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	ctx = util.WithInstrumentation(ctx, instrumentation)
+
+	cfg := server.ServerConfig{
+		Version: "0.0.0",
+		ToolConfigs: server.ToolConfigs{
+			"execute_sql": tools.StatementLengthConfig{ToolConfig: refToolConfig{}},
+			"short_sql":   tools.StatementLengthConfig{ToolConfig: refToolConfig{}, Option: tools.StatementLengthOption{MaxLength: 50}},
+			"list_tables": refToolConfig{},
+		},
+		MaxStatementLength: 100,
+	}
+	_, _, toolsMap, _, err := server.InitializeConfigs(ctx, cfg)
+	if err != nil {
+		t.Fatalf("unable to initialize configs: %s", err)
+	}
+	if got, ok := toolsMap["execute_sql"].(tools.StatementLengthTool); !ok || got.MaxLength != 100 {
+		t.Fatalf("tool that takes a statement should have the server default, got %#v", toolsMap["execute_sql"])
+	}
+	if got, ok := toolsMap["short_sql"].(tools.StatementLengthTool); !ok || got.MaxLength != 50 {
+		t.Fatalf("tool that sets its own limit should keep it, got %#v", toolsMap["short_sql"])
+	}
+	if _, ok := toolsMap["list_tables"].(tools.StatementLengthTool); ok {
+		t.Fatalf("tool that doesn't take a statement should not be limited")
+	}
+}
@@ -99,6 +99,11 @@ func (s *Source) PostgresPool() *pgxpool.Pool {
 	return s.Pool
 }
 
+// Ping checks that the source can reach the database.
+func (s *Source) Ping(ctx context.Context) error {
+	return s.Pool.Ping(ctx)
+}
+
 func getOpts(ipType, userAgent string, useIAM bool) ([]alloydbconn.Option, error) {
 	opts := []alloydbconn.Option{alloydbconn.WithUserAgent(userAgent)}
 	switch strings.ToLower(ipType) {
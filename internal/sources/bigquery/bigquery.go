@@ -18,8 +18,11 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"regexp"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	bigqueryapi "cloud.google.com/go/bigquery"
 	dataplexapi "cloud.google.com/go/dataplex/apiv1"
@@ -57,14 +60,30 @@ func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (sources
 	return actual, nil
 }
 
+// queryTagRegex matches the values BigQuery allows for job labels.
+var queryTagRegex = regexp.MustCompile(`^[a-z0-9_-]{1,63}$`)
+
 type Config struct {
 	// BigQuery configs
-	Name            string   `yaml:"name" validate:"required"`
-	Kind            string   `yaml:"kind" validate:"required"`
-	Project         string   `yaml:"project" validate:"required"`
-	Location        string   `yaml:"location"`
+	Name     string `yaml:"name" validate:"required"`
+	Kind     string `yaml:"kind" validate:"required"`
+	Project  string `yaml:"project" validate:"required"`
+	Location string `yaml:"location"`
+	// DefaultLocation is used for queries whose location is not set and
+	// cannot be detected from the datasets they read.
+	DefaultLocation string   `yaml:"defaultLocation"`
 	AllowedDatasets []string `yaml:"allowedDatasets"`
+	// AllowedProjects are the projects, besides Project, that tools may run
+	// in and read metadata from.
+	AllowedProjects []string `yaml:"allowedProjects"`
 	UseClientOAuth  bool     `yaml:"useClientOAuth"`
+	// MaxBytesPerUser caps the bytes each client OAuth user may process
+	// within QuotaWindow. Zero disables the quota.
+	MaxBytesPerUser int64  `yaml:"maxBytesPerUser"`
+	QuotaWindow     string `yaml:"quotaWindow"`
+	// QueryTag labels the query jobs of tools, so that the jobs of a
+	// deployment can be told apart in monitoring.
+	QueryTag string `yaml:"queryTag"`
 }
 
 func (r Config) SourceConfigKind() string {
@@ -77,19 +96,67 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 	var restService *bigqueryrestapi.Service
 	var tokenSource oauth2.TokenSource
 	var clientCreator BigqueryClientCreator
+	projectClients := make(map[string]*bigqueryapi.Client)
+	projectClientCreators := make(map[string]BigqueryClientCreator)
 	var err error
 
+	var quota *QuotaTracker
+	var identities *TokenIdentities
+	if r.MaxBytesPerUser > 0 {
+		if !r.UseClientOAuth {
+			return nil, fmt.Errorf("maxBytesPerUser requires useClientOAuth, since queries are otherwise not made on behalf of a user")
+		}
+		window := defaultQuotaWindow
+		if r.QuotaWindow != "" {
+			window, err = time.ParseDuration(r.QuotaWindow)
+			if err != nil {
+				return nil, fmt.Errorf("unable to parse quotaWindow string as time.Duration: %s", err)
+			}
+			if window <= 0 {
+				return nil, fmt.Errorf("quotaWindow must be positive, got %q", r.QuotaWindow)
+			}
+		}
+		quota = NewQuotaTracker(r.MaxBytesPerUser, window)
+		identities = NewTokenIdentities(TokenInfoLookup)
+	}
+
+	if r.QueryTag != "" && !queryTagRegex.MatchString(r.QueryTag) {
+		return nil, fmt.Errorf("invalid queryTag %q: must be at most 63 lowercase letters, digits, underscores or hyphens", r.QueryTag)
+	}
+
+	allowedProjects := make(map[string]struct{})
+	for _, project := range r.AllowedProjects {
+		if project == "" || strings.Contains(project, ".") {
+			return nil, fmt.Errorf("invalid allowedProject %q, expected a project ID", project)
+		}
+		if project != r.Project {
+			allowedProjects[project] = struct{}{}
+		}
+	}
+
 	if r.UseClientOAuth {
 		clientCreator, err = newBigQueryClientCreator(ctx, tracer, r.Project, r.Location, r.Name)
 		if err != nil {
 			return nil, fmt.Errorf("error constructing client creator: %w", err)
 		}
+		for project := range allowedProjects {
+			projectClientCreators[project], err = newBigQueryClientCreator(ctx, tracer, project, r.Location, r.Name)
+			if err != nil {
+				return nil, fmt.Errorf("error constructing client creator for project %q: %w", project, err)
+			}
+		}
 	} else {
 		// Initializes a BigQuery Google SQL source
 		client, restService, tokenSource, err = initBigQueryConnection(ctx, tracer, r.Name, r.Project, r.Location)
 		if err != nil {
 			return nil, fmt.Errorf("error creating client from ADC: %w", err)
 		}
+		for project := range allowedProjects {
+			projectClients[project], err = initBigQueryProjectClient(ctx, project, r.Location, tokenSource)
+			if err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	allowedDatasets := make(map[string]struct{})
@@ -110,6 +177,9 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 				datasetID = allowed
 				allowedFullID = fmt.Sprintf("%s.%s", projectID, datasetID)
 			}
+			if _, ok := allowedProjects[projectID]; len(allowedProjects) > 0 && projectID != r.Project && !ok {
+				return nil, fmt.Errorf("allowedDataset %q is in project %q, which is not in allowedProjects", allowed, projectID)
+			}
 
 			dataset := client.DatasetInProject(projectID, datasetID)
 			_, err := dataset.Metadata(ctx)
@@ -128,15 +198,23 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 		Kind:               SourceKind,
 		Project:            r.Project,
 		Location:           r.Location,
+		DefaultLocation:    r.DefaultLocation,
 		Client:             client,
 		RestService:        restService,
 		TokenSource:        tokenSource,
 		MaxQueryResultRows: 50,
 		ClientCreator:      clientCreator,
 		AllowedDatasets:    allowedDatasets,
+		AllowedProjects:    allowedProjects,
+		ProjectClients:     projectClients,
 		UseClientOAuth:     r.UseClientOAuth,
+		Quota:              quota,
+		Identities:         identities,
+		QueryTag:           r.QueryTag,
 	}
+	s.projectClientCreators = projectClientCreators
 	s.makeDataplexCatalogClient = s.lazyInitDataplexClient(ctx, tracer)
+	s.makeStorageReadClient = s.lazyInitStorageReadClients(ctx)
 	return s, nil
 
 }
@@ -149,14 +227,27 @@ type Source struct {
 	Kind               string `yaml:"kind"`
 	Project            string
 	Location           string
+	DefaultLocation    string
 	Client             *bigqueryapi.Client
 	RestService        *bigqueryrestapi.Service
 	TokenSource        oauth2.TokenSource
 	MaxQueryResultRows int
 	ClientCreator      BigqueryClientCreator
 	AllowedDatasets    map[string]struct{}
-	UseClientOAuth     bool
+	// AllowedProjects are the projects other than Project that tools may use.
+	// Tools are limited to Project if it is empty.
+	AllowedProjects map[string]struct{}
+	// ProjectClients holds a client per allowed project for sources that use
+	// ADC, and projectClientCreators a client creator per allowed project for
+	// sources that use client OAuth.
+	ProjectClients            map[string]*bigqueryapi.Client
+	projectClientCreators     map[string]BigqueryClientCreator
+	UseClientOAuth            bool
+	Quota                     *QuotaTracker
+	Identities                *TokenIdentities
+	QueryTag                  string
 	makeDataplexCatalogClient func() (*dataplexapi.CatalogClient, DataplexClientCreator, error)
+	makeStorageReadClient     func(projectID string) (*bigqueryapi.Client, error)
 }
 
 func (s *Source) SourceKind() string {
@@ -168,6 +259,12 @@ func (s *Source) BigQueryClient() *bigqueryapi.Client {
 	return s.Client
 }
 
+// BigQueryQueryTag returns the tag that labels the query jobs of tools, which
+// is empty if the source doesn't set one.
+func (s *Source) BigQueryQueryTag() string {
+	return s.QueryTag
+}
+
 func (s *Source) BigQueryRestService() *bigqueryrestapi.Service {
 	return s.RestService
 }
@@ -184,6 +281,10 @@ func (s *Source) BigQueryLocation() string {
 	return s.Location
 }
 
+func (s *Source) BigQueryDefaultLocation() string {
+	return s.DefaultLocation
+}
+
 func (s *Source) BigQueryTokenSource() oauth2.TokenSource {
 	return s.TokenSource
 }
@@ -223,6 +324,105 @@ func (s *Source) IsDatasetAllowed(projectID, datasetID string) bool {
 	return ok
 }
 
+// BigQueryAllowedProjects returns the projects tools may use, with the source
+// project first, or nil if tools are not restricted to a list of projects.
+func (s *Source) BigQueryAllowedProjects() []string {
+	if len(s.AllowedProjects) == 0 {
+		return nil
+	}
+	projects := make([]string, 0, len(s.AllowedProjects))
+	for p := range s.AllowedProjects {
+		projects = append(projects, p)
+	}
+	sort.Strings(projects)
+	return append([]string{s.Project}, projects...)
+}
+
+// IsProjectAllowed reports whether tools may use the given project. Every
+// project is allowed if the source doesn't set allowedProjects.
+func (s *Source) IsProjectAllowed(projectID string) bool {
+	if len(s.AllowedProjects) == 0 || projectID == s.Project {
+		return true
+	}
+	_, ok := s.AllowedProjects[projectID]
+	return ok
+}
+
+// BigQueryClientForProject returns the clients that run jobs in the given
+// project, which must be the source project or one of its allowedProjects.
+// tokenString is the caller's access token for sources that use client OAuth.
+func (s *Source) BigQueryClientForProject(projectID, tokenString string) (*bigqueryapi.Client, *bigqueryrestapi.Service, error) {
+	creator := s.ClientCreator
+	client := s.Client
+	if projectID != s.Project {
+		if _, ok := s.AllowedProjects[projectID]; !ok {
+			return nil, nil, fmt.Errorf("project %q is not one of the allowedProjects of source %q", projectID, s.Name)
+		}
+		creator = s.projectClientCreators[projectID]
+		client = s.ProjectClients[projectID]
+	}
+	if s.UseClientOAuth {
+		return creator(tokenString, true)
+	}
+	return client, s.RestService, nil
+}
+
+// Ping checks that the source can run queries by dry running `SELECT 1`.
+// Sources that use client OAuth have no credentials of their own to check with,
+// so they are always considered ready.
+func (s *Source) Ping(ctx context.Context) error {
+	if s.UseClientOAuth {
+		return nil
+	}
+	q := s.Client.Query("SELECT 1")
+	q.DryRun = true
+	q.Location = s.Location
+	if _, err := q.Run(ctx); err != nil {
+		return fmt.Errorf("dry run failed: %w", err)
+	}
+	return nil
+}
+
+// BigQueryStorageReadClient returns a client that runs jobs in the given
+// project and reads their results through the BigQuery Storage Read API, which
+// serves them in Arrow format. tokenString is the caller's access token for
+// sources that use client OAuth.
+func (s *Source) BigQueryStorageReadClient(ctx context.Context, projectID, tokenString string) (*bigqueryapi.Client, error) {
+	if !s.UseClientOAuth {
+		if projectID != s.Project {
+			if _, ok := s.AllowedProjects[projectID]; !ok {
+				return nil, fmt.Errorf("project %q is not one of the allowedProjects of source %q", projectID, s.Name)
+			}
+		}
+		return s.makeStorageReadClient(projectID)
+	}
+	client, _, err := s.BigQueryClientForProject(projectID, tokenString)
+	if err != nil {
+		return nil, err
+	}
+	tokenSource := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: tokenString})
+	if err := client.EnableStorageReadClient(ctx, option.WithTokenSource(tokenSource)); err != nil {
+		return nil, fmt.Errorf("failed to create BigQuery Storage Read client: %w", err)
+	}
+	return client, nil
+}
+
+// ReserveQuota records the bytes a query is about to process against the quota
+// of the user behind the OAuth access token, who is identified by Identities
+// so that all their tokens share the quota. It returns an error wrapping
+// ErrQuotaExceeded if the query would take the user over their quota, and does
+// nothing if no quota is configured.
+func (s *Source) ReserveQuota(ctx context.Context, tokenString string, bytes int64) error {
+	if s.Quota == nil {
+		return nil
+	}
+	user, err := s.Identities.User(ctx, tokenString)
+	if err != nil {
+		return err
+	}
+	return s.Quota.Reserve(user, bytes)
+}
+
 func (s *Source) MakeDataplexCatalogClient() func() (*dataplexapi.CatalogClient, DataplexClientCreator, error) {
 	return s.makeDataplexCatalogClient
 }
@@ -247,6 +447,31 @@ func (s *Source) lazyInitDataplexClient(ctx context.Context, tracer trace.Tracer
 	}
 }
 
+// lazyInitStorageReadClients returns a function that initializes a client per
+// project with the Storage Read API enabled on first use. They are separate
+// from the source's clients, so that other tools keep reading results as rows.
+func (s *Source) lazyInitStorageReadClients(ctx context.Context) func(projectID string) (*bigqueryapi.Client, error) {
+	var mu sync.Mutex
+	clients := make(map[string]*bigqueryapi.Client)
+
+	return func(projectID string) (*bigqueryapi.Client, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if client, ok := clients[projectID]; ok {
+			return client, nil
+		}
+		client, err := initBigQueryProjectClient(ctx, projectID, s.Location, s.TokenSource)
+		if err != nil {
+			return nil, err
+		}
+		if err := client.EnableStorageReadClient(ctx, option.WithTokenSource(s.TokenSource)); err != nil {
+			return nil, fmt.Errorf("failed to create BigQuery Storage Read client for project %q: %w", projectID, err)
+		}
+		clients[projectID] = client
+		return client, nil
+	}
+}
+
 func initBigQueryConnection(
 	ctx context.Context,
 	tracer trace.Tracer,
@@ -283,6 +508,21 @@ func initBigQueryConnection(
 	return client, restService, cred.TokenSource, nil
 }
 
+// initBigQueryProjectClient initializes a client that runs jobs in an allowed
+// project, with the credentials of the source.
+func initBigQueryProjectClient(ctx context.Context, project, location string, tokenSource oauth2.TokenSource) (*bigqueryapi.Client, error) {
+	userAgent, err := util.UserAgentFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	client, err := bigqueryapi.NewClient(ctx, project, option.WithUserAgent(userAgent), option.WithTokenSource(tokenSource))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create BigQuery client for project %q: %w", project, err)
+	}
+	client.Location = location
+	return client, nil
+}
+
 // initBigQueryConnectionWithOAuthToken initialize a BigQuery client with an
 // OAuth access token.
 func initBigQueryConnectionWithOAuthToken(
@@ -405,4 +645,4 @@ func newDataplexClientCreator(
 	return func(tokenString string) (*dataplexapi.CatalogClient, error) {
 		return initDataplexConnectionWithOAuthToken(ctx, project, userAgent, tokenString)
 	}
-}
\ No newline at end of file
+}
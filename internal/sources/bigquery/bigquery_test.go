@@ -15,13 +15,17 @@
 package bigquery_test
 
 import (
+	"context"
+	"strings"
 	"testing"
 
+	bigqueryapi "cloud.google.com/go/bigquery"
 	yaml "github.com/goccy/go-yaml"
 	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/genai-toolbox/internal/server"
 	"github.com/googleapis/genai-toolbox/internal/sources/bigquery"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
 func TestParseFromYamlBigQuery(t *testing.T) {
@@ -90,6 +94,85 @@ func TestParseFromYamlBigQuery(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "with per-user quota example",
+			in: `
+			sources:
+				my-instance:
+					kind: bigquery
+					project: my-project
+					location: us
+					useClientOAuth: true
+					maxBytesPerUser: 1000000000
+					quotaWindow: 1h
+			`,
+			want: server.SourceConfigs{
+				"my-instance": bigquery.Config{
+					Name:            "my-instance",
+					Kind:            bigquery.SourceKind,
+					Project:         "my-project",
+					Location:        "us",
+					UseClientOAuth:  true,
+					MaxBytesPerUser: 1000000000,
+					QuotaWindow:     "1h",
+				},
+			},
+		},
+		{
+			desc: "with default location example",
+			in: `
+			sources:
+				my-instance:
+					kind: bigquery
+					project: my-project
+					defaultLocation: eu
+			`,
+			want: server.SourceConfigs{
+				"my-instance": bigquery.Config{
+					Name:            "my-instance",
+					Kind:            bigquery.SourceKind,
+					Project:         "my-project",
+					DefaultLocation: "eu",
+				},
+			},
+		},
+		{
+			desc: "with allowed projects example",
+			in: `
+			sources:
+				my-instance:
+					kind: bigquery
+					project: my-project
+					allowedProjects:
+						- other-project
+			`,
+			want: server.SourceConfigs{
+				"my-instance": bigquery.Config{
+					Name:            "my-instance",
+					Kind:            bigquery.SourceKind,
+					Project:         "my-project",
+					AllowedProjects: []string{"other-project"},
+				},
+			},
+		},
+		{
+			desc: "with query tag example",
+			in: `
+			sources:
+				my-instance:
+					kind: bigquery
+					project: my-project
+					queryTag: analytics-agent-prod
+			`,
+			want: server.SourceConfigs{
+				"my-instance": bigquery.Config{
+					Name:     "my-instance",
+					Kind:     bigquery.SourceKind,
+					Project:  "my-project",
+					QueryTag: "analytics-agent-prod",
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -155,3 +238,54 @@ func TestFailParseFromYaml(t *testing.T) {
 		})
 	}
 }
+
+func TestSourceProjects(t *testing.T) {
+	defaultClient, otherClient := &bigqueryapi.Client{}, &bigqueryapi.Client{}
+	s := &bigquery.Source{
+		Name:            "my-instance",
+		Project:         "my-project",
+		Client:          defaultClient,
+		AllowedProjects: map[string]struct{}{"other-project": {}, "another-project": {}},
+		ProjectClients:  map[string]*bigqueryapi.Client{"other-project": otherClient},
+	}
+
+	want := []string{"my-project", "another-project", "other-project"}
+	if diff := cmp.Diff(want, s.BigQueryAllowedProjects()); diff != "" {
+		t.Fatalf("incorrect allowed projects: diff %v", diff)
+	}
+	for project, wantAllowed := range map[string]bool{"my-project": true, "other-project": true, "unknown-project": false} {
+		if got := s.IsProjectAllowed(project); got != wantAllowed {
+			t.Fatalf("IsProjectAllowed(%q) = %t, want %t", project, got, wantAllowed)
+		}
+	}
+
+	// the same source runs jobs in each of its projects
+	for project, wantClient := range map[string]*bigqueryapi.Client{"my-project": defaultClient, "other-project": otherClient} {
+		got, _, err := s.BigQueryClientForProject(project, "")
+		if err != nil {
+			t.Fatalf("unexpected error for project %q: %s", project, err)
+		}
+		if got != wantClient {
+			t.Fatalf("incorrect client for project %q", project)
+		}
+	}
+	if _, _, err := s.BigQueryClientForProject("unknown-project", ""); err == nil {
+		t.Fatalf("expected an error for a project that is not allowed")
+	}
+
+	// every project is allowed without allowedProjects
+	unrestricted := &bigquery.Source{Project: "my-project"}
+	if unrestricted.BigQueryAllowedProjects() != nil || !unrestricted.IsProjectAllowed("unknown-project") {
+		t.Fatalf("expected every project to be allowed without allowedProjects")
+	}
+}
+
+func TestInvalidQueryTag(t *testing.T) {
+	for _, tag := range []string{"Uppercase", "with space", "dot.ted", strings.Repeat("a", 64)} {
+		cfg := bigquery.Config{Name: "my-instance", Kind: bigquery.SourceKind, Project: "my-project", QueryTag: tag}
+		_, err := cfg.Initialize(context.Background(), noop.NewTracerProvider().Tracer("test"))
+		if err == nil || !strings.Contains(err.Error(), "invalid queryTag") {
+			t.Fatalf("expected an invalid queryTag error for %q, got %v", tag, err)
+		}
+	}
+}
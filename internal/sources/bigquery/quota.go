@@ -0,0 +1,213 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bigquery
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrQuotaExceeded is returned when a query would take a user over the bytes
+// processed quota configured on the source.
+var ErrQuotaExceeded = errors.New("bigquery quota exceeded")
+
+// defaultQuotaWindow is used when a quota is configured without a window.
+const defaultQuotaWindow = 24 * time.Hour
+
+type quotaUsage struct {
+	at    time.Time
+	bytes int64
+}
+
+// QuotaTracker caps the bytes processed by each user within a rolling window.
+type QuotaTracker struct {
+	maxBytes int64
+	window   time.Duration
+	now      func() time.Time
+
+	mu        sync.Mutex
+	usage     map[string][]quotaUsage
+	lastSweep time.Time
+}
+
+// NewQuotaTracker returns a tracker that allows each user to process at most
+// maxBytes within any period of length window.
+func NewQuotaTracker(maxBytes int64, window time.Duration) *QuotaTracker {
+	return &QuotaTracker{
+		maxBytes: maxBytes,
+		window:   window,
+		now:      time.Now,
+		usage:    make(map[string][]quotaUsage),
+	}
+}
+
+// Reserve records that user is about to process the given number of bytes. It
+// returns an error wrapping ErrQuotaExceeded, and records nothing, if doing so
+// would take the user over their quota for the current window.
+func (q *QuotaTracker) Reserve(user string, bytes int64) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := q.now()
+	cutoff := now.Add(-q.window)
+	// Occasionally drop users that have not run a query within the window, so
+	// that inactive users do not accumulate.
+	if now.Sub(q.lastSweep) >= q.window {
+		for u, entries := range q.usage {
+			if len(entries) == 0 || !entries[len(entries)-1].at.After(cutoff) {
+				delete(q.usage, u)
+			}
+		}
+		q.lastSweep = now
+	}
+
+	entries := q.usage[user]
+	// entries are in insertion order, so drop the expired prefix
+	i := 0
+	for i < len(entries) && !entries[i].at.After(cutoff) {
+		i++
+	}
+	entries = entries[i:]
+
+	var used int64
+	for _, e := range entries {
+		used += e.bytes
+	}
+	if used+bytes > q.maxBytes {
+		q.usage[user] = entries
+		return fmt.Errorf("%w: query would process %d bytes, but only %d of the %d bytes allowed per %s remain", ErrQuotaExceeded, bytes, max(q.maxBytes-used, 0), q.maxBytes, q.window)
+	}
+	q.usage[user] = append(entries, quotaUsage{at: now, bytes: bytes})
+	return nil
+}
+
+// tokenInfoURL is the endpoint that returns the user of an access token.
+var tokenInfoURL = "https://oauth2.googleapis.com/tokeninfo"
+
+// defaultIdentityTTL is how long the user of an access token is cached for if
+// tokeninfo doesn't return its expiry.
+const defaultIdentityTTL = 5 * time.Minute
+
+// IdentityLookup returns the stable identity of the user behind an OAuth access
+// token, e.g. their email, and when the token expires.
+type IdentityLookup func(ctx context.Context, tokenString string) (user string, expiry time.Time, err error)
+
+// TokenInfoLookup is an IdentityLookup that asks the tokeninfo endpoint for
+// the email, or failing that the subject, of the token.
+func TokenInfoLookup(ctx context.Context, tokenString string) (string, time.Time, error) {
+	form := url.Values{"access_token": {tokenString}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenInfoURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to call tokeninfo endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", time.Time{}, fmt.Errorf("tokeninfo endpoint returned non-OK status %d", resp.StatusCode)
+	}
+	var info struct {
+		Email     string `json:"email"`
+		Sub       string `json:"sub"`
+		ExpiresIn string `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return "", time.Time{}, fmt.Errorf("error parsing tokeninfo response: %w", err)
+	}
+	user := info.Email
+	if user == "" {
+		user = info.Sub
+	}
+	if user == "" {
+		return "", time.Time{}, fmt.Errorf("tokeninfo response has neither an email nor a subject")
+	}
+	ttl := defaultIdentityTTL
+	if secs, err := strconv.Atoi(info.ExpiresIn); err == nil && secs > 0 {
+		ttl = time.Duration(secs) * time.Second
+	}
+	return user, time.Now().Add(ttl), nil
+}
+
+type tokenIdentity struct {
+	user   string
+	expiry time.Time
+}
+
+// TokenIdentities resolves OAuth access tokens to the user behind them, so
+// that quotas outlive the hourly rotation of tokens. Users are cached until
+// their token expires, keyed by a hash rather than the token itself.
+type TokenIdentities struct {
+	lookup IdentityLookup
+	now    func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]tokenIdentity
+}
+
+// NewTokenIdentities returns TokenIdentities that resolve tokens with lookup.
+func NewTokenIdentities(lookup IdentityLookup) *TokenIdentities {
+	return &TokenIdentities{
+		lookup: lookup,
+		now:    time.Now,
+		cache:  make(map[string]tokenIdentity),
+	}
+}
+
+// User returns the user behind tokenString.
+func (ti *TokenIdentities) User(ctx context.Context, tokenString string) (string, error) {
+	key := tokenKey(tokenString)
+	ti.mu.Lock()
+	id, ok := ti.cache[key]
+	ti.mu.Unlock()
+	if ok && ti.now().Before(id.expiry) {
+		return id.user, nil
+	}
+
+	user, expiry, err := ti.lookup(ctx, tokenString)
+	if err != nil {
+		return "", fmt.Errorf("unable to identify the user of the access token: %w", err)
+	}
+
+	ti.mu.Lock()
+	defer ti.mu.Unlock()
+	now := ti.now()
+	for k, id := range ti.cache {
+		if !now.Before(id.expiry) {
+			delete(ti.cache, k)
+		}
+	}
+	ti.cache[key] = tokenIdentity{user: user, expiry: expiry}
+	return user, nil
+}
+
+// tokenKey identifies an OAuth access token without keeping the token itself
+// in memory.
+func tokenKey(tokenString string) string {
+	sum := sha256.Sum256([]byte(tokenString))
+	return hex.EncodeToString(sum[:])
+}
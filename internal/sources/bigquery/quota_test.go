@@ -0,0 +1,188 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bigquery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestQuotaTrackerReserve(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	q := NewQuotaTracker(100, time.Hour)
+	q.now = func() time.Time { return now }
+
+	// exhaust the quota of user "a"
+	for _, bytes := range []int64{60, 40} {
+		if err := q.Reserve("a", bytes); err != nil {
+			t.Fatalf("unexpected error reserving %d bytes: %s", bytes, err)
+		}
+	}
+	err := q.Reserve("a", 1)
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("expected ErrQuotaExceeded once the quota is used up, got %v", err)
+	}
+
+	// other users have their own quota
+	if err := q.Reserve("b", 100); err != nil {
+		t.Fatalf("unexpected error for another user: %s", err)
+	}
+
+	now = now.Add(30 * time.Minute)
+	if err := q.Reserve("b", 1); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("expected ErrQuotaExceeded for user b, got %v", err)
+	}
+
+	// the reservations of user "a" leave the window after an hour, while the
+	// rejected one was never counted
+	now = now.Add(31 * time.Minute)
+	if err := q.Reserve("a", 100); err != nil {
+		t.Fatalf("unexpected error after the window passed: %s", err)
+	}
+	if err := q.Reserve("a", 1); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("expected ErrQuotaExceeded, got %v", err)
+	}
+}
+
+// fakeLookup identifies the users of tokens from users, counting its calls.
+func fakeLookup(users map[string]string, calls *int) IdentityLookup {
+	return func(_ context.Context, tokenString string) (string, time.Time, error) {
+		*calls++
+		user, ok := users[tokenString]
+		if !ok {
+			return "", time.Time{}, errors.New("invalid token")
+		}
+		return user, time.Now().Add(time.Hour), nil
+	}
+}
+
+func TestSourceReserveQuota(t *testing.T) {
+	s := &Source{}
+	if err := s.ReserveQuota(context.Background(), "token", 1<<40); err != nil {
+		t.Fatalf("unexpected error without a quota: %s", err)
+	}
+
+	var calls int
+	s.Quota = NewQuotaTracker(10, time.Hour)
+	s.Identities = NewTokenIdentities(fakeLookup(map[string]string{
+		"token-1": "alice@example.com",
+		"token-2": "alice@example.com",
+		"token-3": "bob@example.com",
+	}, &calls))
+	ctx := context.Background()
+	if err := s.ReserveQuota(ctx, "token-1", 10); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	// a refreshed token of the same user shares their quota
+	if err := s.ReserveQuota(ctx, "token-2", 1); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("expected ErrQuotaExceeded for another token of the same user, got %v", err)
+	}
+	if err := s.ReserveQuota(ctx, "token-3", 10); err != nil {
+		t.Fatalf("unexpected error for another user: %s", err)
+	}
+	if err := s.ReserveQuota(ctx, "token-4", 1); err == nil || errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("expected an error for a token that can't be identified, got %v", err)
+	}
+}
+
+func TestTokenIdentitiesCache(t *testing.T) {
+	var calls int
+	ids := NewTokenIdentities(fakeLookup(map[string]string{"token-1": "alice@example.com"}, &calls))
+	now := time.Now()
+	ids.now = func() time.Time { return now }
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		user, err := ids.User(ctx, "token-1")
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if user != "alice@example.com" {
+			t.Fatalf("incorrect user: got %q", user)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("expected the user to be cached, got %d lookups", calls)
+	}
+	// looked up again once the token expires
+	now = now.Add(2 * time.Hour)
+	if _, err := ids.User(ctx, "token-1"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected another lookup after the token expired, got %d lookups", calls)
+	}
+}
+
+func TestTokenInfoLookup(t *testing.T) {
+	tcs := []struct {
+		desc     string
+		response string
+		want     string
+		wantErr  bool
+	}{
+		{
+			desc:     "email",
+			response: `{"sub": "123", "email": "alice@example.com", "expires_in": "3599"}`,
+			want:     "alice@example.com",
+		},
+		{
+			desc:     "subject",
+			response: `{"sub": "123", "expires_in": "3599"}`,
+			want:     "123",
+		},
+		{
+			desc:     "neither",
+			response: `{"expires_in": "3599"}`,
+			wantErr:  true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if err := r.ParseForm(); err != nil || r.PostForm.Get("access_token") != "token-1" {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				fmt.Fprint(w, tc.response)
+			}))
+			defer server.Close()
+			old := tokenInfoURL
+			tokenInfoURL = server.URL
+			defer func() { tokenInfoURL = old }()
+
+			user, expiry, err := TokenInfoLookup(context.Background(), "token-1")
+			if err != nil {
+				if tc.wantErr {
+					return
+				}
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if tc.wantErr {
+				t.Fatalf("expected error but got nil")
+			}
+			if user != tc.want {
+				t.Fatalf("incorrect user: got %q, want %q", user, tc.want)
+			}
+			if d := time.Until(expiry); d < 59*time.Minute || d > time.Hour {
+				t.Fatalf("incorrect expiry: in %s", d)
+			}
+		})
+	}
+}
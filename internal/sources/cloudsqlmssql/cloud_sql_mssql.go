@@ -106,6 +106,11 @@ func (s *Source) MSSQLDB() *sql.DB {
 	return s.Db
 }
 
+// Ping checks that the source can reach the database.
+func (s *Source) Ping(ctx context.Context) error {
+	return s.Db.PingContext(ctx)
+}
+
 func initCloudSQLMssqlConnection(ctx context.Context, tracer trace.Tracer, name, project, region, instance, ipAddress, ipType, user, pass, dbname string) (*sql.DB, error) {
 	//nolint:all // Reassigned ctx
 	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, name)
@@ -97,6 +97,11 @@ func (s *Source) MySQLPool() *sql.DB {
 	return s.Pool
 }
 
+// Ping checks that the source can reach the database.
+func (s *Source) Ping(ctx context.Context) error {
+	return s.Pool.PingContext(ctx)
+}
+
 func initCloudSQLMySQLConnectionPool(ctx context.Context, tracer trace.Tracer, name, project, region, instance, ipType, user, pass, dbname string) (*sql.DB, error) {
 	//nolint:all // Reassigned ctx
 	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, name)
@@ -97,6 +97,11 @@ func (s *Source) PostgresPool() *pgxpool.Pool {
 	return s.Pool
 }
 
+// Ping checks that the source can reach the database.
+func (s *Source) Ping(ctx context.Context) error {
+	return s.Pool.Ping(ctx)
+}
+
 func getConnectionConfig(ctx context.Context, user, pass, dbname string) (string, bool, error) {
 	useIAM := true
 
@@ -102,6 +102,11 @@ func (s *Source) MSSQLDB() *sql.DB {
 	return s.Db
 }
 
+// Ping checks that the source can reach the database.
+func (s *Source) Ping(ctx context.Context) error {
+	return s.Db.PingContext(ctx)
+}
+
 func initMssqlConnection(
 	ctx context.Context,
 	tracer trace.Tracer,
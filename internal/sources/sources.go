@@ -64,6 +64,13 @@ type Source interface {
 	SourceKind() string
 }
 
+// Pinger is implemented by sources that can cheaply check that they are able
+// to serve requests, e.g. by pinging their connection pool. It backs the
+// server's readiness check. Sources that don't implement it count as ready.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
 // InitConnectionSpan adds a span for database pool connection initialization
 func InitConnectionSpan(ctx context.Context, tracer trace.Tracer, sourceKind, sourceName string) (context.Context, trace.Span) {
 	ctx, span := tracer.Start(
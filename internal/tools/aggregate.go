@@ -0,0 +1,208 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	aggregateAsKey  = "aggregateAs"
+	aggregateKeyKey = "aggregateKey"
+)
+
+// The shapes that result rows can be aggregated into.
+const (
+	AggregateArray  = "array"
+	AggregateObject = "object"
+	AggregateScalar = "scalar"
+)
+
+var aggregates = []string{AggregateArray, AggregateObject, AggregateScalar}
+
+// AggregateOptions configures how the result rows of a tool are aggregated.
+type AggregateOptions struct {
+	// As is one of the Aggregate constants.
+	As string
+	// Key is the column whose values key the rows for AggregateObject.
+	Key string
+}
+
+// ExtractAggregate removes the `aggregateAs` and `aggregateKey` fields from a
+// raw tool config, so that the remaining config can be decoded strictly by the
+// tool kind. A nil AggregateOptions is returned if neither is set.
+func ExtractAggregate(toolName string, v map[string]any) (*AggregateOptions, error) {
+	rawAs, hasAs := v[aggregateAsKey]
+	rawKey, hasKey := v[aggregateKeyKey]
+	delete(v, aggregateAsKey)
+	delete(v, aggregateKeyKey)
+	if !hasAs && !hasKey {
+		return nil, nil
+	}
+
+	opts := AggregateOptions{As: AggregateArray}
+	if hasAs {
+		as, ok := rawAs.(string)
+		if !ok || !validAggregate(as) {
+			return nil, fmt.Errorf("%q must be one of %q for tool %q", aggregateAsKey, aggregates, toolName)
+		}
+		opts.As = as
+	}
+	if hasKey {
+		key, ok := rawKey.(string)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("%q must be a non-empty string for tool %q", aggregateKeyKey, toolName)
+		}
+		opts.Key = key
+	}
+	if opts.As == AggregateObject && opts.Key == "" {
+		return nil, fmt.Errorf("%q %q requires %q to be set for tool %q", aggregateAsKey, AggregateObject, aggregateKeyKey, toolName)
+	}
+	if opts.As != AggregateObject && opts.Key != "" {
+		return nil, fmt.Errorf("%q is only supported with %q %q for tool %q", aggregateKeyKey, aggregateAsKey, AggregateObject, toolName)
+	}
+	return &opts, nil
+}
+
+func validAggregate(as string) bool {
+	for _, a := range aggregates {
+		if as == a {
+			return true
+		}
+	}
+	return false
+}
+
+// AggregateConfig wraps a ToolConfig so that its result rows are aggregated
+// as set by its AggregateOptions.
+type AggregateConfig = WrapperConfig[AggregateOptions]
+
+// validate interface
+var _ ToolReferencingConfig = AggregateConfig{}
+
+func (o AggregateOptions) wrap(_ ToolConfig, t Tool) (Tool, error) {
+	return NewAggregateTool(t, o), nil
+}
+
+// AggregateTool wraps a Tool so that its result rows are returned as a single
+// JSON value: an object keyed by a column with AggregateObject, or the only
+// value of the only row with AggregateScalar. Results without data, and
+// results that aren't a list of rows, e.g. rows with a warning, are returned
+// as is.
+type AggregateTool struct {
+	Tool
+	Options AggregateOptions
+}
+
+// validate interface
+var _ WrapperTool = AggregateTool{}
+
+func (t AggregateTool) Unwrap() Tool {
+	return t.Tool
+}
+
+// NewAggregateTool returns t wrapped to aggregate its result rows as set by
+// opts.
+func NewAggregateTool(t Tool, opts AggregateOptions) AggregateTool {
+	return AggregateTool{Tool: t, Options: opts}
+}
+
+func (t AggregateTool) Invoke(ctx context.Context, params ParamValues, accessToken AccessToken) (any, error) {
+	res, err := t.Tool.Invoke(ctx, params, accessToken)
+	if err != nil {
+		return nil, err
+	}
+	return t.aggregate(res)
+}
+
+func (t AggregateTool) aggregate(res any) (any, error) {
+	switch r := res.(type) {
+	case PrettyResult:
+		aggregated, err := t.aggregate(r.Result)
+		if err != nil {
+			return nil, err
+		}
+		return PrettyResult{Result: aggregated}, nil
+	case ResolvedStatementResult:
+		aggregated, err := t.aggregate(r.Result)
+		if err != nil {
+			return nil, err
+		}
+		r.Result = aggregated
+		return r, nil
+	}
+	if IsEmptyResult(res) {
+		return res, nil
+	}
+
+	var rows []map[string]any
+	switch r := res.(type) {
+	case []map[string]any:
+		rows = r
+	case []any:
+		for _, row := range r {
+			m, ok := row.(map[string]any)
+			if !ok {
+				return res, nil
+			}
+			rows = append(rows, m)
+		}
+	default:
+		return res, nil
+	}
+
+	switch t.Options.As {
+	case AggregateObject:
+		return aggregateObject(rows, t.Options.Key)
+	case AggregateScalar:
+		return aggregateScalar(rows)
+	}
+	return res, nil
+}
+
+// aggregateObject returns rows keyed by the value of their key column.
+func aggregateObject(rows []map[string]any, key string) (map[string]any, error) {
+	out := make(map[string]any, len(rows))
+	for i, row := range rows {
+		raw, ok := row[key]
+		if !ok {
+			return nil, fmt.Errorf("%q column %q is missing from the result rows", aggregateKeyKey, key)
+		}
+		if raw == nil {
+			return nil, fmt.Errorf("%q column %q is null in row %d", aggregateKeyKey, key, i)
+		}
+		k := fmt.Sprint(raw)
+		if _, ok := out[k]; ok {
+			return nil, fmt.Errorf("%q column %q has the duplicate value %q", aggregateKeyKey, key, k)
+		}
+		out[k] = row
+	}
+	return out, nil
+}
+
+// aggregateScalar returns the only value of the only row.
+func aggregateScalar(rows []map[string]any) (any, error) {
+	if len(rows) != 1 {
+		return nil, fmt.Errorf("%q %q requires a single result row, got %d", aggregateAsKey, AggregateScalar, len(rows))
+	}
+	if len(rows[0]) != 1 {
+		return nil, fmt.Errorf("%q %q requires a single result column, got %d", aggregateAsKey, AggregateScalar, len(rows[0]))
+	}
+	for _, value := range rows[0] {
+		return value, nil
+	}
+	return nil, nil
+}
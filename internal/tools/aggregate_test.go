@@ -0,0 +1,214 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestAggregateTool(t *testing.T) {
+	rows := []any{
+		map[string]any{"name": "alice", "age": 30},
+		map[string]any{"name": "bob", "age": 25},
+	}
+	tcs := []struct {
+		desc string
+		opts tools.AggregateOptions
+		res  any
+		want any
+	}{
+		{
+			desc: "array",
+			opts: tools.AggregateOptions{As: tools.AggregateArray},
+			res:  rows,
+			want: rows,
+		},
+		{
+			desc: "object",
+			opts: tools.AggregateOptions{As: tools.AggregateObject, Key: "name"},
+			res:  rows,
+			want: map[string]any{
+				"alice": map[string]any{"name": "alice", "age": 30},
+				"bob":   map[string]any{"name": "bob", "age": 25},
+			},
+		},
+		{
+			desc: "object keyed by a number",
+			opts: tools.AggregateOptions{As: tools.AggregateObject, Key: "id"},
+			res:  []map[string]any{{"id": int64(7), "name": "alice"}},
+			want: map[string]any{"7": map[string]any{"id": int64(7), "name": "alice"}},
+		},
+		{
+			desc: "scalar",
+			opts: tools.AggregateOptions{As: tools.AggregateScalar},
+			res:  []any{map[string]any{"count": int64(42)}},
+			want: int64(42),
+		},
+		{
+			desc: "pretty scalar",
+			opts: tools.AggregateOptions{As: tools.AggregateScalar},
+			res:  tools.PrettyResult{Result: []any{map[string]any{"count": int64(42)}}},
+			want: tools.PrettyResult{Result: int64(42)},
+		},
+		{
+			desc: "scalar with a resolved statement",
+			opts: tools.AggregateOptions{As: tools.AggregateScalar},
+			res:  tools.ResolvedStatementResult{Result: []any{map[string]any{"count": int64(42)}}, ResolvedStatement: "SELECT COUNT(*) AS count FROM t"},
+			want: tools.ResolvedStatementResult{Result: int64(42), ResolvedStatement: "SELECT COUNT(*) AS count FROM t"},
+		},
+		{
+			desc: "no rows",
+			opts: tools.AggregateOptions{As: tools.AggregateScalar},
+			res:  tools.ZeroRowsMessage,
+			want: tools.ZeroRowsMessage,
+		},
+		{
+			desc: "rows with a warning",
+			opts: tools.AggregateOptions{As: tools.AggregateScalar},
+			res:  warningResult{Rows: []any{map[string]any{"count": int64(42)}}, Warning: "incomplete"},
+			want: warningResult{Rows: []any{map[string]any{"count": int64(42)}}, Warning: "incomplete"},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			tool := tools.NewAggregateTool(resultTool{res: tc.res}, tc.opts)
+			got, err := tool.Invoke(context.Background(), nil, "")
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect result: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestAggregateToolErrors(t *testing.T) {
+	tcs := []struct {
+		desc    string
+		opts    tools.AggregateOptions
+		res     any
+		wantErr string
+	}{
+		{
+			desc:    "scalar with multiple rows",
+			opts:    tools.AggregateOptions{As: tools.AggregateScalar},
+			res:     []any{map[string]any{"count": 1}, map[string]any{"count": 2}},
+			wantErr: "requires a single result row, got 2",
+		},
+		{
+			desc:    "scalar with multiple columns",
+			opts:    tools.AggregateOptions{As: tools.AggregateScalar},
+			res:     []any{map[string]any{"count": 1, "total": 2}},
+			wantErr: "requires a single result column, got 2",
+		},
+		{
+			desc:    "object without the key column",
+			opts:    tools.AggregateOptions{As: tools.AggregateObject, Key: "id"},
+			res:     []any{map[string]any{"name": "alice"}},
+			wantErr: `column "id" is missing`,
+		},
+		{
+			desc:    "object with a null key",
+			opts:    tools.AggregateOptions{As: tools.AggregateObject, Key: "name"},
+			res:     []any{map[string]any{"name": nil}},
+			wantErr: `column "name" is null in row 0`,
+		},
+		{
+			desc:    "object with a duplicate key",
+			opts:    tools.AggregateOptions{As: tools.AggregateObject, Key: "name"},
+			res:     []any{map[string]any{"name": "alice"}, map[string]any{"name": "alice"}},
+			wantErr: `duplicate value "alice"`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			tool := tools.NewAggregateTool(resultTool{res: tc.res}, tc.opts)
+			_, err := tool.Invoke(context.Background(), nil, "")
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestExtractAggregate(t *testing.T) {
+	tcs := []struct {
+		name    string
+		in      map[string]any
+		want    *tools.AggregateOptions
+		wantErr bool
+	}{
+		{
+			name: "not set",
+			in:   map[string]any{"kind": "some-kind"},
+		},
+		{
+			name: "array",
+			in:   map[string]any{"kind": "some-kind", "aggregateAs": "array"},
+			want: &tools.AggregateOptions{As: tools.AggregateArray},
+		},
+		{
+			name: "object",
+			in:   map[string]any{"kind": "some-kind", "aggregateAs": "object", "aggregateKey": "id"},
+			want: &tools.AggregateOptions{As: tools.AggregateObject, Key: "id"},
+		},
+		{
+			name: "scalar",
+			in:   map[string]any{"kind": "some-kind", "aggregateAs": "scalar"},
+			want: &tools.AggregateOptions{As: tools.AggregateScalar},
+		},
+		{
+			name:    "object without a key",
+			in:      map[string]any{"kind": "some-kind", "aggregateAs": "object"},
+			wantErr: true,
+		},
+		{
+			name:    "key without object",
+			in:      map[string]any{"kind": "some-kind", "aggregateAs": "scalar", "aggregateKey": "id"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown aggregate",
+			in:      map[string]any{"kind": "some-kind", "aggregateAs": "csv"},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tools.ExtractAggregate("my-tool", tc.in)
+			if err != nil {
+				if tc.wantErr {
+					return
+				}
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if tc.wantErr {
+				t.Fatalf("expected error but got nil")
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect options: diff %v", diff)
+			}
+			if diff := cmp.Diff(map[string]any{"kind": "some-kind"}, tc.in); diff != "" {
+				t.Fatalf("aggregate fields not removed from config: diff %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"fmt"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+)
+
+const (
+	aliasesKey = "aliases"
+	aliasKind  = "alias"
+)
+
+// ExtractAliases removes the `aliases` field from a raw tool config, so that
+// the remaining config can be decoded strictly by the tool kind.
+func ExtractAliases(toolName string, v map[string]any) ([]string, error) {
+	raw, ok := v[aliasesKey]
+	if !ok {
+		return nil, nil
+	}
+	delete(v, aliasesKey)
+	aliases, err := stringList(toolName, aliasesKey, raw)
+	if err != nil {
+		return nil, err
+	}
+	for _, alias := range aliases {
+		if !IsValidName(alias) {
+			return nil, fmt.Errorf("invalid alias %q for tool %q", alias, toolName)
+		}
+		if alias == toolName {
+			return nil, fmt.Errorf("alias %q of tool %q is the name of the tool itself", alias, toolName)
+		}
+	}
+	return aliases, nil
+}
+
+// AliasConfig is the config of an alias, another name that the tool named Tool
+// can be invoked by. It initializes to the very same Tool, and is left out of
+// the default toolset so that the tool is only listed once.
+type AliasConfig struct {
+	Name string
+	Tool string
+}
+
+// validate interface
+var _ ToolReferencingConfig = AliasConfig{}
+
+func (c AliasConfig) ToolConfigKind() string {
+	return aliasKind
+}
+
+func (c AliasConfig) Initialize(srcs map[string]sources.Source) (Tool, error) {
+	return c.InitializeWithTools(srcs, nil)
+}
+
+func (c AliasConfig) ReferencedTools() []string {
+	return []string{c.Tool}
+}
+
+func (c AliasConfig) InitializeWithTools(_ map[string]sources.Source, tls map[string]Tool) (Tool, error) {
+	t, ok := tls[c.Tool]
+	if !ok {
+		return nil, fmt.Errorf("alias %q refers to unknown tool %q", c.Name, c.Tool)
+	}
+	return t, nil
+}
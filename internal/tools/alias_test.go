@@ -0,0 +1,97 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestAliasConfig(t *testing.T) {
+	tool := resultTool{res: "ok"}
+	cfg := tools.AliasConfig{Name: "old_name", Tool: "new_name"}
+	if diff := cmp.Diff([]string{"new_name"}, tools.ReferencedTools(cfg)); diff != "" {
+		t.Fatalf("incorrect referenced tools: diff %v", diff)
+	}
+
+	got, err := tools.InitializeWithTools(cfg, nil, map[string]tools.Tool{"new_name": tool})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got != tool {
+		t.Fatalf("alias should initialize to the tool it refers to, got %v", got)
+	}
+
+	if _, err := tools.InitializeWithTools(cfg, nil, map[string]tools.Tool{}); err == nil {
+		t.Fatalf("expected error for an unknown tool but got nil")
+	}
+}
+
+func TestExtractAliases(t *testing.T) {
+	tcs := []struct {
+		name    string
+		in      map[string]any
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "not set",
+			in:   map[string]any{"kind": "some-kind"},
+			want: nil,
+		},
+		{
+			name: "aliases",
+			in:   map[string]any{"kind": "some-kind", "aliases": []any{"list-tables", "list_tables_v1"}},
+			want: []string{"list-tables", "list_tables_v1"},
+		},
+		{
+			name:    "not a list",
+			in:      map[string]any{"kind": "some-kind", "aliases": "list-tables"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid name",
+			in:      map[string]any{"kind": "some-kind", "aliases": []any{"list tables"}},
+			wantErr: true,
+		},
+		{
+			name:    "name of the tool",
+			in:      map[string]any{"kind": "some-kind", "aliases": []any{"my-tool"}},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tools.ExtractAliases("my-tool", tc.in)
+			if err != nil {
+				if tc.wantErr {
+					return
+				}
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if tc.wantErr {
+				t.Fatalf("expected error but got nil")
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect aliases: diff %v", diff)
+			}
+			if diff := cmp.Diff(map[string]any{"kind": "some-kind"}, tc.in); diff != "" {
+				t.Fatalf("aliases not removed from config: diff %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// ArrowStreamMediaType is the media type of the Apache Arrow IPC stream format.
+const ArrowStreamMediaType = "application/vnd.apache.arrow.stream"
+
+// ArrowStream is a result serialized in the Apache Arrow IPC stream format.
+// Tools that support it return one for invocations with WithArrowStream, and
+// it is sent to the client as is instead of being marshaled to JSON.
+type ArrowStream []byte
+
+type arrowStreamKey struct{}
+
+// WithArrowStream returns a context whose invocations ask for an ArrowStream
+// result. Tools that don't support Arrow ignore it and return their usual
+// result.
+func WithArrowStream(ctx context.Context) context.Context {
+	return context.WithValue(ctx, arrowStreamKey{}, true)
+}
+
+// ArrowStreamRequested reports whether the invocation asks for an ArrowStream
+// result.
+func ArrowStreamRequested(ctx context.Context) bool {
+	arrow, _ := ctx.Value(arrowStreamKey{}).(bool)
+	return arrow
+}
+
+// ArrowStreamAccepted reports whether the request headers ask for results in
+// the Arrow IPC stream format with `Accept: application/vnd.apache.arrow.stream`.
+func ArrowStreamAccepted(header http.Header) bool {
+	for _, v := range header.Values("Accept") {
+		for _, mediaRange := range strings.Split(v, ",") {
+			mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+			if err == nil && mediaType == ArrowStreamMediaType {
+				return true
+			}
+		}
+	}
+	return false
+}
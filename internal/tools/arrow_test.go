@@ -0,0 +1,81 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestArrowStreamAccepted(t *testing.T) {
+	tcs := []struct {
+		desc   string
+		accept []string
+		want   bool
+	}{
+		{
+			desc: "no accept header",
+			want: false,
+		},
+		{
+			desc:   "json",
+			accept: []string{"application/json"},
+			want:   false,
+		},
+		{
+			desc:   "arrow",
+			accept: []string{"application/vnd.apache.arrow.stream"},
+			want:   true,
+		},
+		{
+			desc:   "arrow among other types",
+			accept: []string{"application/json;q=0.5, application/vnd.apache.arrow.stream;q=0.9"},
+			want:   true,
+		},
+		{
+			desc:   "arrow in a later header",
+			accept: []string{"application/json", "application/vnd.apache.arrow.stream"},
+			want:   true,
+		},
+		{
+			desc:   "arrow file format",
+			accept: []string{"application/vnd.apache.arrow.file"},
+			want:   false,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			header := http.Header{}
+			for _, v := range tc.accept {
+				header.Add("Accept", v)
+			}
+			if got := tools.ArrowStreamAccepted(header); got != tc.want {
+				t.Fatalf("incorrect result: got %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestWithArrowStream(t *testing.T) {
+	if tools.ArrowStreamRequested(context.Background()) {
+		t.Fatalf("Arrow requested without WithArrowStream")
+	}
+	if !tools.ArrowStreamRequested(tools.WithArrowStream(context.Background())) {
+		t.Fatalf("Arrow not requested with WithArrowStream")
+	}
+}
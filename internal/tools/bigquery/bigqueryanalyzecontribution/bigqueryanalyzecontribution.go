@@ -25,6 +25,7 @@ import (
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	bigqueryds "github.com/googleapis/genai-toolbox/internal/sources/bigquery"
 	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigquerycommon"
 	bigqueryrestapi "google.golang.org/api/bigquery/v2"
 	"google.golang.org/api/iterator"
 )
@@ -47,9 +48,13 @@ func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.T
 
 type compatibleSource interface {
 	BigQueryClient() *bigqueryapi.Client
+	BigQueryQueryTag() string
 	BigQueryRestService() *bigqueryrestapi.Service
 	BigQueryClientCreator() bigqueryds.BigqueryClientCreator
 	UseClientAuthorization() bool
+	BigQueryAllowedProjects() []string
+	BigQueryClientForProject(projectID, tokenString string) (*bigqueryapi.Client, *bigqueryrestapi.Service, error)
+	ReserveQuota(ctx context.Context, tokenString string, bytes int64) error
 }
 
 // validate compatible sources are still compatible
@@ -117,6 +122,12 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		topKInsightsParameter,
 		pruningMethodParameter,
 	}
+	// On sources with allowedProjects, the project parameter selects the
+	// project to run in.
+	allowedProjects := s.BigQueryAllowedProjects()
+	if len(allowedProjects) > 0 {
+		parameters = append(parameters, bigquerycommon.ProjectParameter(allowedProjects))
+	}
 
 	mcpManifest := tools.McpManifest{
 		Name:        cfg.Name,
@@ -126,16 +137,20 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 
 	// finish tool setup
 	t := Tool{
-		Name:           cfg.Name,
-		Kind:           kind,
-		Parameters:     parameters,
-		AuthRequired:   cfg.AuthRequired,
-		UseClientOAuth: s.UseClientAuthorization(),
-		ClientCreator:  s.BigQueryClientCreator(),
-		Client:         s.BigQueryClient(),
-		RestService:    s.BigQueryRestService(),
-		manifest:       tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
-		mcpManifest:    mcpManifest,
+		Name:             cfg.Name,
+		Kind:             kind,
+		Parameters:       parameters,
+		AuthRequired:     cfg.AuthRequired,
+		UseClientOAuth:   s.UseClientAuthorization(),
+		ClientCreator:    s.BigQueryClientCreator(),
+		Client:           s.BigQueryClient(),
+		QueryTag:         s.BigQueryQueryTag(),
+		MultiProject:     len(allowedProjects) > 0,
+		ClientForProject: s.BigQueryClientForProject,
+		ReserveQuota:     s.ReserveQuota,
+		RestService:      s.BigQueryRestService(),
+		manifest:         tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:      mcpManifest,
 	}
 	return t, nil
 }
@@ -150,11 +165,15 @@ type Tool struct {
 	UseClientOAuth bool             `yaml:"useClientOAuth"`
 	Parameters     tools.Parameters `yaml:"parameters"`
 
-	Client        *bigqueryapi.Client
-	RestService   *bigqueryrestapi.Service
-	ClientCreator bigqueryds.BigqueryClientCreator
-	manifest      tools.Manifest
-	mcpManifest   tools.McpManifest
+	Client           *bigqueryapi.Client
+	QueryTag         string
+	MultiProject     bool
+	ClientForProject bigquerycommon.ClientForProjectFunc
+	ReserveQuota     bigquerycommon.ReserveQuotaFunc
+	RestService      *bigqueryrestapi.Service
+	ClientCreator    bigqueryds.BigqueryClientCreator
+	manifest         tools.Manifest
+	mcpManifest      tools.McpManifest
 }
 
 // Invoke runs the contribution analysis.
@@ -213,8 +232,15 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	bqClient := t.Client
 	var err error
 
-	// Initialize new client if using user OAuth token
-	if t.UseClientOAuth {
+	// Use the clients of the project, or initialize new ones if using user
+	// OAuth token
+	if project, ok := paramsMap[bigquerycommon.ProjectKey].(string); ok && t.MultiProject {
+		// Sources with allowedProjects run the query in the requested project.
+		bqClient, _, err = bigquerycommon.ProjectClient(t.ClientForProject, project, t.UseClientOAuth, accessToken)
+		if err != nil {
+			return nil, err
+		}
+	} else if t.UseClientOAuth {
 		tokenStr, err := accessToken.ParseBearerToken()
 		if err != nil {
 			return nil, fmt.Errorf("error parsing access token: %w", err)
@@ -225,14 +251,21 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		}
 	}
 
+	// Both queries count against the user's quota.
+	quota, err := bigquerycommon.NewQuota(t.ReserveQuota, t.UseClientOAuth, accessToken)
+	if err != nil {
+		return nil, err
+	}
+
 	createModelQuery := bqClient.Query(createModelSQL)
+	bigquerycommon.SetQueryTag(createModelQuery, t.QueryTag)
 	createModelQuery.CreateSession = true
-	createModelJob, err := createModelQuery.Run(ctx)
+	createModelJob, err := bigquerycommon.RunQuery(ctx, createModelQuery, quota)
 	if err != nil {
 		return nil, fmt.Errorf("failed to start create model job: %w", err)
 	}
 
-	status, err := createModelJob.Wait(ctx)
+	status, err := bigquerycommon.WaitForJob(ctx, createModelJob)
 	if err != nil {
 		return nil, fmt.Errorf("failed to wait for create model job: %w", err)
 	}
@@ -247,11 +280,12 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	getInsightsSQL := fmt.Sprintf("SELECT * FROM ML.GET_INSIGHTS(MODEL %s)", modelID)
 
 	getInsightsQuery := bqClient.Query(getInsightsSQL)
+	bigquerycommon.SetQueryTag(getInsightsQuery, t.QueryTag)
 	getInsightsQuery.QueryConfig.ConnectionProperties = []*bigqueryapi.ConnectionProperty{
 		{Key: "session_id", Value: sessionID},
 	}
 
-	job, err := getInsightsQuery.Run(ctx)
+	job, err := bigquerycommon.RunQuery(ctx, getInsightsQuery, quota)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute get insights query: %w", err)
 	}
@@ -283,7 +317,7 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 
 	// This handles the standard case for a SELECT query that successfully
 	// executes but returns zero rows.
-	return "The query returned 0 rows.", nil
+	return tools.ZeroRowsMessage, nil
 }
 
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
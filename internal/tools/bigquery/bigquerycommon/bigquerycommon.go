@@ -0,0 +1,910 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bigquerycommon
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	bigqueryapi "cloud.google.com/go/bigquery"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	bigqueryrestapi "google.golang.org/api/bigquery/v2"
+	"google.golang.org/api/iterator"
+)
+
+// RowIterator is the part of *bigquery.RowIterator used to read query results.
+type RowIterator interface {
+	Next(dst any) error
+}
+
+// validate interface
+var _ RowIterator = &bigqueryapi.RowIterator{}
+
+// RowAccessPolicyWarning is returned with the results of a query that read
+// data protected by a row access policy.
+const RowAccessPolicyWarning = "the results were filtered by a row access policy, so they may not include every row of the queried tables"
+
+// QueryResult holds the rows of a query along with the warnings about them and
+// the statistics of the query job, for tools that add either to their
+// results.
+type QueryResult struct {
+	Rows     []any       `json:"rows"`
+	Warnings []string    `json:"warnings,omitempty"`
+	Stats    *QueryStats `json:"stats,omitempty"`
+}
+
+// validate interface
+var _ tools.RowsResult = QueryResult{}
+
+func (r QueryResult) ResultRows() []any {
+	return r.Rows
+}
+
+func (r QueryResult) WithRows(rows []any) any {
+	r.Rows = rows
+	return r
+}
+
+// NewQueryResult returns the rows of a query as query tools return them: in a
+// QueryResult if there are warnings or statistics to add, and otherwise as
+// is. A query without rows returns ZeroRowsMessage if its statementType is
+// SELECT, and NoContentMessage otherwise.
+func NewQueryResult(rows []any, warnings []string, stats *QueryStats, statementType string) any {
+	if len(warnings) > 0 || stats != nil {
+		if rows == nil {
+			rows = []any{}
+		}
+		return QueryResult{Rows: rows, Warnings: warnings, Stats: stats}
+	}
+	if len(rows) > 0 {
+		return rows
+	}
+	if statementType == "SELECT" {
+		return tools.ZeroRowsMessage
+	}
+	// This is the fallback for a successful query that doesn't return content.
+	// In most cases, this will be for DML/DDL statements like INSERT, UPDATE, CREATE, etc.
+	// However, it is also possible that this was a query that was expected to return rows
+	// but returned none, a case that we cannot distinguish here.
+	return tools.NoContentMessage
+}
+
+// ResultOptions are the settings of a query tool that shape the results it
+// returns.
+type ResultOptions struct {
+	ColumnAliases map[string]string
+	BytesEncoding string
+	EmptyArrays   string
+	// ReturnPartialOnError returns the rows read so far, with a warning,
+	// when reading the results fails part way.
+	ReturnPartialOnError bool
+	// WarnOnRowAccessPolicy adds a warning when a row access policy filtered
+	// the rows the query read.
+	WarnOnRowAccessPolicy bool
+	IncludeStats          bool
+	ArrayRows             bool
+}
+
+// ReadResults reads the rows of a query from it and returns them shaped by
+// opts, along with partitionErr, the ErrMissingPartitionFilter of the query,
+// as a warning if it is set.
+func ReadResults(ctx context.Context, restService *bigqueryrestapi.Service, it *bigqueryapi.RowIterator, statementType string, partitionErr error, opts ResultOptions) (any, error) {
+	rows, err := ReadRows(it, opts.ColumnAliases)
+	rows = EncodeBytes(rows, opts.BytesEncoding)
+	rows = NormalizeRows(rows, opts.EmptyArrays)
+	var warnings []string
+	if err != nil {
+		if !opts.ReturnPartialOnError || len(rows) == 0 {
+			return nil, err
+		}
+		warnings = append(warnings, fmt.Sprintf("the query results are incomplete: %s", err))
+	}
+	if opts.WarnOnRowAccessPolicy {
+		filtered, err := RowAccessPolicyApplied(ctx, restService, it)
+		if err != nil {
+			return nil, err
+		}
+		if filtered {
+			warnings = append(warnings, RowAccessPolicyWarning)
+		}
+	}
+	if partitionErr != nil {
+		warnings = append(warnings, partitionErr.Error())
+	}
+	var stats *QueryStats
+	if opts.IncludeStats {
+		s := IteratorQueryStats(it)
+		stats = &s
+	}
+	res := NewQueryResult(rows, warnings, stats, statementType)
+	if opts.ArrayRows {
+		return ToTableResult(res, it.Schema, opts.ColumnAliases), nil
+	}
+	return res, nil
+}
+
+// DryRunQuery performs a dry run of a GoogleSQL query, with its parameters
+// and connection properties, to validate it and get its metadata, such as its
+// statement type and the bytes it would process.
+func DryRunQuery(ctx context.Context, restService *bigqueryrestapi.Service, projectID, location, sql string, params []*bigqueryrestapi.QueryParameter, connProps []*bigqueryapi.ConnectionProperty) (*bigqueryrestapi.Job, error) {
+	useLegacySql := false
+
+	restConnProps := make([]*bigqueryrestapi.ConnectionProperty, len(connProps))
+	for i, prop := range connProps {
+		restConnProps[i] = &bigqueryrestapi.ConnectionProperty{Key: prop.Key, Value: prop.Value}
+	}
+
+	jobToInsert := &bigqueryrestapi.Job{
+		JobReference: &bigqueryrestapi.JobReference{
+			ProjectId: projectID,
+			Location:  location,
+		},
+		Configuration: &bigqueryrestapi.JobConfiguration{
+			DryRun: true,
+			Query: &bigqueryrestapi.JobConfigurationQuery{
+				Query:                sql,
+				UseLegacySql:         &useLegacySql,
+				ConnectionProperties: restConnProps,
+				QueryParameters:      params,
+			},
+		},
+	}
+
+	insertResponse, err := restService.Jobs.Insert(projectID, jobToInsert).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert dry run job: %w", err)
+	}
+	return insertResponse, nil
+}
+
+// ReadQueryResults runs the query and reads its results. The query is run as
+// a job, rather than with query.Read, so that the job can be cancelled if the
+// invocation is abandoned, and so that a failed query reports every error of
+// the job rather than just the primary one.
+func ReadQueryResults(ctx context.Context, query *bigqueryapi.Query) (*bigqueryapi.RowIterator, error) {
+	job, err := query.Run(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to execute query: %w", err)
+	}
+	status, err := WaitForJob(ctx, job)
+	if err != nil {
+		return nil, fmt.Errorf("unable to wait for query to complete: %w", err)
+	}
+	if err := JobError(status.Err(), status.Errors); err != nil {
+		return nil, fmt.Errorf("unable to execute query: %w", err)
+	}
+	it, err := job.Read(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read query results: %w", err)
+	}
+	return it, nil
+}
+
+// ReserveQuotaFunc records the bytes a query is about to process against the
+// quota of the user behind an OAuth access token, like the ReserveQuota method
+// of BigQuery sources.
+type ReserveQuotaFunc func(ctx context.Context, tokenString string, bytes int64) error
+
+// Quota is the per-user quota of bytes processed that the queries of an
+// invocation count against. It only applies to tools that use client OAuth;
+// the zero Quota counts nothing.
+type Quota struct {
+	reserve ReserveQuotaFunc
+	token   string
+}
+
+// NewQuota returns the quota that the queries of an invocation with
+// accessToken count against.
+func NewQuota(reserve ReserveQuotaFunc, useClientOAuth bool, accessToken tools.AccessToken) (Quota, error) {
+	if !useClientOAuth || reserve == nil {
+		return Quota{}, nil
+	}
+	tokenStr, err := accessToken.ParseBearerToken()
+	if err != nil {
+		return Quota{}, fmt.Errorf("error parsing access token: %w", err)
+	}
+	return Quota{reserve: reserve, token: tokenStr}, nil
+}
+
+// Reserve records bytes against the quota. It returns an error wrapping
+// ErrQuotaExceeded of the source if the user is over their quota.
+func (q Quota) Reserve(ctx context.Context, bytes int64) error {
+	if q.reserve == nil {
+		return nil
+	}
+	return q.reserve(ctx, q.token, bytes)
+}
+
+// reserveQuery performs a dry run of query and reserves the bytes it would
+// process against the quota.
+func (q Quota) reserveQuery(ctx context.Context, query *bigqueryapi.Query) error {
+	if q.reserve == nil {
+		return nil
+	}
+	query.DryRun = true
+	job, err := query.Run(ctx)
+	query.DryRun = false
+	if err != nil {
+		return fmt.Errorf("query validation failed during dry run: %w", err)
+	}
+	status := job.LastStatus()
+	if status == nil || status.Statistics == nil {
+		return fmt.Errorf("dry run of the query returned no statistics")
+	}
+	return q.Reserve(ctx, status.Statistics.TotalBytesProcessed)
+}
+
+// RunQuery runs query and returns its job. The bytes that a dry run of the
+// query says it will process are first reserved against quota, so that a
+// query that would take the user over their quota is never run.
+func RunQuery(ctx context.Context, query *bigqueryapi.Query, quota Quota) (*bigqueryapi.Job, error) {
+	if err := quota.reserveQuery(ctx, query); err != nil {
+		return nil, err
+	}
+	return query.Run(ctx)
+}
+
+// ReadQuery runs query as RunQuery does and returns its results.
+func ReadQuery(ctx context.Context, query *bigqueryapi.Query, quota Quota) (*bigqueryapi.RowIterator, error) {
+	if err := quota.reserveQuery(ctx, query); err != nil {
+		return nil, err
+	}
+	return query.Read(ctx)
+}
+
+// RowAccessPolicyApplied reports whether a row access policy filtered the data
+// read by the query job behind it. It returns false if the results are not
+// backed by a job, as no statistics are available then.
+func RowAccessPolicyApplied(ctx context.Context, restService *bigqueryrestapi.Service, it *bigqueryapi.RowIterator) (bool, error) {
+	job := it.SourceJob()
+	if job == nil {
+		return false, nil
+	}
+	j, err := restService.Jobs.Get(job.ProjectID(), job.ID()).Location(job.Location()).Context(ctx).Do()
+	if err != nil {
+		return false, fmt.Errorf("unable to get statistics for job %q: %w", job.ID(), err)
+	}
+	if j.Statistics == nil || j.Statistics.RowLevelSecurityStatistics == nil {
+		return false, nil
+	}
+	return j.Statistics.RowLevelSecurityStatistics.RowLevelSecurityApplied, nil
+}
+
+// ReadRows reads the rows of a query result as maps of column names to values.
+// Columns named in aliases are renamed to the mapped name. If reading fails
+// part way, it returns the rows read so far together with the error, so that
+// callers can choose to return them with a warning.
+func ReadRows(it RowIterator, aliases map[string]string) ([]any, error) {
+	var out []any
+	for {
+		var row map[string]bigqueryapi.Value
+		err := it.Next(&row)
+		if err == iterator.Done {
+			return out, nil
+		}
+		if err != nil {
+			return out, fmt.Errorf("unable to iterate through query results after %d rows: %w", len(out), err)
+		}
+		vMap := make(map[string]any)
+		for key, value := range row {
+			if alias, ok := aliases[key]; ok {
+				key = alias
+			}
+			if _, ok := vMap[key]; ok {
+				return out, fmt.Errorf("column alias %q clashes with another column in the query results", key)
+			}
+			vMap[key] = value
+		}
+		out = append(out, vMap)
+	}
+}
+
+// The encodings that BYTES values can be returned in.
+const (
+	BytesEncodingBase64 = "base64"
+	BytesEncodingHex    = "hex"
+	BytesEncodingUTF8   = "utf8"
+)
+
+// ValidateBytesEncoding checks the `bytesEncoding` setting of a query tool,
+// which is either unset or one of the BytesEncoding constants.
+func ValidateBytesEncoding(encoding string) error {
+	switch encoding {
+	case "", BytesEncodingBase64, BytesEncodingHex, BytesEncodingUTF8:
+		return nil
+	}
+	return fmt.Errorf("bytesEncoding must be %q, %q or %q, got %q", BytesEncodingBase64, BytesEncodingHex, BytesEncodingUTF8, encoding)
+}
+
+// EncodeBytes replaces the BYTES values of rows read by ReadRows, including
+// those in arrays and structs, by strings in the given encoding, so that they
+// serialize the same way whichever tool reads them. Unset means base64. Bytes
+// that aren't valid UTF-8 are replaced by U+FFFD with BytesEncodingUTF8.
+func EncodeBytes(rows []any, encoding string) []any {
+	for i, row := range rows {
+		rows[i] = encodeBytesValue(row, encoding)
+	}
+	return rows
+}
+
+func encodeBytesValue(v any, encoding string) any {
+	switch v := v.(type) {
+	case []byte:
+		switch encoding {
+		case BytesEncodingHex:
+			return hex.EncodeToString(v)
+		case BytesEncodingUTF8:
+			return strings.ToValidUTF8(string(v), "\uFFFD")
+		}
+		return base64.StdEncoding.EncodeToString(v)
+	case map[string]any:
+		for key, value := range v {
+			v[key] = encodeBytesValue(value, encoding)
+		}
+	case map[string]bigqueryapi.Value:
+		for key, value := range v {
+			v[key] = encodeBytesValue(value, encoding)
+		}
+	case []bigqueryapi.Value:
+		for i, value := range v {
+			v[i] = encodeBytesValue(value, encoding)
+		}
+	}
+	return v
+}
+
+// The ways empty REPEATED values can be returned in.
+const (
+	EmptyArraysArray = "array"
+	EmptyArraysNull  = "null"
+)
+
+// ValidateEmptyArrays checks the `emptyArrays` setting of a query tool, which
+// is either unset or one of the EmptyArrays constants.
+func ValidateEmptyArrays(mode string) error {
+	switch mode {
+	case "", EmptyArraysArray, EmptyArraysNull:
+		return nil
+	}
+	return fmt.Errorf("emptyArrays must be %q or %q, got %q", EmptyArraysArray, EmptyArraysNull, mode)
+}
+
+// NormalizeRows replaces the REPEATED and RECORD values of rows read by
+// ReadRows, which the client returns as []bigqueryapi.Value and
+// map[string]bigqueryapi.Value, by []any and map[string]any, so that REPEATED
+// columns serialize as JSON arrays and REPEATED RECORD columns as arrays of
+// objects whichever tool reads them. BigQuery returns NULL arrays as empty
+// arrays; these are kept as empty arrays unless emptyArrays is
+// EmptyArraysNull. NULL values, including NULL records, stay nil.
+func NormalizeRows(rows []any, emptyArrays string) []any {
+	for i, row := range rows {
+		rows[i] = normalizeValue(row, emptyArrays)
+	}
+	return rows
+}
+
+func normalizeValue(v any, emptyArrays string) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			v[key] = normalizeValue(value, emptyArrays)
+		}
+		return v
+	case map[string]bigqueryapi.Value:
+		out := make(map[string]any, len(v))
+		for key, value := range v {
+			out[key] = normalizeValue(value, emptyArrays)
+		}
+		return out
+	case []bigqueryapi.Value:
+		if len(v) == 0 && emptyArrays == EmptyArraysNull {
+			return nil
+		}
+		out := make([]any, 0, len(v))
+		for _, value := range v {
+			out = append(out, normalizeValue(value, emptyArrays))
+		}
+		return out
+	}
+	return v
+}
+
+// QueryTagLabel is the label of the query jobs of a source with a queryTag.
+const QueryTagLabel = "toolbox_query_tag"
+
+// SetQueryTag labels the job of q with tag, unless it is empty, so that the
+// jobs of a deployment can be told apart in monitoring.
+func SetQueryTag(q *bigqueryapi.Query, tag string) {
+	if tag == "" {
+		return
+	}
+	if q.Labels == nil {
+		q.Labels = make(map[string]string)
+	}
+	q.Labels[QueryTagLabel] = tag
+}
+
+// ReadArrow reads query results that come from the BigQuery Storage Read API
+// into a single Arrow IPC stream: the schema followed by every record batch.
+func ReadArrow(it *bigqueryapi.RowIterator) (tools.ArrowStream, error) {
+	arrowIt, err := it.ArrowIterator()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read query results as Arrow: %w", err)
+	}
+	var buf bytes.Buffer
+	buf.Write(arrowIt.SerializedArrowSchema())
+	for {
+		batch, err := arrowIt.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to read query results as Arrow: %w", err)
+		}
+		buf.Write(batch.Data)
+	}
+	return tools.ArrowStream(buf.Bytes()), nil
+}
+
+// ValidateColumnAliases checks that the column aliases configured on a tool
+// are not empty and that no two columns are renamed to the same name.
+func ValidateColumnAliases(aliases map[string]string) error {
+	seen := make(map[string]string, len(aliases))
+	for column, alias := range aliases {
+		if alias == "" {
+			return fmt.Errorf("alias for column %q must not be empty", column)
+		}
+		if other, ok := seen[alias]; ok {
+			return fmt.Errorf("columns %q and %q are both renamed to %q", min(column, other), max(column, other), alias)
+		}
+		seen[alias] = column
+	}
+	return nil
+}
+
+// knownConnectionProperties are the connection properties that BigQuery
+// accepts on a query.
+var knownConnectionProperties = []string{
+	"dataset_project_id",
+	"query_label",
+	"service_account",
+	"session_id",
+	"time_zone",
+}
+
+// ConnectionProperties validates the connection properties configured on a
+// tool and converts them for use in Query.ConnectionProperties. They are
+// sorted by key so that the same configuration always builds the same query.
+func ConnectionProperties(props map[string]string) ([]*bigqueryapi.ConnectionProperty, error) {
+	if len(props) == 0 {
+		return nil, nil
+	}
+	keys := make([]string, 0, len(props))
+	for key := range props {
+		if !slices.Contains(knownConnectionProperties, key) {
+			return nil, fmt.Errorf("unsupported connection property %q: must be one of %q", key, knownConnectionProperties)
+		}
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	out := make([]*bigqueryapi.ConnectionProperty, 0, len(keys))
+	for _, key := range keys {
+		out = append(out, &bigqueryapi.ConnectionProperty{Key: key, Value: props[key]})
+	}
+	return out, nil
+}
+
+// LocationChain holds the locations a query can run in, in order of
+// precedence: the location set on the tool, the location of the source, the
+// location BigQuery detected from the datasets the query reads and the
+// default location of the source.
+type LocationChain struct {
+	Tool     string
+	Source   string
+	Detected string
+	Default  string
+}
+
+// Explicit returns the location configured on the tool or the source, which
+// is used for the dry run that detects the location otherwise.
+func (c LocationChain) Explicit() string {
+	if c.Tool != "" {
+		return c.Tool
+	}
+	return c.Source
+}
+
+// Resolve returns the first location set in the chain and which level it was
+// taken from. An empty location makes BigQuery use the project default.
+func (c LocationChain) Resolve() (string, string) {
+	levels := []struct{ location, level string }{
+		{c.Tool, "tool location"},
+		{c.Source, "source location"},
+		{c.Detected, "detected location"},
+		{c.Default, "default location"},
+	}
+	for _, l := range levels {
+		if l.location != "" {
+			return l.location, l.level
+		}
+	}
+	return "", "project default"
+}
+
+// WrapError adds the resolved location to an error from BigQuery, so that a
+// query that ran in the wrong location is easy to spot.
+func (c LocationChain) WrapError(err error) error {
+	location, level := c.Resolve()
+	if location == "" {
+		return fmt.Errorf("%w (location: %s)", err, level)
+	}
+	return fmt.Errorf("%w (location: %q, from %s)", err, location, level)
+}
+
+// JobError returns err, the primary error of a finished job, together with
+// every error in the job's error stream. The primary error often leaves out
+// detail, such as which statement of a script failed.
+func JobError(err error, errs []*bigqueryapi.Error) error {
+	if err == nil || len(errs) == 0 {
+		return err
+	}
+	details := make([]string, 0, len(errs))
+	for _, e := range errs {
+		details = append(details, e.Error())
+	}
+	return fmt.Errorf("%w; all job errors: [%s]", err, strings.Join(details, ", "))
+}
+
+// ProjectKey is the parameter of query tools that selects the project to run
+// the query in, on sources with allowedProjects.
+const ProjectKey = "project"
+
+// ProjectParameter returns the optional `project` parameter of query tools on
+// sources with allowedProjects. It defaults to the first, i.e. the source,
+// project.
+func ProjectParameter(allowedProjects []string) tools.Parameter {
+	quoted := make([]string, len(allowedProjects))
+	for i, p := range allowedProjects {
+		quoted[i] = fmt.Sprintf("`%s`", p)
+	}
+	description := fmt.Sprintf("The Google Cloud project to run the query in, which is also the default project of unqualified table names. Must be one of: %s.", strings.Join(quoted, ", "))
+	return tools.NewStringParameterWithDefault(ProjectKey, allowedProjects[0], description)
+}
+
+// ClientForProjectFunc returns the clients that run jobs in a project, like
+// the BigQueryClientForProject method of BigQuery sources.
+type ClientForProjectFunc func(projectID, tokenString string) (*bigqueryapi.Client, *bigqueryrestapi.Service, error)
+
+// ProjectClient returns the clients that run the jobs of an invocation in the
+// given project, using the caller's access token for sources that use client
+// OAuth.
+func ProjectClient(clientForProject ClientForProjectFunc, projectID string, useClientOAuth bool, accessToken tools.AccessToken) (*bigqueryapi.Client, *bigqueryrestapi.Service, error) {
+	var tokenStr string
+	if useClientOAuth {
+		var err error
+		tokenStr, err = accessToken.ParseBearerToken()
+		if err != nil {
+			return nil, nil, fmt.Errorf("error parsing access token: %w", err)
+		}
+	}
+	return clientForProject(projectID, tokenStr)
+}
+
+const (
+	// PartitionFilterReject makes query tools reject queries that scan a
+	// partitioned table without filtering on its partition column.
+	PartitionFilterReject = "reject"
+	// PartitionFilterWarn makes query tools run such queries and add a
+	// warning to their results.
+	PartitionFilterWarn = "warn"
+)
+
+// ErrMissingPartitionFilter is returned for queries that scan a partitioned
+// table without filtering on its partition column.
+var ErrMissingPartitionFilter = errors.New("the query scans partitioned tables without a partition filter")
+
+// ValidatePartitionFilterMode checks the `requirePartitionFilter` setting of a
+// query tool, which is either unset, PartitionFilterReject or
+// PartitionFilterWarn.
+func ValidatePartitionFilterMode(mode string) error {
+	switch mode {
+	case "", PartitionFilterReject, PartitionFilterWarn:
+		return nil
+	}
+	return fmt.Errorf("requirePartitionFilter must be %q or %q, got %q", PartitionFilterReject, PartitionFilterWarn, mode)
+}
+
+// TableMetadataFunc looks up the metadata of a table referenced by a query.
+type TableMetadataFunc func(ctx context.Context, ref *bigqueryrestapi.TableReference) (*bigqueryapi.TableMetadata, error)
+
+// ClientTableMetadata returns a TableMetadataFunc that looks tables up with
+// client.
+func ClientTableMetadata(client *bigqueryapi.Client) TableMetadataFunc {
+	return func(ctx context.Context, ref *bigqueryrestapi.TableReference) (*bigqueryapi.TableMetadata, error) {
+		return client.DatasetInProject(ref.ProjectId, ref.DatasetId).Table(ref.TableId).Metadata(ctx)
+	}
+}
+
+// PartitionColumns returns the columns that select the partitions of a table:
+// its partitioning column, or the _PARTITIONTIME and _PARTITIONDATE pseudo
+// columns of an ingestion-time partitioned table. Nil is returned for tables
+// that aren't partitioned.
+func PartitionColumns(md *bigqueryapi.TableMetadata) []string {
+	switch {
+	case md.TimePartitioning != nil && md.TimePartitioning.Field != "":
+		return []string{md.TimePartitioning.Field}
+	case md.TimePartitioning != nil:
+		return []string{"_PARTITIONTIME", "_PARTITIONDATE"}
+	case md.RangePartitioning != nil:
+		return []string{md.RangePartitioning.Field}
+	}
+	return nil
+}
+
+// CheckPartitionFilters returns ErrMissingPartitionFilter, naming the tables
+// and their partition columns, if any of the partitioned tables referenced by
+// a query, as reported by its dry run, isn't filtered on.
+//
+// Tables that set require_partition_filter are left to the dry run, which
+// BigQuery fails for queries that can't eliminate their partitions. Other
+// tables count as filtered if one of their partition columns appears after a
+// WHERE keyword of the query, outside of comments and string literals. This
+// is a heuristic rather than a full analysis of the query, which errs on the
+// side of accepting it.
+func CheckPartitionFilters(ctx context.Context, sql string, refs []*bigqueryrestapi.TableReference, metadata TableMetadataFunc) error {
+	var unfiltered []string
+	var filtered map[string]bool
+	for _, ref := range refs {
+		md, err := metadata(ctx, ref)
+		if err != nil {
+			return fmt.Errorf("unable to get metadata of table %s.%s.%s: %w", ref.ProjectId, ref.DatasetId, ref.TableId, err)
+		}
+		columns := PartitionColumns(md)
+		if len(columns) == 0 || md.RequirePartitionFilter {
+			continue
+		}
+		if filtered == nil {
+			filtered = filteredIdentifiers(sql)
+		}
+		if slices.ContainsFunc(columns, func(c string) bool { return filtered[strings.ToLower(c)] }) {
+			continue
+		}
+		unfiltered = append(unfiltered, fmt.Sprintf("%s.%s.%s (partitioned on %s)", ref.ProjectId, ref.DatasetId, ref.TableId, strings.Join(columns, " or ")))
+	}
+	if len(unfiltered) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrMissingPartitionFilter, strings.Join(unfiltered, ", "))
+}
+
+// PartitionFilterWarning runs CheckPartitionFilters for a query tool in the
+// given `requirePartitionFilter` mode. A missing filter is returned as err in
+// PartitionFilterReject mode, and as warning, for the tool to add to its
+// results, in PartitionFilterWarn mode. Nothing is checked if mode is unset.
+func PartitionFilterWarning(ctx context.Context, mode, sql string, refs []*bigqueryrestapi.TableReference, metadata TableMetadataFunc) (warning error, err error) {
+	if mode == "" {
+		return nil, nil
+	}
+	err = CheckPartitionFilters(ctx, sql, refs, metadata)
+	if mode == PartitionFilterWarn && errors.Is(err, ErrMissingPartitionFilter) {
+		return err, nil
+	}
+	return nil, err
+}
+
+// sqlTokenPattern matches, in order of precedence, the comments, string
+// literals, quoted and unquoted identifiers, and numbers of a GoogleSQL query.
+// Only identifiers are captured, so that words in comments or strings can't be
+// mistaken for columns.
+var sqlTokenPattern = regexp.MustCompile(`(?s)--[^\n]*|#[^\n]*|/\*.*?(?:\*/|$)|[rRbB]{0,2}(?:'''(?:\\.|[^\\])*?(?:'''|$)|"""(?:\\.|[^\\])*?(?:"""|$)|'(?:\\.|[^\\'\n])*(?:'|$)|"(?:\\.|[^\\"\n])*(?:"|$))` +
+	"|`([^`]*)`|([A-Za-z_][A-Za-z0-9_]*)|[0-9][A-Za-z0-9_.]*")
+
+// filteredIdentifiers returns the lower-cased identifiers that appear after
+// the first WHERE keyword of sql. The parts of quoted paths, such as
+// `t.order_date`, are returned separately.
+func filteredIdentifiers(sql string) map[string]bool {
+	identifiers := make(map[string]bool)
+	where := false
+	for _, m := range sqlTokenPattern.FindAllStringSubmatch(sql, -1) {
+		switch {
+		case m[2] != "" && !where:
+			where = strings.EqualFold(m[2], "WHERE")
+		case m[2] != "":
+			identifiers[strings.ToLower(m[2])] = true
+		case m[1] != "" && where:
+			for _, part := range strings.Split(m[1], ".") {
+				identifiers[strings.ToLower(part)] = true
+			}
+		}
+	}
+	return identifiers
+}
+
+// BIEngineDisabled is the BI Engine mode of queries that BI Engine didn't
+// accelerate.
+const BIEngineDisabled = "DISABLED"
+
+// QueryStats are the statistics of a query job returned with its rows by
+// tools that set includeStats.
+type QueryStats struct {
+	TotalBytesProcessed int64 `json:"totalBytesProcessed"`
+	CacheHit            bool  `json:"cacheHit"`
+	// BIEngineMode is FULL or PARTIAL if BI Engine accelerated the query,
+	// and BIEngineDisabled otherwise.
+	BIEngineMode string `json:"biEngineMode"`
+	// BIEngineReasons explain why the query wasn't fully accelerated.
+	BIEngineReasons []string `json:"biEngineReasons,omitempty"`
+}
+
+// NewQueryStats returns the statistics of a finished query job.
+func NewQueryStats(status *bigqueryapi.JobStatus) QueryStats {
+	stats := QueryStats{BIEngineMode: BIEngineDisabled}
+	if status == nil || status.Statistics == nil {
+		return stats
+	}
+	stats.TotalBytesProcessed = status.Statistics.TotalBytesProcessed
+	q, ok := status.Statistics.Details.(*bigqueryapi.QueryStatistics)
+	if !ok {
+		return stats
+	}
+	stats.CacheHit = q.CacheHit
+	if bi := q.BIEngineStatistics; bi != nil && bi.BIEngineMode != "" {
+		stats.BIEngineMode = bi.BIEngineMode
+		for _, r := range bi.BIEngineReasons {
+			stats.BIEngineReasons = append(stats.BIEngineReasons, r.Message)
+		}
+	}
+	return stats
+}
+
+// IteratorQueryStats returns the statistics of the query job behind it.
+func IteratorQueryStats(it *bigqueryapi.RowIterator) QueryStats {
+	if job := it.SourceJob(); job != nil {
+		return NewQueryStats(job.LastStatus())
+	}
+	return NewQueryStats(nil)
+}
+
+// TableResult holds rows as arrays of values in the order of Columns, the
+// columns of the result schema, along with the warnings and statistics of the
+// QueryResult they were converted from, if any.
+type TableResult struct {
+	Columns  []string    `json:"columns"`
+	Rows     [][]any     `json:"rows"`
+	Warnings []string    `json:"warnings,omitempty"`
+	Stats    *QueryStats `json:"stats,omitempty"`
+}
+
+// ToTableResult converts res, the rows read by ReadRows or a QueryResult
+// holding them, into a TableResult with the columns of schema, renamed by
+// aliases. Other results, such as ZeroRowsMessage, are returned as is.
+func ToTableResult(res any, schema bigqueryapi.Schema, aliases map[string]string) any {
+	var rows []any
+	table := TableResult{}
+	switch r := res.(type) {
+	case []any:
+		rows = r
+	case QueryResult:
+		rows, table.Warnings, table.Stats = r.Rows, r.Warnings, r.Stats
+	default:
+		return res
+	}
+
+	table.Columns = make([]string, 0, len(schema))
+	for _, field := range schema {
+		name := field.Name
+		if alias, ok := aliases[name]; ok {
+			name = alias
+		}
+		table.Columns = append(table.Columns, name)
+	}
+	table.Rows = make([][]any, 0, len(rows))
+	for _, row := range rows {
+		m, _ := row.(map[string]any)
+		values := make([]any, 0, len(table.Columns))
+		for _, column := range table.Columns {
+			values = append(values, m[column])
+		}
+		table.Rows = append(table.Rows, values)
+	}
+	return table
+}
+
+// jobCancelTimeout bounds the request that cancels a job, which is sent after
+// the invocation context is already done.
+const jobCancelTimeout = 10 * time.Second
+
+// JobCanceler is the part of *bigquery.Job used to cancel it.
+type JobCanceler interface {
+	ID() string
+	Cancel(ctx context.Context) error
+}
+
+// validate interface
+var _ JobCanceler = &bigqueryapi.Job{}
+
+// CancelJobOnDone cancels job if ctx is done, e.g. because the client
+// disconnected, before the returned stop function is called, so that an
+// abandoned query doesn't keep running and billing. Call stop once the job
+// has finished.
+func CancelJobOnDone(ctx context.Context, job JobCanceler) (stop func() bool) {
+	return context.AfterFunc(ctx, func() {
+		cancelCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), jobCancelTimeout)
+		defer cancel()
+		if err := job.Cancel(cancelCtx); err != nil {
+			if logger, lerr := util.LoggerFromContext(ctx); lerr == nil {
+				logger.WarnContext(ctx, fmt.Sprintf("unable to cancel job %s: %s", job.ID(), err))
+			}
+		}
+	})
+}
+
+// jobProgressInterval is how often a running job is reported to clients that
+// asked for progress.
+const jobProgressInterval = 10 * time.Second
+
+// Job is the part of *bigquery.Job used to wait for it.
+type Job interface {
+	JobCanceler
+	Wait(ctx context.Context) (*bigqueryapi.JobStatus, error)
+}
+
+// validate interface
+var _ Job = &bigqueryapi.Job{}
+
+// WaitForJob waits for job to complete. The job is cancelled if the invocation
+// is abandoned while it runs, and its progress is reported to clients that
+// asked for it.
+func WaitForJob(ctx context.Context, job Job) (*bigqueryapi.JobStatus, error) {
+	tools.ReportProgress(ctx, fmt.Sprintf("BigQuery job %s submitted", job.ID()))
+
+	// stop the job if the invocation is abandoned while it runs
+	stop := CancelJobOnDone(ctx, job)
+	defer stop()
+
+	if tools.ProgressRequested(ctx) {
+		done := make(chan struct{})
+		defer close(done)
+		go func() {
+			ticker := time.NewTicker(jobProgressInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					tools.ReportProgress(ctx, fmt.Sprintf("BigQuery job %s running", job.ID()))
+				case <-done:
+					return
+				}
+			}
+		}()
+	}
+
+	status, err := job.Wait(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tools.ReportProgress(ctx, fmt.Sprintf("BigQuery job %s complete", job.ID()))
+	return status, nil
+}
@@ -0,0 +1,739 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bigquerycommon_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	bigqueryapi "cloud.google.com/go/bigquery"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigquerycommon"
+	bigqueryrestapi "google.golang.org/api/bigquery/v2"
+	"google.golang.org/api/iterator"
+)
+
+// fakeRowIterator returns its rows and then err, or iterator.Done if err is nil.
+type fakeRowIterator struct {
+	rows []map[string]bigqueryapi.Value
+	err  error
+}
+
+func (it *fakeRowIterator) Next(dst any) error {
+	if len(it.rows) == 0 {
+		if it.err != nil {
+			return it.err
+		}
+		return iterator.Done
+	}
+	*dst.(*map[string]bigqueryapi.Value) = it.rows[0]
+	it.rows = it.rows[1:]
+	return nil
+}
+
+func TestReadRows(t *testing.T) {
+	rows := []map[string]bigqueryapi.Value{{"id": int64(1)}, {"id": int64(2)}}
+	want := []any{map[string]any{"id": int64(1)}, map[string]any{"id": int64(2)}}
+
+	got, err := bigquerycommon.ReadRows(&fakeRowIterator{rows: rows}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("incorrect rows: diff %v", diff)
+	}
+}
+
+func TestReadRowsWithColumnAliases(t *testing.T) {
+	rows := []map[string]bigqueryapi.Value{{"id": int64(1), "name": "Alice"}}
+	want := []any{map[string]any{"id": int64(1), "user_name": "Alice"}}
+
+	got, err := bigquerycommon.ReadRows(&fakeRowIterator{rows: rows}, map[string]string{"name": "user_name", "missing": "other"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("incorrect rows: diff %v", diff)
+	}
+
+	_, err = bigquerycommon.ReadRows(&fakeRowIterator{rows: rows}, map[string]string{"name": "id"})
+	if err == nil || !strings.Contains(err.Error(), `column alias "id" clashes`) {
+		t.Fatalf("expected a clashing alias error, got %v", err)
+	}
+}
+
+func TestValidateColumnAliases(t *testing.T) {
+	if err := bigquerycommon.ValidateColumnAliases(map[string]string{"name": "user_name", "id": "user_id"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	err := bigquerycommon.ValidateColumnAliases(map[string]string{"a": "x", "b": "x"})
+	if err == nil || !strings.Contains(err.Error(), `columns "a" and "b" are both renamed to "x"`) {
+		t.Fatalf("expected a duplicate alias error, got %v", err)
+	}
+	err = bigquerycommon.ValidateColumnAliases(map[string]string{"a": ""})
+	if err == nil || !strings.Contains(err.Error(), `alias for column "a" must not be empty`) {
+		t.Fatalf("expected an empty alias error, got %v", err)
+	}
+}
+
+func TestReadRowsFailsPartWay(t *testing.T) {
+	readErr := errors.New("transient read failure")
+	rows := []map[string]bigqueryapi.Value{{"id": int64(1)}, {"id": int64(2)}}
+	want := []any{map[string]any{"id": int64(1)}, map[string]any{"id": int64(2)}}
+
+	got, err := bigquerycommon.ReadRows(&fakeRowIterator{rows: rows, err: readErr}, nil)
+	if !errors.Is(err, readErr) {
+		t.Fatalf("expected the iteration error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "after 2 rows") {
+		t.Fatalf("expected the error to say how many rows were read, got %q", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("incorrect rows read before the error: diff %v", diff)
+	}
+
+}
+
+func TestNewQueryResult(t *testing.T) {
+	rows := []any{map[string]any{"id": int64(1)}}
+	stats := &bigquerycommon.QueryStats{TotalBytesProcessed: 100, BIEngineMode: "FULL"}
+	warnings := []string{bigquerycommon.RowAccessPolicyWarning, "the query scans partitioned tables without a partition filter"}
+
+	tcs := []struct {
+		desc          string
+		rows          []any
+		warnings      []string
+		stats         *bigquerycommon.QueryStats
+		statementType string
+		want          any
+	}{
+		{
+			desc:          "rows",
+			rows:          rows,
+			statementType: "SELECT",
+			want:          rows,
+		},
+		{
+			desc:          "no rows",
+			statementType: "SELECT",
+			want:          tools.ZeroRowsMessage,
+		},
+		{
+			desc:          "no content",
+			statementType: "CREATE_TABLE",
+			want:          tools.NoContentMessage,
+		},
+		{
+			desc:          "every warning along with the stats",
+			rows:          rows,
+			warnings:      warnings,
+			stats:         stats,
+			statementType: "SELECT",
+			want:          bigquerycommon.QueryResult{Rows: rows, Warnings: warnings, Stats: stats},
+		},
+		{
+			desc:          "no rows with stats",
+			stats:         stats,
+			statementType: "SELECT",
+			want:          bigquerycommon.QueryResult{Rows: []any{}, Stats: stats},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := bigquerycommon.NewQueryResult(tc.rows, tc.warnings, tc.stats, tc.statementType)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect result: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestEncodeBytes(t *testing.T) {
+	newRows := func() []any {
+		return []any{map[string]any{
+			"id":      int64(1),
+			"payload": []byte("hi!"),
+			"chunks":  []bigqueryapi.Value{[]byte("a"), []byte("b")},
+			"meta":    map[string]bigqueryapi.Value{"sig": []byte{0xff}},
+			"missing": nil,
+		}}
+	}
+	tcs := []struct {
+		encoding string
+		want     []any
+	}{
+		{
+			encoding: "",
+			want: []any{map[string]any{
+				"id":      int64(1),
+				"payload": "aGkh",
+				"chunks":  []bigqueryapi.Value{"YQ==", "Yg=="},
+				"meta":    map[string]bigqueryapi.Value{"sig": "/w=="},
+				"missing": nil,
+			}},
+		},
+		{
+			encoding: bigquerycommon.BytesEncodingBase64,
+			want: []any{map[string]any{
+				"id":      int64(1),
+				"payload": "aGkh",
+				"chunks":  []bigqueryapi.Value{"YQ==", "Yg=="},
+				"meta":    map[string]bigqueryapi.Value{"sig": "/w=="},
+				"missing": nil,
+			}},
+		},
+		{
+			encoding: bigquerycommon.BytesEncodingHex,
+			want: []any{map[string]any{
+				"id":      int64(1),
+				"payload": "686921",
+				"chunks":  []bigqueryapi.Value{"61", "62"},
+				"meta":    map[string]bigqueryapi.Value{"sig": "ff"},
+				"missing": nil,
+			}},
+		},
+		{
+			encoding: bigquerycommon.BytesEncodingUTF8,
+			want: []any{map[string]any{
+				"id":      int64(1),
+				"payload": "hi!",
+				"chunks":  []bigqueryapi.Value{"a", "b"},
+				"meta":    map[string]bigqueryapi.Value{"sig": "\uFFFD"},
+				"missing": nil,
+			}},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.encoding, func(t *testing.T) {
+			if err := bigquerycommon.ValidateBytesEncoding(tc.encoding); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			got := bigquerycommon.EncodeBytes(newRows(), tc.encoding)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect rows: diff %v", diff)
+			}
+		})
+	}
+
+	err := bigquerycommon.ValidateBytesEncoding("base32")
+	if err == nil || !strings.Contains(err.Error(), `got "base32"`) {
+		t.Fatalf("expected an unknown encoding error, got %v", err)
+	}
+}
+
+func TestSetQueryTag(t *testing.T) {
+	q := &bigqueryapi.Query{}
+	bigquerycommon.SetQueryTag(q, "")
+	if q.Labels != nil {
+		t.Fatalf("expected no labels without a tag, got %v", q.Labels)
+	}
+
+	q.Labels = map[string]string{"team": "analytics"}
+	bigquerycommon.SetQueryTag(q, "analytics-agent-prod")
+	want := map[string]string{"team": "analytics", bigquerycommon.QueryTagLabel: "analytics-agent-prod"}
+	if diff := cmp.Diff(want, q.Labels); diff != "" {
+		t.Fatalf("incorrect labels: diff %v", diff)
+	}
+}
+
+func TestConnectionProperties(t *testing.T) {
+	got, err := bigquerycommon.ConnectionProperties(map[string]string{
+		"time_zone":   "America/New_York",
+		"query_label": "team:analytics",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := []*bigqueryapi.ConnectionProperty{
+		{Key: "query_label", Value: "team:analytics"},
+		{Key: "time_zone", Value: "America/New_York"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("incorrect connection properties: diff %v", diff)
+	}
+
+	got, err = bigquerycommon.ConnectionProperties(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got != nil {
+		t.Fatalf("expected no connection properties, got %v", got)
+	}
+
+	_, err = bigquerycommon.ConnectionProperties(map[string]string{"timezone": "UTC"})
+	if err == nil || !strings.Contains(err.Error(), `unsupported connection property "timezone"`) {
+		t.Fatalf("expected an unsupported connection property error, got %v", err)
+	}
+}
+
+func TestLocationChain(t *testing.T) {
+	tcs := []struct {
+		desc         string
+		chain        bigquerycommon.LocationChain
+		wantLocation string
+		wantLevel    string
+		wantExplicit string
+	}{
+		{
+			desc:         "tool location",
+			chain:        bigquerycommon.LocationChain{Tool: "EU", Source: "US", Detected: "asia-east1", Default: "us-central1"},
+			wantLocation: "EU",
+			wantLevel:    "tool location",
+			wantExplicit: "EU",
+		},
+		{
+			desc:         "source location",
+			chain:        bigquerycommon.LocationChain{Source: "US", Detected: "asia-east1", Default: "us-central1"},
+			wantLocation: "US",
+			wantLevel:    "source location",
+			wantExplicit: "US",
+		},
+		{
+			desc:         "detected location",
+			chain:        bigquerycommon.LocationChain{Detected: "asia-east1", Default: "us-central1"},
+			wantLocation: "asia-east1",
+			wantLevel:    "detected location",
+		},
+		{
+			desc:         "default location",
+			chain:        bigquerycommon.LocationChain{Default: "us-central1"},
+			wantLocation: "us-central1",
+			wantLevel:    "default location",
+		},
+		{
+			desc:      "project default",
+			wantLevel: "project default",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			location, level := tc.chain.Resolve()
+			if location != tc.wantLocation || level != tc.wantLevel {
+				t.Fatalf("unexpected resolved location: got (%q, %q), want (%q, %q)", location, level, tc.wantLocation, tc.wantLevel)
+			}
+			if got := tc.chain.Explicit(); got != tc.wantExplicit {
+				t.Fatalf("unexpected explicit location: got %q, want %q", got, tc.wantExplicit)
+			}
+		})
+	}
+}
+
+func TestLocationChainWrapError(t *testing.T) {
+	errNotFound := errors.New("Not found: Dataset my-project:my_dataset was not found in location US")
+	err := bigquerycommon.LocationChain{Default: "US"}.WrapError(errNotFound)
+	if !errors.Is(err, errNotFound) {
+		t.Fatalf("expected wrapped error to match %v", errNotFound)
+	}
+	if want := `(location: "US", from default location)`; !strings.Contains(err.Error(), want) {
+		t.Fatalf("unexpected error: got %q, want it to contain %q", err, want)
+	}
+}
+
+func TestJobError(t *testing.T) {
+	primary := &bigqueryapi.Error{Location: "query", Message: "Query error: division by zero: 1 / 0 at [2:8]", Reason: "invalidQuery"}
+	errs := []*bigqueryapi.Error{
+		primary,
+		{Location: "query", Message: "Error in script at [2:1]: division by zero", Reason: "invalidQuery"},
+	}
+
+	err := bigquerycommon.JobError(primary, errs)
+	var bqErr *bigqueryapi.Error
+	if !errors.As(err, &bqErr) || bqErr != primary {
+		t.Fatalf("expected error to wrap the primary error, got %v", err)
+	}
+	for _, want := range []string{"at [2:8]", "Error in script at [2:1]: division by zero"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("unexpected error: got %q, want it to contain %q", err, want)
+		}
+	}
+
+	if err := bigquerycommon.JobError(nil, nil); err != nil {
+		t.Fatalf("expected no error for a successful job, got %v", err)
+	}
+	if err := bigquerycommon.JobError(primary, nil); err != primary {
+		t.Fatalf("expected the primary error without an error stream, got %v", err)
+	}
+}
+
+func TestCheckPartitionFilters(t *testing.T) {
+	tables := map[string]*bigqueryapi.TableMetadata{
+		"orders":   {TimePartitioning: &bigqueryapi.TimePartitioning{Field: "order_date"}},
+		"events":   {TimePartitioning: &bigqueryapi.TimePartitioning{}},
+		"accounts": {RangePartitioning: &bigqueryapi.RangePartitioning{Field: "account_id"}},
+		"regions":  {},
+		"visits":   {TimePartitioning: &bigqueryapi.TimePartitioning{Field: "visit_date"}, RequirePartitionFilter: true},
+	}
+	metadata := func(_ context.Context, ref *bigqueryrestapi.TableReference) (*bigqueryapi.TableMetadata, error) {
+		return tables[ref.TableId], nil
+	}
+	ref := func(table string) *bigqueryrestapi.TableReference {
+		return &bigqueryrestapi.TableReference{ProjectId: "my-project", DatasetId: "sales", TableId: table}
+	}
+
+	tcs := []struct {
+		desc    string
+		sql     string
+		refs    []*bigqueryrestapi.TableReference
+		wantErr string
+	}{
+		{
+			desc:    "without partition filter",
+			sql:     "SELECT SUM(amount) FROM sales.orders WHERE customer_id = 1",
+			refs:    []*bigqueryrestapi.TableReference{ref("orders")},
+			wantErr: "my-project.sales.orders (partitioned on order_date)",
+		},
+		{
+			desc: "with partition filter",
+			sql:  "SELECT SUM(amount) FROM sales.orders WHERE customer_id = 1 AND order_date >= '2025-01-01'",
+			refs: []*bigqueryrestapi.TableReference{ref("orders")},
+		},
+		{
+			desc: "qualified partition filter",
+			sql:  "SELECT * FROM sales.orders o JOIN sales.regions r USING (region_id)\nwhere o.ORDER_DATE = @day",
+			refs: []*bigqueryrestapi.TableReference{ref("orders"), ref("regions")},
+		},
+		{
+			desc: "ingestion time partitioned",
+			sql:  "SELECT * FROM sales.events WHERE _PARTITIONDATE = CURRENT_DATE()",
+			refs: []*bigqueryrestapi.TableReference{ref("events")},
+		},
+		{
+			desc:    "partition column outside of a filter",
+			sql:     "SELECT account_id, balance FROM sales.accounts",
+			refs:    []*bigqueryrestapi.TableReference{ref("accounts")},
+			wantErr: "my-project.sales.accounts (partitioned on account_id)",
+		},
+		{
+			desc: "quoted partition filter",
+			sql:  "SELECT * FROM `my-project.sales.orders` AS o WHERE `o.order_date` = @day",
+			refs: []*bigqueryrestapi.TableReference{ref("orders")},
+		},
+		{
+			desc:    "partition column in a comment",
+			sql:     "SELECT * FROM sales.orders WHERE customer_id = 1 -- order_date is not needed\n/* nor order_date */",
+			refs:    []*bigqueryrestapi.TableReference{ref("orders")},
+			wantErr: "my-project.sales.orders (partitioned on order_date)",
+		},
+		{
+			desc:    "partition column in a string",
+			sql:     "SELECT * FROM sales.orders WHERE note = 'order_date' OR note = \"\"\"\norder_date\"\"\"",
+			refs:    []*bigqueryrestapi.TableReference{ref("orders")},
+			wantErr: "my-project.sales.orders (partitioned on order_date)",
+		},
+		{
+			desc:    "partition column before WHERE",
+			sql:     "SELECT order_date FROM sales.orders WHERE customer_id = 1",
+			refs:    []*bigqueryrestapi.TableReference{ref("orders")},
+			wantErr: "my-project.sales.orders (partitioned on order_date)",
+		},
+		{
+			// the dry run already fails for tables that require a filter
+			desc: "table requiring a partition filter",
+			sql:  "SELECT * FROM sales.visits",
+			refs: []*bigqueryrestapi.TableReference{ref("visits")},
+		},
+		{
+			desc: "not partitioned",
+			sql:  "SELECT * FROM sales.regions",
+			refs: []*bigqueryrestapi.TableReference{ref("regions")},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := bigquerycommon.CheckPartitionFilters(context.Background(), tc.sql, tc.refs, metadata)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if !errors.Is(err, bigquerycommon.ErrMissingPartitionFilter) {
+				t.Fatalf("expected a missing partition filter error, got %v", err)
+			}
+			if !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("expected %q to contain %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestPartitionFilterWarning(t *testing.T) {
+	metadata := func(context.Context, *bigqueryrestapi.TableReference) (*bigqueryapi.TableMetadata, error) {
+		return &bigqueryapi.TableMetadata{TimePartitioning: &bigqueryapi.TimePartitioning{Field: "order_date"}}, nil
+	}
+	refs := []*bigqueryrestapi.TableReference{{ProjectId: "my-project", DatasetId: "sales", TableId: "orders"}}
+	const sql = "SELECT * FROM sales.orders"
+
+	warning, err := bigquerycommon.PartitionFilterWarning(context.Background(), "", sql, refs, metadata)
+	if warning != nil || err != nil {
+		t.Fatalf("expected no check without a mode, got warning %v and error %v", warning, err)
+	}
+	warning, err = bigquerycommon.PartitionFilterWarning(context.Background(), bigquerycommon.PartitionFilterReject, sql, refs, metadata)
+	if warning != nil || !errors.Is(err, bigquerycommon.ErrMissingPartitionFilter) {
+		t.Fatalf("expected the query to be rejected, got warning %v and error %v", warning, err)
+	}
+	warning, err = bigquerycommon.PartitionFilterWarning(context.Background(), bigquerycommon.PartitionFilterWarn, sql, refs, metadata)
+	if err != nil || !errors.Is(warning, bigquerycommon.ErrMissingPartitionFilter) {
+		t.Fatalf("expected a warning, got warning %v and error %v", warning, err)
+	}
+
+	if err := bigquerycommon.ValidatePartitionFilterMode("fail"); err == nil {
+		t.Fatalf("expected an error for an unknown mode")
+	}
+}
+
+// fakeJob records the calls to cancel it.
+type fakeJob struct {
+	cancelled chan struct{}
+}
+
+func (j *fakeJob) Wait(context.Context) (*bigqueryapi.JobStatus, error) {
+	return &bigqueryapi.JobStatus{State: bigqueryapi.Done}, nil
+}
+
+func (j *fakeJob) ID() string {
+	return "my-job"
+}
+
+func (j *fakeJob) Cancel(context.Context) error {
+	close(j.cancelled)
+	return nil
+}
+
+func TestCancelJobOnDone(t *testing.T) {
+	job := &fakeJob{cancelled: make(chan struct{})}
+	ctx, cancel := context.WithCancel(context.Background())
+	stop := bigquerycommon.CancelJobOnDone(ctx, job)
+	defer stop()
+
+	// the client disconnects while the query runs
+	cancel()
+	select {
+	case <-job.cancelled:
+	case <-time.After(time.Second):
+		t.Fatalf("the job was not cancelled after the context was cancelled")
+	}
+}
+
+func TestCancelJobOnDoneStopped(t *testing.T) {
+	job := &fakeJob{cancelled: make(chan struct{})}
+	ctx, cancel := context.WithCancel(context.Background())
+	stop := bigquerycommon.CancelJobOnDone(ctx, job)
+
+	// the query finishes before the request ends
+	stop()
+	cancel()
+	select {
+	case <-job.cancelled:
+		t.Fatalf("a finished job was cancelled")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestWaitForJobProgress(t *testing.T) {
+	var got []string
+	ctx := tools.WithProgress(context.Background(), func(message string) {
+		got = append(got, message)
+	})
+	if _, err := bigquerycommon.WaitForJob(ctx, &fakeJob{}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := []string{"BigQuery job my-job submitted", "BigQuery job my-job complete"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("incorrect progress: diff %v", diff)
+	}
+}
+
+func TestNewQueryStats(t *testing.T) {
+	tcs := []struct {
+		desc   string
+		status *bigqueryapi.JobStatus
+		want   bigquerycommon.QueryStats
+	}{
+		{
+			desc:   "no statistics",
+			status: &bigqueryapi.JobStatus{},
+			want:   bigquerycommon.QueryStats{BIEngineMode: "DISABLED"},
+		},
+		{
+			desc: "without bi engine",
+			status: &bigqueryapi.JobStatus{Statistics: &bigqueryapi.JobStatistics{
+				TotalBytesProcessed: 100,
+				Details:             &bigqueryapi.QueryStatistics{CacheHit: true},
+			}},
+			want: bigquerycommon.QueryStats{TotalBytesProcessed: 100, CacheHit: true, BIEngineMode: "DISABLED"},
+		},
+		{
+			desc: "partially accelerated",
+			status: &bigqueryapi.JobStatus{Statistics: &bigqueryapi.JobStatistics{
+				TotalBytesProcessed: 100,
+				Details: &bigqueryapi.QueryStatistics{BIEngineStatistics: &bigqueryapi.BIEngineStatistics{
+					BIEngineMode:    "PARTIAL",
+					BIEngineReasons: []*bigqueryapi.BIEngineReason{{Code: "INPUT_TOO_LARGE", Message: "Input too large."}},
+				}},
+			}},
+			want: bigquerycommon.QueryStats{TotalBytesProcessed: 100, BIEngineMode: "PARTIAL", BIEngineReasons: []string{"Input too large."}},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := bigquerycommon.NewQueryStats(tc.status)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect stats: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestToTableResult(t *testing.T) {
+	schema := bigqueryapi.Schema{
+		{Name: "name", Type: bigqueryapi.StringFieldType},
+		{Name: "id", Type: bigqueryapi.IntegerFieldType},
+		{Name: "score", Type: bigqueryapi.FloatFieldType},
+	}
+	rows := []any{
+		map[string]any{"id": int64(1), "user_name": "Alice", "score": 0.5},
+		map[string]any{"score": nil, "user_name": "Bob", "id": int64(2)},
+	}
+	aliases := map[string]string{"name": "user_name"}
+	wantColumns := []string{"user_name", "id", "score"}
+	wantRows := [][]any{{"Alice", int64(1), 0.5}, {"Bob", int64(2), nil}}
+	stats := bigquerycommon.QueryStats{TotalBytesProcessed: 100, BIEngineMode: "DISABLED"}
+
+	tcs := []struct {
+		desc string
+		res  any
+		want any
+	}{
+		{
+			desc: "rows",
+			res:  rows,
+			want: bigquerycommon.TableResult{Columns: wantColumns, Rows: wantRows},
+		},
+		{
+			desc: "rows with warnings and stats",
+			res:  bigquerycommon.NewQueryResult(rows, []string{bigquerycommon.RowAccessPolicyWarning}, &stats, "SELECT"),
+			want: bigquerycommon.TableResult{Columns: wantColumns, Rows: wantRows, Warnings: []string{bigquerycommon.RowAccessPolicyWarning}, Stats: &stats},
+		},
+		{
+			desc: "no rows with stats",
+			res:  bigquerycommon.NewQueryResult(nil, nil, &stats, "SELECT"),
+			want: bigquerycommon.TableResult{Columns: wantColumns, Rows: [][]any{}, Stats: &stats},
+		},
+		{
+			desc: "message",
+			res:  tools.ZeroRowsMessage,
+			want: tools.ZeroRowsMessage,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := bigquerycommon.ToTableResult(tc.res, schema, aliases)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect result: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestNormalizeRows(t *testing.T) {
+	// newRows returns rows as read by ReadRows from the client, which loads
+	// REPEATED columns as []bigqueryapi.Value, never nil, and RECORD columns
+	// as map[string]bigqueryapi.Value.
+	newRows := func() []any {
+		return []any{
+			map[string]any{
+				"id":   int64(1),
+				"tags": []bigqueryapi.Value{"a", "b"},
+				"attrs": []bigqueryapi.Value{
+					map[string]bigqueryapi.Value{"key": "x", "values": []bigqueryapi.Value{int64(1)}},
+					map[string]bigqueryapi.Value{"key": "y", "values": []bigqueryapi.Value{}},
+				},
+				"owner": nil,
+			},
+			map[string]any{
+				"id":    int64(2),
+				"tags":  []bigqueryapi.Value{},
+				"attrs": []bigqueryapi.Value{},
+				"owner": map[string]bigqueryapi.Value{"name": "alice", "emails": []bigqueryapi.Value{}},
+			},
+		}
+	}
+	tcs := []struct {
+		emptyArrays string
+		want        []any
+	}{
+		{
+			emptyArrays: "",
+			want: []any{
+				map[string]any{
+					"id":   int64(1),
+					"tags": []any{"a", "b"},
+					"attrs": []any{
+						map[string]any{"key": "x", "values": []any{int64(1)}},
+						map[string]any{"key": "y", "values": []any{}},
+					},
+					"owner": nil,
+				},
+				map[string]any{
+					"id":    int64(2),
+					"tags":  []any{},
+					"attrs": []any{},
+					"owner": map[string]any{"name": "alice", "emails": []any{}},
+				},
+			},
+		},
+		{
+			emptyArrays: bigquerycommon.EmptyArraysNull,
+			want: []any{
+				map[string]any{
+					"id":   int64(1),
+					"tags": []any{"a", "b"},
+					"attrs": []any{
+						map[string]any{"key": "x", "values": []any{int64(1)}},
+						map[string]any{"key": "y", "values": nil},
+					},
+					"owner": nil,
+				},
+				map[string]any{
+					"id":    int64(2),
+					"tags":  nil,
+					"attrs": nil,
+					"owner": map[string]any{"name": "alice", "emails": nil},
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.emptyArrays, func(t *testing.T) {
+			if err := bigquerycommon.ValidateEmptyArrays(tc.emptyArrays); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			got := bigquerycommon.NormalizeRows(newRows(), tc.emptyArrays)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect rows: diff %v", diff)
+			}
+		})
+	}
+
+	err := bigquerycommon.ValidateEmptyArrays("omit")
+	if err == nil || !strings.Contains(err.Error(), `got "omit"`) {
+		t.Fatalf("expected an unknown mode error, got %v", err)
+	}
+}
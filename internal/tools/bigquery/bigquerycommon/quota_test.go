@@ -0,0 +1,169 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bigquerycommon_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	bigqueryapi "cloud.google.com/go/bigquery"
+	"github.com/googleapis/genai-toolbox/internal/log"
+	bigqueryds "github.com/googleapis/genai-toolbox/internal/sources/bigquery"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigquerycommon"
+	"github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigqueryforecast"
+	"github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigquerylistcolumns"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	bigqueryrestapi "google.golang.org/api/bigquery/v2"
+	"google.golang.org/api/option"
+)
+
+// fakeBigQuery answers dry runs as processing bytesPerQuery bytes, and fails
+// every query that is actually run, counting them.
+type fakeBigQuery struct {
+	bytesPerQuery int64
+	mu            sync.Mutex
+	runs          int
+}
+
+func (f *fakeBigQuery) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/jobs") {
+		var job bigqueryrestapi.Job
+		if err := json.NewDecoder(r.Body).Decode(&job); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if job.Configuration != nil && job.Configuration.DryRun {
+			job.Status = &bigqueryrestapi.JobStatus{State: "DONE"}
+			job.Statistics = &bigqueryrestapi.JobStatistics{TotalBytesProcessed: f.bytesPerQuery}
+			_ = json.NewEncoder(w).Encode(job)
+			return
+		}
+	}
+	f.mu.Lock()
+	f.runs++
+	f.mu.Unlock()
+	http.Error(w, `{"error": {"code": 400, "message": "queries aren't run by the fake"}}`, http.StatusBadRequest)
+}
+
+func TestQuotaSharedByToolKinds(t *testing.T) {
+	fake := &fakeBigQuery{bytesPerQuery: 60}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	ctx := context.Background()
+	logger, err := log.NewStdLogger(io.Discard, io.Discard, "info")
+	if err != nil {
+		t.Fatalf("unable to create logger: %s", err)
+	}
+	ctx = util.WithLogger(ctx, logger)
+
+	client, err := bigqueryapi.NewClient(ctx, "my-project", option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("unable to create client: %s", err)
+	}
+	defer client.Close()
+	clientCreator := func(string, bool) (*bigqueryapi.Client, *bigqueryrestapi.Service, error) {
+		return client, nil, nil
+	}
+
+	// The quota of 100 bytes fits the first query of 60 bytes, but not a
+	// second one.
+	source := &bigqueryds.Source{
+		Quota: bigqueryds.NewQuotaTracker(100, time.Hour),
+		Identities: bigqueryds.NewTokenIdentities(func(context.Context, string) (string, time.Time, error) {
+			return "alice@example.com", time.Now().Add(time.Hour), nil
+		}),
+	}
+	allowed := func(string, string) bool { return true }
+	listColumns := bigquerylistcolumns.Tool{
+		UseClientOAuth:   true,
+		ClientCreator:    clientCreator,
+		ReserveQuota:     source.ReserveQuota,
+		IsProjectAllowed: func(string) bool { return true },
+		IsDatasetAllowed: allowed,
+	}
+	forecast := bigqueryforecast.Tool{
+		UseClientOAuth: true,
+		ClientCreator:  clientCreator,
+		ReserveQuota:   source.ReserveQuota,
+	}
+	accessToken := tools.AccessToken("Bearer token")
+
+	_, err = listColumns.Invoke(ctx, tools.ParamValues{
+		{Name: "project", Value: "my-project"},
+		{Name: "dataset", Value: "my_dataset"},
+		{Name: "table", Value: "my_table"},
+	}, accessToken)
+	if err == nil || errors.Is(err, bigqueryds.ErrQuotaExceeded) {
+		t.Fatalf("expected the first query to be run, got %v", err)
+	}
+	if fake.runs != 1 {
+		t.Fatalf("expected the first query to be run once, got %d runs", fake.runs)
+	}
+
+	_, err = forecast.Invoke(ctx, tools.ParamValues{
+		{Name: "history_data", Value: "my-project.my_dataset.my_table"},
+		{Name: "timestamp_col", Value: "ts"},
+		{Name: "data_col", Value: "value"},
+		{Name: "id_cols", Value: []any{}},
+		{Name: "horizon", Value: 10},
+	}, accessToken)
+	if !errors.Is(err, bigqueryds.ErrQuotaExceeded) {
+		t.Fatalf("expected ErrQuotaExceeded for a query of another tool kind, got %v", err)
+	}
+	if fake.runs != 1 {
+		t.Fatalf("expected the query over the quota not to be run, got %d runs", fake.runs)
+	}
+}
+
+func TestNewQuota(t *testing.T) {
+	var reserved int64
+	reserve := func(_ context.Context, tokenString string, bytes int64) error {
+		if tokenString != "token" {
+			return errors.New("unexpected token")
+		}
+		reserved += bytes
+		return nil
+	}
+	ctx := context.Background()
+
+	quota, err := bigquerycommon.NewQuota(reserve, false, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := quota.Reserve(ctx, 10); err != nil || reserved != 0 {
+		t.Fatalf("expected nothing to be reserved without client OAuth, got %d bytes and error %v", reserved, err)
+	}
+
+	if _, err := bigquerycommon.NewQuota(reserve, true, "token"); err == nil {
+		t.Fatalf("expected an error for a malformed access token but got nil")
+	}
+	quota, err = bigquerycommon.NewQuota(reserve, true, "Bearer token")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := quota.Reserve(ctx, 10); err != nil || reserved != 10 {
+		t.Fatalf("expected 10 bytes to be reserved, got %d bytes and error %v", reserved, err)
+	}
+}
@@ -0,0 +1,305 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bigquerydescribeerror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	bigqueryapi "cloud.google.com/go/bigquery"
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	bigqueryds "github.com/googleapis/genai-toolbox/internal/sources/bigquery"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigquerycommon"
+	bigqueryrestapi "google.golang.org/api/bigquery/v2"
+	"google.golang.org/api/googleapi"
+)
+
+const kind string = "bigquery-describe-error"
+const sqlKey string = "sql"
+
+// Error types of a Diagnosis.
+const (
+	ErrorTypeSyntax              = "SYNTAX_ERROR"
+	ErrorTypeUnrecognizedName    = "UNRECOGNIZED_NAME"
+	ErrorTypeFunctionNotFound    = "FUNCTION_NOT_FOUND"
+	ErrorTypeNoMatchingSignature = "NO_MATCHING_SIGNATURE"
+	ErrorTypeNotFound            = "NOT_FOUND"
+	ErrorTypeAccessDenied        = "ACCESS_DENIED"
+	ErrorTypeOther               = "OTHER"
+)
+
+// Fix categories of a Diagnosis.
+const (
+	FixSyntax         = "fix_syntax"
+	FixColumnName     = "check_column_names"
+	FixFunctionName   = "check_function_names"
+	FixArgumentTypes  = "cast_arguments"
+	FixTableReference = "check_table_references"
+	FixPermissions    = "check_permissions"
+	FixReviewMessage  = "review_error_message"
+)
+
+var (
+	// positionRe matches the position BigQuery appends to errors, e.g. "at [1:8]".
+	positionRe = regexp.MustCompile(`\s*at \[(\d+):(\d+)\]$`)
+	// unexpectedRe matches the token of syntax errors such as
+	// `Unexpected identifier "SELEC"` or `Expected end of input but got keyword FROM`.
+	unexpectedRe   = regexp.MustCompile(`(?:Unexpected|but got) (?:identifier|keyword|string literal|integer literal|floating point literal) ("[^"]*"|'[^']*'|\S+)`)
+	unrecognizedRe = regexp.MustCompile(`^Unrecognized name: (\S+?);?(?: Did you mean (\S+?)\?)?$`)
+	functionRe     = regexp.MustCompile(`^Function not found: (\S+?);?(?: Did you mean (\S+?)\?)?$`)
+	notFoundRe     = regexp.MustCompile(`^Not found: (?:Table|Dataset|View|Model|Routine) (\S+)`)
+	accessDeniedRe = regexp.MustCompile(`^Access Denied: (?:Table|Dataset|Project|View) ([^:\s]+(?::[^:\s]+)?)`)
+)
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type compatibleSource interface {
+	BigQueryClient() *bigqueryapi.Client
+	BigQueryRestService() *bigqueryrestapi.Service
+	BigQueryClientCreator() bigqueryds.BigqueryClientCreator
+	UseClientAuthorization() bool
+	BigQueryDefaultLocation() string
+}
+
+// validate compatible sources are still compatible
+var _ compatibleSource = &bigqueryds.Source{}
+
+var compatibleSources = [...]string{bigqueryds.SourceKind}
+
+type Config struct {
+	Name         string   `yaml:"name" validate:"required"`
+	Kind         string   `yaml:"kind" validate:"required"`
+	Source       string   `yaml:"source" validate:"required"`
+	Description  string   `yaml:"description" validate:"required"`
+	AuthRequired []string `yaml:"authRequired"`
+}
+
+// validate interface
+var _ tools.StatementConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+// TakesStatement marks the tool as one that runs the statement given in its
+// "sql" parameter.
+func (cfg Config) TakesStatement() {}
+
+func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	// verify source exists
+	rawS, ok := srcs[cfg.Source]
+	if !ok {
+		return nil, fmt.Errorf("no source named %q configured", cfg.Source)
+	}
+
+	// verify the source is compatible
+	s, ok := rawS.(compatibleSource)
+	if !ok {
+		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
+	}
+
+	sqlParameter := tools.NewStringParameter(sqlKey, "The failing SQL statement to diagnose.")
+	parameters := tools.Parameters{sqlParameter}
+
+	mcpManifest := tools.McpManifest{
+		Name:        cfg.Name,
+		Description: cfg.Description,
+		InputSchema: parameters.McpManifest(),
+	}
+
+	// finish tool setup
+	t := Tool{
+		Name:            cfg.Name,
+		Kind:            kind,
+		Parameters:      parameters,
+		AuthRequired:    cfg.AuthRequired,
+		DefaultLocation: s.BigQueryDefaultLocation(),
+		UseClientOAuth:  s.UseClientAuthorization(),
+		ClientCreator:   s.BigQueryClientCreator(),
+		Client:          s.BigQueryClient(),
+		RestService:     s.BigQueryRestService(),
+		manifest:        tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:     mcpManifest,
+	}
+	return t, nil
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name            string           `yaml:"name"`
+	Kind            string           `yaml:"kind"`
+	AuthRequired    []string         `yaml:"authRequired"`
+	UseClientOAuth  bool             `yaml:"useClientOAuth"`
+	Parameters      tools.Parameters `yaml:"parameters"`
+	DefaultLocation string           `yaml:"defaultLocation"`
+
+	Client        *bigqueryapi.Client
+	RestService   *bigqueryrestapi.Service
+	ClientCreator bigqueryds.BigqueryClientCreator
+	manifest      tools.Manifest
+	mcpManifest   tools.McpManifest
+}
+
+// Diagnosis is the structured description of a BigQuery error.
+type Diagnosis struct {
+	// Valid is true if the statement passed the dry run.
+	Valid bool `json:"valid"`
+	// ErrorType is one of the ErrorType constants.
+	ErrorType string `json:"errorType,omitempty"`
+	// Message is the error message without its position.
+	Message string `json:"message,omitempty"`
+	// Token is the offending token, column, function or table, if known.
+	Token string `json:"token,omitempty"`
+	// Line and Column are the 1-based position of the error, if known.
+	Line   int `json:"line,omitempty"`
+	Column int `json:"column,omitempty"`
+	// DidYouMean is the name BigQuery suggests instead of Token, if any.
+	DidYouMean string `json:"didYouMean,omitempty"`
+	// FixCategory is one of the Fix constants.
+	FixCategory string `json:"fixCategory,omitempty"`
+}
+
+func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
+	sql, ok := params.AsMap()[sqlKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("unable to cast %s parameter %v", sqlKey, params.AsMap()[sqlKey])
+	}
+
+	bqClient := t.Client
+	restService := t.RestService
+	// Initialize new client if using user OAuth token
+	if t.UseClientOAuth {
+		tokenStr, err := accessToken.ParseBearerToken()
+		if err != nil {
+			return nil, fmt.Errorf("error parsing access token: %w", err)
+		}
+		bqClient, restService, err = t.ClientCreator(tokenStr, true)
+		if err != nil {
+			return nil, fmt.Errorf("error creating client from OAuth access token: %w", err)
+		}
+	}
+
+	locations := bigquerycommon.LocationChain{Source: bqClient.Location, Default: t.DefaultLocation}
+	useLegacySql := false
+	job := &bigqueryrestapi.Job{
+		JobReference: &bigqueryrestapi.JobReference{
+			ProjectId: bqClient.Project(),
+			Location:  locations.Explicit(),
+		},
+		Configuration: &bigqueryrestapi.JobConfiguration{
+			DryRun: true,
+			Query: &bigqueryrestapi.JobConfigurationQuery{
+				Query:        sql,
+				UseLegacySql: &useLegacySql,
+			},
+		},
+	}
+	_, err := restService.Jobs.Insert(bqClient.Project(), job).Context(ctx).Do()
+	if err == nil {
+		return Diagnosis{Valid: true}, nil
+	}
+	// only errors reported by BigQuery are diagnosed, others mean the dry run
+	// couldn't be done
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) || apiErr.Code >= 500 {
+		return nil, fmt.Errorf("failed to insert dry run job: %w", err)
+	}
+	return Diagnose(apiErr.Message), nil
+}
+
+// Diagnose parses a BigQuery error message, e.g.
+// `Syntax error: Unexpected identifier "SELEC" at [1:1]`, into a Diagnosis.
+func Diagnose(message string) Diagnosis {
+	d := Diagnosis{ErrorType: ErrorTypeOther, FixCategory: FixReviewMessage}
+
+	msg := strings.TrimSpace(message)
+	if m := positionRe.FindStringSubmatch(msg); m != nil {
+		d.Line, _ = strconv.Atoi(m[1])
+		d.Column, _ = strconv.Atoi(m[2])
+		msg = strings.TrimSpace(msg[:len(msg)-len(m[0])])
+	}
+	d.Message = msg
+
+	switch {
+	case strings.HasPrefix(msg, "Syntax error:"):
+		d.ErrorType, d.FixCategory = ErrorTypeSyntax, FixSyntax
+		if m := unexpectedRe.FindStringSubmatch(msg); m != nil {
+			d.Token = strings.Trim(m[1], `"'`)
+		}
+	case strings.HasPrefix(msg, "Unrecognized name:"):
+		d.ErrorType, d.FixCategory = ErrorTypeUnrecognizedName, FixColumnName
+		if m := unrecognizedRe.FindStringSubmatch(msg); m != nil {
+			d.Token, d.DidYouMean = m[1], m[2]
+		}
+	case strings.HasPrefix(msg, "Function not found:"):
+		d.ErrorType, d.FixCategory = ErrorTypeFunctionNotFound, FixFunctionName
+		if m := functionRe.FindStringSubmatch(msg); m != nil {
+			d.Token, d.DidYouMean = m[1], m[2]
+		}
+	case strings.HasPrefix(msg, "No matching signature"):
+		d.ErrorType, d.FixCategory = ErrorTypeNoMatchingSignature, FixArgumentTypes
+	case strings.HasPrefix(msg, "Not found:"):
+		d.ErrorType, d.FixCategory = ErrorTypeNotFound, FixTableReference
+		if m := notFoundRe.FindStringSubmatch(msg); m != nil {
+			d.Token = m[1]
+		}
+	case strings.HasPrefix(msg, "Access Denied:"):
+		d.ErrorType, d.FixCategory = ErrorTypeAccessDenied, FixPermissions
+		if m := accessDeniedRe.FindStringSubmatch(msg); m != nil {
+			d.Token = m[1]
+		}
+	}
+	return d
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.Parameters, data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
+
+func (t Tool) RequiresClientAuthorization() bool {
+	return t.UseClientOAuth
+}
@@ -0,0 +1,193 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bigquerydescribeerror_test
+
+import (
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigquerydescribeerror"
+)
+
+func TestParseFromYamlBigQueryDescribeError(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		want server.ToolConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			tools:
+				example_tool:
+					kind: bigquery-describe-error
+					source: my-instance
+					description: some description
+			`,
+			want: server.ToolConfigs{
+				"example_tool": tools.StatementLengthConfig{
+					ToolConfig: bigquerydescribeerror.Config{
+						Name:         "example_tool",
+						Kind:         "bigquery-describe-error",
+						Source:       "my-instance",
+						Description:  "some description",
+						AuthRequired: []string{},
+					},
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			// Parse contents
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Tools); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestDiagnose(t *testing.T) {
+	tcs := []struct {
+		desc string
+		in   string
+		want bigquerydescribeerror.Diagnosis
+	}{
+		{
+			desc: "unexpected identifier",
+			in:   `Syntax error: Unexpected identifier "SELEC" at [1:1]`,
+			want: bigquerydescribeerror.Diagnosis{
+				ErrorType:   bigquerydescribeerror.ErrorTypeSyntax,
+				Message:     `Syntax error: Unexpected identifier "SELEC"`,
+				Token:       "SELEC",
+				Line:        1,
+				Column:      1,
+				FixCategory: bigquerydescribeerror.FixSyntax,
+			},
+		},
+		{
+			desc: "unexpected keyword",
+			in:   "Syntax error: Expected end of input but got keyword FROM at [2:15]",
+			want: bigquerydescribeerror.Diagnosis{
+				ErrorType:   bigquerydescribeerror.ErrorTypeSyntax,
+				Message:     "Syntax error: Expected end of input but got keyword FROM",
+				Token:       "FROM",
+				Line:        2,
+				Column:      15,
+				FixCategory: bigquerydescribeerror.FixSyntax,
+			},
+		},
+		{
+			desc: "syntax error without a token",
+			in:   "Syntax error: Unclosed string literal at [1:15]",
+			want: bigquerydescribeerror.Diagnosis{
+				ErrorType:   bigquerydescribeerror.ErrorTypeSyntax,
+				Message:     "Syntax error: Unclosed string literal",
+				Line:        1,
+				Column:      15,
+				FixCategory: bigquerydescribeerror.FixSyntax,
+			},
+		},
+		{
+			desc: "unrecognized name with suggestion",
+			in:   "Unrecognized name: nme; Did you mean name? at [1:8]",
+			want: bigquerydescribeerror.Diagnosis{
+				ErrorType:   bigquerydescribeerror.ErrorTypeUnrecognizedName,
+				Message:     "Unrecognized name: nme; Did you mean name?",
+				Token:       "nme",
+				Line:        1,
+				Column:      8,
+				DidYouMean:  "name",
+				FixCategory: bigquerydescribeerror.FixColumnName,
+			},
+		},
+		{
+			desc: "function not found",
+			in:   "Function not found: CONCATT; Did you mean concat? at [1:8]",
+			want: bigquerydescribeerror.Diagnosis{
+				ErrorType:   bigquerydescribeerror.ErrorTypeFunctionNotFound,
+				Message:     "Function not found: CONCATT; Did you mean concat?",
+				Token:       "CONCATT",
+				Line:        1,
+				Column:      8,
+				DidYouMean:  "concat",
+				FixCategory: bigquerydescribeerror.FixFunctionName,
+			},
+		},
+		{
+			desc: "no matching signature",
+			in:   "No matching signature for operator = for argument types: INT64, STRING. Supported signature: ANY = ANY at [1:30]",
+			want: bigquerydescribeerror.Diagnosis{
+				ErrorType:   bigquerydescribeerror.ErrorTypeNoMatchingSignature,
+				Message:     "No matching signature for operator = for argument types: INT64, STRING. Supported signature: ANY = ANY",
+				Line:        1,
+				Column:      30,
+				FixCategory: bigquerydescribeerror.FixArgumentTypes,
+			},
+		},
+		{
+			desc: "table not found",
+			in:   "Not found: Table my-project:my_dataset.my_table was not found in location US",
+			want: bigquerydescribeerror.Diagnosis{
+				ErrorType:   bigquerydescribeerror.ErrorTypeNotFound,
+				Message:     "Not found: Table my-project:my_dataset.my_table was not found in location US",
+				Token:       "my-project:my_dataset.my_table",
+				FixCategory: bigquerydescribeerror.FixTableReference,
+			},
+		},
+		{
+			desc: "access denied",
+			in:   "Access Denied: Table my-project:my_dataset.my_table: User does not have permission to query table my-project:my_dataset.my_table.",
+			want: bigquerydescribeerror.Diagnosis{
+				ErrorType:   bigquerydescribeerror.ErrorTypeAccessDenied,
+				Message:     "Access Denied: Table my-project:my_dataset.my_table: User does not have permission to query table my-project:my_dataset.my_table.",
+				Token:       "my-project:my_dataset.my_table",
+				FixCategory: bigquerydescribeerror.FixPermissions,
+			},
+		},
+		{
+			desc: "other error",
+			in:   "Resources exceeded during query execution",
+			want: bigquerydescribeerror.Diagnosis{
+				ErrorType:   bigquerydescribeerror.ErrorTypeOther,
+				Message:     "Resources exceeded during query execution",
+				FixCategory: bigquerydescribeerror.FixReviewMessage,
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := bigquerydescribeerror.Diagnose(tc.in)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect diagnosis: diff %v", diff)
+			}
+		})
+	}
+}
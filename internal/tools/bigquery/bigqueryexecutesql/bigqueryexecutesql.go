@@ -18,12 +18,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	bigqueryapi "cloud.google.com/go/bigquery"
 	yaml "github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	bigqueryds "github.com/googleapis/genai-toolbox/internal/sources/bigquery"
 	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigquerycommon"
 	"github.com/googleapis/genai-toolbox/internal/util"
 	bigqueryrestapi "google.golang.org/api/bigquery/v2"
 	"google.golang.org/api/iterator"
@@ -31,6 +35,10 @@ import (
 
 const kind string = "bigquery-execute-sql"
 
+// asyncSubmitTimeout bounds how long an async invocation waits for the job to
+// be accepted by BigQuery. It does not limit the job itself.
+const asyncSubmitTimeout = 30 * time.Second
+
 func init() {
 	if !tools.Register(kind, newConfig) {
 		panic(fmt.Sprintf("tool kind %q already registered", kind))
@@ -47,9 +55,14 @@ func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.T
 
 type compatibleSource interface {
 	BigQueryClient() *bigqueryapi.Client
+	BigQueryQueryTag() string
 	BigQueryRestService() *bigqueryrestapi.Service
 	BigQueryClientCreator() bigqueryds.BigqueryClientCreator
 	UseClientAuthorization() bool
+	ReserveQuota(ctx context.Context, tokenString string, bytes int64) error
+	BigQueryDefaultLocation() string
+	BigQueryAllowedProjects() []string
+	BigQueryClientForProject(projectID, tokenString string) (*bigqueryapi.Client, *bigqueryrestapi.Service, error)
 }
 
 // validate compatible sources are still compatible
@@ -58,20 +71,55 @@ var _ compatibleSource = &bigqueryds.Source{}
 var compatibleSources = [...]string{bigqueryds.SourceKind}
 
 type Config struct {
-	Name         string   `yaml:"name" validate:"required"`
-	Kind         string   `yaml:"kind" validate:"required"`
-	Source       string   `yaml:"source" validate:"required"`
-	Description  string   `yaml:"description" validate:"required"`
-	AuthRequired []string `yaml:"authRequired"`
+	Name                 string   `yaml:"name" validate:"required"`
+	Kind                 string   `yaml:"kind" validate:"required"`
+	Source               string   `yaml:"source" validate:"required"`
+	Description          string   `yaml:"description" validate:"required"`
+	AuthRequired         []string `yaml:"authRequired"`
+	DeniedStatementTypes []string `yaml:"deniedStatementTypes"`
+	ReturnPartialOnError bool     `yaml:"returnPartialOnError"`
+	// ConnectionProperties sets session settings, e.g. time_zone, on each query.
+	ConnectionProperties map[string]string `yaml:"connectionProperties"`
+	// WarnOnRowAccessPolicy adds a warning to the results when a row access
+	// policy filtered the rows the query read.
+	WarnOnRowAccessPolicy bool `yaml:"warnOnRowAccessPolicy"`
+	// ColumnAliases renames result columns, e.g. name to user_name.
+	ColumnAliases map[string]string `yaml:"columnAliases"`
+	// Location runs the queries in the given location, overriding the
+	// location of the source.
+	Location string `yaml:"location"`
+	// RequirePartitionFilter rejects, with "reject", or adds a warning to
+	// the results of, with "warn", queries that scan a partitioned table
+	// without filtering on its partition column.
+	RequirePartitionFilter string `yaml:"requirePartitionFilter"`
+	// IncludeStats returns the rows along with the statistics of the query
+	// job, e.g. the bytes processed and the BI Engine acceleration mode.
+	IncludeStats bool `yaml:"includeStats"`
+	// ArrayRows returns the rows as arrays of values in the order of the
+	// result schema, along with the column names, instead of as objects.
+	ArrayRows bool `yaml:"arrayRows"`
+	// BytesEncoding is the encoding of BYTES values in the results: base64,
+	// the default, hex or utf8.
+	BytesEncoding string `yaml:"bytesEncoding"`
+	// EmptyArrays is how empty REPEATED values are returned: as empty
+	// arrays, with "array", the default, or as null, with "null".
+	EmptyArrays string `yaml:"emptyArrays"`
+	// SingleStatementOnly rejects SQL with more than one statement, so that
+	// each call runs a single operation rather than a script.
+	SingleStatementOnly bool `yaml:"singleStatementOnly"`
 }
 
 // validate interface
-var _ tools.ToolConfig = Config{}
+var _ tools.StatementConfig = Config{}
 
 func (cfg Config) ToolConfigKind() string {
 	return kind
 }
 
+// TakesStatement marks the tool as one that runs the statement given in its
+// "sql" parameter.
+func (cfg Config) TakesStatement() {}
+
 func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
 	// verify source exists
 	rawS, ok := srcs[cfg.Source]
@@ -85,6 +133,27 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
 	}
 
+	connProps, err := bigquerycommon.ConnectionProperties(cfg.ConnectionProperties)
+	if err != nil {
+		return nil, fmt.Errorf("invalid connection properties for tool %q: %w", cfg.Name, err)
+	}
+
+	if err := bigquerycommon.ValidateColumnAliases(cfg.ColumnAliases); err != nil {
+		return nil, fmt.Errorf("invalid column aliases for tool %q: %w", cfg.Name, err)
+	}
+
+	if err := bigquerycommon.ValidatePartitionFilterMode(cfg.RequirePartitionFilter); err != nil {
+		return nil, fmt.Errorf("invalid config for tool %q: %w", cfg.Name, err)
+	}
+
+	if err := bigquerycommon.ValidateBytesEncoding(cfg.BytesEncoding); err != nil {
+		return nil, fmt.Errorf("invalid config for tool %q: %w", cfg.Name, err)
+	}
+
+	if err := bigquerycommon.ValidateEmptyArrays(cfg.EmptyArrays); err != nil {
+		return nil, fmt.Errorf("invalid config for tool %q: %w", cfg.Name, err)
+	}
+
 	sqlParameter := tools.NewStringParameter("sql", "The sql to execute.")
 	dryRunParameter := tools.NewBooleanParameterWithDefault(
 		"dry_run",
@@ -92,7 +161,17 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		"If set to true, the query will be validated and information about the execution "+
 			"will be returned without running the query. Defaults to false.",
 	)
-	parameters := tools.Parameters{sqlParameter, dryRunParameter}
+	asyncParameter := tools.NewBooleanParameterWithDefault(
+		"async",
+		false,
+		"If set to true, the query is submitted as a job and its job ID is returned "+
+			"immediately without waiting for results. Defaults to false.",
+	)
+	parameters := tools.Parameters{sqlParameter, dryRunParameter, asyncParameter}
+	allowedProjects := s.BigQueryAllowedProjects()
+	if len(allowedProjects) > 0 {
+		parameters = append(parameters, bigquerycommon.ProjectParameter(allowedProjects))
+	}
 
 	mcpManifest := tools.McpManifest{
 		Name:        cfg.Name,
@@ -102,16 +181,33 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 
 	// finish tool setup
 	t := Tool{
-		Name:           cfg.Name,
-		Kind:           kind,
-		Parameters:     parameters,
-		AuthRequired:   cfg.AuthRequired,
-		UseClientOAuth: s.UseClientAuthorization(),
-		ClientCreator:  s.BigQueryClientCreator(),
-		Client:         s.BigQueryClient(),
-		RestService:    s.BigQueryRestService(),
-		manifest:       tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
-		mcpManifest:    mcpManifest,
+		Name:                   cfg.Name,
+		Kind:                   kind,
+		Parameters:             parameters,
+		AuthRequired:           cfg.AuthRequired,
+		DeniedStatementTypes:   cfg.DeniedStatementTypes,
+		ReturnPartialOnError:   cfg.ReturnPartialOnError,
+		ConnectionProperties:   connProps,
+		WarnOnRowAccessPolicy:  cfg.WarnOnRowAccessPolicy,
+		ColumnAliases:          cfg.ColumnAliases,
+		Location:               cfg.Location,
+		DefaultLocation:        s.BigQueryDefaultLocation(),
+		MultiProject:           len(allowedProjects) > 0,
+		RequirePartitionFilter: cfg.RequirePartitionFilter,
+		IncludeStats:           cfg.IncludeStats,
+		ArrayRows:              cfg.ArrayRows,
+		BytesEncoding:          cfg.BytesEncoding,
+		EmptyArrays:            cfg.EmptyArrays,
+		SingleStatementOnly:    cfg.SingleStatementOnly,
+		UseClientOAuth:         s.UseClientAuthorization(),
+		ClientCreator:          s.BigQueryClientCreator(),
+		ClientForProject:       s.BigQueryClientForProject,
+		ReserveQuota:           s.ReserveQuota,
+		Client:                 s.BigQueryClient(),
+		QueryTag:               s.BigQueryQueryTag(),
+		RestService:            s.BigQueryRestService(),
+		manifest:               tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:            mcpManifest,
 	}
 	return t, nil
 }
@@ -126,11 +222,43 @@ type Tool struct {
 	UseClientOAuth bool             `yaml:"useClientOAuth"`
 	Parameters     tools.Parameters `yaml:"parameters"`
 
-	Client        *bigqueryapi.Client
-	RestService   *bigqueryrestapi.Service
-	ClientCreator bigqueryds.BigqueryClientCreator
-	manifest      tools.Manifest
-	mcpManifest   tools.McpManifest
+	// DeniedStatementTypes lists dry-run statement types, e.g. DROP_TABLE,
+	// that the tool refuses to execute.
+	DeniedStatementTypes []string `yaml:"deniedStatementTypes"`
+	// ReturnPartialOnError returns the rows read so far, with a warning, when
+	// reading the query results fails part way.
+	ReturnPartialOnError bool `yaml:"returnPartialOnError"`
+	// ConnectionProperties are set on every query the tool runs.
+	ConnectionProperties []*bigqueryapi.ConnectionProperty `yaml:"connectionProperties"`
+	// WarnOnRowAccessPolicy adds a warning to the results when a row access
+	// policy filtered the rows the query read.
+	WarnOnRowAccessPolicy bool `yaml:"warnOnRowAccessPolicy"`
+	// ColumnAliases renames result columns before they are returned.
+	ColumnAliases map[string]string `yaml:"columnAliases"`
+	// Location and DefaultLocation are the tool location and the source
+	// default location of the chain queries resolve their location from.
+	Location        string `yaml:"location"`
+	DefaultLocation string `yaml:"defaultLocation"`
+	// MultiProject is set for sources with allowedProjects, whose queries
+	// run in the project given by the project parameter.
+	MultiProject bool `yaml:"multiProject"`
+	// RequirePartitionFilter is the PartitionFilterReject or
+	// PartitionFilterWarn mode of the partition filter check, if any.
+	RequirePartitionFilter string `yaml:"requirePartitionFilter"`
+	IncludeStats           bool   `yaml:"includeStats"`
+	ArrayRows              bool   `yaml:"arrayRows"`
+	BytesEncoding          string `yaml:"bytesEncoding"`
+	EmptyArrays            string `yaml:"emptyArrays"`
+	SingleStatementOnly    bool   `yaml:"singleStatementOnly"`
+
+	Client           *bigqueryapi.Client
+	QueryTag         string
+	RestService      *bigqueryrestapi.Service
+	ClientCreator    bigqueryds.BigqueryClientCreator
+	ClientForProject func(projectID, tokenString string) (*bigqueryapi.Client, *bigqueryrestapi.Service, error)
+	ReserveQuota     bigquerycommon.ReserveQuotaFunc
+	manifest         tools.Manifest
+	mcpManifest      tools.McpManifest
 }
 
 func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
@@ -143,26 +271,49 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	if !ok {
 		return nil, fmt.Errorf("unable to cast dry_run parameter %s", paramsMap["dry_run"])
 	}
+	async, ok := paramsMap["async"].(bool)
+	if !ok {
+		return nil, fmt.Errorf("unable to cast async parameter %s", paramsMap["async"])
+	}
+
+	if t.SingleStatementOnly {
+		if n := CountStatements(sql); n > 1 {
+			return nil, fmt.Errorf("tool %q only runs a single statement, got %d statements", t.Name, n)
+		}
+	}
 
 	bqClient := t.Client
 	restService := t.RestService
 
 	var err error
 	// Initialize new client if using user OAuth token
+	var tokenStr string
 	if t.UseClientOAuth {
-		tokenStr, err := accessToken.ParseBearerToken()
+		tokenStr, err = accessToken.ParseBearerToken()
 		if err != nil {
 			return nil, fmt.Errorf("error parsing access token: %w", err)
 		}
+	}
+	if project, ok := paramsMap[bigquerycommon.ProjectKey].(string); ok && t.MultiProject {
+		// Sources with allowedProjects run the query in the requested project.
+		bqClient, restService, err = t.ClientForProject(project, tokenStr)
+		if err != nil {
+			return nil, err
+		}
+	} else if t.UseClientOAuth {
 		bqClient, restService, err = t.ClientCreator(tokenStr, true)
 		if err != nil {
 			return nil, fmt.Errorf("error creating client from OAuth access token: %w", err)
 		}
 	}
 
-	dryRunJob, err := dryRunQuery(ctx, restService, bqClient.Project(), bqClient.Location, sql)
+	locations := bigquerycommon.LocationChain{Tool: t.Location, Source: bqClient.Location, Default: t.DefaultLocation}
+	dryRunJob, err := bigquerycommon.DryRunQuery(ctx, restService, bqClient.Project(), locations.Explicit(), sql, nil, t.ConnectionProperties)
 	if err != nil {
-		return nil, fmt.Errorf("query validation failed during dry run: %w", err)
+		return nil, fmt.Errorf("query validation failed during dry run: %w", locations.WrapError(err))
+	}
+	if dryRunJob.JobReference != nil {
+		locations.Detected = dryRunJob.JobReference.Location
 	}
 
 	if dryRun {
@@ -179,54 +330,86 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 
 	statementType := dryRunJob.Statistics.Query.StatementType
 	// JobStatistics.QueryStatistics.StatementType
+	if t.isStatementTypeDenied(statementType) {
+		return nil, fmt.Errorf("statement type %q is not allowed by tool %q", statementType, t.Name)
+	}
+	partitionErr, err := bigquerycommon.PartitionFilterWarning(ctx, t.RequirePartitionFilter, sql, dryRunJob.Statistics.Query.ReferencedTables, bigquerycommon.ClientTableMetadata(bqClient))
+	if err != nil {
+		return nil, err
+	}
+	// Count the query against the user's quota before running it.
+	quota, err := bigquerycommon.NewQuota(t.ReserveQuota, t.UseClientOAuth, accessToken)
+	if err != nil {
+		return nil, err
+	}
+	if err := quota.Reserve(ctx, dryRunJob.Statistics.TotalBytesProcessed); err != nil {
+		return nil, err
+	}
 	query := bqClient.Query(sql)
-	query.Location = bqClient.Location
+	bigquerycommon.SetQueryTag(query, t.QueryTag)
+	query.Location, _ = locations.Resolve()
+	query.ConnectionProperties = t.ConnectionProperties
 
-	// Log the query executed for debugging.
+	// Log the query executed for debugging. The statement may hold sensitive
+	// values, so only its type and length are logged.
 	logger, err := util.LoggerFromContext(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("error getting logger: %s", err)
 	}
-	logger.DebugContext(ctx, "executing `%s` tool query: %s", kind, sql)
+	logger.DebugContext(ctx, "executing `%s` tool query: %s statement of %d characters", kind, statementType, len(sql))
+
+	if async {
+		res, err := submitQuery(ctx, query)
+		if err != nil {
+			return nil, locations.WrapError(err)
+		}
+		return res, nil
+	}
+
+	// DML statements don't return rows, so report how many rows they changed.
+	switch statementType {
+	case "INSERT", "UPDATE", "DELETE", "MERGE":
+		res, err := runDMLQuery(ctx, query)
+		if err != nil {
+			return nil, locations.WrapError(err)
+		}
+		return res, nil
+	}
 
 	// This block handles SELECT statements, which return a row set.
 	// We iterate through the results, convert each row into a map of
 	// column names to values, and return the collection of rows.
-	var out []any
-	it, err := query.Read(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("unable to execute query: %w", err)
-	}
-	for {
-		var row map[string]bigqueryapi.Value
-		err = it.Next(&row)
-		if err == iterator.Done {
-			break
-		}
+	var it *bigqueryapi.RowIterator
+	if statementType == "SCRIPT" {
+		// Scripts run their statements as child jobs, so the rows live on
+		// the child job of the last SELECT rather than on the script itself.
+		it, err = readScriptResults(ctx, query)
 		if err != nil {
-			return nil, fmt.Errorf("unable to iterate through query results: %w", err)
+			return nil, locations.WrapError(err)
 		}
-		vMap := make(map[string]any)
-		for key, value := range row {
-			vMap[key] = value
+		if it == nil {
+			return tools.NoContentMessage, nil
+		}
+	} else {
+		it, err = bigquerycommon.ReadQueryResults(ctx, query)
+		if err != nil {
+			return nil, locations.WrapError(err)
 		}
-		out = append(out, vMap)
-	}
-	// If the query returned any rows, return them directly.
-	if len(out) > 0 {
-		return out, nil
 	}
+	return bigquerycommon.ReadResults(ctx, restService, it, statementType, partitionErr, t.resultOptions())
+}
 
-	// This handles the standard case for a SELECT query that successfully
-	// executes but returns zero rows.
-	if statementType == "SELECT" {
-		return "The query returned 0 rows.", nil
+// resultOptions returns the settings of the tool that shape its results.
+func (t Tool) resultOptions() bigquerycommon.ResultOptions {
+	return bigquerycommon.ResultOptions{
+		ColumnAliases:         t.ColumnAliases,
+		BytesEncoding:         t.BytesEncoding,
+		EmptyArrays:           t.EmptyArrays,
+		ReturnPartialOnError:  t.ReturnPartialOnError,
+		WarnOnRowAccessPolicy: t.WarnOnRowAccessPolicy,
+		IncludeStats:          t.IncludeStats,
+		ArrayRows:             t.ArrayRows,
 	}
-	// This is the fallback for a successful query that doesn't return content.
-	// In most cases, this will be for DML/DDL statements like INSERT, UPDATE, CREATE, etc.
-	// However, it is also possible that this was a query that was expected to return rows
-	// but returned none, a case that we cannot distinguish here.
-	return "Query executed successfully and returned no content.", nil
 }
 
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
@@ -249,26 +432,143 @@ func (t Tool) RequiresClientAuthorization() bool {
 	return t.UseClientOAuth
 }
 
-// dryRunQuery performs a dry run of the SQL query to validate it and get metadata.
-func dryRunQuery(ctx context.Context, restService *bigqueryrestapi.Service, projectID string, location string, sql string) (*bigqueryrestapi.Job, error) {
-	useLegacySql := false
-	jobToInsert := &bigqueryrestapi.Job{
-		JobReference: &bigqueryrestapi.JobReference{
-			ProjectId: projectID,
-			Location:  location,
-		},
-		Configuration: &bigqueryrestapi.JobConfiguration{
-			DryRun: true,
-			Query: &bigqueryrestapi.JobConfigurationQuery{
-				Query:        sql,
-				UseLegacySql: &useLegacySql,
-			},
-		},
-	}
-
-	insertResponse, err := restService.Jobs.Insert(projectID, jobToInsert).Context(ctx).Do()
+// isStatementTypeDenied reports whether the tool is configured to reject the
+// given dry-run statement type. Statement types are compared case-insensitively.
+func (t Tool) isStatementTypeDenied(statementType string) bool {
+	return slices.ContainsFunc(t.DeniedStatementTypes, func(denied string) bool {
+		return strings.EqualFold(denied, statementType)
+	})
+}
+
+// CountStatements returns the number of statements in sql, the parts separated
+// by semicolons that hold more than whitespace and comments. Semicolons in
+// quoted strings, quoted identifiers and comments don't separate statements.
+func CountStatements(sql string) int {
+	count := 0
+	content := false
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case c == ';':
+			if content {
+				count++
+			}
+			content = false
+		case c == '-' && strings.HasPrefix(sql[i:], "--"), c == '#':
+			if end := strings.IndexByte(sql[i:], '\n'); end >= 0 {
+				i += end
+			} else {
+				i = len(sql)
+			}
+		case c == '/' && strings.HasPrefix(sql[i:], "/*"):
+			if end := strings.Index(sql[i+2:], "*/"); end >= 0 {
+				i += end + 3
+			} else {
+				i = len(sql)
+			}
+		case c == '\'' || c == '"' || c == '`':
+			content = true
+			quote := string(c)
+			if c != '`' && strings.HasPrefix(sql[i:], strings.Repeat(quote, 3)) {
+				quote = strings.Repeat(quote, 3)
+			}
+			i = skipQuoted(sql, i+len(quote), quote)
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+		default:
+			content = true
+		}
+	}
+	if content {
+		count++
+	}
+	return count
+}
+
+// skipQuoted returns the index of the last character of the quoted string or
+// identifier whose content starts at i, skipping backslash escapes.
+func skipQuoted(sql string, i int, quote string) int {
+	for ; i < len(sql); i++ {
+		if sql[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.HasPrefix(sql[i:], quote) {
+			return i + len(quote) - 1
+		}
+	}
+	return len(sql)
+}
+
+// readScriptResults runs a multi-statement script and returns a row iterator
+// over the results of its last SELECT statement. Child jobs are listed newest
+// first, so the first SELECT found is the final one. A nil iterator is
+// returned when the script contains no SELECT statement.
+func readScriptResults(ctx context.Context, query *bigqueryapi.Query) (*bigqueryapi.RowIterator, error) {
+	job, err := query.Run(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to execute script: %w", err)
+	}
+	status, err := bigquerycommon.WaitForJob(ctx, job)
+	if err != nil {
+		return nil, fmt.Errorf("unable to wait for script to complete: %w", err)
+	}
+	if err := bigquerycommon.JobError(status.Err(), status.Errors); err != nil {
+		return nil, fmt.Errorf("script execution failed: %w", err)
+	}
+
+	children := job.Children(ctx)
+	for {
+		child, err := children.Next()
+		if err == iterator.Done {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to list script child jobs: %w", err)
+		}
+		stats, ok := child.LastStatus().Statistics.Details.(*bigqueryapi.QueryStatistics)
+		if !ok || stats.StatementType != "SELECT" {
+			continue
+		}
+		it, err := child.Read(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read script results: %w", err)
+		}
+		return it, nil
+	}
+}
+
+// runDMLQuery runs a DML statement and returns the number of rows it affected.
+func runDMLQuery(ctx context.Context, query *bigqueryapi.Query) (any, error) {
+	job, err := query.Run(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to execute query: %w", err)
+	}
+	status, err := bigquerycommon.WaitForJob(ctx, job)
+	if err != nil {
+		return nil, fmt.Errorf("unable to wait for query to complete: %w", err)
+	}
+	if err := bigquerycommon.JobError(status.Err(), status.Errors); err != nil {
+		return nil, fmt.Errorf("query execution failed: %w", err)
+	}
+	stats, ok := status.Statistics.Details.(*bigqueryapi.QueryStatistics)
+	if !ok {
+		return tools.NoContentMessage, nil
+	}
+	return map[string]any{"affectedRows": stats.NumDMLAffectedRows}, nil
+}
+
+// submitQuery starts the query as a job without waiting for it to finish and
+// returns the information needed to look the job up later.
+func submitQuery(ctx context.Context, query *bigqueryapi.Query) (any, error) {
+	ctx, cancel := context.WithTimeout(ctx, asyncSubmitTimeout)
+	defer cancel()
+	job, err := query.Run(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to insert dry run job: %w", err)
+		return nil, fmt.Errorf("unable to submit query: %w", err)
 	}
-	return insertResponse, nil
+	return map[string]any{
+		"jobId":     job.ID(),
+		"projectId": job.ProjectID(),
+		"location":  job.Location(),
+	}, nil
 }
@@ -21,6 +21,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/genai-toolbox/internal/server"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigqueryexecutesql"
 )
 
@@ -44,12 +45,246 @@ func TestParseFromYamlBigQueryExecuteSql(t *testing.T) {
 					description: some description
 			`,
 			want: server.ToolConfigs{
-				"example_tool": bigqueryexecutesql.Config{
-					Name:         "example_tool",
-					Kind:         "bigquery-execute-sql",
-					Source:       "my-instance",
-					Description:  "some description",
-					AuthRequired: []string{},
+				"example_tool": tools.StatementLengthConfig{
+					ToolConfig: bigqueryexecutesql.Config{
+						Name:         "example_tool",
+						Kind:         "bigquery-execute-sql",
+						Source:       "my-instance",
+						Description:  "some description",
+						AuthRequired: []string{},
+					},
+				},
+			},
+		},
+		{
+			desc: "with denied statement types",
+			in: `
+			tools:
+				example_tool:
+					kind: bigquery-execute-sql
+					source: my-instance
+					description: some description
+					deniedStatementTypes:
+						- DROP_TABLE
+						- EXPORT_DATA
+			`,
+			want: server.ToolConfigs{
+				"example_tool": tools.StatementLengthConfig{
+					ToolConfig: bigqueryexecutesql.Config{
+						Name:                 "example_tool",
+						Kind:                 "bigquery-execute-sql",
+						Source:               "my-instance",
+						Description:          "some description",
+						AuthRequired:         []string{},
+						DeniedStatementTypes: []string{"DROP_TABLE", "EXPORT_DATA"},
+					},
+				},
+			},
+		},
+		{
+			desc: "with partial results",
+			in: `
+			tools:
+				example_tool:
+					kind: bigquery-execute-sql
+					source: my-instance
+					description: some description
+					returnPartialOnError: true
+			`,
+			want: server.ToolConfigs{
+				"example_tool": tools.StatementLengthConfig{
+					ToolConfig: bigqueryexecutesql.Config{
+						Name:                 "example_tool",
+						Kind:                 "bigquery-execute-sql",
+						Source:               "my-instance",
+						Description:          "some description",
+						AuthRequired:         []string{},
+						ReturnPartialOnError: true,
+					},
+				},
+			},
+		},
+		{
+			desc: "with row access policy warning",
+			in: `
+			tools:
+				example_tool:
+					kind: bigquery-execute-sql
+					source: my-instance
+					description: some description
+					warnOnRowAccessPolicy: true
+			`,
+			want: server.ToolConfigs{
+				"example_tool": tools.StatementLengthConfig{
+					ToolConfig: bigqueryexecutesql.Config{
+						Name:                  "example_tool",
+						Kind:                  "bigquery-execute-sql",
+						Source:                "my-instance",
+						Description:           "some description",
+						AuthRequired:          []string{},
+						WarnOnRowAccessPolicy: true,
+					},
+				},
+			},
+		},
+		{
+			desc: "with location",
+			in: `
+			tools:
+				example_tool:
+					kind: bigquery-execute-sql
+					source: my-instance
+					description: some description
+					location: asia-northeast1
+			`,
+			want: server.ToolConfigs{
+				"example_tool": tools.StatementLengthConfig{
+					ToolConfig: bigqueryexecutesql.Config{
+						Name:         "example_tool",
+						Kind:         "bigquery-execute-sql",
+						Source:       "my-instance",
+						Description:  "some description",
+						AuthRequired: []string{},
+						Location:     "asia-northeast1",
+					},
+				},
+			},
+		},
+		{
+			desc: "with partition filter required",
+			in: `
+			tools:
+				example_tool:
+					kind: bigquery-execute-sql
+					source: my-instance
+					description: some description
+					requirePartitionFilter: reject
+			`,
+			want: server.ToolConfigs{
+				"example_tool": tools.StatementLengthConfig{
+					ToolConfig: bigqueryexecutesql.Config{
+						Name:                   "example_tool",
+						Kind:                   "bigquery-execute-sql",
+						Source:                 "my-instance",
+						Description:            "some description",
+						AuthRequired:           []string{},
+						RequirePartitionFilter: "reject",
+					},
+				},
+			},
+		},
+		{
+			desc: "with stats",
+			in: `
+			tools:
+				example_tool:
+					kind: bigquery-execute-sql
+					source: my-instance
+					description: some description
+					includeStats: true
+			`,
+			want: server.ToolConfigs{
+				"example_tool": tools.StatementLengthConfig{
+					ToolConfig: bigqueryexecutesql.Config{
+						Name:         "example_tool",
+						Kind:         "bigquery-execute-sql",
+						Source:       "my-instance",
+						Description:  "some description",
+						AuthRequired: []string{},
+						IncludeStats: true,
+					},
+				},
+			},
+		},
+		{
+			desc: "with array rows",
+			in: `
+			tools:
+				example_tool:
+					kind: bigquery-execute-sql
+					source: my-instance
+					description: some description
+					arrayRows: true
+			`,
+			want: server.ToolConfigs{
+				"example_tool": tools.StatementLengthConfig{
+					ToolConfig: bigqueryexecutesql.Config{
+						Name:         "example_tool",
+						Kind:         "bigquery-execute-sql",
+						Source:       "my-instance",
+						Description:  "some description",
+						AuthRequired: []string{},
+						ArrayRows:    true,
+					},
+				},
+			},
+		},
+		{
+			desc: "with bytes encoding",
+			in: `
+			tools:
+				example_tool:
+					kind: bigquery-execute-sql
+					source: my-instance
+					description: some description
+					bytesEncoding: hex
+			`,
+			want: server.ToolConfigs{
+				"example_tool": tools.StatementLengthConfig{
+					ToolConfig: bigqueryexecutesql.Config{
+						Name:          "example_tool",
+						Kind:          "bigquery-execute-sql",
+						Source:        "my-instance",
+						Description:   "some description",
+						AuthRequired:  []string{},
+						BytesEncoding: "hex",
+					},
+				},
+			},
+		},
+		{
+			desc: "with empty arrays",
+			in: `
+			tools:
+				example_tool:
+					kind: bigquery-execute-sql
+					source: my-instance
+					description: some description
+					emptyArrays: "null"
+			`,
+			want: server.ToolConfigs{
+				"example_tool": tools.StatementLengthConfig{
+					ToolConfig: bigqueryexecutesql.Config{
+						Name:         "example_tool",
+						Kind:         "bigquery-execute-sql",
+						Source:       "my-instance",
+						Description:  "some description",
+						AuthRequired: []string{},
+						EmptyArrays:  "null",
+					},
+				},
+			},
+		},
+		{
+			desc: "with single statement only",
+			in: `
+			tools:
+				example_tool:
+					kind: bigquery-execute-sql
+					source: my-instance
+					description: some description
+					singleStatementOnly: true
+			`,
+			want: server.ToolConfigs{
+				"example_tool": tools.StatementLengthConfig{
+					ToolConfig: bigqueryexecutesql.Config{
+						Name:                "example_tool",
+						Kind:                "bigquery-execute-sql",
+						Source:              "my-instance",
+						Description:         "some description",
+						AuthRequired:        []string{},
+						SingleStatementOnly: true,
+					},
 				},
 			},
 		},
@@ -71,3 +306,33 @@ func TestParseFromYamlBigQueryExecuteSql(t *testing.T) {
 	}
 
 }
+
+func TestCountStatements(t *testing.T) {
+	tcs := []struct {
+		desc string
+		sql  string
+		want int
+	}{
+		{desc: "single statement", sql: "SELECT 1", want: 1},
+		{desc: "trailing semicolon", sql: "SELECT 1;", want: 1},
+		{desc: "trailing semicolons and whitespace", sql: "SELECT 1 ;\n ;  ", want: 1},
+		{desc: "two statements", sql: "SELECT 1; SELECT 2", want: 2},
+		{desc: "two statements with trailing semicolon", sql: "DELETE FROM t WHERE true; DROP TABLE t;", want: 2},
+		{desc: "trailing comment", sql: "SELECT 1; -- DROP TABLE t", want: 1},
+		{desc: "trailing hash comment", sql: "SELECT 1; # DROP TABLE t", want: 1},
+		{desc: "trailing block comment", sql: "SELECT 1; /* DROP TABLE t; */", want: 1},
+		{desc: "statement after a comment", sql: "SELECT 1; -- comment\nSELECT 2", want: 2},
+		{desc: "semicolon in a string", sql: `SELECT 'a;b', "c;d"`, want: 1},
+		{desc: "escaped quote in a string", sql: `SELECT 'it\'s; fine'`, want: 1},
+		{desc: "semicolon in a triple-quoted string", sql: `SELECT '''a';b'''`, want: 1},
+		{desc: "semicolon in a quoted identifier", sql: "SELECT * FROM `my;table`", want: 1},
+		{desc: "empty", sql: " ; ", want: 0},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := bigqueryexecutesql.CountStatements(tc.sql); got != tc.want {
+				t.Fatalf("incorrect number of statements in %q: got %d, want %d", tc.sql, got, tc.want)
+			}
+		})
+	}
+}
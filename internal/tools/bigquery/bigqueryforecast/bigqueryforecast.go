@@ -24,6 +24,7 @@ import (
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	bigqueryds "github.com/googleapis/genai-toolbox/internal/sources/bigquery"
 	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigquerycommon"
 	"github.com/googleapis/genai-toolbox/internal/util"
 	bigqueryrestapi "google.golang.org/api/bigquery/v2"
 	"google.golang.org/api/iterator"
@@ -47,9 +48,13 @@ func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.T
 
 type compatibleSource interface {
 	BigQueryClient() *bigqueryapi.Client
+	BigQueryQueryTag() string
 	BigQueryRestService() *bigqueryrestapi.Service
 	BigQueryClientCreator() bigqueryds.BigqueryClientCreator
 	UseClientAuthorization() bool
+	BigQueryAllowedProjects() []string
+	BigQueryClientForProject(projectID, tokenString string) (*bigqueryapi.Client, *bigqueryrestapi.Service, error)
+	ReserveQuota(ctx context.Context, tokenString string, bytes int64) error
 }
 
 // validate compatible sources are still compatible
@@ -97,6 +102,12 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 	horizonParameter := tools.NewIntParameterWithDefault("horizon", 10, "The number of forecasting steps.")
 	parameters := tools.Parameters{historyDataParameter,
 		timestampColumnNameParameter, dataColumnNameParameter, idColumnNameParameter, horizonParameter}
+	// On sources with allowedProjects, the project parameter selects the
+	// project to run in.
+	allowedProjects := s.BigQueryAllowedProjects()
+	if len(allowedProjects) > 0 {
+		parameters = append(parameters, bigquerycommon.ProjectParameter(allowedProjects))
+	}
 
 	mcpManifest := tools.McpManifest{
 		Name:        cfg.Name,
@@ -106,16 +117,20 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 
 	// finish tool setup
 	t := Tool{
-		Name:           cfg.Name,
-		Kind:           kind,
-		Parameters:     parameters,
-		AuthRequired:   cfg.AuthRequired,
-		UseClientOAuth: s.UseClientAuthorization(),
-		ClientCreator:  s.BigQueryClientCreator(),
-		Client:         s.BigQueryClient(),
-		RestService:    s.BigQueryRestService(),
-		manifest:       tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
-		mcpManifest:    mcpManifest,
+		Name:             cfg.Name,
+		Kind:             kind,
+		Parameters:       parameters,
+		AuthRequired:     cfg.AuthRequired,
+		UseClientOAuth:   s.UseClientAuthorization(),
+		ClientCreator:    s.BigQueryClientCreator(),
+		Client:           s.BigQueryClient(),
+		QueryTag:         s.BigQueryQueryTag(),
+		MultiProject:     len(allowedProjects) > 0,
+		ClientForProject: s.BigQueryClientForProject,
+		ReserveQuota:     s.ReserveQuota,
+		RestService:      s.BigQueryRestService(),
+		manifest:         tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:      mcpManifest,
 	}
 	return t, nil
 }
@@ -130,11 +145,15 @@ type Tool struct {
 	UseClientOAuth bool             `yaml:"useClientOAuth"`
 	Parameters     tools.Parameters `yaml:"parameters"`
 
-	Client        *bigqueryapi.Client
-	RestService   *bigqueryrestapi.Service
-	ClientCreator bigqueryds.BigqueryClientCreator
-	manifest      tools.Manifest
-	mcpManifest   tools.McpManifest
+	Client           *bigqueryapi.Client
+	QueryTag         string
+	MultiProject     bool
+	ClientForProject bigquerycommon.ClientForProjectFunc
+	ReserveQuota     bigquerycommon.ReserveQuotaFunc
+	RestService      *bigqueryrestapi.Service
+	ClientCreator    bigqueryds.BigqueryClientCreator
+	manifest         tools.Manifest
+	mcpManifest      tools.McpManifest
 }
 
 func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
@@ -197,8 +216,15 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	bqClient := t.Client
 	var err error
 
-	// Initialize new client if using user OAuth token
-	if t.UseClientOAuth {
+	// Use the clients of the project, or initialize new ones if using user
+	// OAuth token
+	if project, ok := paramsMap[bigquerycommon.ProjectKey].(string); ok && t.MultiProject {
+		// Sources with allowedProjects run the query in the requested project.
+		bqClient, _, err = bigquerycommon.ProjectClient(t.ClientForProject, project, t.UseClientOAuth, accessToken)
+		if err != nil {
+			return nil, err
+		}
+	} else if t.UseClientOAuth {
 		tokenStr, err := accessToken.ParseBearerToken()
 		if err != nil {
 			return nil, fmt.Errorf("error parsing access token: %w", err)
@@ -211,23 +237,40 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 
 	// JobStatistics.QueryStatistics.StatementType
 	query := bqClient.Query(sql)
+	bigquerycommon.SetQueryTag(query, t.QueryTag)
 	query.Location = bqClient.Location
 
-	// Log the query executed for debugging.
+	// Log the query executed for debugging. The statement may hold sensitive
+	// values, so only its type and length are logged.
 	logger, err := util.LoggerFromContext(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("error getting logger: %s", err)
 	}
-	logger.DebugContext(ctx, "executing `%s` tool query: %s", kind, sql)
+	logger.DebugContext(ctx, "executing `%s` tool query: %s", kind, tools.DescribeStatement(sql))
 
 	// This block handles SELECT statements, which return a row set.
 	// We iterate through the results, convert each row into a map of
 	// column names to values, and return the collection of rows.
+	quota, err := bigquerycommon.NewQuota(t.ReserveQuota, t.UseClientOAuth, accessToken)
+	if err != nil {
+		return nil, err
+	}
 	var out []any
-	it, err := query.Read(ctx)
+	job, err := bigquerycommon.RunQuery(ctx, query, quota)
 	if err != nil {
 		return nil, fmt.Errorf("unable to execute query: %w", err)
 	}
+	status, err := bigquerycommon.WaitForJob(ctx, job)
+	if err != nil {
+		return nil, fmt.Errorf("unable to wait for query to complete: %w", err)
+	}
+	if err := bigquerycommon.JobError(status.Err(), status.Errors); err != nil {
+		return nil, fmt.Errorf("unable to execute query: %w", err)
+	}
+	it, err := job.Read(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read query results: %w", err)
+	}
 	for {
 		var row map[string]bigqueryapi.Value
 		err = it.Next(&row)
@@ -249,7 +292,7 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	}
 
 	// This handles the standard case for a SELECT query that successfully
-	return "The query returned 0 rows.", nil
+	return tools.ZeroRowsMessage, nil
 }
 
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
@@ -0,0 +1,352 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bigquerygenerateembedding
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	bigqueryapi "cloud.google.com/go/bigquery"
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	bigqueryds "github.com/googleapis/genai-toolbox/internal/sources/bigquery"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigquerycommon"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	bigqueryrestapi "google.golang.org/api/bigquery/v2"
+	"google.golang.org/api/iterator"
+)
+
+const kind string = "bigquery-generate-embedding"
+const modelKey string = "model"
+const textKey string = "text"
+const inputDataKey string = "input_data"
+
+// modelReferenceRegex matches `project.dataset.model` and `dataset.model`.
+var modelReferenceRegex = regexp.MustCompile(`^(?:([a-z][a-z0-9-]{4,28}[a-z0-9])\.)?([A-Za-z0-9_]+)\.([A-Za-z0-9_]+)$`)
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type compatibleSource interface {
+	BigQueryClient() *bigqueryapi.Client
+	BigQueryQueryTag() string
+	BigQueryRestService() *bigqueryrestapi.Service
+	BigQueryClientCreator() bigqueryds.BigqueryClientCreator
+	UseClientAuthorization() bool
+	BigQueryAllowedDatasets() []string
+	IsDatasetAllowed(projectID, datasetID string) bool
+	BigQueryAllowedProjects() []string
+	BigQueryClientForProject(projectID, tokenString string) (*bigqueryapi.Client, *bigqueryrestapi.Service, error)
+	ReserveQuota(ctx context.Context, tokenString string, bytes int64) error
+}
+
+// validate compatible sources are still compatible
+var _ compatibleSource = &bigqueryds.Source{}
+
+var compatibleSources = [...]string{bigqueryds.SourceKind}
+
+type Config struct {
+	Name         string   `yaml:"name" validate:"required"`
+	Kind         string   `yaml:"kind" validate:"required"`
+	Source       string   `yaml:"source" validate:"required"`
+	Description  string   `yaml:"description" validate:"required"`
+	AuthRequired []string `yaml:"authRequired"`
+}
+
+// validate interface
+var _ tools.ToolConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	// verify source exists
+	rawS, ok := srcs[cfg.Source]
+	if !ok {
+		return nil, fmt.Errorf("no source named %q configured", cfg.Source)
+	}
+
+	// verify the source is compatible
+	s, ok := rawS.(compatibleSource)
+	if !ok {
+		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
+	}
+
+	modelParameter := tools.NewStringParameter(modelKey,
+		"The remote embedding model to use, as `project.dataset.model` or `dataset.model`.")
+	textParameter := tools.NewStringParameterWithDefault(textKey, "",
+		"The text to generate an embedding for. Exactly one of `text` and `input_data` must be set.")
+	inputDataParameter := tools.NewStringParameterWithDefault(inputDataKey, "",
+		"The table id or the query of the data to generate embeddings for. The text to embed must be in a column named `content`. "+
+			"Exactly one of `text` and `input_data` must be set.")
+	parameters := tools.Parameters{modelParameter, textParameter, inputDataParameter}
+	// On sources with allowedProjects, the project parameter selects the
+	// project to run in.
+	allowedProjects := s.BigQueryAllowedProjects()
+	if len(allowedProjects) > 0 {
+		parameters = append(parameters, bigquerycommon.ProjectParameter(allowedProjects))
+	}
+
+	mcpManifest := tools.McpManifest{
+		Name:        cfg.Name,
+		Description: cfg.Description,
+		InputSchema: parameters.McpManifest(),
+	}
+
+	// finish tool setup
+	t := Tool{
+		Name:                   cfg.Name,
+		Kind:                   kind,
+		Parameters:             parameters,
+		AuthRequired:           cfg.AuthRequired,
+		UseClientOAuth:         s.UseClientAuthorization(),
+		ClientCreator:          s.BigQueryClientCreator(),
+		Client:                 s.BigQueryClient(),
+		QueryTag:               s.BigQueryQueryTag(),
+		MultiProject:           len(allowedProjects) > 0,
+		ClientForProject:       s.BigQueryClientForProject,
+		ReserveQuota:           s.ReserveQuota,
+		RestService:            s.BigQueryRestService(),
+		IsDatasetAllowed:       s.IsDatasetAllowed,
+		HasDatasetRestrictions: len(s.BigQueryAllowedDatasets()) > 0,
+		manifest:               tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:            mcpManifest,
+	}
+	return t, nil
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name           string           `yaml:"name"`
+	Kind           string           `yaml:"kind"`
+	AuthRequired   []string         `yaml:"authRequired"`
+	UseClientOAuth bool             `yaml:"useClientOAuth"`
+	Parameters     tools.Parameters `yaml:"parameters"`
+
+	Client                 *bigqueryapi.Client
+	QueryTag               string
+	MultiProject           bool
+	ClientForProject       bigquerycommon.ClientForProjectFunc
+	ReserveQuota           bigquerycommon.ReserveQuotaFunc
+	RestService            *bigqueryrestapi.Service
+	ClientCreator          bigqueryds.BigqueryClientCreator
+	IsDatasetAllowed       func(projectID, datasetID string) bool
+	HasDatasetRestrictions bool
+	manifest               tools.Manifest
+	mcpManifest            tools.McpManifest
+}
+
+func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
+	paramsMap := params.AsMap()
+	model, ok := paramsMap[modelKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("unable to cast %s parameter %v", modelKey, paramsMap[modelKey])
+	}
+	text, ok := paramsMap[textKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("unable to cast %s parameter %v", textKey, paramsMap[textKey])
+	}
+	inputData, ok := paramsMap[inputDataKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("unable to cast %s parameter %v", inputDataKey, paramsMap[inputDataKey])
+	}
+	if (text == "") == (inputData == "") {
+		return nil, fmt.Errorf("exactly one of '%s' and '%s' must be set", textKey, inputDataKey)
+	}
+
+	bqClient := t.Client
+	restService := t.RestService
+	var err error
+
+	// Use the clients of the project, or initialize new ones if using user
+	// OAuth token
+	if project, ok := paramsMap[bigquerycommon.ProjectKey].(string); ok && t.MultiProject {
+		// Sources with allowedProjects run the query in the requested project.
+		bqClient, restService, err = bigquerycommon.ProjectClient(t.ClientForProject, project, t.UseClientOAuth, accessToken)
+		if err != nil {
+			return nil, err
+		}
+	} else if t.UseClientOAuth {
+		tokenStr, err := accessToken.ParseBearerToken()
+		if err != nil {
+			return nil, fmt.Errorf("error parsing access token: %w", err)
+		}
+		bqClient, restService, err = t.ClientCreator(tokenStr, true)
+		if err != nil {
+			return nil, fmt.Errorf("error creating client from OAuth access token: %w", err)
+		}
+	}
+
+	modelProject, modelDataset, modelID, err := ParseModelReference(model, bqClient.Project())
+	if err != nil {
+		return nil, err
+	}
+	if !t.IsDatasetAllowed(modelProject, modelDataset) {
+		return nil, fmt.Errorf("access denied to dataset '%s' because it is not in the configured list of allowed datasets for project '%s'", modelDataset, modelProject)
+	}
+
+	var inputDataSource string
+	var queryParameters []bigqueryapi.QueryParameter
+	var restQueryParameters []*bigqueryrestapi.QueryParameter
+	if text != "" {
+		inputDataSource = "(SELECT @text AS content)"
+		queryParameters = []bigqueryapi.QueryParameter{{Name: textKey, Value: text}}
+		restQueryParameters = []*bigqueryrestapi.QueryParameter{{
+			Name:           textKey,
+			ParameterType:  &bigqueryrestapi.QueryParameterType{Type: "STRING"},
+			ParameterValue: &bigqueryrestapi.QueryParameterValue{Value: text},
+		}}
+	} else {
+		inputDataSource, err = InputDataSource(inputData)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sql := EmbeddingStatement(fmt.Sprintf("%s.%s.%s", modelProject, modelDataset, modelID), inputDataSource)
+
+	// The model is checked above, so check the tables read for the input data.
+	if t.HasDatasetRestrictions {
+		dryRunJob, err := bigquerycommon.DryRunQuery(ctx, restService, bqClient.Project(), bqClient.Location, sql, restQueryParameters, nil)
+		if err != nil {
+			return nil, fmt.Errorf("query validation failed during dry run: %w", err)
+		}
+		if dryRunJob.Statistics != nil && dryRunJob.Statistics.Query != nil {
+			for _, table := range dryRunJob.Statistics.Query.ReferencedTables {
+				if !t.IsDatasetAllowed(table.ProjectId, table.DatasetId) {
+					return nil, fmt.Errorf("access denied to dataset '%s' because it is not in the configured list of allowed datasets for project '%s'", table.DatasetId, table.ProjectId)
+				}
+			}
+		}
+	}
+
+	query := bqClient.Query(sql)
+	bigquerycommon.SetQueryTag(query, t.QueryTag)
+	query.Parameters = queryParameters
+	query.Location = bqClient.Location
+
+	// Log the query executed for debugging. The statement may hold sensitive
+	// values, so only its type and length are logged.
+	logger, err := util.LoggerFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting logger: %s", err)
+	}
+	logger.DebugContext(ctx, "executing `%s` tool query: %s", kind, tools.DescribeStatement(sql))
+
+	quota, err := bigquerycommon.NewQuota(t.ReserveQuota, t.UseClientOAuth, accessToken)
+	if err != nil {
+		return nil, err
+	}
+	var out []any
+	it, err := bigquerycommon.ReadQuery(ctx, query, quota)
+	if err != nil {
+		return nil, fmt.Errorf("unable to execute query: %w", err)
+	}
+	for {
+		var row map[string]bigqueryapi.Value
+		err = it.Next(&row)
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to iterate through query results: %w", err)
+		}
+		vMap := make(map[string]any)
+		for key, value := range row {
+			vMap[key] = value
+		}
+		out = append(out, vMap)
+	}
+	if len(out) > 0 {
+		return out, nil
+	}
+	return tools.ZeroRowsMessage, nil
+}
+
+// InputDataSource returns the input data argument of ML.GENERATE_EMBEDDING for
+// inputData, which is either a query or a table ID. Table IDs are quoted, so
+// they must not be able to break out of the quoted path.
+func InputDataSource(inputData string) (string, error) {
+	trimmedUpperInputData := strings.TrimSpace(strings.ToUpper(inputData))
+	if strings.HasPrefix(trimmedUpperInputData, "SELECT") || strings.HasPrefix(trimmedUpperInputData, "WITH") {
+		return fmt.Sprintf("(%s)", inputData), nil
+	}
+	if strings.Contains(inputData, "`") {
+		return "", fmt.Errorf("invalid %s table %q", inputDataKey, inputData)
+	}
+	return fmt.Sprintf("TABLE `%s`", strings.TrimSpace(inputData)), nil
+}
+
+// EmbeddingStatement returns the statement that generates the embeddings of the
+// input data with model, a `project.dataset.model` path.
+func EmbeddingStatement(model, inputDataSource string) string {
+	return fmt.Sprintf(`SELECT *
+		FROM ML.GENERATE_EMBEDDING(
+			MODEL `+"`%s`"+`,
+			%s,
+			STRUCT(TRUE AS flatten_json_output))`,
+		model, inputDataSource)
+}
+
+// ParseModelReference splits a model reference into its project, dataset and
+// model IDs, using defaultProject when the reference has no project.
+func ParseModelReference(ref, defaultProject string) (string, string, string, error) {
+	matches := modelReferenceRegex.FindStringSubmatch(strings.TrimSpace(ref))
+	if matches == nil {
+		return "", "", "", fmt.Errorf("invalid model reference %q, expected `project.dataset.model` or `dataset.model`", ref)
+	}
+	project := matches[1]
+	if project == "" {
+		project = defaultProject
+	}
+	return project, matches[2], matches[3], nil
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.Parameters, data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
+
+func (t Tool) RequiresClientAuthorization() bool {
+	return t.UseClientOAuth
+}
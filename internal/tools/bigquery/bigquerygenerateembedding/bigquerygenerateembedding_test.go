@@ -0,0 +1,220 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bigquerygenerateembedding_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	bigqueryapi "cloud.google.com/go/bigquery"
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigquerygenerateembedding"
+	"google.golang.org/api/option"
+)
+
+func TestParseFromYamlBigQueryGenerateEmbedding(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		want server.ToolConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			tools:
+				example_tool:
+					kind: bigquery-generate-embedding
+					source: my-instance
+					description: some description
+			`,
+			want: server.ToolConfigs{
+				"example_tool": bigquerygenerateembedding.Config{
+					Name:         "example_tool",
+					Kind:         "bigquery-generate-embedding",
+					Source:       "my-instance",
+					Description:  "some description",
+					AuthRequired: []string{},
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			// Parse contents
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Tools); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+
+}
+
+func TestFailInvokeBigQueryGenerateEmbedding(t *testing.T) {
+	ctx := context.Background()
+	// the model and input data are checked before any call to BigQuery
+	client, err := bigqueryapi.NewClient(ctx, "my-project", option.WithEndpoint("http://127.0.0.1:0"), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("unable to create client: %s", err)
+	}
+	defer client.Close()
+	tool := bigquerygenerateembedding.Tool{
+		Client:           client,
+		IsDatasetAllowed: func(_, datasetID string) bool { return datasetID != "private" },
+	}
+	params := func(model, text, inputData string) tools.ParamValues {
+		return tools.ParamValues{
+			{Name: "model", Value: model},
+			{Name: "text", Value: text},
+			{Name: "input_data", Value: inputData},
+		}
+	}
+	tcs := []struct {
+		desc   string
+		params tools.ParamValues
+		err    string
+	}{
+		{
+			desc:   "model without a dataset",
+			params: params("my_model", "some text", ""),
+			err:    `invalid model reference "my_model"`,
+		},
+		{
+			desc:   "model breaking out of the quoted path",
+			params: params("my_dataset.my_model`, (SELECT 1)); --", "some text", ""),
+			err:    "invalid model reference",
+		},
+		{
+			desc:   "model dataset not allowed",
+			params: params("private.my_model", "some text", ""),
+			err:    "access denied to dataset 'private'",
+		},
+		{
+			desc:   "both text and input data",
+			params: params("my_dataset.my_model", "some text", "my_dataset.docs"),
+			err:    "exactly one of 'text' and 'input_data' must be set",
+		},
+		{
+			desc:   "neither text nor input data",
+			params: params("my_dataset.my_model", "", ""),
+			err:    "exactly one of 'text' and 'input_data' must be set",
+		},
+		{
+			desc:   "input data table breaking out of the quoted path",
+			params: params("my_dataset.my_model", "", "my_dataset.docs`, STRUCT()); DROP TABLE t; --"),
+			err:    "invalid input_data table",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := tool.Invoke(ctx, tc.params, "")
+			if err == nil {
+				t.Fatalf("expect invocation to fail")
+			}
+			if !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("unexpected error string: got %q, want substring %q", err.Error(), tc.err)
+			}
+		})
+	}
+}
+
+func TestParseModelReference(t *testing.T) {
+	tcs := []struct {
+		desc    string
+		ref     string
+		want    []string
+		wantErr bool
+	}{
+		{desc: "full path", ref: "my-project.my_dataset.my_model", want: []string{"my-project", "my_dataset", "my_model"}},
+		{desc: "default project", ref: "my_dataset.my_model", want: []string{"default-project", "my_dataset", "my_model"}},
+		{desc: "model only", ref: "my_model", wantErr: true},
+		{desc: "hyphen in model", ref: "my_dataset.my-model", wantErr: true},
+		{desc: "backtick", ref: "my_dataset.my_model`, (SELECT 1)); --", wantErr: true},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			project, dataset, model, err := bigquerygenerateembedding.ParseModelReference(tc.ref, "default-project")
+			if err != nil {
+				if tc.wantErr {
+					return
+				}
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if tc.wantErr {
+				t.Fatalf("expected error but got nil")
+			}
+			if diff := cmp.Diff(tc.want, []string{project, dataset, model}); diff != "" {
+				t.Fatalf("incorrect model reference: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestInputDataSource(t *testing.T) {
+	tcs := []struct {
+		desc    string
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{desc: "query", in: "SELECT title AS content FROM docs", want: "(SELECT title AS content FROM docs)"},
+		{desc: "query with a cte", in: " with d AS (SELECT 1) SELECT * FROM d", want: "( with d AS (SELECT 1) SELECT * FROM d)"},
+		{desc: "table", in: " my-project.my_dataset.docs ", want: "TABLE `my-project.my_dataset.docs`"},
+		{desc: "backtick in table", in: "my_dataset.docs`, STRUCT()); DROP TABLE t; --", wantErr: true},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := bigquerygenerateembedding.InputDataSource(tc.in)
+			if err != nil {
+				if tc.wantErr {
+					return
+				}
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if tc.wantErr {
+				t.Fatalf("expected error but got nil")
+			}
+			if got != tc.want {
+				t.Fatalf("incorrect input data source: got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestEmbeddingStatement(t *testing.T) {
+	got := bigquerygenerateembedding.EmbeddingStatement("my-project.my_dataset.my_model", "(SELECT @text AS content)")
+	want := "SELECT *\n\t\tFROM ML.GENERATE_EMBEDDING(\n" +
+		"\t\t\tMODEL `my-project.my_dataset.my_model`,\n" +
+		"\t\t\t(SELECT @text AS content),\n" +
+		"\t\t\tSTRUCT(TRUE AS flatten_json_output))"
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("incorrect statement: diff %v", diff)
+	}
+}
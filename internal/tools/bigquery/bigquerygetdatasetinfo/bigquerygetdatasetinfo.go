@@ -48,6 +48,7 @@ type compatibleSource interface {
 	BigQueryClient() *bigqueryapi.Client
 	BigQueryClientCreator() bigqueryds.BigqueryClientCreator
 	UseClientAuthorization() bool
+	IsProjectAllowed(projectID string) bool
 }
 
 // validate compatible sources are still compatible
@@ -61,6 +62,9 @@ type Config struct {
 	Source       string   `yaml:"source" validate:"required"`
 	Description  string   `yaml:"description" validate:"required"`
 	AuthRequired []string `yaml:"authRequired"`
+	// IncludeAccess replaces the raw access entries of the dataset with
+	// AccessEntry objects.
+	IncludeAccess bool `yaml:"includeAccess"`
 }
 
 // validate interface
@@ -95,15 +99,17 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 
 	// finish tool setup
 	t := Tool{
-		Name:           cfg.Name,
-		Kind:           kind,
-		Parameters:     parameters,
-		AuthRequired:   cfg.AuthRequired,
-		UseClientOAuth: s.UseClientAuthorization(),
-		ClientCreator:  s.BigQueryClientCreator(),
-		Client:         s.BigQueryClient(),
-		manifest:       tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
-		mcpManifest:    mcpManifest,
+		Name:             cfg.Name,
+		Kind:             kind,
+		Parameters:       parameters,
+		AuthRequired:     cfg.AuthRequired,
+		UseClientOAuth:   s.UseClientAuthorization(),
+		ClientCreator:    s.BigQueryClientCreator(),
+		Client:           s.BigQueryClient(),
+		IsProjectAllowed: s.IsProjectAllowed,
+		IncludeAccess:    cfg.IncludeAccess,
+		manifest:         tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:      mcpManifest,
 	}
 	return t, nil
 }
@@ -118,11 +124,13 @@ type Tool struct {
 	UseClientOAuth bool             `yaml:"useClientOAuth"`
 	Parameters     tools.Parameters `yaml:"parameters"`
 
-	Client        *bigqueryapi.Client
-	ClientCreator bigqueryds.BigqueryClientCreator
-	Statement     string
-	manifest      tools.Manifest
-	mcpManifest   tools.McpManifest
+	Client           *bigqueryapi.Client
+	ClientCreator    bigqueryds.BigqueryClientCreator
+	IsProjectAllowed func(projectID string) bool
+	IncludeAccess    bool
+	Statement        string
+	manifest         tools.Manifest
+	mcpManifest      tools.McpManifest
 }
 
 func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
@@ -131,6 +139,9 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	if !ok {
 		return nil, fmt.Errorf("invalid or missing '%s' parameter; expected a string", projectKey)
 	}
+	if !t.IsProjectAllowed(projectId) {
+		return nil, fmt.Errorf("access denied to project '%s' because it is not in the configured list of allowed projects", projectId)
+	}
 
 	datasetId, ok := mapParams[datasetKey].(string)
 	if !ok {
@@ -158,9 +169,67 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		return nil, fmt.Errorf("failed to get metadata for dataset %s (in project %s): %w", datasetId, bqClient.Project(), err)
 	}
 
+	if t.IncludeAccess {
+		return DatasetInfo{DatasetMetadata: metadata, Access: FormatAccess(metadata.Access)}, nil
+	}
 	return metadata, nil
 }
 
+// DatasetInfo is the metadata of a dataset with readable access entries.
+type DatasetInfo struct {
+	*bigqueryapi.DatasetMetadata
+	// Access shadows the raw access entries of the metadata.
+	Access []AccessEntry
+}
+
+// AccessEntry is a readable entry of the access control list of a dataset.
+type AccessEntry struct {
+	Role       string `json:"role"`
+	MemberType string `json:"memberType"`
+	Member     string `json:"member"`
+}
+
+// FormatAccess converts the access entries of a dataset to AccessEntry
+// objects. Views, routines and datasets, which have no role, are identified by
+// their fully qualified names.
+func FormatAccess(entries []*bigqueryapi.AccessEntry) []AccessEntry {
+	access := make([]AccessEntry, 0, len(entries))
+	for _, e := range entries {
+		a := AccessEntry{Role: string(e.Role), Member: e.Entity}
+		switch e.EntityType {
+		case bigqueryapi.DomainEntity:
+			a.MemberType = "domain"
+		case bigqueryapi.GroupEmailEntity:
+			a.MemberType = "group"
+		case bigqueryapi.UserEmailEntity:
+			a.MemberType = "user"
+		case bigqueryapi.SpecialGroupEntity:
+			a.MemberType = "specialGroup"
+		case bigqueryapi.IAMMemberEntity:
+			a.MemberType = "iamMember"
+		case bigqueryapi.ViewEntity:
+			a.MemberType = "view"
+			if e.View != nil {
+				a.Member = fmt.Sprintf("%s.%s.%s", e.View.ProjectID, e.View.DatasetID, e.View.TableID)
+			}
+		case bigqueryapi.RoutineEntity:
+			a.MemberType = "routine"
+			if e.Routine != nil {
+				a.Member = fmt.Sprintf("%s.%s.%s", e.Routine.ProjectID, e.Routine.DatasetID, e.Routine.RoutineID)
+			}
+		case bigqueryapi.DatasetEntity:
+			a.MemberType = "dataset"
+			if e.Dataset != nil && e.Dataset.Dataset != nil {
+				a.Member = fmt.Sprintf("%s.%s", e.Dataset.Dataset.ProjectID, e.Dataset.Dataset.DatasetID)
+			}
+		default:
+			a.MemberType = "unknown"
+		}
+		access = append(access, a)
+	}
+	return access
+}
+
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
 	return tools.ParseParams(t.Parameters, data, claims)
 }
@@ -15,8 +15,10 @@
 package bigquerygetdatasetinfo_test
 
 import (
+	"encoding/json"
 	"testing"
 
+	bigqueryapi "cloud.google.com/go/bigquery"
 	yaml "github.com/goccy/go-yaml"
 	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/genai-toolbox/internal/server"
@@ -53,6 +55,27 @@ func TestParseFromYamlBigQueryGetDatasetInfo(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "include access",
+			in: `
+			tools:
+				example_tool:
+					kind: bigquery-get-dataset-info
+					source: my-instance
+					description: some description
+					includeAccess: true
+			`,
+			want: server.ToolConfigs{
+				"example_tool": bigquerygetdatasetinfo.Config{
+					Name:          "example_tool",
+					Kind:          "bigquery-get-dataset-info",
+					Source:        "my-instance",
+					Description:   "some description",
+					AuthRequired:  []string{},
+					IncludeAccess: true,
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -71,3 +94,49 @@ func TestParseFromYamlBigQueryGetDatasetInfo(t *testing.T) {
 	}
 
 }
+
+func TestFormatAccess(t *testing.T) {
+	client := &bigqueryapi.Client{}
+	md := &bigqueryapi.DatasetMetadata{
+		Name: "my_dataset",
+		Access: []*bigqueryapi.AccessEntry{
+			{Role: bigqueryapi.OwnerRole, EntityType: bigqueryapi.SpecialGroupEntity, Entity: "projectOwners"},
+			{Role: bigqueryapi.ReaderRole, EntityType: bigqueryapi.UserEmailEntity, Entity: "alice@example.com"},
+			{Role: bigqueryapi.WriterRole, EntityType: bigqueryapi.GroupEmailEntity, Entity: "analysts@example.com"},
+			{Role: bigqueryapi.ReaderRole, EntityType: bigqueryapi.DomainEntity, Entity: "example.com"},
+			{EntityType: bigqueryapi.ViewEntity, View: client.DatasetInProject("my-project", "reporting").Table("daily_sales")},
+			{EntityType: bigqueryapi.DatasetEntity, Dataset: &bigqueryapi.DatasetAccessEntry{Dataset: client.DatasetInProject("my-project", "shared")}},
+		},
+	}
+	want := []bigquerygetdatasetinfo.AccessEntry{
+		{Role: "OWNER", MemberType: "specialGroup", Member: "projectOwners"},
+		{Role: "READER", MemberType: "user", Member: "alice@example.com"},
+		{Role: "WRITER", MemberType: "group", Member: "analysts@example.com"},
+		{Role: "READER", MemberType: "domain", Member: "example.com"},
+		{Role: "", MemberType: "view", Member: "my-project.reporting.daily_sales"},
+		{Role: "", MemberType: "dataset", Member: "my-project.shared"},
+	}
+	got := bigquerygetdatasetinfo.FormatAccess(md.Access)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("incorrect access entries: diff %v", diff)
+	}
+
+	// the readable entries replace the raw ones in the result
+	b, err := json.Marshal(bigquerygetdatasetinfo.DatasetInfo{DatasetMetadata: md, Access: got})
+	if err != nil {
+		t.Fatalf("unable to marshal dataset info: %s", err)
+	}
+	var info struct {
+		Name   string
+		Access []bigquerygetdatasetinfo.AccessEntry
+	}
+	if err := json.Unmarshal(b, &info); err != nil {
+		t.Fatalf("unable to unmarshal dataset info: %s", err)
+	}
+	if info.Name != "my_dataset" {
+		t.Fatalf("incorrect dataset name: got %q", info.Name)
+	}
+	if diff := cmp.Diff(want, info.Access); diff != "" {
+		t.Fatalf("incorrect access entries in result: diff %v", diff)
+	}
+}
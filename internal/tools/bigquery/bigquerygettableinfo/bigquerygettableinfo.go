@@ -49,6 +49,7 @@ type compatibleSource interface {
 	BigQueryClient() *bigqueryapi.Client
 	BigQueryClientCreator() bigqueryds.BigqueryClientCreator
 	UseClientAuthorization() bool
+	IsProjectAllowed(projectID string) bool
 }
 
 // validate compatible sources are still compatible
@@ -97,15 +98,16 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 
 	// finish tool setup
 	t := Tool{
-		Name:           cfg.Name,
-		Kind:           kind,
-		Parameters:     parameters,
-		AuthRequired:   cfg.AuthRequired,
-		UseClientOAuth: s.UseClientAuthorization(),
-		ClientCreator:  s.BigQueryClientCreator(),
-		Client:         s.BigQueryClient(),
-		manifest:       tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
-		mcpManifest:    mcpManifest,
+		Name:             cfg.Name,
+		Kind:             kind,
+		Parameters:       parameters,
+		AuthRequired:     cfg.AuthRequired,
+		UseClientOAuth:   s.UseClientAuthorization(),
+		ClientCreator:    s.BigQueryClientCreator(),
+		Client:           s.BigQueryClient(),
+		IsProjectAllowed: s.IsProjectAllowed,
+		manifest:         tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:      mcpManifest,
 	}
 	return t, nil
 }
@@ -120,11 +122,12 @@ type Tool struct {
 	UseClientOAuth bool             `yaml:"useClientOAuth"`
 	Parameters     tools.Parameters `yaml:"parameters"`
 
-	Client        *bigqueryapi.Client
-	ClientCreator bigqueryds.BigqueryClientCreator
-	Statement     string
-	manifest      tools.Manifest
-	mcpManifest   tools.McpManifest
+	Client           *bigqueryapi.Client
+	ClientCreator    bigqueryds.BigqueryClientCreator
+	IsProjectAllowed func(projectID string) bool
+	Statement        string
+	manifest         tools.Manifest
+	mcpManifest      tools.McpManifest
 }
 
 func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
@@ -133,6 +136,9 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	if !ok {
 		return nil, fmt.Errorf("invalid or missing '%s' parameter; expected a string", projectKey)
 	}
+	if !t.IsProjectAllowed(projectId) {
+		return nil, fmt.Errorf("access denied to project '%s' because it is not in the configured list of allowed projects", projectId)
+	}
 
 	datasetId, ok := mapParams[datasetKey].(string)
 	if !ok {
@@ -167,7 +173,62 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		return nil, fmt.Errorf("failed to get metadata for table %s.%s.%s: %w", projectId, datasetId, tableId, err)
 	}
 
-	return metadata, nil
+	return NewTableInfo(metadata), nil
+}
+
+// TableInfo is the metadata of a table with a summary of its partitioning
+// and clustering, which queries should filter on to scan less data.
+type TableInfo struct {
+	*bigqueryapi.TableMetadata
+	Partitioning     *Partitioning `json:"partitioning,omitempty"`
+	ClusteringFields []string      `json:"clusteringFields,omitempty"`
+}
+
+// Partitioning summarizes how a table is partitioned.
+type Partitioning struct {
+	// Type is the granularity of time partitioning, e.g. DAY, or RANGE for
+	// integer range partitioning.
+	Type string `json:"type"`
+	// Field is the partitioning column, or the _PARTITIONTIME pseudo column
+	// for ingestion-time partitioned tables.
+	Field string `json:"field"`
+	// Expiration is how long partitions are kept, e.g. 720h0m0s.
+	Expiration             string                              `json:"expiration,omitempty"`
+	Range                  *bigqueryapi.RangePartitioningRange `json:"range,omitempty"`
+	RequirePartitionFilter bool                                `json:"requirePartitionFilter"`
+}
+
+// NewTableInfo returns the metadata of a table with its partitioning and
+// clustering summary.
+func NewTableInfo(md *bigqueryapi.TableMetadata) TableInfo {
+	info := TableInfo{TableMetadata: md}
+	if tp := md.TimePartitioning; tp != nil {
+		info.Partitioning = &Partitioning{
+			Type:                   string(tp.Type),
+			Field:                  tp.Field,
+			RequirePartitionFilter: md.RequirePartitionFilter,
+		}
+		if info.Partitioning.Type == "" {
+			info.Partitioning.Type = string(bigqueryapi.DayPartitioningType)
+		}
+		if info.Partitioning.Field == "" {
+			info.Partitioning.Field = "_PARTITIONTIME"
+		}
+		if tp.Expiration > 0 {
+			info.Partitioning.Expiration = tp.Expiration.String()
+		}
+	} else if rp := md.RangePartitioning; rp != nil {
+		info.Partitioning = &Partitioning{
+			Type:                   "RANGE",
+			Field:                  rp.Field,
+			Range:                  rp.Range,
+			RequirePartitionFilter: md.RequirePartitionFilter,
+		}
+	}
+	if md.Clustering != nil {
+		info.ClusteringFields = md.Clustering.Fields
+	}
+	return info
 }
 
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
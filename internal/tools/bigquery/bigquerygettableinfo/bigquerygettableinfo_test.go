@@ -16,7 +16,9 @@ package bigquerygettableinfo_test
 
 import (
 	"testing"
+	"time"
 
+	bigqueryapi "cloud.google.com/go/bigquery"
 	yaml "github.com/goccy/go-yaml"
 	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/genai-toolbox/internal/server"
@@ -71,3 +73,52 @@ func TestParseFromYamlBigQueryGetTableInfo(t *testing.T) {
 	}
 
 }
+
+func TestNewTableInfo(t *testing.T) {
+	tcs := []struct {
+		desc             string
+		md               *bigqueryapi.TableMetadata
+		wantPartitioning *bigquerygettableinfo.Partitioning
+		wantClustering   []string
+	}{
+		{
+			desc: "partitioned and clustered",
+			md: &bigqueryapi.TableMetadata{
+				TimePartitioning:       &bigqueryapi.TimePartitioning{Type: bigqueryapi.DayPartitioningType, Field: "order_date", Expiration: 30 * 24 * time.Hour},
+				Clustering:             &bigqueryapi.Clustering{Fields: []string{"customer_id", "region"}},
+				RequirePartitionFilter: true,
+			},
+			wantPartitioning: &bigquerygettableinfo.Partitioning{Type: "DAY", Field: "order_date", Expiration: "720h0m0s", RequirePartitionFilter: true},
+			wantClustering:   []string{"customer_id", "region"},
+		},
+		{
+			desc: "ingestion time partitioned",
+			md: &bigqueryapi.TableMetadata{
+				TimePartitioning: &bigqueryapi.TimePartitioning{},
+			},
+			wantPartitioning: &bigquerygettableinfo.Partitioning{Type: "DAY", Field: "_PARTITIONTIME"},
+		},
+		{
+			desc: "range partitioned",
+			md: &bigqueryapi.TableMetadata{
+				RangePartitioning: &bigqueryapi.RangePartitioning{Field: "id", Range: &bigqueryapi.RangePartitioningRange{Start: 0, End: 100, Interval: 10}},
+			},
+			wantPartitioning: &bigquerygettableinfo.Partitioning{Type: "RANGE", Field: "id", Range: &bigqueryapi.RangePartitioningRange{Start: 0, End: 100, Interval: 10}},
+		},
+		{
+			desc: "neither",
+			md:   &bigqueryapi.TableMetadata{},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := bigquerygettableinfo.NewTableInfo(tc.md)
+			if diff := cmp.Diff(tc.wantPartitioning, got.Partitioning); diff != "" {
+				t.Fatalf("incorrect partitioning: diff %v", diff)
+			}
+			if diff := cmp.Diff(tc.wantClustering, got.ClusteringFields); diff != "" {
+				t.Fatalf("incorrect clustering fields: diff %v", diff)
+			}
+		})
+	}
+}
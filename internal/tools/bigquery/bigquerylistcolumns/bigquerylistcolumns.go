@@ -0,0 +1,279 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bigquerylistcolumns
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	bigqueryapi "cloud.google.com/go/bigquery"
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	bigqueryds "github.com/googleapis/genai-toolbox/internal/sources/bigquery"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigquerycommon"
+	bigqueryrestapi "google.golang.org/api/bigquery/v2"
+	"google.golang.org/api/iterator"
+)
+
+const kind string = "bigquery-list-columns"
+const projectKey string = "project"
+const datasetKey string = "dataset"
+const tableKey string = "table"
+
+// listColumnsStatement lists a table's columns in declaration order. Repeated
+// fields are reported by INFORMATION_SCHEMA as ARRAY types, so the mode is
+// derived from the data type and nullability.
+const listColumnsStatement = `
+	SELECT
+		column_name,
+		data_type,
+		CASE
+			WHEN STARTS_WITH(data_type, 'ARRAY<') THEN 'REPEATED'
+			WHEN is_nullable = 'NO' THEN 'REQUIRED'
+			ELSE 'NULLABLE'
+		END AS mode,
+		ordinal_position
+	FROM %s.INFORMATION_SCHEMA.COLUMNS
+	WHERE table_name = @table_name
+	ORDER BY ordinal_position`
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type compatibleSource interface {
+	BigQueryProject() string
+	BigQueryClient() *bigqueryapi.Client
+	BigQueryQueryTag() string
+	BigQueryClientCreator() bigqueryds.BigqueryClientCreator
+	UseClientAuthorization() bool
+	IsDatasetAllowed(projectID, datasetID string) bool
+	IsProjectAllowed(projectID string) bool
+	BigQueryAllowedProjects() []string
+	BigQueryClientForProject(projectID, tokenString string) (*bigqueryapi.Client, *bigqueryrestapi.Service, error)
+	ReserveQuota(ctx context.Context, tokenString string, bytes int64) error
+}
+
+// validate compatible sources are still compatible
+var _ compatibleSource = &bigqueryds.Source{}
+
+var compatibleSources = [...]string{bigqueryds.SourceKind}
+
+type Config struct {
+	Name         string   `yaml:"name" validate:"required"`
+	Kind         string   `yaml:"kind" validate:"required"`
+	Source       string   `yaml:"source" validate:"required"`
+	Description  string   `yaml:"description" validate:"required"`
+	AuthRequired []string `yaml:"authRequired"`
+}
+
+// validate interface
+var _ tools.ToolConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	// verify source exists
+	rawS, ok := srcs[cfg.Source]
+	if !ok {
+		return nil, fmt.Errorf("no source named %q configured", cfg.Source)
+	}
+
+	// verify the source is compatible
+	s, ok := rawS.(compatibleSource)
+	if !ok {
+		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
+	}
+
+	projectParameter := tools.NewStringParameterWithDefault(projectKey, s.BigQueryProject(), "The Google Cloud project ID containing the dataset and table.")
+	datasetParameter := tools.NewStringParameter(datasetKey, "The table's parent dataset.")
+	tableParameter := tools.NewStringParameter(tableKey, "The table to list columns for.")
+	parameters := tools.Parameters{projectParameter, datasetParameter, tableParameter}
+
+	mcpManifest := tools.McpManifest{
+		Name:        cfg.Name,
+		Description: cfg.Description,
+		InputSchema: parameters.McpManifest(),
+	}
+
+	// finish tool setup
+	t := Tool{
+		Name:             cfg.Name,
+		Kind:             kind,
+		Parameters:       parameters,
+		AuthRequired:     cfg.AuthRequired,
+		UseClientOAuth:   s.UseClientAuthorization(),
+		ClientCreator:    s.BigQueryClientCreator(),
+		Client:           s.BigQueryClient(),
+		QueryTag:         s.BigQueryQueryTag(),
+		MultiProject:     len(s.BigQueryAllowedProjects()) > 0,
+		ClientForProject: s.BigQueryClientForProject,
+		ReserveQuota:     s.ReserveQuota,
+		IsProjectAllowed: s.IsProjectAllowed,
+		IsDatasetAllowed: s.IsDatasetAllowed,
+		manifest:         tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:      mcpManifest,
+	}
+	return t, nil
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name           string           `yaml:"name"`
+	Kind           string           `yaml:"kind"`
+	AuthRequired   []string         `yaml:"authRequired"`
+	UseClientOAuth bool             `yaml:"useClientOAuth"`
+	Parameters     tools.Parameters `yaml:"parameters"`
+
+	Client           *bigqueryapi.Client
+	QueryTag         string
+	MultiProject     bool
+	ClientForProject bigquerycommon.ClientForProjectFunc
+	ReserveQuota     bigquerycommon.ReserveQuotaFunc
+	ClientCreator    bigqueryds.BigqueryClientCreator
+	IsProjectAllowed func(projectID string) bool
+	IsDatasetAllowed func(projectID, datasetID string) bool
+	manifest         tools.Manifest
+	mcpManifest      tools.McpManifest
+}
+
+func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
+	mapParams := params.AsMap()
+	projectId, ok := mapParams[projectKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid or missing '%s' parameter; expected a string", projectKey)
+	}
+	if !t.IsProjectAllowed(projectId) {
+		return nil, fmt.Errorf("access denied to project '%s' because it is not in the configured list of allowed projects", projectId)
+	}
+
+	datasetId, ok := mapParams[datasetKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid or missing '%s' parameter; expected a string", datasetKey)
+	}
+
+	tableId, ok := mapParams[tableKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid or missing '%s' parameter; expected a string", tableKey)
+	}
+
+	if !t.IsDatasetAllowed(projectId, datasetId) {
+		return nil, fmt.Errorf("access denied to dataset '%s' because it is not in the configured list of allowed datasets for project '%s'", datasetId, projectId)
+	}
+	statement, err := ListColumnsStatement(projectId, datasetId)
+	if err != nil {
+		return nil, err
+	}
+
+	bqClient := t.Client
+	// Use the clients of the project, or initialize new ones if using user
+	// OAuth token
+	if t.MultiProject {
+		// Sources with allowedProjects run the job in the project of the table.
+		bqClient, _, err = bigquerycommon.ProjectClient(t.ClientForProject, projectId, t.UseClientOAuth, accessToken)
+		if err != nil {
+			return nil, err
+		}
+	} else if t.UseClientOAuth {
+		tokenStr, err := accessToken.ParseBearerToken()
+		if err != nil {
+			return nil, fmt.Errorf("error parsing access token: %w", err)
+		}
+		bqClient, _, err = t.ClientCreator(tokenStr, false)
+		if err != nil {
+			return nil, fmt.Errorf("error creating client from OAuth access token: %w", err)
+		}
+	}
+
+	query := bqClient.Query(statement)
+	bigquerycommon.SetQueryTag(query, t.QueryTag)
+	query.Location = bqClient.Location
+	query.Parameters = []bigqueryapi.QueryParameter{{Name: "table_name", Value: tableId}}
+
+	quota, err := bigquerycommon.NewQuota(t.ReserveQuota, t.UseClientOAuth, accessToken)
+	if err != nil {
+		return nil, err
+	}
+	it, err := bigquerycommon.ReadQuery(ctx, query, quota)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list columns for table %s.%s.%s: %w", projectId, datasetId, tableId, err)
+	}
+	var out []any
+	for {
+		var row map[string]bigqueryapi.Value
+		err = it.Next(&row)
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to iterate through query results: %w", err)
+		}
+		vMap := make(map[string]any)
+		for key, value := range row {
+			vMap[key] = value
+		}
+		out = append(out, vMap)
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("table %s.%s.%s not found or has no columns", projectId, datasetId, tableId)
+	}
+	return out, nil
+}
+
+// ListColumnsStatement returns the statement that lists the columns of the
+// tables of a dataset. The project and dataset are interpolated as an
+// identifier, so they must not be able to break out of the quoted path.
+func ListColumnsStatement(projectID, datasetID string) (string, error) {
+	if strings.Contains(projectID, "`") || strings.Contains(datasetID, "`") {
+		return "", fmt.Errorf("invalid project or dataset name: %s.%s", projectID, datasetID)
+	}
+	return fmt.Sprintf(listColumnsStatement, fmt.Sprintf("`%s.%s`", projectID, datasetID)), nil
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.Parameters, data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
+
+func (t Tool) RequiresClientAuthorization() bool {
+	return t.UseClientOAuth
+}
@@ -0,0 +1,181 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bigquerylistcolumns_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigquerylistcolumns"
+)
+
+func TestParseFromYamlBigQueryListColumns(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		want server.ToolConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			tools:
+				example_tool:
+					kind: bigquery-list-columns
+					source: my-instance
+					description: some description
+			`,
+			want: server.ToolConfigs{
+				"example_tool": bigquerylistcolumns.Config{
+					Name:         "example_tool",
+					Kind:         "bigquery-list-columns",
+					Source:       "my-instance",
+					Description:  "some description",
+					AuthRequired: []string{},
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			// Parse contents
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Tools); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+
+}
+
+func TestFailInvokeBigQueryListColumns(t *testing.T) {
+	// the tables are checked before any call to BigQuery, so the tool needs
+	// no client
+	tool := bigquerylistcolumns.Tool{
+		IsProjectAllowed: func(projectID string) bool { return projectID != "other-project" },
+		IsDatasetAllowed: func(_, datasetID string) bool { return datasetID != "private" },
+	}
+	tcs := []struct {
+		desc    string
+		project string
+		dataset string
+		err     string
+	}{
+		{
+			desc:    "project not allowed",
+			project: "other-project",
+			dataset: "my_dataset",
+			err:     "access denied to project 'other-project'",
+		},
+		{
+			desc:    "dataset not allowed",
+			project: "my-project",
+			dataset: "private",
+			err:     "access denied to dataset 'private'",
+		},
+		{
+			desc:    "backtick in project",
+			project: "my-project`.other",
+			dataset: "my_dataset",
+			err:     "invalid project or dataset name",
+		},
+		{
+			desc:    "backtick in dataset",
+			project: "my-project",
+			dataset: "my_dataset`; DROP TABLE t; --",
+			err:     "invalid project or dataset name",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			params := tools.ParamValues{
+				{Name: "project", Value: tc.project},
+				{Name: "dataset", Value: tc.dataset},
+				{Name: "table", Value: "my_table"},
+			}
+			_, err := tool.Invoke(context.Background(), params, "")
+			if err == nil {
+				t.Fatalf("expect invocation to fail")
+			}
+			if !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("unexpected error string: got %q, want substring %q", err.Error(), tc.err)
+			}
+		})
+	}
+}
+
+func TestListColumnsStatement(t *testing.T) {
+	tcs := []struct {
+		desc    string
+		project string
+		dataset string
+		want    string
+		wantErr bool
+	}{
+		{
+			desc:    "quoted path",
+			project: "my-project",
+			dataset: "my_dataset",
+			want:    "FROM `my-project.my_dataset`.INFORMATION_SCHEMA.COLUMNS",
+		},
+		{
+			desc:    "backtick in project",
+			project: "my-project`.other",
+			dataset: "my_dataset",
+			wantErr: true,
+		},
+		{
+			desc:    "backtick in dataset",
+			project: "my-project",
+			dataset: "my_dataset`; DROP TABLE t; --",
+			wantErr: true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := bigquerylistcolumns.ListColumnsStatement(tc.project, tc.dataset)
+			if err != nil {
+				if tc.wantErr {
+					return
+				}
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if tc.wantErr {
+				t.Fatalf("expected error but got nil")
+			}
+			if !strings.Contains(got, tc.want) {
+				t.Fatalf("incorrect statement: got %q, want it to contain %q", got, tc.want)
+			}
+			if !strings.Contains(got, "WHERE table_name = @table_name") {
+				t.Fatalf("the table name should be a query parameter: got %q", got)
+			}
+		})
+	}
+}
@@ -28,6 +28,8 @@ import (
 
 const kind string = "bigquery-list-dataset-ids"
 const projectKey string = "project"
+const pageSizeKey string = "page_size"
+const pageTokenKey string = "page_token"
 
 func init() {
 	if !tools.Register(kind, newConfig) {
@@ -48,6 +50,7 @@ type compatibleSource interface {
 	BigQueryClient() *bigqueryapi.Client
 	BigQueryClientCreator() bigqueryds.BigqueryClientCreator
 	UseClientAuthorization() bool
+	IsProjectAllowed(projectID string) bool
 }
 
 // validate compatible sources are still compatible
@@ -85,7 +88,10 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 
 	projectParameter := tools.NewStringParameterWithDefault(projectKey, s.BigQueryProject(), "The Google Cloud project to list dataset ids.")
 
-	parameters := tools.Parameters{projectParameter}
+	pageSizeParameter := tools.NewIntParameterWithDefault(pageSizeKey, 0, "The maximum number of dataset ids to return. Returns all dataset ids if 0.")
+	pageTokenParameter := tools.NewStringParameterWithDefault(pageTokenKey, "", "The next_page_token returned by a previous call, to list the following page of dataset ids.")
+
+	parameters := tools.Parameters{projectParameter, pageSizeParameter, pageTokenParameter}
 
 	mcpManifest := tools.McpManifest{
 		Name:        cfg.Name,
@@ -95,15 +101,16 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 
 	// finish tool setup
 	t := Tool{
-		Name:           cfg.Name,
-		Kind:           kind,
-		Parameters:     parameters,
-		AuthRequired:   cfg.AuthRequired,
-		UseClientOAuth: s.UseClientAuthorization(),
-		ClientCreator:  s.BigQueryClientCreator(),
-		Client:         s.BigQueryClient(),
-		manifest:       tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
-		mcpManifest:    mcpManifest,
+		Name:             cfg.Name,
+		Kind:             kind,
+		Parameters:       parameters,
+		AuthRequired:     cfg.AuthRequired,
+		UseClientOAuth:   s.UseClientAuthorization(),
+		ClientCreator:    s.BigQueryClientCreator(),
+		Client:           s.BigQueryClient(),
+		IsProjectAllowed: s.IsProjectAllowed,
+		manifest:         tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:      mcpManifest,
 	}
 	return t, nil
 }
@@ -118,11 +125,12 @@ type Tool struct {
 	UseClientOAuth bool             `yaml:"useClientOAuth"`
 	Parameters     tools.Parameters `yaml:"parameters"`
 
-	Client        *bigqueryapi.Client
-	ClientCreator bigqueryds.BigqueryClientCreator
-	Statement     string
-	manifest      tools.Manifest
-	mcpManifest   tools.McpManifest
+	Client           *bigqueryapi.Client
+	ClientCreator    bigqueryds.BigqueryClientCreator
+	IsProjectAllowed func(projectID string) bool
+	Statement        string
+	manifest         tools.Manifest
+	mcpManifest      tools.McpManifest
 }
 
 func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
@@ -131,6 +139,17 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	if !ok {
 		return nil, fmt.Errorf("invalid or missing '%s' parameter; expected a string", projectKey)
 	}
+	if !t.IsProjectAllowed(projectId) {
+		return nil, fmt.Errorf("access denied to project '%s' because it is not in the configured list of allowed projects", projectId)
+	}
+	pageSize, ok := mapParams[pageSizeKey].(int)
+	if !ok || pageSize < 0 {
+		return nil, fmt.Errorf("invalid '%s' parameter; expected a non-negative integer", pageSizeKey)
+	}
+	pageToken, _ := mapParams[pageTokenKey].(string)
+	if pageToken != "" && pageSize == 0 {
+		return nil, fmt.Errorf("'%s' requires '%s' to be set", pageTokenKey, pageSizeKey)
+	}
 
 	bqClient := t.Client
 	// Initialize new client if using user OAuth token
@@ -147,6 +166,23 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	datasetIterator := bqClient.Datasets(ctx)
 	datasetIterator.ProjectID = projectId
 
+	if pageSize > 0 {
+		// Only fetch a single page, returning the token of the next one.
+		var datasets []*bigqueryapi.Dataset
+		nextPageToken, err := iterator.NewPager(datasetIterator, pageSize, pageToken).NextPage(&datasets)
+		if err != nil {
+			return nil, fmt.Errorf("unable to list datasets: %w", err)
+		}
+		datasetIds := make([]any, 0, len(datasets))
+		for _, dataset := range datasets {
+			datasetIds = append(datasetIds, trimQuotes(dataset.DatasetID))
+		}
+		return map[string]any{
+			"datasetIds":      datasetIds,
+			"next_page_token": nextPageToken,
+		}, nil
+	}
+
 	var datasetIds []any
 	for {
 		dataset, err := datasetIterator.Next()
@@ -156,18 +192,20 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		if err != nil {
 			return nil, fmt.Errorf("unable to iterate through datasets: %w", err)
 		}
-
-		// Remove leading and trailing quotes
-		id := dataset.DatasetID
-		if len(id) >= 2 && id[0] == '"' && id[len(id)-1] == '"' {
-			id = id[1 : len(id)-1]
-		}
-		datasetIds = append(datasetIds, id)
+		datasetIds = append(datasetIds, trimQuotes(dataset.DatasetID))
 	}
 
 	return datasetIds, nil
 }
 
+// trimQuotes removes leading and trailing quotes from a dataset ID.
+func trimQuotes(id string) string {
+	if len(id) >= 2 && id[0] == '"' && id[len(id)-1] == '"' {
+		return id[1 : len(id)-1]
+	}
+	return id
+}
+
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
 	return tools.ParseParams(t.Parameters, data, claims)
 }
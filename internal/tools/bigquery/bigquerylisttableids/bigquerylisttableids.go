@@ -17,6 +17,7 @@ package bigquerylisttableids
 import (
 	"context"
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 
@@ -31,6 +32,18 @@ import (
 const kind string = "bigquery-list-table-ids"
 const projectKey string = "project"
 const datasetKey string = "dataset"
+const typesKey string = "types"
+const pageSizeKey string = "page_size"
+const pageTokenKey string = "page_token"
+
+// tableTypes are the table types that the types parameter can filter on.
+var tableTypes = []string{
+	string(bigqueryapi.RegularTable),
+	string(bigqueryapi.ViewTable),
+	string(bigqueryapi.MaterializedView),
+	string(bigqueryapi.ExternalTable),
+	string(bigqueryapi.Snapshot),
+}
 
 func init() {
 	if !tools.Register(kind, newConfig) {
@@ -53,6 +66,7 @@ type compatibleSource interface {
 	UseClientAuthorization() bool
 	IsDatasetAllowed(projectID, datasetID string) bool
 	BigQueryAllowedDatasets() []string
+	IsProjectAllowed(projectID string) bool
 }
 
 // validate compatible sources are still compatible
@@ -66,6 +80,9 @@ type Config struct {
 	Source       string   `yaml:"source" validate:"required"`
 	Description  string   `yaml:"description" validate:"required"`
 	AuthRequired []string `yaml:"authRequired"`
+	// IncludeMetadata returns the type, row count and creation time of each
+	// table instead of only its ID.
+	IncludeMetadata bool `yaml:"includeMetadata"`
 }
 
 // validate interface
@@ -126,7 +143,17 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 	}
 	projectParameter := tools.NewStringParameterWithDefault(projectKey, defaultProjectID, projectDescription)
 
-	parameters := tools.Parameters{projectParameter, datasetParameter}
+	typesParameter := tools.NewArrayParameterWithDefault(
+		typesKey,
+		[]any{},
+		fmt.Sprintf("Only return tables of these types, one of %s. Returns tables of all types if empty.", strings.Join(tableTypes, ", ")),
+		tools.NewStringParameter("type", "A table type, e.g. TABLE or VIEW."),
+	)
+
+	pageSizeParameter := tools.NewIntParameterWithDefault(pageSizeKey, 0, "The maximum number of tables to list. Lists all tables if 0. Tables filtered out by types are counted too, so a page can hold fewer table ids.")
+	pageTokenParameter := tools.NewStringParameterWithDefault(pageTokenKey, "", "The next_page_token returned by a previous call, to list the following page of tables.")
+
+	parameters := tools.Parameters{projectParameter, datasetParameter, typesParameter, pageSizeParameter, pageTokenParameter}
 
 	mcpManifest := tools.McpManifest{
 		Name:        cfg.Name,
@@ -143,7 +170,9 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		UseClientOAuth:   s.UseClientAuthorization(),
 		ClientCreator:    s.BigQueryClientCreator(),
 		Client:           s.BigQueryClient(),
+		IsProjectAllowed: s.IsProjectAllowed,
 		IsDatasetAllowed: s.IsDatasetAllowed,
+		IncludeMetadata:  cfg.IncludeMetadata,
 		manifest:         tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
 		mcpManifest:      mcpManifest,
 	}
@@ -162,7 +191,9 @@ type Tool struct {
 
 	Client           *bigqueryapi.Client
 	ClientCreator    bigqueryds.BigqueryClientCreator
+	IsProjectAllowed func(projectID string) bool
 	IsDatasetAllowed func(projectID, datasetID string) bool
+	IncludeMetadata  bool
 	Statement        string
 	manifest         tools.Manifest
 	mcpManifest      tools.McpManifest
@@ -174,12 +205,29 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	if !ok {
 		return nil, fmt.Errorf("invalid or missing '%s' parameter; expected a string", projectKey)
 	}
+	if !t.IsProjectAllowed(projectId) {
+		return nil, fmt.Errorf("access denied to project '%s' because it is not in the configured list of allowed projects", projectId)
+	}
 
 	datasetId, ok := mapParams[datasetKey].(string)
 	if !ok {
 		return nil, fmt.Errorf("invalid or missing '%s' parameter; expected a string", datasetKey)
 	}
 
+	types, err := parseTableTypes(mapParams[typesKey])
+	if err != nil {
+		return nil, err
+	}
+
+	pageSize, ok := mapParams[pageSizeKey].(int)
+	if !ok || pageSize < 0 {
+		return nil, fmt.Errorf("invalid '%s' parameter; expected a non-negative integer", pageSizeKey)
+	}
+	pageToken, _ := mapParams[pageTokenKey].(string)
+	if pageToken != "" && pageSize == 0 {
+		return nil, fmt.Errorf("'%s' requires '%s' to be set", pageTokenKey, pageSizeKey)
+	}
+
 	if !t.IsDatasetAllowed(projectId, datasetId) {
 		return nil, fmt.Errorf("access denied to dataset '%s' because it is not in the configured list of allowed datasets for project '%s'", datasetId, projectId)
 	}
@@ -199,8 +247,32 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 
 	dsHandle := bqClient.DatasetInProject(projectId, datasetId)
 
-	var tableIds []any
 	tableIterator := dsHandle.Tables(ctx)
+
+	if pageSize > 0 {
+		// Only fetch a single page, returning the token of the next one.
+		var tables []*bigqueryapi.Table
+		nextPageToken, err := iterator.NewPager(tableIterator, pageSize, pageToken).NextPage(&tables)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tables in dataset %s.%s: %w", projectId, datasetId, err)
+		}
+		tableIds := make([]any, 0, len(tables))
+		for _, table := range tables {
+			entry, ok, err := t.tableEntry(ctx, table, types)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				tableIds = append(tableIds, entry)
+			}
+		}
+		return map[string]any{
+			"tableIds":        tableIds,
+			"next_page_token": nextPageToken,
+		}, nil
+	}
+
+	var tableIds []any
 	for {
 		table, err := tableIterator.Next()
 		if err == iterator.Done {
@@ -209,18 +281,71 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		if err != nil {
 			return nil, fmt.Errorf("failed to iterate through tables in dataset %s.%s: %w", projectId, datasetId, err)
 		}
-
-		// Remove leading and trailing quotes
-		id := table.TableID
-		if len(id) >= 2 && id[0] == '"' && id[len(id)-1] == '"' {
-			id = id[1 : len(id)-1]
+		entry, ok, err := t.tableEntry(ctx, table, types)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			tableIds = append(tableIds, entry)
 		}
-		tableIds = append(tableIds, id)
 	}
 
 	return tableIds, nil
 }
 
+// tableEntry returns the ID of table, or its metadata if IncludeMetadata is
+// set. It reports false if the table's type is not one of types.
+func (t Tool) tableEntry(ctx context.Context, table *bigqueryapi.Table, types []string) (any, bool, error) {
+	// Remove leading and trailing quotes
+	id := table.TableID
+	if len(id) >= 2 && id[0] == '"' && id[len(id)-1] == '"' {
+		id = id[1 : len(id)-1]
+	}
+	if !t.IncludeMetadata && len(types) == 0 {
+		return id, true, nil
+	}
+	// The tables iterator only returns table references, so the metadata
+	// is fetched for each table.
+	metadata, err := table.Metadata(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get metadata for table %s.%s.%s: %w", table.ProjectID, table.DatasetID, table.TableID, err)
+	}
+	if len(types) > 0 && !slices.Contains(types, string(metadata.Type)) {
+		return nil, false, nil
+	}
+	if !t.IncludeMetadata {
+		return id, true, nil
+	}
+	return map[string]any{
+		"tableId":      id,
+		"type":         string(metadata.Type),
+		"numRows":      metadata.NumRows,
+		"creationTime": metadata.CreationTime,
+	}, true, nil
+}
+
+// parseTableTypes returns the upper-cased table types of the types parameter,
+// checking that each one is a known table type.
+func parseTableTypes(value any) ([]string, error) {
+	values, ok := value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("invalid '%s' parameter; expected a list of strings", typesKey)
+	}
+	types := make([]string, 0, len(values))
+	for _, v := range values {
+		tableType, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid '%s' parameter; expected a list of strings", typesKey)
+		}
+		tableType = strings.ToUpper(tableType)
+		if !slices.Contains(tableTypes, tableType) {
+			return nil, fmt.Errorf("invalid table type %q in '%s' parameter, must be one of %s", v, typesKey, strings.Join(tableTypes, ", "))
+		}
+		types = append(types, tableType)
+	}
+	return types, nil
+}
+
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
 	return tools.ParseParams(t.Parameters, data, claims)
 }
@@ -53,6 +53,27 @@ func TestParseFromYamlBigQueryListTableIds(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "with metadata",
+			in: `
+			tools:
+				example_tool:
+					kind: bigquery-list-table-ids
+					source: my-instance
+					description: some description
+					includeMetadata: true
+			`,
+			want: server.ToolConfigs{
+				"example_tool": bigquerylisttableids.Config{
+					Name:            "example_tool",
+					Kind:            "bigquery-list-table-ids",
+					Source:          "my-instance",
+					Description:     "some description",
+					AuthRequired:    []string{},
+					IncludeMetadata: true,
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
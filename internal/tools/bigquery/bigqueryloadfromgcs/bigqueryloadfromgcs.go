@@ -0,0 +1,347 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bigqueryloadfromgcs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	bigqueryapi "cloud.google.com/go/bigquery"
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	bigqueryds "github.com/googleapis/genai-toolbox/internal/sources/bigquery"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigquerycommon"
+)
+
+const kind string = "bigquery-load-from-gcs"
+const sourceURIsKey string = "source_uris"
+const projectKey string = "project"
+const datasetKey string = "dataset"
+const tableKey string = "table"
+const sourceFormatKey string = "source_format"
+const writeDispositionKey string = "write_disposition"
+const schemaKey string = "schema"
+const skipLeadingRowsKey string = "skip_leading_rows"
+
+// supportedFormats maps the accepted source_format values to BigQuery data formats.
+var supportedFormats = map[string]bigqueryapi.DataFormat{
+	"CSV":                    bigqueryapi.CSV,
+	"NEWLINE_DELIMITED_JSON": bigqueryapi.JSON,
+	"PARQUET":                bigqueryapi.Parquet,
+	"AVRO":                   bigqueryapi.Avro,
+	"ORC":                    bigqueryapi.ORC,
+}
+
+// supportedWriteDispositions maps the accepted write_disposition values to
+// BigQuery write dispositions.
+var supportedWriteDispositions = map[string]bigqueryapi.TableWriteDisposition{
+	"WRITE_APPEND":   bigqueryapi.WriteAppend,
+	"WRITE_TRUNCATE": bigqueryapi.WriteTruncate,
+	"WRITE_EMPTY":    bigqueryapi.WriteEmpty,
+}
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type compatibleSource interface {
+	BigQueryProject() string
+	BigQueryClient() *bigqueryapi.Client
+	BigQueryClientCreator() bigqueryds.BigqueryClientCreator
+	UseClientAuthorization() bool
+	IsDatasetAllowed(projectID, datasetID string) bool
+	IsProjectAllowed(projectID string) bool
+}
+
+// validate compatible sources are still compatible
+var _ compatibleSource = &bigqueryds.Source{}
+
+var compatibleSources = [...]string{bigqueryds.SourceKind}
+
+type Config struct {
+	Name         string   `yaml:"name" validate:"required"`
+	Kind         string   `yaml:"kind" validate:"required"`
+	Source       string   `yaml:"source" validate:"required"`
+	Description  string   `yaml:"description" validate:"required"`
+	AuthRequired []string `yaml:"authRequired"`
+}
+
+// validate interface
+var _ tools.ToolConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	// verify source exists
+	rawS, ok := srcs[cfg.Source]
+	if !ok {
+		return nil, fmt.Errorf("no source named %q configured", cfg.Source)
+	}
+
+	// verify the source is compatible
+	s, ok := rawS.(compatibleSource)
+	if !ok {
+		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
+	}
+
+	sourceURIsParameter := tools.NewArrayParameter(sourceURIsKey,
+		"The Cloud Storage URIs of the files to load, e.g. gs://bucket/path/*.csv. Each URI may contain a single '*' wildcard.",
+		tools.NewStringParameter("source_uri", "A Cloud Storage URI."))
+	projectParameter := tools.NewStringParameterWithDefault(projectKey, s.BigQueryProject(),
+		"The Google Cloud project ID containing the destination dataset.")
+	datasetParameter := tools.NewStringParameter(datasetKey, "The dataset of the destination table.")
+	tableParameter := tools.NewStringParameter(tableKey,
+		"The destination table. It is created if it doesn't exist.")
+	sourceFormatParameter := tools.NewStringParameterWithDefault(sourceFormatKey, "CSV",
+		"The format of the files. One of CSV, NEWLINE_DELIMITED_JSON, PARQUET, AVRO or ORC.")
+	writeDispositionParameter := tools.NewStringParameterWithDefault(writeDispositionKey, "WRITE_APPEND",
+		"What to do if the destination table already has data. WRITE_APPEND adds the rows, "+
+			"WRITE_TRUNCATE replaces the existing rows and WRITE_EMPTY fails the load.")
+	schemaParameter := tools.NewStringParameterWithDefault(schemaKey, "",
+		"The schema of the data as comma-separated name:TYPE pairs, e.g. 'id:INT64,name:STRING'. "+
+			"If empty, the schema is auto-detected.")
+	skipLeadingRowsParameter := tools.NewIntParameterWithDefault(skipLeadingRowsKey, 0,
+		"The number of header rows to skip. Only applies to CSV data.")
+	parameters := tools.Parameters{sourceURIsParameter, projectParameter, datasetParameter, tableParameter,
+		sourceFormatParameter, writeDispositionParameter, schemaParameter, skipLeadingRowsParameter}
+
+	mcpManifest := tools.McpManifest{
+		Name:        cfg.Name,
+		Description: cfg.Description,
+		InputSchema: parameters.McpManifest(),
+	}
+
+	// finish tool setup
+	t := Tool{
+		Name:             cfg.Name,
+		Kind:             kind,
+		Parameters:       parameters,
+		AuthRequired:     cfg.AuthRequired,
+		UseClientOAuth:   s.UseClientAuthorization(),
+		ClientCreator:    s.BigQueryClientCreator(),
+		Client:           s.BigQueryClient(),
+		IsProjectAllowed: s.IsProjectAllowed,
+		IsDatasetAllowed: s.IsDatasetAllowed,
+		manifest:         tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:      mcpManifest,
+	}
+	return t, nil
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name           string           `yaml:"name"`
+	Kind           string           `yaml:"kind"`
+	AuthRequired   []string         `yaml:"authRequired"`
+	UseClientOAuth bool             `yaml:"useClientOAuth"`
+	Parameters     tools.Parameters `yaml:"parameters"`
+
+	Client           *bigqueryapi.Client
+	ClientCreator    bigqueryds.BigqueryClientCreator
+	IsProjectAllowed func(projectID string) bool
+	IsDatasetAllowed func(projectID, datasetID string) bool
+	manifest         tools.Manifest
+	mcpManifest      tools.McpManifest
+}
+
+func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
+	mapParams := params.AsMap()
+	rawURIs, ok := mapParams[sourceURIsKey].([]any)
+	if !ok {
+		return nil, fmt.Errorf("invalid or missing '%s' parameter; expected an array of strings", sourceURIsKey)
+	}
+
+	projectId, ok := mapParams[projectKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid or missing '%s' parameter; expected a string", projectKey)
+	}
+	if !t.IsProjectAllowed(projectId) {
+		return nil, fmt.Errorf("access denied to project '%s' because it is not in the configured list of allowed projects", projectId)
+	}
+	datasetId, ok := mapParams[datasetKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid or missing '%s' parameter; expected a string", datasetKey)
+	}
+	tableId, ok := mapParams[tableKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid or missing '%s' parameter; expected a string", tableKey)
+	}
+	if !t.IsDatasetAllowed(projectId, datasetId) {
+		return nil, fmt.Errorf("access denied to dataset '%s' because it is not in the configured list of allowed datasets for project '%s'", datasetId, projectId)
+	}
+
+	sourceFormat, ok := mapParams[sourceFormatKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid or missing '%s' parameter; expected a string", sourceFormatKey)
+	}
+	writeDisposition, ok := mapParams[writeDispositionKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid or missing '%s' parameter; expected a string", writeDispositionKey)
+	}
+	schema, ok := mapParams[schemaKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid or missing '%s' parameter; expected a string", schemaKey)
+	}
+	skipLeadingRows, ok := mapParams[skipLeadingRowsKey].(int)
+	if !ok {
+		return nil, fmt.Errorf("invalid or missing '%s' parameter; expected an integer", skipLeadingRowsKey)
+	}
+	loadConfig, err := LoadConfig(rawURIs, sourceFormat, writeDisposition, schema, skipLeadingRows)
+	if err != nil {
+		return nil, err
+	}
+
+	bqClient := t.Client
+	// Initialize new client if using user OAuth token
+	if t.UseClientOAuth {
+		tokenStr, err := accessToken.ParseBearerToken()
+		if err != nil {
+			return nil, fmt.Errorf("error parsing access token: %w", err)
+		}
+		bqClient, _, err = t.ClientCreator(tokenStr, false)
+		if err != nil {
+			return nil, fmt.Errorf("error creating client from OAuth access token: %w", err)
+		}
+	}
+
+	table := bqClient.DatasetInProject(projectId, datasetId).Table(tableId)
+	loadConfig.Dst = table
+	loader := table.LoaderFrom(loadConfig.Src)
+	loader.LoadConfig = loadConfig
+	loader.Location = bqClient.Location
+
+	job, err := loader.Run(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to start load job: %w", err)
+	}
+	status, err := bigquerycommon.WaitForJob(ctx, job)
+	if err != nil {
+		return nil, fmt.Errorf("unable to wait for load job %s: %w", job.ID(), err)
+	}
+	if err := status.Err(); err != nil {
+		return nil, fmt.Errorf("load job %s failed: %w", job.ID(), err)
+	}
+
+	var outputRows int64
+	if status.Statistics != nil {
+		if stats, ok := status.Statistics.Details.(*bigqueryapi.LoadStatistics); ok {
+			outputRows = stats.OutputRows
+		}
+	}
+	return map[string]any{
+		"jobId":            job.ID(),
+		"destinationTable": fmt.Sprintf("%s.%s.%s", projectId, datasetId, tableId),
+		"outputRows":       outputRows,
+	}, nil
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.Parameters, data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
+
+func (t Tool) RequiresClientAuthorization() bool {
+	return t.UseClientOAuth
+}
+
+// LoadConfig returns the configuration of the job that loads the files at the
+// given gs:// URIs, without its destination table. The schema is auto-detected
+// if schema is empty.
+func LoadConfig(rawURIs []any, sourceFormat, writeDisposition, schema string, skipLeadingRows int) (bigqueryapi.LoadConfig, error) {
+	sourceURIs := make([]string, 0, len(rawURIs))
+	for _, u := range rawURIs {
+		uri, ok := u.(string)
+		if !ok || !strings.HasPrefix(uri, "gs://") {
+			return bigqueryapi.LoadConfig{}, fmt.Errorf("invalid source URI %v: expected a gs:// URI", u)
+		}
+		sourceURIs = append(sourceURIs, uri)
+	}
+	if len(sourceURIs) == 0 {
+		return bigqueryapi.LoadConfig{}, fmt.Errorf("'%s' must contain at least one URI", sourceURIsKey)
+	}
+	format, ok := supportedFormats[strings.ToUpper(strings.TrimSpace(sourceFormat))]
+	if !ok {
+		return bigqueryapi.LoadConfig{}, fmt.Errorf("unsupported source_format %q: must be one of CSV, NEWLINE_DELIMITED_JSON, PARQUET, AVRO or ORC", sourceFormat)
+	}
+	disposition, ok := supportedWriteDispositions[strings.ToUpper(strings.TrimSpace(writeDisposition))]
+	if !ok {
+		return bigqueryapi.LoadConfig{}, fmt.Errorf("unsupported write_disposition %q: must be one of WRITE_APPEND, WRITE_TRUNCATE or WRITE_EMPTY", writeDisposition)
+	}
+	fields, err := parseSchema(schema)
+	if err != nil {
+		return bigqueryapi.LoadConfig{}, err
+	}
+
+	gcsRef := bigqueryapi.NewGCSReference(sourceURIs...)
+	gcsRef.SourceFormat = format
+	gcsRef.Schema = fields
+	gcsRef.AutoDetect = len(fields) == 0
+	if format == bigqueryapi.CSV {
+		gcsRef.SkipLeadingRows = int64(skipLeadingRows)
+	}
+	return bigqueryapi.LoadConfig{
+		Src:               gcsRef,
+		WriteDisposition:  disposition,
+		CreateDisposition: bigqueryapi.CreateIfNeeded,
+	}, nil
+}
+
+// parseSchema parses a comma-separated list of name:TYPE pairs into a schema.
+// An empty string returns a nil schema, which means the schema is auto-detected.
+func parseSchema(s string) (bigqueryapi.Schema, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	var schema bigqueryapi.Schema
+	for _, field := range strings.Split(s, ",") {
+		name, fieldType, ok := strings.Cut(strings.TrimSpace(field), ":")
+		name, fieldType = strings.TrimSpace(name), strings.TrimSpace(fieldType)
+		if !ok || name == "" || fieldType == "" {
+			return nil, fmt.Errorf("invalid schema field %q: expected name:TYPE", field)
+		}
+		schema = append(schema, &bigqueryapi.FieldSchema{
+			Name: name,
+			Type: bigqueryapi.FieldType(strings.ToUpper(fieldType)),
+		})
+	}
+	return schema, nil
+}
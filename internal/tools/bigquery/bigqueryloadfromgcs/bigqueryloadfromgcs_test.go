@@ -0,0 +1,248 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bigqueryloadfromgcs_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	bigqueryapi "cloud.google.com/go/bigquery"
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigqueryloadfromgcs"
+)
+
+func TestParseFromYamlBigQueryLoadFromGCS(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		want server.ToolConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			tools:
+				example_tool:
+					kind: bigquery-load-from-gcs
+					source: my-instance
+					description: some description
+			`,
+			want: server.ToolConfigs{
+				"example_tool": bigqueryloadfromgcs.Config{
+					Name:         "example_tool",
+					Kind:         "bigquery-load-from-gcs",
+					Source:       "my-instance",
+					Description:  "some description",
+					AuthRequired: []string{},
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			// Parse contents
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Tools); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+
+}
+
+func TestFailInvokeBigQueryLoadFromGCS(t *testing.T) {
+	// the destination and the load job config are checked before any call to
+	// BigQuery, so the tool needs no client
+	tool := bigqueryloadfromgcs.Tool{
+		IsProjectAllowed: func(string) bool { return true },
+		IsDatasetAllowed: func(_, datasetID string) bool { return datasetID != "private" },
+	}
+	params := func(dataset string, sourceURIs []any, sourceFormat, writeDisposition string) tools.ParamValues {
+		return tools.ParamValues{
+			{Name: "source_uris", Value: sourceURIs},
+			{Name: "project", Value: "my-project"},
+			{Name: "dataset", Value: dataset},
+			{Name: "table", Value: "my_table"},
+			{Name: "source_format", Value: sourceFormat},
+			{Name: "write_disposition", Value: writeDisposition},
+			{Name: "schema", Value: ""},
+			{Name: "skip_leading_rows", Value: 0},
+		}
+	}
+	tcs := []struct {
+		desc   string
+		params tools.ParamValues
+		err    string
+	}{
+		{
+			desc:   "dataset not allowed",
+			params: params("private", []any{"gs://my-bucket/data.csv"}, "CSV", "WRITE_APPEND"),
+			err:    "access denied to dataset 'private'",
+		},
+		{
+			desc:   "no source uris",
+			params: params("my_dataset", []any{}, "CSV", "WRITE_APPEND"),
+			err:    "'source_uris' must contain at least one URI",
+		},
+		{
+			desc:   "source uri outside of cloud storage",
+			params: params("my_dataset", []any{"gs://my-bucket/a.csv", "/tmp/b.csv"}, "CSV", "WRITE_APPEND"),
+			err:    "invalid source URI /tmp/b.csv: expected a gs:// URI",
+		},
+		{
+			desc:   "unsupported source format",
+			params: params("my_dataset", []any{"gs://my-bucket/data.xml"}, "XML", "WRITE_APPEND"),
+			err:    `unsupported source_format "XML"`,
+		},
+		{
+			desc:   "unsupported write disposition",
+			params: params("my_dataset", []any{"gs://my-bucket/data.csv"}, "CSV", "WRITE_MERGE"),
+			err:    `unsupported write_disposition "WRITE_MERGE"`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := tool.Invoke(context.Background(), tc.params, "")
+			if err == nil {
+				t.Fatalf("expect invocation to fail")
+			}
+			if !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("unexpected error string: got %q, want substring %q", err.Error(), tc.err)
+			}
+		})
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	tcs := []struct {
+		desc             string
+		uris             []any
+		sourceFormat     string
+		writeDisposition string
+		schema           string
+		skipLeadingRows  int
+		want             bigqueryapi.LoadConfig
+		wantErr          bool
+	}{
+		{
+			desc:             "csv with a schema",
+			uris:             []any{"gs://my-bucket/a.csv", "gs://my-bucket/b*.csv"},
+			sourceFormat:     "csv",
+			writeDisposition: "WRITE_TRUNCATE",
+			schema:           "id:int64, name : string",
+			skipLeadingRows:  1,
+			want: bigqueryapi.LoadConfig{
+				Src: &bigqueryapi.GCSReference{
+					URIs: []string{"gs://my-bucket/a.csv", "gs://my-bucket/b*.csv"},
+					FileConfig: bigqueryapi.FileConfig{
+						SourceFormat: bigqueryapi.CSV,
+						Schema: bigqueryapi.Schema{
+							{Name: "id", Type: bigqueryapi.FieldType("INT64")},
+							{Name: "name", Type: bigqueryapi.StringFieldType},
+						},
+						CSVOptions: bigqueryapi.CSVOptions{SkipLeadingRows: 1},
+					},
+				},
+				WriteDisposition:  bigqueryapi.WriteTruncate,
+				CreateDisposition: bigqueryapi.CreateIfNeeded,
+			},
+		},
+		{
+			desc:             "parquet with an auto-detected schema",
+			uris:             []any{"gs://my-bucket/data.parquet"},
+			sourceFormat:     "PARQUET",
+			writeDisposition: "WRITE_APPEND",
+			skipLeadingRows:  1,
+			want: bigqueryapi.LoadConfig{
+				Src: &bigqueryapi.GCSReference{
+					URIs: []string{"gs://my-bucket/data.parquet"},
+					FileConfig: bigqueryapi.FileConfig{
+						SourceFormat: bigqueryapi.Parquet,
+						AutoDetect:   true,
+					},
+				},
+				WriteDisposition:  bigqueryapi.WriteAppend,
+				CreateDisposition: bigqueryapi.CreateIfNeeded,
+			},
+		},
+		{
+			desc:             "no uris",
+			uris:             []any{},
+			sourceFormat:     "CSV",
+			writeDisposition: "WRITE_APPEND",
+			wantErr:          true,
+		},
+		{
+			desc:             "not a gs uri",
+			uris:             []any{"https://storage.googleapis.com/my-bucket/a.csv"},
+			sourceFormat:     "CSV",
+			writeDisposition: "WRITE_APPEND",
+			wantErr:          true,
+		},
+		{
+			desc:             "unsupported format",
+			uris:             []any{"gs://my-bucket/a.xml"},
+			sourceFormat:     "XML",
+			writeDisposition: "WRITE_APPEND",
+			wantErr:          true,
+		},
+		{
+			desc:             "unsupported write disposition",
+			uris:             []any{"gs://my-bucket/a.csv"},
+			sourceFormat:     "CSV",
+			writeDisposition: "WRITE_SOMETIMES",
+			wantErr:          true,
+		},
+		{
+			desc:             "invalid schema",
+			uris:             []any{"gs://my-bucket/a.csv"},
+			sourceFormat:     "CSV",
+			writeDisposition: "WRITE_APPEND",
+			schema:           "id INT64",
+			wantErr:          true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := bigqueryloadfromgcs.LoadConfig(tc.uris, tc.sourceFormat, tc.writeDisposition, tc.schema, tc.skipLeadingRows)
+			if err != nil {
+				if tc.wantErr {
+					return
+				}
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if tc.wantErr {
+				t.Fatalf("expected error but got nil")
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect load config: diff %v", diff)
+			}
+		})
+	}
+}
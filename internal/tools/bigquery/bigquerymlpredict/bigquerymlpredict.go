@@ -0,0 +1,430 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bigquerymlpredict
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	bigqueryapi "cloud.google.com/go/bigquery"
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	bigqueryds "github.com/googleapis/genai-toolbox/internal/sources/bigquery"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigquerycommon"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	bigqueryrestapi "google.golang.org/api/bigquery/v2"
+)
+
+const kind string = "bigquery-ml-predict"
+const modelKey string = "model"
+const inputDataKey string = "input_data"
+const rowsKey string = "rows"
+
+// modelReferenceRegex matches `project.dataset.model` and `dataset.model`, as
+// well as table IDs of the same form.
+var modelReferenceRegex = regexp.MustCompile(`^(?:([a-z][a-z0-9-]{4,28}[a-z0-9])\.)?([A-Za-z0-9_]+)\.([A-Za-z0-9_]+)$`)
+
+// columnNameRegex matches the column names allowed in inline rows.
+var columnNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type compatibleSource interface {
+	BigQueryClient() *bigqueryapi.Client
+	BigQueryQueryTag() string
+	BigQueryRestService() *bigqueryrestapi.Service
+	BigQueryClientCreator() bigqueryds.BigqueryClientCreator
+	UseClientAuthorization() bool
+	BigQueryAllowedDatasets() []string
+	IsDatasetAllowed(projectID, datasetID string) bool
+	BigQueryAllowedProjects() []string
+	BigQueryClientForProject(projectID, tokenString string) (*bigqueryapi.Client, *bigqueryrestapi.Service, error)
+	ReserveQuota(ctx context.Context, tokenString string, bytes int64) error
+}
+
+// validate compatible sources are still compatible
+var _ compatibleSource = &bigqueryds.Source{}
+
+var compatibleSources = [...]string{bigqueryds.SourceKind}
+
+type Config struct {
+	Name         string   `yaml:"name" validate:"required"`
+	Kind         string   `yaml:"kind" validate:"required"`
+	Source       string   `yaml:"source" validate:"required"`
+	Description  string   `yaml:"description" validate:"required"`
+	AuthRequired []string `yaml:"authRequired"`
+}
+
+// validate interface
+var _ tools.ToolConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	// verify source exists
+	rawS, ok := srcs[cfg.Source]
+	if !ok {
+		return nil, fmt.Errorf("no source named %q configured", cfg.Source)
+	}
+
+	// verify the source is compatible
+	s, ok := rawS.(compatibleSource)
+	if !ok {
+		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
+	}
+
+	modelParameter := tools.NewStringParameter(modelKey,
+		"The trained model to predict with, as `project.dataset.model` or `dataset.model`.")
+	inputDataParameter := tools.NewStringParameterWithDefault(inputDataKey, "",
+		"The table id or the query of the data to predict for. Its columns must match the model's features. "+
+			"Exactly one of `input_data` and `rows` must be set.")
+	rowsParameter := tools.NewStringParameterWithDefault(rowsKey, "",
+		"A JSON array of objects, one per row to predict for, keyed by feature name, e.g. `[{\"age\": 42, \"country\": \"US\"}]`. "+
+			"Exactly one of `input_data` and `rows` must be set.")
+	parameters := tools.Parameters{modelParameter, inputDataParameter, rowsParameter}
+	// On sources with allowedProjects, the project parameter selects the
+	// project to run in.
+	allowedProjects := s.BigQueryAllowedProjects()
+	if len(allowedProjects) > 0 {
+		parameters = append(parameters, bigquerycommon.ProjectParameter(allowedProjects))
+	}
+
+	mcpManifest := tools.McpManifest{
+		Name:        cfg.Name,
+		Description: cfg.Description,
+		InputSchema: parameters.McpManifest(),
+	}
+
+	// finish tool setup
+	t := Tool{
+		Name:                   cfg.Name,
+		Kind:                   kind,
+		Parameters:             parameters,
+		AuthRequired:           cfg.AuthRequired,
+		UseClientOAuth:         s.UseClientAuthorization(),
+		ClientCreator:          s.BigQueryClientCreator(),
+		Client:                 s.BigQueryClient(),
+		QueryTag:               s.BigQueryQueryTag(),
+		MultiProject:           len(allowedProjects) > 0,
+		ClientForProject:       s.BigQueryClientForProject,
+		ReserveQuota:           s.ReserveQuota,
+		RestService:            s.BigQueryRestService(),
+		IsDatasetAllowed:       s.IsDatasetAllowed,
+		HasDatasetRestrictions: len(s.BigQueryAllowedDatasets()) > 0,
+		manifest:               tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:            mcpManifest,
+	}
+	return t, nil
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name           string           `yaml:"name"`
+	Kind           string           `yaml:"kind"`
+	AuthRequired   []string         `yaml:"authRequired"`
+	UseClientOAuth bool             `yaml:"useClientOAuth"`
+	Parameters     tools.Parameters `yaml:"parameters"`
+
+	Client                 *bigqueryapi.Client
+	QueryTag               string
+	MultiProject           bool
+	ClientForProject       bigquerycommon.ClientForProjectFunc
+	ReserveQuota           bigquerycommon.ReserveQuotaFunc
+	RestService            *bigqueryrestapi.Service
+	ClientCreator          bigqueryds.BigqueryClientCreator
+	IsDatasetAllowed       func(projectID, datasetID string) bool
+	HasDatasetRestrictions bool
+	manifest               tools.Manifest
+	mcpManifest            tools.McpManifest
+}
+
+func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
+	paramsMap := params.AsMap()
+	model, ok := paramsMap[modelKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("unable to cast %s parameter %v", modelKey, paramsMap[modelKey])
+	}
+	inputData, ok := paramsMap[inputDataKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("unable to cast %s parameter %v", inputDataKey, paramsMap[inputDataKey])
+	}
+	rows, ok := paramsMap[rowsKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("unable to cast %s parameter %v", rowsKey, paramsMap[rowsKey])
+	}
+	if (inputData == "") == (rows == "") {
+		return nil, fmt.Errorf("exactly one of '%s' and '%s' must be set", inputDataKey, rowsKey)
+	}
+
+	bqClient := t.Client
+	restService := t.RestService
+	var err error
+
+	// Use the clients of the project, or initialize new ones if using user
+	// OAuth token
+	if project, ok := paramsMap[bigquerycommon.ProjectKey].(string); ok && t.MultiProject {
+		// Sources with allowedProjects run the query in the requested project.
+		bqClient, restService, err = bigquerycommon.ProjectClient(t.ClientForProject, project, t.UseClientOAuth, accessToken)
+		if err != nil {
+			return nil, err
+		}
+	} else if t.UseClientOAuth {
+		tokenStr, err := accessToken.ParseBearerToken()
+		if err != nil {
+			return nil, fmt.Errorf("error parsing access token: %w", err)
+		}
+		bqClient, restService, err = t.ClientCreator(tokenStr, true)
+		if err != nil {
+			return nil, fmt.Errorf("error creating client from OAuth access token: %w", err)
+		}
+	}
+
+	modelProject, modelDataset, modelID, err := parseModelReference(model, bqClient.Project())
+	if err != nil {
+		return nil, err
+	}
+	if !t.IsDatasetAllowed(modelProject, modelDataset) {
+		return nil, fmt.Errorf("access denied to dataset '%s' because it is not in the configured list of allowed datasets for project '%s'", modelDataset, modelProject)
+	}
+
+	var inputDataSource string
+	var queryParameters []bigqueryapi.QueryParameter
+	if rows != "" {
+		rowsSQL, rowsParameters, err := InlineRowsQuery(rows)
+		if err != nil {
+			return nil, err
+		}
+		inputDataSource = fmt.Sprintf("(%s)", rowsSQL)
+		queryParameters = rowsParameters
+	} else {
+		var tableProject, tableDataset string
+		inputDataSource, tableProject, tableDataset, err = InputDataSource(inputData, bqClient.Project())
+		if err != nil {
+			return nil, err
+		}
+		if tableDataset != "" && !t.IsDatasetAllowed(tableProject, tableDataset) {
+			return nil, fmt.Errorf("access denied to dataset '%s' because it is not in the configured list of allowed datasets for project '%s'", tableDataset, tableProject)
+		}
+	}
+
+	sql := fmt.Sprintf(`SELECT *
+		FROM ML.PREDICT(
+			MODEL `+"`%s.%s.%s`"+`,
+			%s)`,
+		modelProject, modelDataset, modelID, inputDataSource)
+
+	// The model and input_data tables are checked above, and inline rows don't
+	// read any tables, so only the tables read by input_data queries, or
+	// through views, are left to check.
+	if t.HasDatasetRestrictions && inputData != "" {
+		dryRunJob, err := bigquerycommon.DryRunQuery(ctx, restService, bqClient.Project(), bqClient.Location, sql, nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("query validation failed during dry run: %w", err)
+		}
+		if dryRunJob.Statistics != nil && dryRunJob.Statistics.Query != nil {
+			for _, table := range dryRunJob.Statistics.Query.ReferencedTables {
+				if !t.IsDatasetAllowed(table.ProjectId, table.DatasetId) {
+					return nil, fmt.Errorf("access denied to dataset '%s' because it is not in the configured list of allowed datasets for project '%s'", table.DatasetId, table.ProjectId)
+				}
+			}
+		}
+	}
+
+	query := bqClient.Query(sql)
+	bigquerycommon.SetQueryTag(query, t.QueryTag)
+	query.Parameters = queryParameters
+	query.Location = bqClient.Location
+
+	// Log the query executed for debugging. The statement may hold sensitive
+	// values, so only its type and length are logged.
+	logger, err := util.LoggerFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting logger: %s", err)
+	}
+	logger.DebugContext(ctx, "executing `%s` tool query: %s", kind, tools.DescribeStatement(sql))
+
+	quota, err := bigquerycommon.NewQuota(t.ReserveQuota, t.UseClientOAuth, accessToken)
+	if err != nil {
+		return nil, err
+	}
+	it, err := bigquerycommon.ReadQuery(ctx, query, quota)
+	if err != nil {
+		return nil, fmt.Errorf("unable to execute query: %w", err)
+	}
+	out, err := bigquerycommon.ReadRows(it, nil)
+	if err != nil {
+		return nil, err
+	}
+	out = bigquerycommon.NormalizeRows(out, "")
+	if len(out) > 0 {
+		return out, nil
+	}
+	return tools.ZeroRowsMessage, nil
+}
+
+// InlineRowsQuery turns a JSON array of objects into a query that selects one
+// row per object, with the values passed as query parameters. Every object
+// must have the same keys, which become the column names. Values must be
+// strings, numbers, booleans or null.
+func InlineRowsQuery(rowsJSON string) (string, []bigqueryapi.QueryParameter, error) {
+	var rows []map[string]any
+	if err := util.DecodeJSON(strings.NewReader(rowsJSON), &rows); err != nil {
+		return "", nil, fmt.Errorf("'%s' must be a JSON array of objects: %w", rowsKey, err)
+	}
+	if len(rows) == 0 {
+		return "", nil, fmt.Errorf("'%s' must hold at least one row", rowsKey)
+	}
+
+	columns := make([]string, 0, len(rows[0]))
+	for column := range rows[0] {
+		if !columnNameRegex.MatchString(column) {
+			return "", nil, fmt.Errorf("invalid column name %q in '%s'", column, rowsKey)
+		}
+		columns = append(columns, column)
+	}
+	if len(columns) == 0 {
+		return "", nil, fmt.Errorf("the rows in '%s' must have at least one column", rowsKey)
+	}
+	sort.Strings(columns)
+
+	var selects []string
+	var queryParameters []bigqueryapi.QueryParameter
+	for i, row := range rows {
+		if len(row) != len(columns) {
+			return "", nil, fmt.Errorf("row %d in '%s' doesn't have the columns %q", i, rowsKey, columns)
+		}
+		fields := make([]string, 0, len(columns))
+		for j, column := range columns {
+			value, ok := row[column]
+			if !ok {
+				return "", nil, fmt.Errorf("row %d in '%s' doesn't have the columns %q", i, rowsKey, columns)
+			}
+			if value == nil {
+				fields = append(fields, fmt.Sprintf("NULL AS `%s`", column))
+				continue
+			}
+			v, err := rowValue(value)
+			if err != nil {
+				return "", nil, fmt.Errorf("invalid value for column %q of row %d in '%s': %w", column, i, rowsKey, err)
+			}
+			name := fmt.Sprintf("row%d_%d", i, j)
+			fields = append(fields, fmt.Sprintf("@%s AS `%s`", name, column))
+			queryParameters = append(queryParameters, bigqueryapi.QueryParameter{Name: name, Value: v})
+		}
+		selects = append(selects, "SELECT "+strings.Join(fields, ", "))
+	}
+	return strings.Join(selects, " UNION ALL "), queryParameters, nil
+}
+
+// rowValue converts a value decoded from JSON into a query parameter value.
+// Whole numbers become INT64 and other numbers FLOAT64.
+func rowValue(value any) (any, error) {
+	switch v := value.(type) {
+	case string, bool:
+		return v, nil
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i, nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	default:
+		return nil, fmt.Errorf("%T values are not supported, use a string, number, boolean or null", value)
+	}
+}
+
+// parseModelReference splits a model reference into its project, dataset and
+// model IDs, using defaultProject when the reference has no project.
+// InputDataSource returns the input data argument of ML.PREDICT for
+// inputData, which is either a query or a table ID. Queries start with SELECT
+// or WITH, possibly after opening parentheses. Table IDs are checked like
+// model references, so they can't break out of the quoted path, and their
+// project, which defaults to defaultProject, and dataset are returned.
+func InputDataSource(inputData, defaultProject string) (source, project, dataset string, err error) {
+	trimmed := strings.TrimSpace(inputData)
+	keyword := strings.TrimLeft(trimmed, "( \t\r\n")
+	if end := strings.IndexFunc(keyword, func(r rune) bool { return !unicode.IsLetter(r) }); end >= 0 {
+		keyword = keyword[:end]
+	}
+	if strings.EqualFold(keyword, "SELECT") || strings.EqualFold(keyword, "WITH") {
+		return fmt.Sprintf("(%s)", trimmed), "", "", nil
+	}
+	matches := modelReferenceRegex.FindStringSubmatch(trimmed)
+	if matches == nil {
+		return "", "", "", fmt.Errorf("invalid %s %q, expected a query or a table as `project.dataset.table` or `dataset.table`", inputDataKey, inputData)
+	}
+	project = matches[1]
+	if project == "" {
+		project = defaultProject
+	}
+	return fmt.Sprintf("TABLE `%s.%s.%s`", project, matches[2], matches[3]), project, matches[2], nil
+}
+
+func parseModelReference(ref, defaultProject string) (string, string, string, error) {
+	matches := modelReferenceRegex.FindStringSubmatch(strings.TrimSpace(ref))
+	if matches == nil {
+		return "", "", "", fmt.Errorf("invalid model reference %q, expected `project.dataset.model` or `dataset.model`", ref)
+	}
+	project := matches[1]
+	if project == "" {
+		project = defaultProject
+	}
+	return project, matches[2], matches[3], nil
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.Parameters, data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
+
+func (t Tool) RequiresClientAuthorization() bool {
+	return t.UseClientOAuth
+}
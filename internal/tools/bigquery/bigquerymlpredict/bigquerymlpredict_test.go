@@ -0,0 +1,253 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bigquerymlpredict_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	bigqueryapi "cloud.google.com/go/bigquery"
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigquerymlpredict"
+	"google.golang.org/api/option"
+)
+
+func TestParseFromYamlBigQueryMLPredict(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		want server.ToolConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			tools:
+				example_tool:
+					kind: bigquery-ml-predict
+					source: my-instance
+					description: some description
+			`,
+			want: server.ToolConfigs{
+				"example_tool": bigquerymlpredict.Config{
+					Name:         "example_tool",
+					Kind:         "bigquery-ml-predict",
+					Source:       "my-instance",
+					Description:  "some description",
+					AuthRequired: []string{},
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			// Parse contents
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Tools); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+
+}
+
+func TestInlineRowsQuery(t *testing.T) {
+	tcs := []struct {
+		desc       string
+		in         string
+		wantSQL    string
+		wantParams []bigqueryapi.QueryParameter
+		wantErr    bool
+	}{
+		{
+			desc:    "single row",
+			in:      `[{"country": "US", "age": 42}]`,
+			wantSQL: "SELECT @row0_0 AS `age`, @row0_1 AS `country`",
+			wantParams: []bigqueryapi.QueryParameter{
+				{Name: "row0_0", Value: int64(42)},
+				{Name: "row0_1", Value: "US"},
+			},
+		},
+		{
+			desc:    "several rows with a null",
+			in:      `[{"score": 0.5, "active": true}, {"score": null, "active": false}]`,
+			wantSQL: "SELECT @row0_0 AS `active`, @row0_1 AS `score` UNION ALL SELECT @row1_0 AS `active`, NULL AS `score`",
+			wantParams: []bigqueryapi.QueryParameter{
+				{Name: "row0_0", Value: true},
+				{Name: "row0_1", Value: 0.5},
+				{Name: "row1_0", Value: false},
+			},
+		},
+		{
+			desc:    "not json",
+			in:      "age=42",
+			wantErr: true,
+		},
+		{
+			desc:    "no rows",
+			in:      "[]",
+			wantErr: true,
+		},
+		{
+			desc:    "rows with different columns",
+			in:      `[{"age": 42}, {"country": "US"}]`,
+			wantErr: true,
+		},
+		{
+			desc:    "invalid column name",
+			in:      "[{\"age`; DROP TABLE t; --\": 42}]",
+			wantErr: true,
+		},
+		{
+			desc:    "nested value",
+			in:      `[{"tags": ["a", "b"]}]`,
+			wantErr: true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			gotSQL, gotParams, err := bigquerymlpredict.InlineRowsQuery(tc.in)
+			if err != nil {
+				if tc.wantErr {
+					return
+				}
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if tc.wantErr {
+				t.Fatalf("expected error but got nil")
+			}
+			if gotSQL != tc.wantSQL {
+				t.Fatalf("incorrect query: got %q, want %q", gotSQL, tc.wantSQL)
+			}
+			if diff := cmp.Diff(tc.wantParams, gotParams); diff != "" {
+				t.Fatalf("incorrect query parameters: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestInputDataSource(t *testing.T) {
+	tcs := []struct {
+		desc        string
+		in          string
+		wantSource  string
+		wantProject string
+		wantDataset string
+		wantErr     bool
+	}{
+		{
+			desc:        "table",
+			in:          "my-project.my_dataset.my_table",
+			wantSource:  "TABLE `my-project.my_dataset.my_table`",
+			wantProject: "my-project",
+			wantDataset: "my_dataset",
+		},
+		{
+			desc:        "table in the default project",
+			in:          " my_dataset.my_table ",
+			wantSource:  "TABLE `default-project.my_dataset.my_table`",
+			wantProject: "default-project",
+			wantDataset: "my_dataset",
+		},
+		{
+			desc:       "query",
+			in:         "select * from my_dataset.my_table",
+			wantSource: "(select * from my_dataset.my_table)",
+		},
+		{
+			desc:       "query in parentheses",
+			in:         "(SELECT 1 AS x)",
+			wantSource: "((SELECT 1 AS x))",
+		},
+		{
+			desc:       "query with a common table expression",
+			in:         "WITH t AS (SELECT 1 AS x) SELECT * FROM t",
+			wantSource: "(WITH t AS (SELECT 1 AS x) SELECT * FROM t)",
+		},
+		{
+			desc:        "dataset named like a keyword",
+			in:          "selections.my_table",
+			wantSource:  "TABLE `default-project.selections.my_table`",
+			wantProject: "default-project",
+			wantDataset: "selections",
+		},
+		{
+			desc:    "table breaking out of the quoted path",
+			in:      "my_dataset.my_table` LIMIT 1; DROP TABLE my_dataset.other; --",
+			wantErr: true,
+		},
+		{
+			desc:    "table without a dataset",
+			in:      "my_table",
+			wantErr: true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			source, project, dataset, err := bigquerymlpredict.InputDataSource(tc.in, "default-project")
+			if err != nil {
+				if tc.wantErr {
+					return
+				}
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if tc.wantErr {
+				t.Fatalf("expected error but got nil")
+			}
+			if source != tc.wantSource || project != tc.wantProject || dataset != tc.wantDataset {
+				t.Fatalf("incorrect input data source: got (%q, %q, %q), want (%q, %q, %q)", source, project, dataset, tc.wantSource, tc.wantProject, tc.wantDataset)
+			}
+		})
+	}
+}
+
+func TestInvokeChecksInputDataTable(t *testing.T) {
+	ctx := context.Background()
+	// the dataset of the table is checked before any call to BigQuery
+	client, err := bigqueryapi.NewClient(ctx, "my-project", option.WithEndpoint("http://127.0.0.1:0"), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("unable to create client: %s", err)
+	}
+	defer client.Close()
+	tool := bigquerymlpredict.Tool{
+		Client:                 client,
+		HasDatasetRestrictions: true,
+		IsDatasetAllowed: func(projectID, datasetID string) bool {
+			return projectID == "my-project" && datasetID == "models"
+		},
+	}
+	_, err = tool.Invoke(ctx, tools.ParamValues{
+		{Name: "model", Value: "models.my_model"},
+		{Name: "input_data", Value: "other-project.models.my_table"},
+		{Name: "rows", Value: ""},
+	}, "")
+	if err == nil || !strings.Contains(err.Error(), "access denied to dataset 'models'") || !strings.Contains(err.Error(), "'other-project'") {
+		t.Fatalf("expected access to the table's dataset to be denied, got %v", err)
+	}
+}
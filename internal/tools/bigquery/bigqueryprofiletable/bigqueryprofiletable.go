@@ -0,0 +1,368 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bigqueryprofiletable
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	bigqueryapi "cloud.google.com/go/bigquery"
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	bigqueryds "github.com/googleapis/genai-toolbox/internal/sources/bigquery"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigquerycommon"
+	bigqueryrestapi "google.golang.org/api/bigquery/v2"
+	"google.golang.org/api/iterator"
+)
+
+const kind string = "bigquery-profile-table"
+const projectKey string = "project"
+const datasetKey string = "dataset"
+const tableKey string = "table"
+
+// distinctTypes are the column types that APPROX_COUNT_DISTINCT accepts.
+var distinctTypes = map[bigqueryapi.FieldType]bool{
+	bigqueryapi.StringFieldType:     true,
+	bigqueryapi.BytesFieldType:      true,
+	bigqueryapi.IntegerFieldType:    true,
+	bigqueryapi.FloatFieldType:      true,
+	bigqueryapi.NumericFieldType:    true,
+	bigqueryapi.BigNumericFieldType: true,
+	bigqueryapi.BooleanFieldType:    true,
+	bigqueryapi.TimestampFieldType:  true,
+	bigqueryapi.DateFieldType:       true,
+	bigqueryapi.TimeFieldType:       true,
+	bigqueryapi.DateTimeFieldType:   true,
+}
+
+// numericTypes are the column types that get their min, average and max.
+var numericTypes = map[bigqueryapi.FieldType]bool{
+	bigqueryapi.IntegerFieldType:    true,
+	bigqueryapi.FloatFieldType:      true,
+	bigqueryapi.NumericFieldType:    true,
+	bigqueryapi.BigNumericFieldType: true,
+}
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type compatibleSource interface {
+	BigQueryProject() string
+	BigQueryClient() *bigqueryapi.Client
+	BigQueryQueryTag() string
+	BigQueryClientCreator() bigqueryds.BigqueryClientCreator
+	UseClientAuthorization() bool
+	IsDatasetAllowed(projectID, datasetID string) bool
+	IsProjectAllowed(projectID string) bool
+	BigQueryAllowedProjects() []string
+	BigQueryClientForProject(projectID, tokenString string) (*bigqueryapi.Client, *bigqueryrestapi.Service, error)
+	ReserveQuota(ctx context.Context, tokenString string, bytes int64) error
+}
+
+// validate compatible sources are still compatible
+var _ compatibleSource = &bigqueryds.Source{}
+
+var compatibleSources = [...]string{bigqueryds.SourceKind}
+
+type Config struct {
+	Name         string   `yaml:"name" validate:"required"`
+	Kind         string   `yaml:"kind" validate:"required"`
+	Source       string   `yaml:"source" validate:"required"`
+	Description  string   `yaml:"description" validate:"required"`
+	AuthRequired []string `yaml:"authRequired"`
+}
+
+// validate interface
+var _ tools.ToolConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	// verify source exists
+	rawS, ok := srcs[cfg.Source]
+	if !ok {
+		return nil, fmt.Errorf("no source named %q configured", cfg.Source)
+	}
+
+	// verify the source is compatible
+	s, ok := rawS.(compatibleSource)
+	if !ok {
+		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
+	}
+
+	projectParameter := tools.NewStringParameterWithDefault(projectKey, s.BigQueryProject(), "The Google Cloud project ID containing the dataset and table.")
+	datasetParameter := tools.NewStringParameter(datasetKey, "The table's parent dataset.")
+	tableParameter := tools.NewStringParameter(tableKey, "The table to profile.")
+	parameters := tools.Parameters{projectParameter, datasetParameter, tableParameter}
+
+	mcpManifest := tools.McpManifest{
+		Name:        cfg.Name,
+		Description: cfg.Description,
+		InputSchema: parameters.McpManifest(),
+	}
+
+	// finish tool setup
+	t := Tool{
+		Name:             cfg.Name,
+		Kind:             kind,
+		Parameters:       parameters,
+		AuthRequired:     cfg.AuthRequired,
+		UseClientOAuth:   s.UseClientAuthorization(),
+		ClientCreator:    s.BigQueryClientCreator(),
+		Client:           s.BigQueryClient(),
+		QueryTag:         s.BigQueryQueryTag(),
+		MultiProject:     len(s.BigQueryAllowedProjects()) > 0,
+		ClientForProject: s.BigQueryClientForProject,
+		ReserveQuota:     s.ReserveQuota,
+		IsProjectAllowed: s.IsProjectAllowed,
+		IsDatasetAllowed: s.IsDatasetAllowed,
+		manifest:         tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:      mcpManifest,
+	}
+	return t, nil
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name           string           `yaml:"name"`
+	Kind           string           `yaml:"kind"`
+	AuthRequired   []string         `yaml:"authRequired"`
+	UseClientOAuth bool             `yaml:"useClientOAuth"`
+	Parameters     tools.Parameters `yaml:"parameters"`
+
+	Client           *bigqueryapi.Client
+	QueryTag         string
+	MultiProject     bool
+	ClientForProject bigquerycommon.ClientForProjectFunc
+	ReserveQuota     bigquerycommon.ReserveQuotaFunc
+	ClientCreator    bigqueryds.BigqueryClientCreator
+	IsProjectAllowed func(projectID string) bool
+	IsDatasetAllowed func(projectID, datasetID string) bool
+	manifest         tools.Manifest
+	mcpManifest      tools.McpManifest
+}
+
+// TableProfile summarizes the contents of a table.
+type TableProfile struct {
+	RowCount int64           `json:"rowCount"`
+	Columns  []ColumnProfile `json:"columns"`
+}
+
+// ColumnProfile summarizes the values of a top-level column. The distinct
+// count is an estimate; it is omitted for types that can't be counted, such
+// as RECORD, JSON and GEOGRAPHY, and for REPEATED columns. Min, Avg and Max
+// are only set for numeric columns with values.
+type ColumnProfile struct {
+	Name                string `json:"name"`
+	Type                string `json:"type"`
+	NullCount           int64  `json:"nullCount"`
+	ApproxDistinctCount *int64 `json:"approxDistinctCount,omitempty"`
+	Min                 any    `json:"min,omitempty"`
+	Avg                 any    `json:"avg,omitempty"`
+	Max                 any    `json:"max,omitempty"`
+}
+
+// quoteIdentifier quotes a column name for use in a query.
+func quoteIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "\\`") + "`"
+}
+
+// ProfileStatement returns the query that computes the profile of the table
+// with the given schema, as a single row computed with conditional
+// aggregation. The columns of the row are named by the position of the
+// column in the schema, since column names may not be valid aliases.
+func ProfileStatement(table string, schema bigqueryapi.Schema) string {
+	exprs := []string{"COUNT(*) AS row_count"}
+	for i, f := range schema {
+		col := quoteIdentifier(f.Name)
+		exprs = append(exprs, fmt.Sprintf("COUNTIF(%s IS NULL) AS c%d_nulls", col, i))
+		if f.Repeated {
+			continue
+		}
+		if distinctTypes[f.Type] {
+			exprs = append(exprs, fmt.Sprintf("APPROX_COUNT_DISTINCT(%s) AS c%d_distinct", col, i))
+		}
+		if numericTypes[f.Type] {
+			exprs = append(exprs,
+				fmt.Sprintf("MIN(%s) AS c%d_min", col, i),
+				fmt.Sprintf("AVG(%s) AS c%d_avg", col, i),
+				fmt.Sprintf("MAX(%s) AS c%d_max", col, i),
+			)
+		}
+	}
+	return fmt.Sprintf("SELECT\n\t%s\nFROM %s", strings.Join(exprs, ",\n\t"), table)
+}
+
+// NewTableProfile reads the profile of the table with the given schema from
+// the row returned by the ProfileStatement query.
+func NewTableProfile(schema bigqueryapi.Schema, row map[string]bigqueryapi.Value) TableProfile {
+	rowCount, _ := row["row_count"].(int64)
+	profile := TableProfile{RowCount: rowCount, Columns: make([]ColumnProfile, 0, len(schema))}
+	for i, f := range schema {
+		c := ColumnProfile{Name: f.Name, Type: string(f.Type)}
+		if f.Repeated {
+			c.Type = "ARRAY<" + c.Type + ">"
+		}
+		c.NullCount, _ = row[fmt.Sprintf("c%d_nulls", i)].(int64)
+		if v, ok := row[fmt.Sprintf("c%d_distinct", i)].(int64); ok {
+			c.ApproxDistinctCount = &v
+		}
+		c.Min = profileValue(f.Type, row[fmt.Sprintf("c%d_min", i)])
+		c.Avg = profileValue(f.Type, row[fmt.Sprintf("c%d_avg", i)])
+		c.Max = profileValue(f.Type, row[fmt.Sprintf("c%d_max", i)])
+		profile.Columns = append(profile.Columns, c)
+	}
+	return profile
+}
+
+// profileValue formats NUMERIC and BIGNUMERIC values, which are read as
+// *big.Rat, as decimal strings.
+func profileValue(t bigqueryapi.FieldType, v bigqueryapi.Value) any {
+	r, ok := v.(*big.Rat)
+	if !ok {
+		return v
+	}
+	if t == bigqueryapi.BigNumericFieldType {
+		return bigqueryapi.BigNumericString(r)
+	}
+	return bigqueryapi.NumericString(r)
+}
+
+func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
+	mapParams := params.AsMap()
+	projectId, ok := mapParams[projectKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid or missing '%s' parameter; expected a string", projectKey)
+	}
+	if !t.IsProjectAllowed(projectId) {
+		return nil, fmt.Errorf("access denied to project '%s' because it is not in the configured list of allowed projects", projectId)
+	}
+
+	datasetId, ok := mapParams[datasetKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid or missing '%s' parameter; expected a string", datasetKey)
+	}
+
+	tableId, ok := mapParams[tableKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid or missing '%s' parameter; expected a string", tableKey)
+	}
+
+	if !t.IsDatasetAllowed(projectId, datasetId) {
+		return nil, fmt.Errorf("access denied to dataset '%s' because it is not in the configured list of allowed datasets for project '%s'", datasetId, projectId)
+	}
+	// The table path is interpolated as an identifier, so it must not be able
+	// to break out of the quoted path.
+	if strings.Contains(projectId, "`") || strings.Contains(datasetId, "`") || strings.Contains(tableId, "`") {
+		return nil, fmt.Errorf("invalid table name: %s.%s.%s", projectId, datasetId, tableId)
+	}
+
+	bqClient := t.Client
+	// Use the clients of the project, or initialize new ones if using user
+	// OAuth token
+	if t.MultiProject {
+		// Sources with allowedProjects run the job in the project of the table.
+		var err error
+		bqClient, _, err = bigquerycommon.ProjectClient(t.ClientForProject, projectId, t.UseClientOAuth, accessToken)
+		if err != nil {
+			return nil, err
+		}
+	} else if t.UseClientOAuth {
+		tokenStr, err := accessToken.ParseBearerToken()
+		if err != nil {
+			return nil, fmt.Errorf("error parsing access token: %w", err)
+		}
+		bqClient, _, err = t.ClientCreator(tokenStr, false)
+		if err != nil {
+			return nil, fmt.Errorf("error creating client from OAuth access token: %w", err)
+		}
+	}
+
+	md, err := bqClient.DatasetInProject(projectId, datasetId).Table(tableId).Metadata(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get metadata for table %s.%s.%s: %w", projectId, datasetId, tableId, err)
+	}
+
+	table := fmt.Sprintf("`%s`.`%s`.`%s`", projectId, datasetId, tableId)
+	query := bqClient.Query(ProfileStatement(table, md.Schema))
+	bigquerycommon.SetQueryTag(query, t.QueryTag)
+	query.Location = md.Location
+
+	quota, err := bigquerycommon.NewQuota(t.ReserveQuota, t.UseClientOAuth, accessToken)
+	if err != nil {
+		return nil, err
+	}
+	job, err := bigquerycommon.RunQuery(ctx, query, quota)
+	if err != nil {
+		return nil, fmt.Errorf("failed to profile table %s.%s.%s: %w", projectId, datasetId, tableId, err)
+	}
+	status, err := bigquerycommon.WaitForJob(ctx, job)
+	if err != nil {
+		return nil, fmt.Errorf("unable to wait for query to complete: %w", err)
+	}
+	if err := bigquerycommon.JobError(status.Err(), status.Errors); err != nil {
+		return nil, fmt.Errorf("failed to profile table %s.%s.%s: %w", projectId, datasetId, tableId, err)
+	}
+	it, err := job.Read(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read query results: %w", err)
+	}
+	var row map[string]bigqueryapi.Value
+	if err := it.Next(&row); err != nil {
+		if err == iterator.Done {
+			return nil, fmt.Errorf("profile query for table %s.%s.%s returned no rows", projectId, datasetId, tableId)
+		}
+		return nil, fmt.Errorf("unable to iterate through query results: %w", err)
+	}
+	return NewTableProfile(md.Schema, row), nil
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.Parameters, data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
+
+func (t Tool) RequiresClientAuthorization() bool {
+	return t.UseClientOAuth
+}
@@ -0,0 +1,158 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bigqueryprofiletable_test
+
+import (
+	"math/big"
+	"testing"
+
+	bigqueryapi "cloud.google.com/go/bigquery"
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigqueryprofiletable"
+)
+
+func TestParseFromYamlBigQueryProfileTable(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		want server.ToolConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			tools:
+				example_tool:
+					kind: bigquery-profile-table
+					source: my-instance
+					description: some description
+			`,
+			want: server.ToolConfigs{
+				"example_tool": bigqueryprofiletable.Config{
+					Name:         "example_tool",
+					Kind:         "bigquery-profile-table",
+					Source:       "my-instance",
+					Description:  "some description",
+					AuthRequired: []string{},
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			// Parse contents
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Tools); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+
+}
+
+var testSchema = bigqueryapi.Schema{
+	{Name: "id", Type: bigqueryapi.IntegerFieldType},
+	{Name: "name", Type: bigqueryapi.StringFieldType},
+	{Name: "price", Type: bigqueryapi.NumericFieldType},
+	{Name: "tags", Type: bigqueryapi.StringFieldType, Repeated: true},
+	{Name: "location", Type: bigqueryapi.GeographyFieldType},
+}
+
+func TestProfileStatement(t *testing.T) {
+	got := bigqueryprofiletable.ProfileStatement("`p`.`d`.`t`", testSchema)
+	want := "SELECT\n" +
+		"\tCOUNT(*) AS row_count,\n" +
+		"\tCOUNTIF(`id` IS NULL) AS c0_nulls,\n" +
+		"\tAPPROX_COUNT_DISTINCT(`id`) AS c0_distinct,\n" +
+		"\tMIN(`id`) AS c0_min,\n" +
+		"\tAVG(`id`) AS c0_avg,\n" +
+		"\tMAX(`id`) AS c0_max,\n" +
+		"\tCOUNTIF(`name` IS NULL) AS c1_nulls,\n" +
+		"\tAPPROX_COUNT_DISTINCT(`name`) AS c1_distinct,\n" +
+		"\tCOUNTIF(`price` IS NULL) AS c2_nulls,\n" +
+		"\tAPPROX_COUNT_DISTINCT(`price`) AS c2_distinct,\n" +
+		"\tMIN(`price`) AS c2_min,\n" +
+		"\tAVG(`price`) AS c2_avg,\n" +
+		"\tMAX(`price`) AS c2_max,\n" +
+		"\tCOUNTIF(`tags` IS NULL) AS c3_nulls,\n" +
+		"\tCOUNTIF(`location` IS NULL) AS c4_nulls\n" +
+		"FROM `p`.`d`.`t`"
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("incorrect statement: diff %v", diff)
+	}
+}
+
+func TestNewTableProfile(t *testing.T) {
+	row := map[string]bigqueryapi.Value{
+		"row_count":   int64(4),
+		"c0_nulls":    int64(0),
+		"c0_distinct": int64(4),
+		"c0_min":      int64(1),
+		"c0_avg":      2.5,
+		"c0_max":      int64(4),
+		"c1_nulls":    int64(1),
+		"c1_distinct": int64(3),
+		"c2_nulls":    int64(4),
+		"c2_distinct": int64(0),
+		"c2_min":      nil,
+		"c2_avg":      nil,
+		"c2_max":      nil,
+		"c3_nulls":    int64(0),
+		"c4_nulls":    int64(2),
+	}
+	four, three, zero := int64(4), int64(3), int64(0)
+	want := bigqueryprofiletable.TableProfile{
+		RowCount: 4,
+		Columns: []bigqueryprofiletable.ColumnProfile{
+			{Name: "id", Type: "INTEGER", NullCount: 0, ApproxDistinctCount: &four, Min: int64(1), Avg: 2.5, Max: int64(4)},
+			{Name: "name", Type: "STRING", NullCount: 1, ApproxDistinctCount: &three},
+			{Name: "price", Type: "NUMERIC", NullCount: 4, ApproxDistinctCount: &zero},
+			{Name: "tags", Type: "ARRAY<STRING>", NullCount: 0},
+			{Name: "location", Type: "GEOGRAPHY", NullCount: 2},
+		},
+	}
+	got := bigqueryprofiletable.NewTableProfile(testSchema, row)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("incorrect profile: diff %v", diff)
+	}
+}
+
+func TestNewTableProfileNumeric(t *testing.T) {
+	schema := bigqueryapi.Schema{{Name: "price", Type: bigqueryapi.NumericFieldType}}
+	row := map[string]bigqueryapi.Value{
+		"row_count":   int64(2),
+		"c0_nulls":    int64(0),
+		"c0_distinct": int64(2),
+		"c0_min":      big.NewRat(1, 2),
+		"c0_avg":      big.NewRat(3, 4),
+		"c0_max":      big.NewRat(1, 1),
+	}
+	got := bigqueryprofiletable.NewTableProfile(schema, row).Columns[0]
+	if got.Min != "0.500000000" || got.Avg != "0.750000000" || got.Max != "1.000000000" {
+		t.Fatalf("numeric values should be decimal strings: got min %v, avg %v, max %v", got.Min, got.Avg, got.Max)
+	}
+}
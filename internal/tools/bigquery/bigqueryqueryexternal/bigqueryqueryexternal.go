@@ -0,0 +1,343 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bigqueryqueryexternal
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	bigqueryapi "cloud.google.com/go/bigquery"
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	bigqueryds "github.com/googleapis/genai-toolbox/internal/sources/bigquery"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigquerycommon"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	bigqueryrestapi "google.golang.org/api/bigquery/v2"
+	"google.golang.org/api/iterator"
+)
+
+const kind string = "bigquery-query-external"
+
+// supportedFormats maps the accepted source_format values to BigQuery data formats.
+var supportedFormats = map[string]bigqueryapi.DataFormat{
+	"CSV":                    bigqueryapi.CSV,
+	"NEWLINE_DELIMITED_JSON": bigqueryapi.JSON,
+	"PARQUET":                bigqueryapi.Parquet,
+	"AVRO":                   bigqueryapi.Avro,
+	"ORC":                    bigqueryapi.ORC,
+}
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type compatibleSource interface {
+	BigQueryClient() *bigqueryapi.Client
+	BigQueryQueryTag() string
+	BigQueryRestService() *bigqueryrestapi.Service
+	BigQueryClientCreator() bigqueryds.BigqueryClientCreator
+	UseClientAuthorization() bool
+	BigQueryAllowedProjects() []string
+	BigQueryClientForProject(projectID, tokenString string) (*bigqueryapi.Client, *bigqueryrestapi.Service, error)
+	ReserveQuota(ctx context.Context, tokenString string, bytes int64) error
+}
+
+// validate compatible sources are still compatible
+var _ compatibleSource = &bigqueryds.Source{}
+
+var compatibleSources = [...]string{bigqueryds.SourceKind}
+
+type Config struct {
+	Name         string   `yaml:"name" validate:"required"`
+	Kind         string   `yaml:"kind" validate:"required"`
+	Source       string   `yaml:"source" validate:"required"`
+	Description  string   `yaml:"description" validate:"required"`
+	AuthRequired []string `yaml:"authRequired"`
+}
+
+// validate interface
+var _ tools.StatementConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+// TakesStatement marks the tool as one that runs the statement given in its
+// "sql" parameter.
+func (cfg Config) TakesStatement() {}
+
+func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	// verify source exists
+	rawS, ok := srcs[cfg.Source]
+	if !ok {
+		return nil, fmt.Errorf("no source named %q configured", cfg.Source)
+	}
+
+	// verify the source is compatible
+	s, ok := rawS.(compatibleSource)
+	if !ok {
+		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
+	}
+
+	sqlParameter := tools.NewStringParameter("sql",
+		"The sql to execute. Refer to the external data by the name given in table_name.")
+	sourceURIParameter := tools.NewStringParameter("source_uri",
+		"The Cloud Storage URI of the data, e.g. gs://bucket/path/*.csv. A single '*' wildcard is allowed.")
+	tableNameParameter := tools.NewStringParameter("table_name",
+		"The name of the temporary table the external data is exposed as within the sql.")
+	sourceFormatParameter := tools.NewStringParameterWithDefault("source_format", "CSV",
+		"The format of the data. One of CSV, NEWLINE_DELIMITED_JSON, PARQUET, AVRO or ORC.")
+	schemaParameter := tools.NewStringParameterWithDefault("schema", "",
+		"The schema of the data as comma-separated name:TYPE pairs, e.g. 'id:INT64,name:STRING'. "+
+			"If empty, the schema is auto-detected.")
+	skipLeadingRowsParameter := tools.NewIntParameterWithDefault("skip_leading_rows", 0,
+		"The number of header rows to skip. Only applies to CSV data.")
+	parameters := tools.Parameters{sqlParameter, sourceURIParameter, tableNameParameter,
+		sourceFormatParameter, schemaParameter, skipLeadingRowsParameter}
+	// On sources with allowedProjects, the project parameter selects the
+	// project to run in.
+	allowedProjects := s.BigQueryAllowedProjects()
+	if len(allowedProjects) > 0 {
+		parameters = append(parameters, bigquerycommon.ProjectParameter(allowedProjects))
+	}
+
+	mcpManifest := tools.McpManifest{
+		Name:        cfg.Name,
+		Description: cfg.Description,
+		InputSchema: parameters.McpManifest(),
+	}
+
+	// finish tool setup
+	t := Tool{
+		Name:             cfg.Name,
+		Kind:             kind,
+		Parameters:       parameters,
+		AuthRequired:     cfg.AuthRequired,
+		UseClientOAuth:   s.UseClientAuthorization(),
+		ClientCreator:    s.BigQueryClientCreator(),
+		Client:           s.BigQueryClient(),
+		QueryTag:         s.BigQueryQueryTag(),
+		MultiProject:     len(allowedProjects) > 0,
+		ClientForProject: s.BigQueryClientForProject,
+		ReserveQuota:     s.ReserveQuota,
+		RestService:      s.BigQueryRestService(),
+		manifest:         tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:      mcpManifest,
+	}
+	return t, nil
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name           string           `yaml:"name"`
+	Kind           string           `yaml:"kind"`
+	AuthRequired   []string         `yaml:"authRequired"`
+	UseClientOAuth bool             `yaml:"useClientOAuth"`
+	Parameters     tools.Parameters `yaml:"parameters"`
+
+	Client           *bigqueryapi.Client
+	QueryTag         string
+	MultiProject     bool
+	ClientForProject bigquerycommon.ClientForProjectFunc
+	ReserveQuota     bigquerycommon.ReserveQuotaFunc
+	RestService      *bigqueryrestapi.Service
+	ClientCreator    bigqueryds.BigqueryClientCreator
+	manifest         tools.Manifest
+	mcpManifest      tools.McpManifest
+}
+
+func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
+	paramsMap := params.AsMap()
+	sql, ok := paramsMap["sql"].(string)
+	if !ok {
+		return nil, fmt.Errorf("unable to cast sql parameter %v", paramsMap["sql"])
+	}
+	sourceURI, ok := paramsMap["source_uri"].(string)
+	if !ok {
+		return nil, fmt.Errorf("unable to cast source_uri parameter %v", paramsMap["source_uri"])
+	}
+	tableName, ok := paramsMap["table_name"].(string)
+	if !ok {
+		return nil, fmt.Errorf("unable to cast table_name parameter %v", paramsMap["table_name"])
+	}
+	sourceFormat, ok := paramsMap["source_format"].(string)
+	if !ok {
+		return nil, fmt.Errorf("unable to cast source_format parameter %v", paramsMap["source_format"])
+	}
+	schemaStr, ok := paramsMap["schema"].(string)
+	if !ok {
+		return nil, fmt.Errorf("unable to cast schema parameter %v", paramsMap["schema"])
+	}
+	skipLeadingRows, ok := paramsMap["skip_leading_rows"].(int)
+	if !ok {
+		if s, ok := paramsMap["skip_leading_rows"].(float64); ok {
+			skipLeadingRows = int(s)
+		} else {
+			return nil, fmt.Errorf("unable to cast skip_leading_rows parameter %v", paramsMap["skip_leading_rows"])
+		}
+	}
+
+	externalData, err := ExternalDataConfig(sourceURI, sourceFormat, schemaStr, skipLeadingRows)
+	if err != nil {
+		return nil, err
+	}
+
+	bqClient := t.Client
+
+	// Use the clients of the project, or initialize new ones if using user
+	// OAuth token
+	if project, ok := paramsMap[bigquerycommon.ProjectKey].(string); ok && t.MultiProject {
+		// Sources with allowedProjects run the query in the requested project.
+		bqClient, _, err = bigquerycommon.ProjectClient(t.ClientForProject, project, t.UseClientOAuth, accessToken)
+		if err != nil {
+			return nil, err
+		}
+	} else if t.UseClientOAuth {
+		tokenStr, err := accessToken.ParseBearerToken()
+		if err != nil {
+			return nil, fmt.Errorf("error parsing access token: %w", err)
+		}
+		bqClient, _, err = t.ClientCreator(tokenStr, false)
+		if err != nil {
+			return nil, fmt.Errorf("error creating client from OAuth access token: %w", err)
+		}
+	}
+
+	query := bqClient.Query(sql)
+	bigquerycommon.SetQueryTag(query, t.QueryTag)
+	query.Location = bqClient.Location
+	query.TableDefinitions = map[string]bigqueryapi.ExternalData{tableName: externalData}
+
+	// Log the query executed for debugging. The statement may hold sensitive
+	// values, so only its type and length are logged.
+	logger, err := util.LoggerFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting logger: %s", err)
+	}
+	logger.DebugContext(ctx, "executing `%s` tool query over %s: %s", kind, sourceURI, tools.DescribeStatement(sql))
+
+	quota, err := bigquerycommon.NewQuota(t.ReserveQuota, t.UseClientOAuth, accessToken)
+	if err != nil {
+		return nil, err
+	}
+	var out []any
+	it, err := bigquerycommon.ReadQuery(ctx, query, quota)
+	if err != nil {
+		return nil, fmt.Errorf("unable to execute query: %w", err)
+	}
+	for {
+		var row map[string]bigqueryapi.Value
+		err = it.Next(&row)
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to iterate through query results: %w", err)
+		}
+		vMap := make(map[string]any)
+		for key, value := range row {
+			vMap[key] = value
+		}
+		out = append(out, vMap)
+	}
+	// If the query returned any rows, return them directly.
+	if len(out) > 0 {
+		return out, nil
+	}
+
+	return tools.ZeroRowsMessage, nil
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.Parameters, data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
+
+func (t Tool) RequiresClientAuthorization() bool {
+	return t.UseClientOAuth
+}
+
+// ExternalDataConfig returns the definition of the temporary table over the
+// file at the given gs:// URI. The schema is auto-detected if schema is empty.
+func ExternalDataConfig(sourceURI, sourceFormat, schema string, skipLeadingRows int) (*bigqueryapi.ExternalDataConfig, error) {
+	if !strings.HasPrefix(sourceURI, "gs://") {
+		return nil, fmt.Errorf("invalid source URI %q: expected a gs:// URI", sourceURI)
+	}
+	format, ok := supportedFormats[strings.ToUpper(strings.TrimSpace(sourceFormat))]
+	if !ok {
+		return nil, fmt.Errorf("unsupported source_format %q: must be one of CSV, NEWLINE_DELIMITED_JSON, PARQUET, AVRO or ORC", sourceFormat)
+	}
+	fields, err := parseSchema(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	externalData := &bigqueryapi.ExternalDataConfig{
+		SourceFormat: format,
+		SourceURIs:   []string{sourceURI},
+		Schema:       fields,
+		AutoDetect:   len(fields) == 0,
+	}
+	if format == bigqueryapi.CSV && skipLeadingRows > 0 {
+		externalData.Options = &bigqueryapi.CSVOptions{SkipLeadingRows: int64(skipLeadingRows)}
+	}
+	return externalData, nil
+}
+
+// parseSchema parses a comma-separated list of name:TYPE pairs into a
+// BigQuery schema. An empty string yields a nil schema.
+func parseSchema(s string) (bigqueryapi.Schema, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	var schema bigqueryapi.Schema
+	for _, field := range strings.Split(s, ",") {
+		name, fieldType, ok := strings.Cut(strings.TrimSpace(field), ":")
+		name, fieldType = strings.TrimSpace(name), strings.TrimSpace(fieldType)
+		if !ok || name == "" || fieldType == "" {
+			return nil, fmt.Errorf("invalid schema field %q: expected name:TYPE", field)
+		}
+		schema = append(schema, &bigqueryapi.FieldSchema{
+			Name: name,
+			Type: bigqueryapi.FieldType(strings.ToUpper(fieldType)),
+		})
+	}
+	return schema, nil
+}
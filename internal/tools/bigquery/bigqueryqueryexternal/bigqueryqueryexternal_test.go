@@ -0,0 +1,208 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bigqueryqueryexternal_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	bigqueryapi "cloud.google.com/go/bigquery"
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigqueryqueryexternal"
+)
+
+func TestParseFromYamlBigQueryQueryExternal(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		want server.ToolConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			tools:
+				example_tool:
+					kind: bigquery-query-external
+					source: my-instance
+					description: some description
+			`,
+			want: server.ToolConfigs{
+				"example_tool": tools.StatementLengthConfig{
+					ToolConfig: bigqueryqueryexternal.Config{
+						Name:         "example_tool",
+						Kind:         "bigquery-query-external",
+						Source:       "my-instance",
+						Description:  "some description",
+						AuthRequired: []string{},
+					},
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			// Parse contents
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Tools); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+
+}
+
+func TestFailInvokeBigQueryQueryExternal(t *testing.T) {
+	// the external data is checked before any call to BigQuery, so the tool
+	// needs no client
+	tool := bigqueryqueryexternal.Tool{}
+	params := func(sourceURI, sourceFormat, schema string) tools.ParamValues {
+		return tools.ParamValues{
+			{Name: "sql", Value: "SELECT * FROM ext"},
+			{Name: "source_uri", Value: sourceURI},
+			{Name: "table_name", Value: "ext"},
+			{Name: "source_format", Value: sourceFormat},
+			{Name: "schema", Value: schema},
+			{Name: "skip_leading_rows", Value: 0},
+		}
+	}
+	tcs := []struct {
+		desc   string
+		params tools.ParamValues
+		err    string
+	}{
+		{
+			desc:   "source uri outside of cloud storage",
+			params: params("https://example.com/data.csv", "CSV", ""),
+			err:    `invalid source URI "https://example.com/data.csv": expected a gs:// URI`,
+		},
+		{
+			desc:   "unsupported source format",
+			params: params("gs://my-bucket/data.xml", "XML", ""),
+			err:    `unsupported source_format "XML"`,
+		},
+		{
+			desc:   "schema field without a type",
+			params: params("gs://my-bucket/data.csv", "CSV", "name,age:INT64"),
+			err:    `invalid schema field "name"`,
+		},
+		{
+			desc:   "missing source uri",
+			params: tools.ParamValues{{Name: "sql", Value: "SELECT * FROM ext"}},
+			err:    "unable to cast source_uri parameter",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := tool.Invoke(context.Background(), tc.params, "")
+			if err == nil {
+				t.Fatalf("expect invocation to fail")
+			}
+			if !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("unexpected error string: got %q, want substring %q", err.Error(), tc.err)
+			}
+		})
+	}
+}
+
+func TestExternalDataConfig(t *testing.T) {
+	tcs := []struct {
+		desc            string
+		uri             string
+		sourceFormat    string
+		schema          string
+		skipLeadingRows int
+		want            *bigqueryapi.ExternalDataConfig
+		wantErr         bool
+	}{
+		{
+			desc:            "csv with a schema and a header",
+			uri:             "gs://my-bucket/data/*.csv",
+			sourceFormat:    "csv",
+			schema:          "id:INT64,name:string",
+			skipLeadingRows: 1,
+			want: &bigqueryapi.ExternalDataConfig{
+				SourceFormat: bigqueryapi.CSV,
+				SourceURIs:   []string{"gs://my-bucket/data/*.csv"},
+				Schema: bigqueryapi.Schema{
+					{Name: "id", Type: bigqueryapi.FieldType("INT64")},
+					{Name: "name", Type: bigqueryapi.StringFieldType},
+				},
+				Options: &bigqueryapi.CSVOptions{SkipLeadingRows: 1},
+			},
+		},
+		{
+			desc:            "json with an auto-detected schema",
+			uri:             "gs://my-bucket/data.json",
+			sourceFormat:    "NEWLINE_DELIMITED_JSON",
+			skipLeadingRows: 1,
+			want: &bigqueryapi.ExternalDataConfig{
+				SourceFormat: bigqueryapi.JSON,
+				SourceURIs:   []string{"gs://my-bucket/data.json"},
+				AutoDetect:   true,
+			},
+		},
+		{
+			desc:         "not a gs uri",
+			uri:          "/tmp/data.csv",
+			sourceFormat: "CSV",
+			wantErr:      true,
+		},
+		{
+			desc:         "unsupported format",
+			uri:          "gs://my-bucket/data.xml",
+			sourceFormat: "XML",
+			wantErr:      true,
+		},
+		{
+			desc:         "invalid schema",
+			uri:          "gs://my-bucket/data.csv",
+			sourceFormat: "CSV",
+			schema:       "id:INT64,name",
+			wantErr:      true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := bigqueryqueryexternal.ExternalDataConfig(tc.uri, tc.sourceFormat, tc.schema, tc.skipLeadingRows)
+			if err != nil {
+				if tc.wantErr {
+					return
+				}
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if tc.wantErr {
+				t.Fatalf("expected error but got nil")
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect external data config: diff %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bigqueryrunsavedquery
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	bigqueryapi "cloud.google.com/go/bigquery"
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	bigqueryds "github.com/googleapis/genai-toolbox/internal/sources/bigquery"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigquerycommon"
+	"github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigquerysql"
+)
+
+const kind string = "bigquery-run-saved-query"
+const nameKey string = "name"
+const parametersKey string = "parameters"
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type compatibleSource interface {
+	BigQueryClient() *bigqueryapi.Client
+	UseClientAuthorization() bool
+	BigQueryAllowedProjects() []string
+}
+
+// validate compatible sources are still compatible
+var _ compatibleSource = &bigqueryds.Source{}
+
+var compatibleSources = [...]string{bigqueryds.SourceKind}
+
+// SavedQuery is a pre-approved statement that callers can run by name.
+type SavedQuery struct {
+	Description string           `yaml:"description"`
+	Statement   string           `yaml:"statement" validate:"required"`
+	Parameters  tools.Parameters `yaml:"parameters"`
+}
+
+type Config struct {
+	Name         string                `yaml:"name" validate:"required"`
+	Kind         string                `yaml:"kind" validate:"required"`
+	Source       string                `yaml:"source" validate:"required"`
+	Description  string                `yaml:"description" validate:"required"`
+	AuthRequired []string              `yaml:"authRequired"`
+	Queries      map[string]SavedQuery `yaml:"queries" validate:"required"`
+}
+
+// validate interface
+var _ tools.ToolConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	// verify source exists
+	rawS, ok := srcs[cfg.Source]
+	if !ok {
+		return nil, fmt.Errorf("no source named %q configured", cfg.Source)
+	}
+
+	// verify the source is compatible
+	s, ok := rawS.(compatibleSource)
+	if !ok {
+		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
+	}
+
+	if len(cfg.Queries) == 0 {
+		return nil, fmt.Errorf("tool %q must configure at least one saved query", cfg.Name)
+	}
+
+	names := make([]string, 0, len(cfg.Queries))
+	for name := range cfg.Queries {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	// Each saved query runs as a bigquery-sql tool, so that parameter binding,
+	// client OAuth and quotas behave the same way.
+	queries := make(map[string]tools.Tool, len(cfg.Queries))
+	var b strings.Builder
+	b.WriteString(cfg.Description)
+	b.WriteString("\n\nAvailable saved queries:")
+	for _, name := range names {
+		q := cfg.Queries[name]
+		sqlCfg := bigquerysql.Config{
+			Name:         cfg.Name,
+			Kind:         bigquerysql.Config{}.ToolConfigKind(),
+			Source:       cfg.Source,
+			Description:  q.Description,
+			Statement:    q.Statement,
+			AuthRequired: cfg.AuthRequired,
+			Parameters:   q.Parameters,
+		}
+		t, err := sqlCfg.Initialize(srcs)
+		if err != nil {
+			return nil, fmt.Errorf("unable to initialize saved query %q: %w", name, err)
+		}
+		queries[name] = t
+
+		fmt.Fprintf(&b, "\n- %s", name)
+		if q.Description != "" {
+			fmt.Fprintf(&b, ": %s", strings.TrimSpace(q.Description))
+		}
+		for _, p := range q.Parameters {
+			fmt.Fprintf(&b, "\n  - parameter %q (%s): %s", p.GetName(), p.GetType(), p.Manifest().Description)
+		}
+	}
+	description := b.String()
+
+	nameParameter := tools.NewStringParameter(nameKey, "The name of the saved query to run. Must be one of the available saved queries.")
+	parametersParameter := tools.NewMapParameterWithDefault(parametersKey, map[string]any{}, "The parameters for the selected saved query, keyed by parameter name.", "")
+	parameters := tools.Parameters{nameParameter, parametersParameter}
+	// On sources with allowedProjects, the project parameter selects the
+	// project to run the saved query in.
+	allowedProjects := s.BigQueryAllowedProjects()
+	if len(allowedProjects) > 0 {
+		parameters = append(parameters, bigquerycommon.ProjectParameter(allowedProjects))
+	}
+
+	mcpManifest := tools.McpManifest{
+		Name:        cfg.Name,
+		Description: description,
+		InputSchema: parameters.McpManifest(),
+	}
+
+	// finish tool setup
+	t := Tool{
+		Name:           cfg.Name,
+		Kind:           kind,
+		Parameters:     parameters,
+		AuthRequired:   cfg.AuthRequired,
+		UseClientOAuth: s.UseClientAuthorization(),
+		queries:        queries,
+		manifest:       tools.Manifest{Description: description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:    mcpManifest,
+	}
+	return t, nil
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name           string           `yaml:"name"`
+	Kind           string           `yaml:"kind"`
+	AuthRequired   []string         `yaml:"authRequired"`
+	UseClientOAuth bool             `yaml:"useClientOAuth"`
+	Parameters     tools.Parameters `yaml:"parameters"`
+
+	queries     map[string]tools.Tool
+	manifest    tools.Manifest
+	mcpManifest tools.McpManifest
+}
+
+func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
+	paramsMap := params.AsMap()
+	name, ok := paramsMap[nameKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("unable to get cast %s", paramsMap[nameKey])
+	}
+	query, ok := t.queries[name]
+	if !ok {
+		return nil, fmt.Errorf("no saved query named %q", name)
+	}
+	queryParams, ok := paramsMap[parametersKey].(tools.ParamValues)
+	if !ok {
+		return nil, fmt.Errorf("unable to get cast %s", paramsMap[parametersKey])
+	}
+	return query.Invoke(ctx, queryParams, accessToken)
+}
+
+// ParseParams parses the saved query name and then validates the supplied
+// parameters against the schema of that saved query.
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	params, err := tools.ParseParams(t.Parameters, data, claims)
+	if err != nil {
+		return nil, err
+	}
+	paramsMap := params.AsMap()
+	name, _ := paramsMap[nameKey].(string)
+	query, ok := t.queries[name]
+	if !ok {
+		return nil, fmt.Errorf("no saved query named %q", name)
+	}
+	queryData, _ := paramsMap[parametersKey].(map[string]any)
+	// Each saved query takes the project parameter of its bigquery-sql tool.
+	if project, ok := paramsMap[bigquerycommon.ProjectKey].(string); ok {
+		queryData = maps.Clone(queryData)
+		if queryData == nil {
+			queryData = map[string]any{}
+		}
+		queryData[bigquerycommon.ProjectKey] = project
+	}
+	queryParams, err := query.ParseParams(queryData, claims)
+	if err != nil {
+		return nil, fmt.Errorf("invalid parameters for saved query %q: %w", name, err)
+	}
+	return tools.ParamValues{
+		{Name: nameKey, Value: name},
+		{Name: parametersKey, Value: queryParams},
+	}, nil
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
+
+func (t Tool) RequiresClientAuthorization() bool {
+	return t.UseClientOAuth
+}
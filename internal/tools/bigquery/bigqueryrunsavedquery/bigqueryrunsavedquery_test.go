@@ -0,0 +1,97 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bigqueryrunsavedquery_test
+
+import (
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigqueryrunsavedquery"
+)
+
+func TestParseFromYamlBigQueryRunSavedQuery(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		want server.ToolConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			tools:
+				example_tool:
+					kind: bigquery-run-saved-query
+					source: my-instance
+					description: some description
+					queries:
+						count_orders:
+							description: Counts orders for a customer.
+							statement: |
+								SELECT COUNT(*) FROM orders WHERE customer_id = @customer_id;
+							parameters:
+								- name: customer_id
+								  type: integer
+								  description: the customer id
+						list_regions:
+							statement: SELECT DISTINCT region FROM orders;
+			`,
+			want: server.ToolConfigs{
+				"example_tool": bigqueryrunsavedquery.Config{
+					Name:         "example_tool",
+					Kind:         "bigquery-run-saved-query",
+					Source:       "my-instance",
+					Description:  "some description",
+					AuthRequired: []string{},
+					Queries: map[string]bigqueryrunsavedquery.SavedQuery{
+						"count_orders": {
+							Description: "Counts orders for a customer.",
+							Statement:   "SELECT COUNT(*) FROM orders WHERE customer_id = @customer_id;\n",
+							Parameters: []tools.Parameter{
+								tools.NewIntParameter("customer_id", "the customer id"),
+							},
+						},
+						"list_regions": {
+							Statement: "SELECT DISTINCT region FROM orders;",
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			// Parse contents
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Tools); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+
+}
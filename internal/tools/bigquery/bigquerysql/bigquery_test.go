@@ -64,6 +64,183 @@ func TestParseFromYamlBigQuery(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "read-only example",
+			in: `
+			tools:
+				example_tool:
+					kind: bigquery-sql
+					source: my-instance
+					description: some description
+					readOnly: true
+					statement: |
+						SELECT * FROM {{.tableName}};
+					templateParameters:
+						- name: tableName
+						  type: string
+						  description: some description
+			`,
+			want: server.ToolConfigs{
+				"example_tool": bigquerysql.Config{
+					Name:         "example_tool",
+					Kind:         "bigquery-sql",
+					Source:       "my-instance",
+					Description:  "some description",
+					Statement:    "SELECT * FROM {{.tableName}};\n",
+					AuthRequired: []string{},
+					ReadOnly:     true,
+					TemplateParameters: []tools.Parameter{
+						tools.NewStringParameter("tableName", "some description"),
+					},
+				},
+			},
+		},
+		{
+			desc: "partial results example",
+			in: `
+			tools:
+				example_tool:
+					kind: bigquery-sql
+					source: my-instance
+					description: some description
+					returnPartialOnError: true
+					statement: |
+						SELECT * FROM my_table;
+			`,
+			want: server.ToolConfigs{
+				"example_tool": bigquerysql.Config{
+					Name:                 "example_tool",
+					Kind:                 "bigquery-sql",
+					Source:               "my-instance",
+					Description:          "some description",
+					Statement:            "SELECT * FROM my_table;\n",
+					AuthRequired:         []string{},
+					ReturnPartialOnError: true,
+				},
+			},
+		},
+		{
+			desc: "arrow results example",
+			in: `
+			tools:
+				example_tool:
+					kind: bigquery-sql
+					source: my-instance
+					description: some description
+					arrowResults: true
+					statement: |
+						SELECT * FROM my_table;
+			`,
+			want: server.ToolConfigs{
+				"example_tool": bigquerysql.Config{
+					Name:         "example_tool",
+					Kind:         "bigquery-sql",
+					Source:       "my-instance",
+					Description:  "some description",
+					Statement:    "SELECT * FROM my_table;\n",
+					AuthRequired: []string{},
+					ArrowResults: true,
+				},
+			},
+		},
+		{
+			desc: "connection properties example",
+			in: `
+			tools:
+				example_tool:
+					kind: bigquery-sql
+					source: my-instance
+					description: some description
+					connectionProperties:
+						time_zone: America/New_York
+					statement: |
+						SELECT CURRENT_DATE();
+			`,
+			want: server.ToolConfigs{
+				"example_tool": bigquerysql.Config{
+					Name:                 "example_tool",
+					Kind:                 "bigquery-sql",
+					Source:               "my-instance",
+					Description:          "some description",
+					Statement:            "SELECT CURRENT_DATE();\n",
+					AuthRequired:         []string{},
+					ConnectionProperties: map[string]string{"time_zone": "America/New_York"},
+				},
+			},
+		},
+		{
+			desc: "column aliases example",
+			in: `
+			tools:
+				example_tool:
+					kind: bigquery-sql
+					source: my-instance
+					description: some description
+					columnAliases:
+						name: user_name
+					statement: |
+						SELECT id, name FROM users;
+			`,
+			want: server.ToolConfigs{
+				"example_tool": bigquerysql.Config{
+					Name:          "example_tool",
+					Kind:          "bigquery-sql",
+					Source:        "my-instance",
+					Description:   "some description",
+					Statement:     "SELECT id, name FROM users;\n",
+					AuthRequired:  []string{},
+					ColumnAliases: map[string]string{"name": "user_name"},
+				},
+			},
+		},
+		{
+			desc: "validate on load example",
+			in: `
+			tools:
+				example_tool:
+					kind: bigquery-sql
+					source: my-instance
+					description: some description
+					validateOnLoad: true
+					statement: |
+						SELECT id, name FROM users;
+			`,
+			want: server.ToolConfigs{
+				"example_tool": bigquerysql.Config{
+					Name:           "example_tool",
+					Kind:           "bigquery-sql",
+					Source:         "my-instance",
+					Description:    "some description",
+					Statement:      "SELECT id, name FROM users;\n",
+					AuthRequired:   []string{},
+					ValidateOnLoad: true,
+				},
+			},
+		},
+		{
+			desc: "location example",
+			in: `
+			tools:
+				example_tool:
+					kind: bigquery-sql
+					source: my-instance
+					description: some description
+					location: asia-northeast1
+					statement: |
+						SELECT id, name FROM users;
+			`,
+			want: server.ToolConfigs{
+				"example_tool": bigquerysql.Config{
+					Name:         "example_tool",
+					Kind:         "bigquery-sql",
+					Source:       "my-instance",
+					Description:  "some description",
+					Statement:    "SELECT id, name FROM users;\n",
+					AuthRequired: []string{},
+					Location:     "asia-northeast1",
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
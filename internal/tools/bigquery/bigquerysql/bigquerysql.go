@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"slices"
 	"strings"
 
 	bigqueryapi "cloud.google.com/go/bigquery"
@@ -26,8 +27,8 @@ import (
 
 	bigqueryds "github.com/googleapis/genai-toolbox/internal/sources/bigquery"
 	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigquerycommon"
 	bigqueryrestapi "google.golang.org/api/bigquery/v2"
-	"google.golang.org/api/iterator"
 )
 
 const kind string = "bigquery-sql"
@@ -48,9 +49,15 @@ func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.T
 
 type compatibleSource interface {
 	BigQueryClient() *bigqueryapi.Client
+	BigQueryQueryTag() string
 	BigQueryRestService() *bigqueryrestapi.Service
 	BigQueryClientCreator() bigqueryds.BigqueryClientCreator
 	UseClientAuthorization() bool
+	ReserveQuota(ctx context.Context, tokenString string, bytes int64) error
+	BigQueryDefaultLocation() string
+	BigQueryAllowedProjects() []string
+	BigQueryClientForProject(projectID, tokenString string) (*bigqueryapi.Client, *bigqueryrestapi.Service, error)
+	BigQueryStorageReadClient(ctx context.Context, projectID, tokenString string) (*bigqueryapi.Client, error)
 }
 
 // validate compatible sources are still compatible
@@ -67,6 +74,44 @@ type Config struct {
 	AuthRequired       []string         `yaml:"authRequired"`
 	Parameters         tools.Parameters `yaml:"parameters"`
 	TemplateParameters tools.Parameters `yaml:"templateParameters"`
+	ReadOnly           bool             `yaml:"readOnly"`
+	// ReturnPartialOnError returns the rows read so far, with a warning, when
+	// reading the query results fails part way.
+	ReturnPartialOnError bool `yaml:"returnPartialOnError"`
+	// ConnectionProperties sets session settings, e.g. time_zone, on each query.
+	ConnectionProperties map[string]string `yaml:"connectionProperties"`
+	// WarnOnRowAccessPolicy adds a warning to the results when a row access
+	// policy filtered the rows the query read.
+	WarnOnRowAccessPolicy bool `yaml:"warnOnRowAccessPolicy"`
+	// ColumnAliases renames result columns, e.g. name to user_name.
+	ColumnAliases map[string]string `yaml:"columnAliases"`
+	// ValidateOnLoad dry runs the statement when the tool is loaded, so that a
+	// malformed statement fails startup. Statements with template parameters
+	// are not validated.
+	ValidateOnLoad bool `yaml:"validateOnLoad"`
+	// Location runs the query in the given location, overriding the location
+	// of the source.
+	Location string `yaml:"location"`
+	// ArrowResults returns the results of SELECT statements in the Apache
+	// Arrow IPC stream format, read through the BigQuery Storage Read API,
+	// to clients that accept application/vnd.apache.arrow.stream.
+	ArrowResults bool `yaml:"arrowResults"`
+	// RequirePartitionFilter rejects, with "reject", or adds a warning to
+	// the results of, with "warn", queries that scan a partitioned table
+	// without filtering on its partition column.
+	RequirePartitionFilter string `yaml:"requirePartitionFilter"`
+	// IncludeStats returns the rows along with the statistics of the query
+	// job, e.g. the bytes processed and the BI Engine acceleration mode.
+	IncludeStats bool `yaml:"includeStats"`
+	// ArrayRows returns the rows as arrays of values in the order of the
+	// result schema, along with the column names, instead of as objects.
+	ArrayRows bool `yaml:"arrayRows"`
+	// BytesEncoding is the encoding of BYTES values in the results: base64,
+	// the default, hex or utf8.
+	BytesEncoding string `yaml:"bytesEncoding"`
+	// EmptyArrays is how empty REPEATED values are returned: as empty
+	// arrays, with "array", the default, or as null, with "null".
+	EmptyArrays string `yaml:"emptyArrays"`
 }
 
 // validate interface
@@ -89,11 +134,39 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
 	}
 
-	allParameters, paramManifest, paramMcpManifest, err := tools.ProcessParameters(cfg.TemplateParameters, cfg.Parameters)
+	// The project parameter selects the project to run in and isn't bound to
+	// the statement, so it isn't part of Tool.Parameters.
+	params := cfg.Parameters
+	allowedProjects := s.BigQueryAllowedProjects()
+	if len(allowedProjects) > 0 {
+		params = append(slices.Clone(params), bigquerycommon.ProjectParameter(allowedProjects))
+	}
+	allParameters, paramManifest, paramMcpManifest, err := tools.ProcessParameters(cfg.TemplateParameters, params)
 	if err != nil {
 		return nil, err
 	}
 
+	connProps, err := bigquerycommon.ConnectionProperties(cfg.ConnectionProperties)
+	if err != nil {
+		return nil, fmt.Errorf("invalid connection properties for tool %q: %w", cfg.Name, err)
+	}
+
+	if err := bigquerycommon.ValidateColumnAliases(cfg.ColumnAliases); err != nil {
+		return nil, fmt.Errorf("invalid column aliases for tool %q: %w", cfg.Name, err)
+	}
+
+	if err := bigquerycommon.ValidatePartitionFilterMode(cfg.RequirePartitionFilter); err != nil {
+		return nil, fmt.Errorf("invalid config for tool %q: %w", cfg.Name, err)
+	}
+
+	if err := bigquerycommon.ValidateBytesEncoding(cfg.BytesEncoding); err != nil {
+		return nil, fmt.Errorf("invalid config for tool %q: %w", cfg.Name, err)
+	}
+
+	if err := bigquerycommon.ValidateEmptyArrays(cfg.EmptyArrays); err != nil {
+		return nil, fmt.Errorf("invalid config for tool %q: %w", cfg.Name, err)
+	}
+
 	mcpManifest := tools.McpManifest{
 		Name:        cfg.Name,
 		Description: cfg.Description,
@@ -108,14 +181,43 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		Parameters:         cfg.Parameters,
 		TemplateParameters: cfg.TemplateParameters,
 		AllParams:          allParameters,
+		ReadOnly:           cfg.ReadOnly,
+
+		ReturnPartialOnError:  cfg.ReturnPartialOnError,
+		ConnectionProperties:  connProps,
+		WarnOnRowAccessPolicy: cfg.WarnOnRowAccessPolicy,
+		ColumnAliases:         cfg.ColumnAliases,
+		Location:              cfg.Location,
+		DefaultLocation:       s.BigQueryDefaultLocation(),
+		MultiProject:          len(allowedProjects) > 0,
+		ArrowResults:          cfg.ArrowResults,
+
+		RequirePartitionFilter: cfg.RequirePartitionFilter,
+		IncludeStats:           cfg.IncludeStats,
+		ArrayRows:              cfg.ArrayRows,
+		BytesEncoding:          cfg.BytesEncoding,
+		EmptyArrays:            cfg.EmptyArrays,
+
+		Statement:         cfg.Statement,
+		UseClientOAuth:    s.UseClientAuthorization(),
+		Client:            s.BigQueryClient(),
+		QueryTag:          s.BigQueryQueryTag(),
+		RestService:       s.BigQueryRestService(),
+		ClientCreator:     s.BigQueryClientCreator(),
+		ClientForProject:  s.BigQueryClientForProject,
+		StorageReadClient: s.BigQueryStorageReadClient,
+		ReserveQuota:      s.ReserveQuota,
+		manifest:          tools.Manifest{Description: cfg.Description, Parameters: paramManifest, AuthRequired: cfg.AuthRequired},
+		mcpManifest:       mcpManifest,
+	}
 
-		Statement:      cfg.Statement,
-		UseClientOAuth: s.UseClientAuthorization(),
-		Client:         s.BigQueryClient(),
-		RestService:    s.BigQueryRestService(),
-		ClientCreator:  s.BigQueryClientCreator(),
-		manifest:       tools.Manifest{Description: cfg.Description, Parameters: paramManifest, AuthRequired: cfg.AuthRequired},
-		mcpManifest:    mcpManifest,
+	if cfg.ValidateOnLoad && len(cfg.TemplateParameters) == 0 {
+		if t.UseClientOAuth {
+			return nil, fmt.Errorf("validateOnLoad is not supported for tool %q because its source uses client OAuth", cfg.Name)
+		}
+		if err := validateStatement(t); err != nil {
+			return nil, fmt.Errorf("invalid statement for tool %q: %w", cfg.Name, err)
+		}
 	}
 	return t, nil
 }
@@ -131,46 +233,191 @@ type Tool struct {
 	Parameters         tools.Parameters `yaml:"parameters"`
 	TemplateParameters tools.Parameters `yaml:"templateParameters"`
 	AllParams          tools.Parameters `yaml:"allParams"`
-
-	Statement     string
-	Client        *bigqueryapi.Client
-	RestService   *bigqueryrestapi.Service
-	ClientCreator bigqueryds.BigqueryClientCreator
-	manifest      tools.Manifest
-	mcpManifest   tools.McpManifest
+	ReadOnly           bool             `yaml:"readOnly"`
+
+	ReturnPartialOnError  bool                              `yaml:"returnPartialOnError"`
+	ConnectionProperties  []*bigqueryapi.ConnectionProperty `yaml:"connectionProperties"`
+	WarnOnRowAccessPolicy bool                              `yaml:"warnOnRowAccessPolicy"`
+	ColumnAliases         map[string]string                 `yaml:"columnAliases"`
+	Location              string                            `yaml:"location"`
+	DefaultLocation       string                            `yaml:"defaultLocation"`
+	// MultiProject is set for sources with allowedProjects, whose queries
+	// run in the project given by the project parameter.
+	MultiProject bool `yaml:"multiProject"`
+	ArrowResults bool `yaml:"arrowResults"`
+	// RequirePartitionFilter is the PartitionFilterReject or
+	// PartitionFilterWarn mode of the partition filter check, if any.
+	RequirePartitionFilter string `yaml:"requirePartitionFilter"`
+	IncludeStats           bool   `yaml:"includeStats"`
+	ArrayRows              bool   `yaml:"arrayRows"`
+	BytesEncoding          string `yaml:"bytesEncoding"`
+	EmptyArrays            string `yaml:"emptyArrays"`
+
+	Statement         string
+	Client            *bigqueryapi.Client
+	QueryTag          string
+	RestService       *bigqueryrestapi.Service
+	ClientCreator     bigqueryds.BigqueryClientCreator
+	ClientForProject  func(projectID, tokenString string) (*bigqueryapi.Client, *bigqueryrestapi.Service, error)
+	StorageReadClient func(ctx context.Context, projectID, tokenString string) (*bigqueryapi.Client, error)
+	ReserveQuota      bigquerycommon.ReserveQuotaFunc
+	manifest          tools.Manifest
+	mcpManifest       tools.McpManifest
 }
 
 func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
-	highLevelParams := make([]bigqueryapi.QueryParameter, 0, len(t.Parameters))
-	lowLevelParams := make([]*bigqueryrestapi.QueryParameter, 0, len(t.Parameters))
-
 	paramsMap := params.AsMap()
 	newStatement, err := tools.ResolveTemplateParams(t.TemplateParameters, t.Statement, paramsMap)
 	if err != nil {
 		return nil, fmt.Errorf("unable to extract template params %w", err)
 	}
 
-	for _, p := range t.Parameters {
+	// Bind values in declaration order so positional `?` placeholders line up.
+	values, err := params.AsOrderedSlice(t.Parameters)
+	if err != nil {
+		return nil, fmt.Errorf("unable to order parameters: %w", err)
+	}
+	highLevelParams, lowLevelParams, err := queryParameters(t.Parameters, values, newStatement)
+	if err != nil {
+		return nil, err
+	}
+
+	bqClient := t.Client
+	restService := t.RestService
+
+	// Initialize new client if using user OAuth token
+	var tokenStr string
+	if t.UseClientOAuth {
+		tokenStr, err = accessToken.ParseBearerToken()
+		if err != nil {
+			return nil, fmt.Errorf("error parsing access token: %w", err)
+		}
+	}
+	if project, ok := paramsMap[bigquerycommon.ProjectKey].(string); ok && t.MultiProject {
+		// Sources with allowedProjects run the query in the requested project.
+		bqClient, restService, err = t.ClientForProject(project, tokenStr)
+		if err != nil {
+			return nil, err
+		}
+	} else if t.UseClientOAuth {
+		bqClient, restService, err = t.ClientCreator(tokenStr, true)
+		if err != nil {
+			return nil, fmt.Errorf("error creating client from OAuth access token: %w", err)
+		}
+	}
+
+	query := bqClient.Query(newStatement)
+	bigquerycommon.SetQueryTag(query, t.QueryTag)
+	query.Parameters = highLevelParams
+	query.ConnectionProperties = t.ConnectionProperties
+
+	locations := bigquerycommon.LocationChain{Tool: t.Location, Source: bqClient.Location, Default: t.DefaultLocation}
+	dryRunJob, err := bigquerycommon.DryRunQuery(ctx, restService, bqClient.Project(), locations.Explicit(), newStatement, lowLevelParams, query.ConnectionProperties)
+	if err != nil {
+		// This is a fallback check in case the switch logic was bypassed.
+		return nil, fmt.Errorf("final query validation failed: %w", locations.WrapError(err))
+	}
+	if dryRunJob.JobReference != nil {
+		locations.Detected = dryRunJob.JobReference.Location
+	}
+	query.Location, _ = locations.Resolve()
+	statementType := dryRunJob.Statistics.Query.StatementType
+	if t.ReadOnly && !isReadOnlyStatement(statementType) {
+		return nil, fmt.Errorf("tool %q is read-only and only runs SELECT statements, got statement type %q", t.Name, statementType)
+	}
+	partitionErr, err := bigquerycommon.PartitionFilterWarning(ctx, t.RequirePartitionFilter, newStatement, dryRunJob.Statistics.Query.ReferencedTables, bigquerycommon.ClientTableMetadata(bqClient))
+	if err != nil {
+		return nil, err
+	}
+	// Count the query against the user's quota before running it.
+	quota, err := bigquerycommon.NewQuota(t.ReserveQuota, t.UseClientOAuth, accessToken)
+	if err != nil {
+		return nil, err
+	}
+	if err := quota.Reserve(ctx, dryRunJob.Statistics.TotalBytesProcessed); err != nil {
+		return nil, err
+	}
+
+	if t.ArrowResults && statementType == "SELECT" && tools.ArrowStreamRequested(ctx) {
+		return t.readArrow(ctx, query, bqClient.Project(), tokenStr, locations)
+	}
+
+	// This block handles SELECT statements, which return a row set.
+	// We iterate through the results, convert each row into a map of
+	// column names to values, and return the collection of rows.
+	it, err := bigquerycommon.ReadQueryResults(ctx, query)
+	if err != nil {
+		return nil, locations.WrapError(err)
+	}
+
+	return bigquerycommon.ReadResults(ctx, restService, it, statementType, partitionErr, t.resultOptions())
+}
+
+// resultOptions returns the settings of the tool that shape its results.
+func (t Tool) resultOptions() bigquerycommon.ResultOptions {
+	return bigquerycommon.ResultOptions{
+		ColumnAliases:         t.ColumnAliases,
+		BytesEncoding:         t.BytesEncoding,
+		EmptyArrays:           t.EmptyArrays,
+		ReturnPartialOnError:  t.ReturnPartialOnError,
+		WarnOnRowAccessPolicy: t.WarnOnRowAccessPolicy,
+		IncludeStats:          t.IncludeStats,
+		ArrayRows:             t.ArrayRows,
+	}
+}
+
+// ResolveStatement returns the statement with the template parameters
+// substituted by their values.
+func (t Tool) ResolveStatement(params tools.ParamValues) (string, error) {
+	return tools.ResolveTemplateParams(t.TemplateParameters, t.Statement, params.AsMap())
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.AllParams, data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
+
+func (t Tool) RequiresClientAuthorization() bool {
+	return t.UseClientOAuth
+}
+
+// queryParameters builds the query parameters for the high-level client and
+// for the dry run from parameter values given in declaration order.
+func queryParameters(params tools.Parameters, values []any, statement string) ([]bigqueryapi.QueryParameter, []*bigqueryrestapi.QueryParameter, error) {
+	highLevelParams := make([]bigqueryapi.QueryParameter, 0, len(params))
+	lowLevelParams := make([]*bigqueryrestapi.QueryParameter, 0, len(params))
+	for i, p := range params {
 		name := p.GetName()
-		value := paramsMap[name]
+		value := values[i]
 
 		// This block for converting []any to typed slices is still necessary and correct.
 		if arrayParam, ok := p.(*tools.ArrayParameter); ok {
 			arrayParamValue, ok := value.([]any)
 			if !ok {
-				return nil, fmt.Errorf("unable to convert parameter `%s` to []any", name)
+				return nil, nil, fmt.Errorf("unable to convert parameter `%s` to []any", name)
 			}
 			itemType := arrayParam.GetItems().GetType()
 			var err error
 			value, err = tools.ConvertAnySliceToTyped(arrayParamValue, itemType)
 			if err != nil {
-				return nil, fmt.Errorf("unable to convert parameter `%s` from []any to typed slice: %w", name, err)
+				return nil, nil, fmt.Errorf("unable to convert parameter `%s` from []any to typed slice: %w", name, err)
 			}
 		}
 
 		// Determine if the parameter is named or positional for the high-level client.
 		var paramNameForHighLevel string
-		if strings.Contains(newStatement, "@"+name) {
+		if strings.Contains(statement, "@"+name) {
 			paramNameForHighLevel = name
 		}
 
@@ -192,7 +439,7 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 			lowLevelParam.ParameterType.Type = "ARRAY"
 			itemType, err := BQTypeStringFromToolType(arrayParam.GetItems().GetType())
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 			lowLevelParam.ParameterType.ArrayType = &bigqueryrestapi.QueryParameterType{Type: itemType}
 
@@ -209,99 +456,47 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 			// Handle scalar types based on their defined type.
 			bqType, err := BQTypeStringFromToolType(p.GetType())
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 			lowLevelParam.ParameterType.Type = bqType
 			lowLevelParam.ParameterValue.Value = fmt.Sprintf("%v", value)
 		}
 		lowLevelParams = append(lowLevelParams, lowLevelParam)
 	}
+	return highLevelParams, lowLevelParams, nil
+}
 
-	bqClient := t.Client
-	restService := t.RestService
-
-	// Initialize new client if using user OAuth token
-	if t.UseClientOAuth {
-		tokenStr, err := accessToken.ParseBearerToken()
-		if err != nil {
-			return nil, fmt.Errorf("error parsing access token: %w", err)
-		}
-		bqClient, restService, err = t.ClientCreator(tokenStr, true)
-		if err != nil {
-			return nil, fmt.Errorf("error creating client from OAuth access token: %w", err)
-		}
-	}
-
-	query := bqClient.Query(newStatement)
-	query.Parameters = highLevelParams
-	query.Location = bqClient.Location
-
-	dryRunJob, err := dryRunQuery(ctx, restService, bqClient.Project(), bqClient.Location, newStatement, lowLevelParams, query.ConnectionProperties)
-	if err != nil {
-		// This is a fallback check in case the switch logic was bypassed.
-		return nil, fmt.Errorf("final query validation failed: %w", err)
+// validateStatement dry runs the statement with placeholder parameter values,
+// so that a malformed statement fails when the tool is loaded rather than on
+// its first invocation.
+func validateStatement(t Tool) error {
+	values := make([]any, len(t.Parameters))
+	for i, p := range t.Parameters {
+		values[i] = placeholderValue(p.GetType())
 	}
-	statementType := dryRunJob.Statistics.Query.StatementType
-
-	// This block handles SELECT statements, which return a row set.
-	// We iterate through the results, convert each row into a map of
-	// column names to values, and return the collection of rows.
-	it, err := query.Read(ctx)
+	_, lowLevelParams, err := queryParameters(t.Parameters, values, t.Statement)
 	if err != nil {
-		return nil, fmt.Errorf("unable to execute query: %w", err)
+		return err
 	}
-
-	var out []any
-	for {
-		var row map[string]bigqueryapi.Value
-		err = it.Next(&row)
-		if err == iterator.Done {
-			break
-		}
-		if err != nil {
-			return nil, fmt.Errorf("unable to iterate through query results: %w", err)
-		}
-		vMap := make(map[string]any)
-		for key, value := range row {
-			vMap[key] = value
-		}
-		out = append(out, vMap)
-	}
-	// If the query returned any rows, return them directly.
-	if len(out) > 0 {
-		return out, nil
-	}
-
-	// This handles the standard case for a SELECT query that successfully
-	// executes but returns zero rows.
-	if statementType == "SELECT" {
-		return "The query returned 0 rows.", nil
-	}
-	// This is the fallback for a successful query that doesn't return content.
-	// In most cases, this will be for DML/DDL statements like INSERT, UPDATE, CREATE, etc.
-	// However, it is also possible that this was a query that was expected to return rows
-	// but returned none, a case that we cannot distinguish here.
-	return "Query executed successfully and returned no content.", nil
-}
-
-func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
-	return tools.ParseParams(t.AllParams, data, claims)
-}
-
-func (t Tool) Manifest() tools.Manifest {
-	return t.manifest
-}
-
-func (t Tool) McpManifest() tools.McpManifest {
-	return t.mcpManifest
-}
-
-func (t Tool) Authorized(verifiedAuthServices []string) bool {
-	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+	locations := bigquerycommon.LocationChain{Tool: t.Location, Source: t.Client.Location}
+	_, err = bigquerycommon.DryRunQuery(context.Background(), t.RestService, t.Client.Project(), locations.Explicit(), t.Statement, lowLevelParams, t.ConnectionProperties)
+	return err
 }
 
-func (t Tool) RequiresClientAuthorization() bool {
-	return t.UseClientOAuth
+// placeholderValue returns the zero value of a parameter type.
+func placeholderValue(paramType string) any {
+	switch paramType {
+	case "array":
+		return []any{}
+	case "integer":
+		return 0
+	case "float":
+		return 0.0
+	case "boolean":
+		return false
+	default:
+		return ""
+	}
 }
 
 func BQTypeStringFromToolType(toolType string) (string, error) {
@@ -320,41 +515,43 @@ func BQTypeStringFromToolType(toolType string) (string, error) {
 	}
 }
 
-func dryRunQuery(
-	ctx context.Context,
-	restService *bigqueryrestapi.Service,
-	projectID string,
-	location string,
-	sql string,
-	params []*bigqueryrestapi.QueryParameter,
-	connProps []*bigqueryapi.ConnectionProperty,
-) (*bigqueryrestapi.Job, error) {
-	useLegacySql := false
-
-	restConnProps := make([]*bigqueryrestapi.ConnectionProperty, len(connProps))
-	for i, prop := range connProps {
-		restConnProps[i] = &bigqueryrestapi.ConnectionProperty{Key: prop.Key, Value: prop.Value}
-	}
-
-	jobToInsert := &bigqueryrestapi.Job{
-		JobReference: &bigqueryrestapi.JobReference{
-			ProjectId: projectID,
-			Location:  location,
-		},
-		Configuration: &bigqueryrestapi.JobConfiguration{
-			DryRun: true,
-			Query: &bigqueryrestapi.JobConfigurationQuery{
-				Query:                sql,
-				UseLegacySql:         &useLegacySql,
-				ConnectionProperties: restConnProps,
-				QueryParameters:      params,
-			},
-		},
-	}
-
-	insertResponse, err := restService.Jobs.Insert(projectID, jobToInsert).Context(ctx).Do()
+// isReadOnlyStatement reports whether a dry-run statement type only reads data.
+// BigQuery reports queries that start with a WITH clause as SELECT, while DML,
+// DDL and multi-statement scripts have their own statement types.
+func isReadOnlyStatement(statementType string) bool {
+	return statementType == "SELECT"
+}
+
+// readArrow runs query with a client that reads its results through the
+// BigQuery Storage Read API, and returns them as an Arrow IPC stream. Column
+// aliases and row access policy warnings only apply to results read as rows.
+func (t Tool) readArrow(ctx context.Context, query *bigqueryapi.Query, projectID, tokenStr string, locations bigquerycommon.LocationChain) (any, error) {
+	client, err := t.StorageReadClient(ctx, projectID, tokenStr)
+	if err != nil {
+		return nil, err
+	}
+	arrowQuery := client.Query(query.Q)
+	arrowQuery.Parameters = query.Parameters
+	arrowQuery.ConnectionProperties = query.ConnectionProperties
+	arrowQuery.Location = query.Location
+	arrowQuery.Labels = query.Labels
+
+	// Results are read from the job rather than with Query.Read, which returns
+	// small results inline as rows instead of through the Storage Read API.
+	job, err := arrowQuery.Run(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to execute query: %w", locations.WrapError(err))
+	}
+	status, err := bigquerycommon.WaitForJob(ctx, job)
+	if err != nil {
+		return nil, fmt.Errorf("unable to wait for query to complete: %w", err)
+	}
+	if err := bigquerycommon.JobError(status.Err(), status.Errors); err != nil {
+		return nil, fmt.Errorf("unable to execute query: %w", err)
+	}
+	it, err := job.Read(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to insert dry run job: %w", err)
+		return nil, fmt.Errorf("unable to read query results: %w", err)
 	}
-	return insertResponse, nil
+	return bigquerycommon.ReadArrow(it)
 }
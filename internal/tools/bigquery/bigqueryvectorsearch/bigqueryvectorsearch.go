@@ -0,0 +1,380 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bigqueryvectorsearch
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	bigqueryapi "cloud.google.com/go/bigquery"
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	bigqueryds "github.com/googleapis/genai-toolbox/internal/sources/bigquery"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigquerycommon"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	bigqueryrestapi "google.golang.org/api/bigquery/v2"
+	"google.golang.org/api/iterator"
+)
+
+const kind string = "bigquery-vector-search"
+const tableKey string = "table"
+const columnKey string = "column_to_search"
+const queryEmbeddingKey string = "query_embedding"
+const queryTextKey string = "query_text"
+const modelKey string = "model"
+const topKKey string = "top_k"
+const distanceTypeKey string = "distance_type"
+
+// distanceTypes are the distance types supported by VECTOR_SEARCH.
+var distanceTypes = []string{"EUCLIDEAN", "COSINE", "DOT_PRODUCT"}
+
+// resourceIDRegex matches `project.dataset.resource` and `dataset.resource`.
+var resourceIDRegex = regexp.MustCompile(`^(?:([a-z][a-z0-9-]{4,28}[a-z0-9])\.)?([A-Za-z0-9_]+)\.([A-Za-z0-9_-]+)$`)
+
+// columnNameRegex matches a column name that can be used without quoting.
+var columnNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type compatibleSource interface {
+	BigQueryClient() *bigqueryapi.Client
+	BigQueryQueryTag() string
+	BigQueryClientCreator() bigqueryds.BigqueryClientCreator
+	UseClientAuthorization() bool
+	IsDatasetAllowed(projectID, datasetID string) bool
+	BigQueryAllowedProjects() []string
+	BigQueryClientForProject(projectID, tokenString string) (*bigqueryapi.Client, *bigqueryrestapi.Service, error)
+	ReserveQuota(ctx context.Context, tokenString string, bytes int64) error
+}
+
+// validate compatible sources are still compatible
+var _ compatibleSource = &bigqueryds.Source{}
+
+var compatibleSources = [...]string{bigqueryds.SourceKind}
+
+type Config struct {
+	Name         string   `yaml:"name" validate:"required"`
+	Kind         string   `yaml:"kind" validate:"required"`
+	Source       string   `yaml:"source" validate:"required"`
+	Description  string   `yaml:"description" validate:"required"`
+	AuthRequired []string `yaml:"authRequired"`
+}
+
+// validate interface
+var _ tools.ToolConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	// verify source exists
+	rawS, ok := srcs[cfg.Source]
+	if !ok {
+		return nil, fmt.Errorf("no source named %q configured", cfg.Source)
+	}
+
+	// verify the source is compatible
+	s, ok := rawS.(compatibleSource)
+	if !ok {
+		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
+	}
+
+	tableParameter := tools.NewStringParameter(tableKey,
+		"The table to search, as `project.dataset.table` or `dataset.table`.")
+	columnParameter := tools.NewStringParameter(columnKey,
+		"The name of the ARRAY<FLOAT64> column of the table that holds the embeddings to search.")
+	queryEmbeddingParameter := tools.NewArrayParameterWithDefault(queryEmbeddingKey, []any{},
+		"The embedding to find the nearest rows to. Exactly one of `query_embedding` and `query_text` must be set.",
+		tools.NewFloatParameter("value", "A value of the embedding."))
+	queryTextParameter := tools.NewStringParameterWithDefault(queryTextKey, "",
+		"Text to embed with `model` and find the nearest rows to. Exactly one of `query_embedding` and `query_text` must be set.")
+	modelParameter := tools.NewStringParameterWithDefault(modelKey, "",
+		"The remote embedding model used to embed `query_text`, as `project.dataset.model` or `dataset.model`. "+
+			"It should be the model that generated the embeddings in the table.")
+	topKParameter := tools.NewIntParameterWithDefault(topKKey, 10, "The number of nearest rows to return.")
+	distanceTypeParameter := tools.NewStringParameterWithDefault(distanceTypeKey, "EUCLIDEAN",
+		fmt.Sprintf("The distance type to use, one of %s.", strings.Join(distanceTypes, ", ")))
+	parameters := tools.Parameters{tableParameter, columnParameter, queryEmbeddingParameter,
+		queryTextParameter, modelParameter, topKParameter, distanceTypeParameter}
+	// On sources with allowedProjects, the project parameter selects the
+	// project to run in.
+	allowedProjects := s.BigQueryAllowedProjects()
+	if len(allowedProjects) > 0 {
+		parameters = append(parameters, bigquerycommon.ProjectParameter(allowedProjects))
+	}
+
+	mcpManifest := tools.McpManifest{
+		Name:        cfg.Name,
+		Description: cfg.Description,
+		InputSchema: parameters.McpManifest(),
+	}
+
+	// finish tool setup
+	t := Tool{
+		Name:             cfg.Name,
+		Kind:             kind,
+		Parameters:       parameters,
+		AuthRequired:     cfg.AuthRequired,
+		UseClientOAuth:   s.UseClientAuthorization(),
+		ClientCreator:    s.BigQueryClientCreator(),
+		Client:           s.BigQueryClient(),
+		QueryTag:         s.BigQueryQueryTag(),
+		MultiProject:     len(allowedProjects) > 0,
+		ClientForProject: s.BigQueryClientForProject,
+		ReserveQuota:     s.ReserveQuota,
+		IsDatasetAllowed: s.IsDatasetAllowed,
+		manifest:         tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:      mcpManifest,
+	}
+	return t, nil
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name           string           `yaml:"name"`
+	Kind           string           `yaml:"kind"`
+	AuthRequired   []string         `yaml:"authRequired"`
+	UseClientOAuth bool             `yaml:"useClientOAuth"`
+	Parameters     tools.Parameters `yaml:"parameters"`
+
+	Client           *bigqueryapi.Client
+	QueryTag         string
+	MultiProject     bool
+	ClientForProject bigquerycommon.ClientForProjectFunc
+	ReserveQuota     bigquerycommon.ReserveQuotaFunc
+	ClientCreator    bigqueryds.BigqueryClientCreator
+	IsDatasetAllowed func(projectID, datasetID string) bool
+	manifest         tools.Manifest
+	mcpManifest      tools.McpManifest
+}
+
+func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
+	paramsMap := params.AsMap()
+	table, ok := paramsMap[tableKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("unable to cast %s parameter %v", tableKey, paramsMap[tableKey])
+	}
+	column, ok := paramsMap[columnKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("unable to cast %s parameter %v", columnKey, paramsMap[columnKey])
+	}
+	if !columnNameRegex.MatchString(column) {
+		return nil, fmt.Errorf("invalid column name %q", column)
+	}
+	queryEmbeddingRaw, ok := paramsMap[queryEmbeddingKey].([]any)
+	if !ok {
+		return nil, fmt.Errorf("unable to cast %s parameter %v", queryEmbeddingKey, paramsMap[queryEmbeddingKey])
+	}
+	queryEmbedding := make([]float64, 0, len(queryEmbeddingRaw))
+	for _, v := range queryEmbeddingRaw {
+		f, ok := v.(float64)
+		if !ok {
+			return nil, fmt.Errorf("%s contains non-numeric value: %v", queryEmbeddingKey, v)
+		}
+		queryEmbedding = append(queryEmbedding, f)
+	}
+	queryText, ok := paramsMap[queryTextKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("unable to cast %s parameter %v", queryTextKey, paramsMap[queryTextKey])
+	}
+	model, ok := paramsMap[modelKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("unable to cast %s parameter %v", modelKey, paramsMap[modelKey])
+	}
+	topK, ok := paramsMap[topKKey].(int)
+	if !ok {
+		return nil, fmt.Errorf("unable to cast %s parameter %v", topKKey, paramsMap[topKKey])
+	}
+	if topK <= 0 {
+		return nil, fmt.Errorf("'%s' must be positive, got %d", topKKey, topK)
+	}
+	distanceType, ok := paramsMap[distanceTypeKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("unable to cast %s parameter %v", distanceTypeKey, paramsMap[distanceTypeKey])
+	}
+	distanceType = strings.ToUpper(distanceType)
+	if !slices.Contains(distanceTypes, distanceType) {
+		return nil, fmt.Errorf("invalid %s %q, must be one of %s", distanceTypeKey, distanceType, strings.Join(distanceTypes, ", "))
+	}
+	if (len(queryEmbedding) == 0) == (queryText == "") {
+		return nil, fmt.Errorf("exactly one of '%s' and '%s' must be set", queryEmbeddingKey, queryTextKey)
+	}
+	if queryText != "" && model == "" {
+		return nil, fmt.Errorf("'%s' is required when '%s' is set", modelKey, queryTextKey)
+	}
+
+	bqClient := t.Client
+	var err error
+
+	// Use the clients of the project, or initialize new ones if using user
+	// OAuth token
+	if project, ok := paramsMap[bigquerycommon.ProjectKey].(string); ok && t.MultiProject {
+		// Sources with allowedProjects run the query in the requested project.
+		bqClient, _, err = bigquerycommon.ProjectClient(t.ClientForProject, project, t.UseClientOAuth, accessToken)
+		if err != nil {
+			return nil, err
+		}
+	} else if t.UseClientOAuth {
+		tokenStr, err := accessToken.ParseBearerToken()
+		if err != nil {
+			return nil, fmt.Errorf("error parsing access token: %w", err)
+		}
+		bqClient, _, err = t.ClientCreator(tokenStr, false)
+		if err != nil {
+			return nil, fmt.Errorf("error creating client from OAuth access token: %w", err)
+		}
+	}
+
+	tableProject, tableDataset, tableID, err := ParseResourceID(table, bqClient.Project())
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", tableKey, err)
+	}
+	if !t.IsDatasetAllowed(tableProject, tableDataset) {
+		return nil, fmt.Errorf("access denied to dataset '%s' because it is not in the configured list of allowed datasets for project '%s'", tableDataset, tableProject)
+	}
+
+	var querySource string
+	var queryParameters []bigqueryapi.QueryParameter
+	if queryText != "" {
+		modelProject, modelDataset, modelID, err := ParseResourceID(model, bqClient.Project())
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", modelKey, err)
+		}
+		if !t.IsDatasetAllowed(modelProject, modelDataset) {
+			return nil, fmt.Errorf("access denied to dataset '%s' because it is not in the configured list of allowed datasets for project '%s'", modelDataset, modelProject)
+		}
+		querySource = TextEmbeddingSource(fmt.Sprintf("%s.%s.%s", modelProject, modelDataset, modelID))
+		queryParameters = []bigqueryapi.QueryParameter{{Name: queryTextKey, Value: queryText}}
+	} else {
+		querySource = "(SELECT @query_embedding AS query_embedding)"
+		queryParameters = []bigqueryapi.QueryParameter{{Name: queryEmbeddingKey, Value: queryEmbedding}}
+	}
+
+	sql := VectorSearchStatement(fmt.Sprintf("%s.%s.%s", tableProject, tableDataset, tableID), column, querySource, topK, distanceType)
+
+	query := bqClient.Query(sql)
+	bigquerycommon.SetQueryTag(query, t.QueryTag)
+	query.Parameters = queryParameters
+	query.Location = bqClient.Location
+
+	// Log the query executed for debugging. The statement may hold sensitive
+	// values, so only its type and length are logged.
+	logger, err := util.LoggerFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting logger: %s", err)
+	}
+	logger.DebugContext(ctx, "executing `%s` tool query: %s", kind, tools.DescribeStatement(sql))
+
+	quota, err := bigquerycommon.NewQuota(t.ReserveQuota, t.UseClientOAuth, accessToken)
+	if err != nil {
+		return nil, err
+	}
+	var out []any
+	it, err := bigquerycommon.ReadQuery(ctx, query, quota)
+	if err != nil {
+		return nil, fmt.Errorf("unable to execute query: %w", err)
+	}
+	for {
+		var row map[string]bigqueryapi.Value
+		err = it.Next(&row)
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to iterate through query results: %w", err)
+		}
+		vMap := make(map[string]any)
+		for key, value := range row {
+			vMap[key] = value
+		}
+		out = append(out, vMap)
+	}
+	if len(out) > 0 {
+		return out, nil
+	}
+	return tools.ZeroRowsMessage, nil
+}
+
+// TextEmbeddingSource returns the subquery that selects the embedding of the
+// @query_text parameter, generated by model, a `project.dataset.model` path.
+func TextEmbeddingSource(model string) string {
+	return fmt.Sprintf("(SELECT ml_generate_embedding_result AS query_embedding "+
+		"FROM ML.GENERATE_EMBEDDING(MODEL `%s`, (SELECT @query_text AS content), STRUCT(TRUE AS flatten_json_output)))",
+		model)
+}
+
+// VectorSearchStatement returns the statement that searches column of table, a
+// `project.dataset.table` path, for the topK rows nearest to the embedding
+// selected by querySource. The embedding column is left out of the results,
+// since it is usually large and the caller already knows what it searched for.
+func VectorSearchStatement(table, column, querySource string, topK int, distanceType string) string {
+	return fmt.Sprintf("SELECT base.* EXCEPT(%s), distance "+
+		"FROM VECTOR_SEARCH(TABLE `%s`, '%s', %s, 'query_embedding', top_k => %d, distance_type => '%s') "+
+		"ORDER BY distance",
+		column, table, column, querySource, topK, distanceType)
+}
+
+// ParseResourceID splits a table or model ID into its project, dataset and
+// resource IDs, using defaultProject when the ID has no project.
+func ParseResourceID(id, defaultProject string) (string, string, string, error) {
+	matches := resourceIDRegex.FindStringSubmatch(strings.TrimSpace(id))
+	if matches == nil {
+		return "", "", "", fmt.Errorf("%q is not of the form `project.dataset.name` or `dataset.name`", id)
+	}
+	project := matches[1]
+	if project == "" {
+		project = defaultProject
+	}
+	return project, matches[2], matches[3], nil
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.Parameters, data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
+
+func (t Tool) RequiresClientAuthorization() bool {
+	return t.UseClientOAuth
+}
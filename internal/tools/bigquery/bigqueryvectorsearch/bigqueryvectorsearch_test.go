@@ -0,0 +1,216 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bigqueryvectorsearch_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigqueryvectorsearch"
+)
+
+func TestParseFromYamlBigQueryVectorSearch(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		want server.ToolConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			tools:
+				example_tool:
+					kind: bigquery-vector-search
+					source: my-instance
+					description: some description
+			`,
+			want: server.ToolConfigs{
+				"example_tool": bigqueryvectorsearch.Config{
+					Name:         "example_tool",
+					Kind:         "bigquery-vector-search",
+					Source:       "my-instance",
+					Description:  "some description",
+					AuthRequired: []string{},
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			// Parse contents
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Tools); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+
+}
+
+func TestFailInvokeBigQueryVectorSearch(t *testing.T) {
+	// the search is checked before any call to BigQuery, so the tool needs no
+	// client
+	tool := bigqueryvectorsearch.Tool{}
+	type search struct {
+		column         string
+		queryEmbedding []any
+		queryText      string
+		model          string
+		topK           int
+		distanceType   string
+	}
+	params := func(s search) tools.ParamValues {
+		return tools.ParamValues{
+			{Name: "table", Value: "my_dataset.docs"},
+			{Name: "column_to_search", Value: s.column},
+			{Name: "query_embedding", Value: s.queryEmbedding},
+			{Name: "query_text", Value: s.queryText},
+			{Name: "model", Value: s.model},
+			{Name: "top_k", Value: s.topK},
+			{Name: "distance_type", Value: s.distanceType},
+		}
+	}
+	embedding := []any{0.1, 0.2}
+	tcs := []struct {
+		desc   string
+		params tools.ParamValues
+		err    string
+	}{
+		{
+			desc:   "unknown distance type",
+			params: params(search{column: "embedding", queryEmbedding: embedding, topK: 10, distanceType: "manhattan"}),
+			err:    `invalid distance_type "MANHATTAN", must be one of EUCLIDEAN, COSINE, DOT_PRODUCT`,
+		},
+		{
+			desc:   "zero top k",
+			params: params(search{column: "embedding", queryEmbedding: embedding, topK: 0, distanceType: "COSINE"}),
+			err:    "'top_k' must be positive, got 0",
+		},
+		{
+			desc:   "negative top k",
+			params: params(search{column: "embedding", queryEmbedding: embedding, topK: -1, distanceType: "COSINE"}),
+			err:    "'top_k' must be positive, got -1",
+		},
+		{
+			desc:   "column breaking out of the query",
+			params: params(search{column: "embedding, (SELECT 1)", queryEmbedding: embedding, topK: 10, distanceType: "COSINE"}),
+			err:    `invalid column name "embedding, (SELECT 1)"`,
+		},
+		{
+			desc:   "both query embedding and query text",
+			params: params(search{column: "embedding", queryEmbedding: embedding, queryText: "cats", model: "my_dataset.my_model", topK: 10, distanceType: "COSINE"}),
+			err:    "exactly one of 'query_embedding' and 'query_text' must be set",
+		},
+		{
+			desc:   "query text without a model",
+			params: params(search{column: "embedding", queryEmbedding: []any{}, queryText: "cats", topK: 10, distanceType: "COSINE"}),
+			err:    "'model' is required when 'query_text' is set",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := tool.Invoke(context.Background(), tc.params, "")
+			if err == nil {
+				t.Fatalf("expect invocation to fail")
+			}
+			if !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("unexpected error string: got %q, want substring %q", err.Error(), tc.err)
+			}
+		})
+	}
+}
+
+func TestParseResourceID(t *testing.T) {
+	tcs := []struct {
+		desc    string
+		id      string
+		want    []string
+		wantErr bool
+	}{
+		{desc: "full path", id: "my-project.my_dataset.my-table", want: []string{"my-project", "my_dataset", "my-table"}},
+		{desc: "default project", id: " my_dataset.my_table ", want: []string{"default-project", "my_dataset", "my_table"}},
+		{desc: "table only", id: "my_table", wantErr: true},
+		{desc: "too many parts", id: "a.my-project.my_dataset.my_table", wantErr: true},
+		{desc: "backtick", id: "my_dataset.my_table` WHERE true; --", wantErr: true},
+		{desc: "quoted path", id: "`my-project.my_dataset.my_table`", wantErr: true},
+		{desc: "uppercase project", id: "My-Project.my_dataset.my_table", wantErr: true},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			project, dataset, id, err := bigqueryvectorsearch.ParseResourceID(tc.id, "default-project")
+			if err != nil {
+				if tc.wantErr {
+					return
+				}
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if tc.wantErr {
+				t.Fatalf("expected error but got nil")
+			}
+			if diff := cmp.Diff(tc.want, []string{project, dataset, id}); diff != "" {
+				t.Fatalf("incorrect resource id: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestVectorSearchStatement(t *testing.T) {
+	tcs := []struct {
+		desc        string
+		querySource string
+		want        string
+	}{
+		{
+			desc:        "embedding",
+			querySource: "(SELECT @query_embedding AS query_embedding)",
+			want: "SELECT base.* EXCEPT(embedding), distance " +
+				"FROM VECTOR_SEARCH(TABLE `my-project.my_dataset.docs`, 'embedding', (SELECT @query_embedding AS query_embedding), " +
+				"'query_embedding', top_k => 5, distance_type => 'COSINE') ORDER BY distance",
+		},
+		{
+			desc:        "text",
+			querySource: bigqueryvectorsearch.TextEmbeddingSource("my-project.my_dataset.my_model"),
+			want: "SELECT base.* EXCEPT(embedding), distance " +
+				"FROM VECTOR_SEARCH(TABLE `my-project.my_dataset.docs`, 'embedding', " +
+				"(SELECT ml_generate_embedding_result AS query_embedding FROM ML.GENERATE_EMBEDDING(MODEL `my-project.my_dataset.my_model`, " +
+				"(SELECT @query_text AS content), STRUCT(TRUE AS flatten_json_output))), " +
+				"'query_embedding', top_k => 5, distance_type => 'COSINE') ORDER BY distance",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := bigqueryvectorsearch.VectorSearchStatement("my-project.my_dataset.docs", "embedding", tc.querySource, 5, "COSINE")
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect statement: diff %v", diff)
+			}
+		})
+	}
+}
@@ -217,6 +217,12 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	return out, nil
 }
 
+// ResolveStatement returns the statement with the template parameters
+// substituted by their values.
+func (t Tool) ResolveStatement(params tools.ParamValues) (string, error) {
+	return tools.ResolveTemplateParams(t.TemplateParameters, t.Statement, params.AsMap())
+}
+
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
 	return tools.ParseParams(t.AllParams, data, claims)
 }
@@ -0,0 +1,312 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	cacheTTLKey         = "cacheTTL"
+	cacheTagsKey        = "cacheTags"
+	invalidatesCacheKey = "invalidatesCache"
+)
+
+// CacheOptions configures how a tool uses the result cache.
+type CacheOptions struct {
+	// TTL is how long results are cached for. Results are not cached if it is
+	// zero.
+	TTL time.Duration
+	// Tags name the tables or datasets, e.g. "sales" or "sales.orders", that
+	// cached results are read from.
+	Tags []string
+	// Invalidates names the tables or datasets that the tool writes to. Every
+	// invocation clears the cached results tagged with them.
+	Invalidates []string
+}
+
+// ExtractCacheOptions removes the `cacheTTL`, `cacheTags` and
+// `invalidatesCache` fields from a raw tool config, so that the remaining
+// config can be decoded strictly by the tool kind. A nil CacheOptions is
+// returned if none of them are set.
+func ExtractCacheOptions(toolName string, v map[string]any) (*CacheOptions, error) {
+	rawTTL, hasTTL := v[cacheTTLKey]
+	rawTags, hasTags := v[cacheTagsKey]
+	rawInvalidates, hasInvalidates := v[invalidatesCacheKey]
+	delete(v, cacheTTLKey)
+	delete(v, cacheTagsKey)
+	delete(v, invalidatesCacheKey)
+	if !hasTTL && !hasTags && !hasInvalidates {
+		return nil, nil
+	}
+	if hasTags && !hasTTL {
+		return nil, fmt.Errorf("%q requires %q to be set for tool %q", cacheTagsKey, cacheTTLKey, toolName)
+	}
+
+	var opts CacheOptions
+	if hasTTL {
+		s, ok := rawTTL.(string)
+		if !ok {
+			return nil, fmt.Errorf("%q must be a duration string, e.g. \"5m\", for tool %q", cacheTTLKey, toolName)
+		}
+		ttl, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %q for tool %q: %w", cacheTTLKey, toolName, err)
+		}
+		if ttl <= 0 {
+			return nil, fmt.Errorf("%q must be positive for tool %q", cacheTTLKey, toolName)
+		}
+		opts.TTL = ttl
+	}
+	var err error
+	if opts.Tags, err = stringList(toolName, cacheTagsKey, rawTags); err != nil {
+		return nil, err
+	}
+	if opts.Invalidates, err = stringList(toolName, invalidatesCacheKey, rawInvalidates); err != nil {
+		return nil, err
+	}
+	return &opts, nil
+}
+
+// stringList converts a raw list of strings from a tool config.
+func stringList(toolName, key string, raw any) ([]string, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	items, ok := raw.([]any)
+	if !ok {
+		return nil, fmt.Errorf("%q must be a list of strings for tool %q", key, toolName)
+	}
+	list := make([]string, 0, len(items))
+	for _, item := range items {
+		s, ok := item.(string)
+		if !ok || s == "" {
+			return nil, fmt.Errorf("%q must be a list of strings for tool %q", key, toolName)
+		}
+		list = append(list, s)
+	}
+	return list, nil
+}
+
+type cacheBypassKey struct{}
+
+// WithCacheBypass returns a context whose invocations skip cached results.
+// Their results are still cached for later invocations.
+func WithCacheBypass(ctx context.Context) context.Context {
+	return context.WithValue(ctx, cacheBypassKey{}, true)
+}
+
+func cacheBypassed(ctx context.Context) bool {
+	bypass, _ := ctx.Value(cacheBypassKey{}).(bool)
+	return bypass
+}
+
+// CacheBypassRequested reports whether the request headers ask for a fresh
+// result with `Cache-Control: no-cache`.
+func CacheBypassRequested(header http.Header) bool {
+	for _, v := range header.Values("Cache-Control") {
+		for _, directive := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(directive), "no-cache") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type cacheEntry struct {
+	result  any
+	expires time.Time
+	tags    []string
+}
+
+// ResultCache holds tool results keyed by the invocations that returned them.
+type ResultCache struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+// NewResultCache returns an empty ResultCache.
+func NewResultCache() *ResultCache {
+	return &ResultCache{entries: make(map[string]cacheEntry)}
+}
+
+// defaultResultCache is shared by every tool configured with `cacheTTL`.
+var defaultResultCache = NewResultCache()
+
+func (c *ResultCache) get(key string) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !time.Now().Before(e.expires) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return e.result, true
+}
+
+func (c *ResultCache) set(key string, result any, ttl time.Duration, tags []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	// drop expired entries so that the cache doesn't grow without bound
+	for k, e := range c.entries {
+		if !now.Before(e.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = cacheEntry{result: result, expires: now.Add(ttl), tags: tags}
+}
+
+// Invalidate removes the cached results tagged with any of the given tags. A
+// dataset tag and the tags of its tables, e.g. "sales" and "sales.orders",
+// match each other.
+func (c *ResultCache) Invalidate(tags ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k, e := range c.entries {
+		if slices.ContainsFunc(e.tags, func(t string) bool {
+			return slices.ContainsFunc(tags, func(u string) bool { return tagsMatch(t, u) })
+		}) {
+			delete(c.entries, k)
+		}
+	}
+}
+
+func tagsMatch(a, b string) bool {
+	return a == b || strings.HasPrefix(a, b+".") || strings.HasPrefix(b, a+".")
+}
+
+// cacheKey identifies an invocation by tool name and config, parameters
+// sorted by name, and access token, so that users of client OAuth tools never
+// share results, and a tool reloaded with a different config doesn't return
+// the results of the previous one.
+func cacheKey(toolName, config string, params ParamValues, accessToken AccessToken) (string, error) {
+	sorted := slices.Clone(params)
+	slices.SortFunc(sorted, func(a, b ParamValue) int { return strings.Compare(a.Name, b.Name) })
+	b, err := json.Marshal(struct {
+		Tool        string
+		Config      string
+		Params      ParamValues
+		AccessToken AccessToken
+	}{toolName, config, sorted, accessToken})
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// CachedConfig wraps a ToolConfig with a result cache.
+type CachedConfig = WrapperConfig[CacheOption]
+
+// validate interface
+var _ ToolReferencingConfig = CachedConfig{}
+
+// CacheOption is the option of a CachedConfig: the options of the cache of
+// the tool called Name.
+type CacheOption struct {
+	Name    string
+	Options CacheOptions
+}
+
+func (o CacheOption) wrap(c ToolConfig, t Tool) (Tool, error) {
+	cached := NewCachedTool(t, o.Name, o.Options, defaultResultCache)
+	cached.config = configHash(c)
+	return cached, nil
+}
+
+// configHash returns a hash of a tool config, which changes when the tool is
+// reloaded with a different config.
+func configHash(c ToolConfig) string {
+	b, err := json.Marshal(c)
+	if err != nil {
+		// configs that can't be marshalled, e.g. as they hold functions, are
+		// hashed as printed
+		b = []byte(fmt.Sprintf("%#v", c))
+	}
+	sum := sha256.Sum256(append([]byte(fmt.Sprintf("%T", c)), b...))
+	return hex.EncodeToString(sum[:])
+}
+
+// CachedTool wraps a Tool so that repeated invocations with the same
+// parameters within Options.TTL return the first result instead of invoking
+// the tool again, and so that its invocations clear the cached results tagged
+// with Options.Invalidates. Failed invocations are not cached, and neither are
+// invocations that ask for an ArrowStream, since their results are in a
+// different format than those of the same parameters otherwise.
+type CachedTool struct {
+	Tool
+	Name    string
+	Options CacheOptions
+	cache   *ResultCache
+	// config is the hash of the config of Tool
+	config string
+}
+
+// validate interface
+var _ WrapperTool = CachedTool{}
+
+func (t CachedTool) Unwrap() Tool {
+	return t.Tool
+}
+
+// NewCachedTool returns t wrapped to use cache as configured by opts.
+func NewCachedTool(t Tool, name string, opts CacheOptions, cache *ResultCache) CachedTool {
+	return CachedTool{Tool: t, Name: name, Options: opts, cache: cache}
+}
+
+func (t CachedTool) Invoke(ctx context.Context, params ParamValues, accessToken AccessToken) (any, error) {
+	if t.Options.TTL <= 0 || ArrowStreamRequested(ctx) {
+		return t.invoke(ctx, params, accessToken)
+	}
+	key, err := cacheKey(t.Name, t.config, params, accessToken)
+	if err != nil {
+		// parameters that can't be keyed are passed through uncached
+		return t.invoke(ctx, params, accessToken)
+	}
+	if !cacheBypassed(ctx) {
+		if res, ok := t.cache.get(key); ok {
+			return res, nil
+		}
+	}
+	res, err := t.invoke(ctx, params, accessToken)
+	if err != nil {
+		return nil, err
+	}
+	t.cache.set(key, res, t.Options.TTL, t.Options.Tags)
+	return res, nil
+}
+
+// invoke runs the tool and clears the results it invalidates. They are cleared
+// even if the invocation fails, since a failed write may have partly applied.
+func (t CachedTool) invoke(ctx context.Context, params ParamValues, accessToken AccessToken) (any, error) {
+	res, err := t.Tool.Invoke(ctx, params, accessToken)
+	if len(t.Options.Invalidates) > 0 {
+		t.cache.Invalidate(t.Options.Invalidates...)
+	}
+	return res, err
+}
@@ -0,0 +1,355 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// backendTool records how many times its backend was called, and returns the
+// call number as its result.
+type backendTool struct {
+	calls *atomic.Int32
+	err   error
+}
+
+func (t backendTool) Invoke(context.Context, tools.ParamValues, tools.AccessToken) (any, error) {
+	n := t.calls.Add(1)
+	if t.err != nil {
+		return nil, t.err
+	}
+	return fmt.Sprintf("call %d", n), nil
+}
+
+func (t backendTool) ParseParams(map[string]any, map[string]map[string]any) (tools.ParamValues, error) {
+	return nil, nil
+}
+
+func (t backendTool) Manifest() tools.Manifest {
+	return tools.Manifest{}
+}
+
+func (t backendTool) McpManifest() tools.McpManifest {
+	return tools.McpManifest{}
+}
+
+func (t backendTool) Authorized([]string) bool {
+	return true
+}
+
+func (t backendTool) RequiresClientAuthorization() bool {
+	return false
+}
+
+func TestCachedTool(t *testing.T) {
+	ctx := context.Background()
+	params := tools.ParamValues{{Name: "dataset", Value: "sales"}, {Name: "limit", Value: 10}}
+	reordered := tools.ParamValues{{Name: "limit", Value: 10}, {Name: "dataset", Value: "sales"}}
+	other := tools.ParamValues{{Name: "dataset", Value: "users"}, {Name: "limit", Value: 10}}
+
+	tcs := []struct {
+		desc      string
+		ttl       time.Duration
+		invoke    func(tool tools.Tool) (any, error)
+		want      any
+		wantCalls int32
+	}{
+		{
+			desc: "same parameters within ttl",
+			ttl:  time.Minute,
+			invoke: func(tool tools.Tool) (any, error) {
+				return tool.Invoke(ctx, reordered, "")
+			},
+			want:      "call 1",
+			wantCalls: 1,
+		},
+		{
+			desc: "different parameters",
+			ttl:  time.Minute,
+			invoke: func(tool tools.Tool) (any, error) {
+				return tool.Invoke(ctx, other, "")
+			},
+			want:      "call 2",
+			wantCalls: 2,
+		},
+		{
+			desc: "different access token",
+			ttl:  time.Minute,
+			invoke: func(tool tools.Tool) (any, error) {
+				return tool.Invoke(ctx, params, "Bearer other-user")
+			},
+			want:      "call 2",
+			wantCalls: 2,
+		},
+		{
+			desc: "bypassed",
+			ttl:  time.Minute,
+			invoke: func(tool tools.Tool) (any, error) {
+				return tool.Invoke(tools.WithCacheBypass(ctx), params, "")
+			},
+			want:      "call 2",
+			wantCalls: 2,
+		},
+		{
+			desc: "expired",
+			ttl:  10 * time.Millisecond,
+			invoke: func(tool tools.Tool) (any, error) {
+				time.Sleep(20 * time.Millisecond)
+				return tool.Invoke(ctx, params, "")
+			},
+			want:      "call 2",
+			wantCalls: 2,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			backend := backendTool{calls: &atomic.Int32{}}
+			tool := tools.NewCachedTool(backend, "list_tables", tools.CacheOptions{TTL: tc.ttl}, tools.NewResultCache())
+
+			first, err := tool.Invoke(ctx, params, "")
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if first != "call 1" {
+				t.Fatalf("unexpected first result: got %v, want %q", first, "call 1")
+			}
+			got, err := tc.invoke(tool)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect result: diff %v", diff)
+			}
+			if calls := backend.calls.Load(); calls != tc.wantCalls {
+				t.Fatalf("unexpected backend calls: got %d, want %d", calls, tc.wantCalls)
+			}
+		})
+	}
+}
+
+func TestCachedToolSharedCache(t *testing.T) {
+	ctx := context.Background()
+	cache := tools.NewResultCache()
+	backend := backendTool{calls: &atomic.Int32{}}
+	a := tools.NewCachedTool(backend, "tool_a", tools.CacheOptions{TTL: time.Minute}, cache)
+	b := tools.NewCachedTool(backend, "tool_b", tools.CacheOptions{TTL: time.Minute}, cache)
+
+	for _, tool := range []tools.Tool{a, b, a, b} {
+		if _, err := tool.Invoke(ctx, nil, ""); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	if calls := backend.calls.Load(); calls != 2 {
+		t.Fatalf("tools sharing a cache should be keyed by name: got %d backend calls, want 2", calls)
+	}
+}
+
+func TestCachedToolErrorsNotCached(t *testing.T) {
+	ctx := context.Background()
+	backend := backendTool{calls: &atomic.Int32{}, err: errors.New("backend unavailable")}
+	tool := tools.NewCachedTool(backend, "list_tables", tools.CacheOptions{TTL: time.Minute}, tools.NewResultCache())
+
+	for i := 0; i < 2; i++ {
+		if _, err := tool.Invoke(ctx, nil, ""); err == nil {
+			t.Fatalf("expected error, got nil")
+		}
+	}
+	if calls := backend.calls.Load(); calls != 2 {
+		t.Fatalf("failed invocations should not be cached: got %d backend calls, want 2", calls)
+	}
+}
+
+func TestCacheInvalidation(t *testing.T) {
+	ctx := context.Background()
+	tcs := []struct {
+		desc        string
+		tags        []string
+		invalidates []string
+		wantCalls   int32
+	}{
+		{
+			desc:        "write to the same table",
+			tags:        []string{"sales.orders"},
+			invalidates: []string{"sales.orders"},
+			wantCalls:   2,
+		},
+		{
+			desc:        "write to the dataset of the table",
+			tags:        []string{"sales.orders"},
+			invalidates: []string{"sales"},
+			wantCalls:   2,
+		},
+		{
+			desc:        "write to a table of the dataset",
+			tags:        []string{"sales"},
+			invalidates: []string{"sales.orders"},
+			wantCalls:   2,
+		},
+		{
+			desc:        "write to another table",
+			tags:        []string{"sales.orders"},
+			invalidates: []string{"sales.customers"},
+			wantCalls:   1,
+		},
+		{
+			desc:        "write to a table with a shared prefix",
+			tags:        []string{"sales.orders"},
+			invalidates: []string{"sales.orders_archive"},
+			wantCalls:   1,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			cache := tools.NewResultCache()
+			backend := backendTool{calls: &atomic.Int32{}}
+			read := tools.NewCachedTool(backend, "list_orders", tools.CacheOptions{TTL: time.Minute, Tags: tc.tags}, cache)
+			write := tools.NewCachedTool(backendTool{calls: &atomic.Int32{}}, "insert_order", tools.CacheOptions{Invalidates: tc.invalidates}, cache)
+
+			if _, err := read.Invoke(ctx, nil, ""); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if _, err := write.Invoke(ctx, nil, ""); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if _, err := read.Invoke(ctx, nil, ""); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if calls := backend.calls.Load(); calls != tc.wantCalls {
+				t.Fatalf("unexpected backend calls for the read: got %d, want %d", calls, tc.wantCalls)
+			}
+		})
+	}
+}
+
+func TestExtractCacheOptions(t *testing.T) {
+	tcs := []struct {
+		name    string
+		in      map[string]any
+		want    *tools.CacheOptions
+		wantErr bool
+	}{
+		{
+			name: "not set",
+			in:   map[string]any{"kind": "some-kind"},
+			want: nil,
+		},
+		{
+			name: "ttl",
+			in:   map[string]any{"kind": "some-kind", "cacheTTL": "5m"},
+			want: &tools.CacheOptions{TTL: 5 * time.Minute},
+		},
+		{
+			name: "ttl and tags",
+			in:   map[string]any{"kind": "some-kind", "cacheTTL": "5m", "cacheTags": []any{"sales.orders"}},
+			want: &tools.CacheOptions{TTL: 5 * time.Minute, Tags: []string{"sales.orders"}},
+		},
+		{
+			name: "invalidates",
+			in:   map[string]any{"kind": "some-kind", "invalidatesCache": []any{"sales"}},
+			want: &tools.CacheOptions{Invalidates: []string{"sales"}},
+		},
+		{
+			name:    "not a duration",
+			in:      map[string]any{"kind": "some-kind", "cacheTTL": "soon"},
+			wantErr: true,
+		},
+		{
+			name:    "integer",
+			in:      map[string]any{"kind": "some-kind", "cacheTTL": uint64(60)},
+			wantErr: true,
+		},
+		{
+			name:    "zero",
+			in:      map[string]any{"kind": "some-kind", "cacheTTL": "0s"},
+			wantErr: true,
+		},
+		{
+			name:    "tags without ttl",
+			in:      map[string]any{"kind": "some-kind", "cacheTags": []any{"sales"}},
+			wantErr: true,
+		},
+		{
+			name:    "tags not a list",
+			in:      map[string]any{"kind": "some-kind", "cacheTTL": "5m", "cacheTags": "sales"},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tools.ExtractCacheOptions("my-tool", tc.in)
+			if err != nil {
+				if tc.wantErr {
+					return
+				}
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if tc.wantErr {
+				t.Fatalf("expected error but got nil")
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect cache options: diff %v", diff)
+			}
+			if diff := cmp.Diff(map[string]any{"kind": "some-kind"}, tc.in); diff != "" {
+				t.Fatalf("cache fields not removed from config: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestCacheBypassRequested(t *testing.T) {
+	tcs := []struct {
+		name   string
+		header http.Header
+		want   bool
+	}{
+		{name: "no header", header: nil, want: false},
+		{name: "no-cache", header: http.Header{"Cache-Control": []string{"no-cache"}}, want: true},
+		{name: "among directives", header: http.Header{"Cache-Control": []string{"max-age=0, No-Cache"}}, want: true},
+		{name: "other directive", header: http.Header{"Cache-Control": []string{"max-age=60"}}, want: false},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tools.CacheBypassRequested(tc.header); got != tc.want {
+				t.Fatalf("unexpected result: got %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCachedToolArrowInvocations(t *testing.T) {
+	calls := &atomic.Int32{}
+	tool := tools.NewCachedTool(backendTool{calls: calls}, "my-tool", tools.CacheOptions{TTL: time.Minute}, tools.NewResultCache())
+	ctx := tools.WithArrowStream(context.Background())
+	for range 2 {
+		if _, err := tool.Invoke(ctx, nil, ""); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	if _, err := tool.Invoke(context.Background(), nil, ""); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Fatalf("Arrow invocations should neither use nor fill the cache: got %d calls, want 3", got)
+	}
+}
@@ -54,12 +54,16 @@ type Config struct {
 	AuthRequired []string `yaml:"authRequired"`
 }
 
-var _ tools.ToolConfig = Config{}
+var _ tools.StatementConfig = Config{}
 
 func (cfg Config) ToolConfigKind() string {
 	return executeSQLKind
 }
 
+// TakesStatement marks the tool as one that runs the statement given in its
+// "sql" parameter.
+func (cfg Config) TakesStatement() {}
+
 func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
 	rawS, ok := srcs[cfg.Source]
 	if !ok {
@@ -21,6 +21,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/genai-toolbox/internal/server"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
 )
 
 func TestParseFromYamlClickHouseExecuteSQL(t *testing.T) {
@@ -43,12 +44,14 @@ func TestParseFromYamlClickHouseExecuteSQL(t *testing.T) {
 					description: some description
 			`,
 			want: server.ToolConfigs{
-				"example_tool": Config{
-					Name:         "example_tool",
-					Kind:         "clickhouse-execute-sql",
-					Source:       "my-instance",
-					Description:  "some description",
-					AuthRequired: []string{},
+				"example_tool": tools.StatementLengthConfig{
+					ToolConfig: Config{
+						Name:         "example_tool",
+						Kind:         "clickhouse-execute-sql",
+						Source:       "my-instance",
+						Description:  "some description",
+						AuthRequired: []string{},
+					},
 				},
 			},
 		},
@@ -0,0 +1,125 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const coerceParametersKey = "coerceParameters"
+
+// ExtractParamCoercion removes the `coerceParameters` field from a raw tool
+// config, so that the remaining config can be decoded strictly by the tool
+// kind.
+func ExtractParamCoercion(toolName string, v map[string]any) (bool, error) {
+	raw, ok := v[coerceParametersKey]
+	if !ok {
+		return false, nil
+	}
+	delete(v, coerceParametersKey)
+	coerce, ok := raw.(bool)
+	if !ok {
+		return false, fmt.Errorf("%q must be a boolean for tool %q", coerceParametersKey, toolName)
+	}
+	return coerce, nil
+}
+
+// CoercingConfig wraps a ToolConfig so that its parameters accept string
+// representations of their values.
+type CoercingConfig = WrapperConfig[CoercingOption]
+
+// validate interface
+var _ ToolReferencingConfig = CoercingConfig{}
+
+// CoercingOption is the option of a CoercingConfig, which has no settings.
+type CoercingOption struct{}
+
+func (CoercingOption) wrap(_ ToolConfig, t Tool) (Tool, error) {
+	return NewCoercingTool(t), nil
+}
+
+// CoercingTool wraps a Tool so that string arguments are converted to the
+// integer, float or boolean type of their parameter before being parsed, e.g.
+// "123" to 123 and "true" to true. Strings that don't unambiguously represent
+// a value of the parameter type are left alone and rejected by the tool.
+type CoercingTool struct {
+	Tool
+	params map[string]ParameterManifest
+}
+
+// validate interface
+var _ WrapperTool = CoercingTool{}
+
+func (t CoercingTool) Unwrap() Tool {
+	return t.Tool
+}
+
+// NewCoercingTool returns t wrapped to coerce string arguments.
+func NewCoercingTool(t Tool) CoercingTool {
+	params := make(map[string]ParameterManifest)
+	for _, p := range t.Manifest().Parameters {
+		params[p.Name] = p
+	}
+	return CoercingTool{Tool: t, params: params}
+}
+
+func (t CoercingTool) ParseParams(data map[string]any, claims map[string]map[string]any) (ParamValues, error) {
+	coerced := make(map[string]any, len(data))
+	for name, v := range data {
+		if p, ok := t.params[name]; ok {
+			v = coerceValue(p, v)
+		}
+		coerced[name] = v
+	}
+	return t.Tool.ParseParams(coerced, claims)
+}
+
+// coerceValue converts v to the type of p if v is a string representation of
+// it, including the items of arrays.
+func coerceValue(p ParameterManifest, v any) any {
+	if arr, ok := v.([]any); ok && p.Type == typeArray && p.Items != nil {
+		out := make([]any, len(arr))
+		for i, item := range arr {
+			out[i] = coerceValue(*p.Items, item)
+		}
+		return out
+	}
+	s, ok := v.(string)
+	if !ok {
+		return v
+	}
+	switch p.Type {
+	case typeInt:
+		if _, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return json.Number(s)
+		}
+	case typeFloat:
+		if f, err := strconv.ParseFloat(s, 64); err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) {
+			return json.Number(s)
+		}
+	case typeBool:
+		if strings.EqualFold(s, "true") {
+			return true
+		}
+		if strings.EqualFold(s, "false") {
+			return false
+		}
+	}
+	return v
+}
@@ -0,0 +1,195 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// paramsTool parses its arguments strictly against its parameters.
+type paramsTool struct {
+	params tools.Parameters
+}
+
+func (t paramsTool) Invoke(context.Context, tools.ParamValues, tools.AccessToken) (any, error) {
+	return nil, nil
+}
+
+func (t paramsTool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.params, data, claims)
+}
+
+func (t paramsTool) Manifest() tools.Manifest {
+	return tools.Manifest{Parameters: t.params.Manifest()}
+}
+
+func (t paramsTool) McpManifest() tools.McpManifest {
+	return tools.McpManifest{}
+}
+
+func (t paramsTool) Authorized([]string) bool {
+	return true
+}
+
+func (t paramsTool) RequiresClientAuthorization() bool {
+	return false
+}
+
+func TestCoercingTool(t *testing.T) {
+	tool := tools.NewCoercingTool(paramsTool{params: tools.Parameters{
+		tools.NewIntParameterWithRequired("limit", "The maximum number of rows.", false),
+		tools.NewFloatParameterWithRequired("ratio", "The sampling ratio.", false),
+		tools.NewBooleanParameterWithRequired("verbose", "Whether to include details.", false),
+		tools.NewStringParameterWithRequired("name", "The name to search for.", false),
+		tools.NewArrayParameterWithRequired("ids", "The ids to look up.", false, tools.NewIntParameter("id", "An id.")),
+	}})
+
+	tcs := []struct {
+		desc    string
+		in      map[string]any
+		want    map[string]any
+		wantErr bool
+	}{
+		{
+			desc: "integer",
+			in:   map[string]any{"limit": "123"},
+			want: map[string]any{"limit": 123},
+		},
+		{
+			desc: "float",
+			in:   map[string]any{"ratio": "0.5"},
+			want: map[string]any{"ratio": 0.5},
+		},
+		{
+			desc: "boolean",
+			in:   map[string]any{"verbose": "true"},
+			want: map[string]any{"verbose": true},
+		},
+		{
+			desc: "boolean in another case",
+			in:   map[string]any{"verbose": "False"},
+			want: map[string]any{"verbose": false},
+		},
+		{
+			desc: "array items",
+			in:   map[string]any{"ids": []any{"1", "2"}},
+			want: map[string]any{"ids": []any{1, 2}},
+		},
+		{
+			desc: "string parameters are unchanged",
+			in:   map[string]any{"name": "123"},
+			want: map[string]any{"name": "123"},
+		},
+		{
+			desc:    "not an integer",
+			in:      map[string]any{"limit": "abc"},
+			wantErr: true,
+		},
+		{
+			desc:    "fractional integer",
+			in:      map[string]any{"limit": "1.5"},
+			wantErr: true,
+		},
+		{
+			desc:    "ambiguous boolean",
+			in:      map[string]any{"verbose": "yes"},
+			wantErr: true,
+		},
+		{
+			desc:    "not a finite float",
+			in:      map[string]any{"ratio": "NaN"},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			params, err := tool.ParseParams(tc.in, nil)
+			if err != nil {
+				if tc.wantErr {
+					return
+				}
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if tc.wantErr {
+				t.Fatalf("expected error but got nil")
+			}
+			got := make(map[string]any)
+			for k, v := range params.AsMap() {
+				if v != nil {
+					got[k] = v
+				}
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect params: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestCoercionDefaultStrict(t *testing.T) {
+	tool := paramsTool{params: tools.Parameters{tools.NewIntParameter("limit", "The maximum number of rows.")}}
+	if _, err := tool.ParseParams(map[string]any{"limit": "123"}, nil); err == nil {
+		t.Fatalf("expected tools without coercion to reject strings for integer parameters")
+	}
+}
+
+func TestExtractParamCoercion(t *testing.T) {
+	tcs := []struct {
+		name    string
+		in      map[string]any
+		want    bool
+		wantErr bool
+	}{
+		{
+			name: "not set",
+			in:   map[string]any{"kind": "some-kind"},
+			want: false,
+		},
+		{
+			name: "enabled",
+			in:   map[string]any{"kind": "some-kind", "coerceParameters": true},
+			want: true,
+		},
+		{
+			name:    "not a boolean",
+			in:      map[string]any{"kind": "some-kind", "coerceParameters": "yes"},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tools.ExtractParamCoercion("my-tool", tc.in)
+			if err != nil {
+				if tc.wantErr {
+					return
+				}
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if tc.wantErr {
+				t.Fatalf("expected error but got nil")
+			}
+			if got != tc.want {
+				t.Fatalf("incorrect coercion: got %t, want %t", got, tc.want)
+			}
+			if diff := cmp.Diff(map[string]any{"kind": "some-kind"}, tc.in); diff != "" {
+				t.Fatalf("coerceParameters not removed from config: diff %v", diff)
+			}
+		})
+	}
+}
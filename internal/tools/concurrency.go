@@ -0,0 +1,135 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+const (
+	maxConcurrencyKey    = "maxConcurrency"
+	concurrencyPolicyKey = "concurrencyPolicy"
+
+	// ConcurrencyPolicyQueue makes excess invocations wait for a free slot.
+	ConcurrencyPolicyQueue = "queue"
+	// ConcurrencyPolicyReject makes excess invocations fail immediately.
+	ConcurrencyPolicyReject = "reject"
+)
+
+// ErrConcurrencyLimit is returned when an invocation is rejected because the
+// tool is already running its maximum number of concurrent invocations.
+var ErrConcurrencyLimit = errors.New("tool concurrency limit reached")
+
+// ConcurrencyLimit bounds the number of simultaneous invocations of a tool.
+type ConcurrencyLimit struct {
+	Max    int
+	Policy string
+}
+
+// ExtractConcurrencyLimit removes the `maxConcurrency` and `concurrencyPolicy`
+// fields from a raw tool config, so that the remaining config can be decoded
+// strictly by the tool kind. A nil ConcurrencyLimit is returned if
+// `maxConcurrency` is not set.
+func ExtractConcurrencyLimit(toolName string, v map[string]any) (*ConcurrencyLimit, error) {
+	rawMax, hasMax := v[maxConcurrencyKey]
+	rawPolicy, hasPolicy := v[concurrencyPolicyKey]
+	delete(v, maxConcurrencyKey)
+	delete(v, concurrencyPolicyKey)
+	if !hasMax {
+		if hasPolicy {
+			return nil, fmt.Errorf("%q requires %q to be set for tool %q", concurrencyPolicyKey, maxConcurrencyKey, toolName)
+		}
+		return nil, nil
+	}
+
+	var max int
+	switch m := rawMax.(type) {
+	case int:
+		max = m
+	case int64:
+		max = int(m)
+	case uint64:
+		max = int(m)
+	case float64:
+		if m != float64(int(m)) {
+			return nil, fmt.Errorf("%q must be an integer for tool %q", maxConcurrencyKey, toolName)
+		}
+		max = int(m)
+	default:
+		return nil, fmt.Errorf("%q must be an integer for tool %q", maxConcurrencyKey, toolName)
+	}
+	if max < 1 {
+		return nil, fmt.Errorf("%q must be at least 1 for tool %q", maxConcurrencyKey, toolName)
+	}
+
+	policy := ConcurrencyPolicyQueue
+	if hasPolicy {
+		p, ok := rawPolicy.(string)
+		if !ok || (p != ConcurrencyPolicyQueue && p != ConcurrencyPolicyReject) {
+			return nil, fmt.Errorf("%q must be one of %q or %q for tool %q", concurrencyPolicyKey, ConcurrencyPolicyQueue, ConcurrencyPolicyReject, toolName)
+		}
+		policy = p
+	}
+	return &ConcurrencyLimit{Max: max, Policy: policy}, nil
+}
+
+// LimitedConfig wraps a ToolConfig with a concurrency limit.
+type LimitedConfig = WrapperConfig[ConcurrencyLimit]
+
+// validate interface
+var _ ToolReferencingConfig = LimitedConfig{}
+
+func (l ConcurrencyLimit) wrap(_ ToolConfig, t Tool) (Tool, error) {
+	return NewLimitedTool(t, l), nil
+}
+
+// LimitedTool wraps a Tool so that at most Limit.Max invocations run at once.
+type LimitedTool struct {
+	Tool
+	Limit ConcurrencyLimit
+	sem   chan struct{}
+}
+
+// validate interface
+var _ WrapperTool = LimitedTool{}
+
+func (t LimitedTool) Unwrap() Tool {
+	return t.Tool
+}
+
+// NewLimitedTool returns t wrapped with the given concurrency limit.
+func NewLimitedTool(t Tool, limit ConcurrencyLimit) LimitedTool {
+	return LimitedTool{Tool: t, Limit: limit, sem: make(chan struct{}, limit.Max)}
+}
+
+func (t LimitedTool) Invoke(ctx context.Context, params ParamValues, accessToken AccessToken) (any, error) {
+	if t.Limit.Policy == ConcurrencyPolicyReject {
+		select {
+		case t.sem <- struct{}{}:
+		default:
+			return nil, fmt.Errorf("%w: at most %d concurrent invocations allowed", ErrConcurrencyLimit, t.Limit.Max)
+		}
+	} else {
+		select {
+		case t.sem <- struct{}{}:
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting for a free invocation slot: %w", ctx.Err())
+		}
+	}
+	defer func() { <-t.sem }()
+	return t.Tool.Invoke(ctx, params, accessToken)
+}
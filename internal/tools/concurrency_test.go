@@ -0,0 +1,202 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// countingTool records the highest number of invocations running at once.
+type countingTool struct {
+	running *atomic.Int32
+	peak    *atomic.Int32
+	delay   time.Duration
+}
+
+func (t countingTool) Invoke(context.Context, tools.ParamValues, tools.AccessToken) (any, error) {
+	n := t.running.Add(1)
+	defer t.running.Add(-1)
+	for {
+		p := t.peak.Load()
+		if n <= p || t.peak.CompareAndSwap(p, n) {
+			break
+		}
+	}
+	time.Sleep(t.delay)
+	return "ok", nil
+}
+
+func (t countingTool) ParseParams(map[string]any, map[string]map[string]any) (tools.ParamValues, error) {
+	return nil, nil
+}
+
+func (t countingTool) Manifest() tools.Manifest {
+	return tools.Manifest{}
+}
+
+func (t countingTool) McpManifest() tools.McpManifest {
+	return tools.McpManifest{}
+}
+
+func (t countingTool) Authorized([]string) bool {
+	return true
+}
+
+func (t countingTool) RequiresClientAuthorization() bool {
+	return false
+}
+
+func newCountingTool(delay time.Duration) countingTool {
+	return countingTool{running: &atomic.Int32{}, peak: &atomic.Int32{}, delay: delay}
+}
+
+func TestLimitedToolQueue(t *testing.T) {
+	inner := newCountingTool(20 * time.Millisecond)
+	tool := tools.NewLimitedTool(inner, tools.ConcurrencyLimit{Max: 2, Policy: tools.ConcurrencyPolicyQueue})
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := tool.Invoke(context.Background(), nil, ""); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if peak := inner.peak.Load(); peak > 2 {
+		t.Fatalf("concurrency limit exceeded: got %d concurrent invocations, want at most 2", peak)
+	}
+}
+
+func TestLimitedToolReject(t *testing.T) {
+	inner := newCountingTool(100 * time.Millisecond)
+	tool := tools.NewLimitedTool(inner, tools.ConcurrencyLimit{Max: 1, Policy: tools.ConcurrencyPolicyReject})
+
+	var wg sync.WaitGroup
+	var succeeded, rejected atomic.Int32
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := tool.Invoke(context.Background(), nil, "")
+			switch {
+			case err == nil:
+				succeeded.Add(1)
+			case errors.Is(err, tools.ErrConcurrencyLimit):
+				rejected.Add(1)
+			default:
+				t.Errorf("unexpected error: %s", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if peak := inner.peak.Load(); peak > 1 {
+		t.Fatalf("concurrency limit exceeded: got %d concurrent invocations, want at most 1", peak)
+	}
+	if succeeded.Load() < 1 || rejected.Load() < 1 {
+		t.Fatalf("expected some invocations to succeed and some to be rejected, got %d succeeded and %d rejected", succeeded.Load(), rejected.Load())
+	}
+}
+
+func TestLimitedToolQueueCancelled(t *testing.T) {
+	inner := newCountingTool(200 * time.Millisecond)
+	tool := tools.NewLimitedTool(inner, tools.ConcurrencyLimit{Max: 1, Policy: tools.ConcurrencyPolicyQueue})
+
+	go func() { _, _ = tool.Invoke(context.Background(), nil, "") }()
+	time.Sleep(20 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := tool.Invoke(ctx, nil, ""); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a deadline error while queued, got %v", err)
+	}
+}
+
+func TestExtractConcurrencyLimit(t *testing.T) {
+	tcs := []struct {
+		name    string
+		in      map[string]any
+		want    *tools.ConcurrencyLimit
+		wantErr bool
+	}{
+		{
+			name: "not set",
+			in:   map[string]any{"kind": "some-kind"},
+			want: nil,
+		},
+		{
+			name: "default policy",
+			in:   map[string]any{"kind": "some-kind", "maxConcurrency": uint64(1)},
+			want: &tools.ConcurrencyLimit{Max: 1, Policy: tools.ConcurrencyPolicyQueue},
+		},
+		{
+			name: "reject policy",
+			in:   map[string]any{"kind": "some-kind", "maxConcurrency": uint64(4), "concurrencyPolicy": "reject"},
+			want: &tools.ConcurrencyLimit{Max: 4, Policy: tools.ConcurrencyPolicyReject},
+		},
+		{
+			name:    "zero",
+			in:      map[string]any{"kind": "some-kind", "maxConcurrency": uint64(0)},
+			wantErr: true,
+		},
+		{
+			name:    "invalid policy",
+			in:      map[string]any{"kind": "some-kind", "maxConcurrency": uint64(1), "concurrencyPolicy": "drop"},
+			wantErr: true,
+		},
+		{
+			name:    "policy without max",
+			in:      map[string]any{"kind": "some-kind", "concurrencyPolicy": "reject"},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tools.ExtractConcurrencyLimit("my-tool", tc.in)
+			if err != nil {
+				if tc.wantErr {
+					return
+				}
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if tc.wantErr {
+				t.Fatalf("expected error but got nil")
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect concurrency limit: diff %v", diff)
+			}
+			if diff := cmp.Diff(map[string]any{"kind": "some-kind"}, tc.in); diff != "" {
+				t.Fatalf("concurrency fields not removed from config: diff %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+const (
+	// ZeroRowsMessage is returned by tools whose query returned no rows.
+	ZeroRowsMessage = "The query returned 0 rows."
+	// NoContentMessage is returned by tools whose statement, e.g. a DDL
+	// statement, doesn't produce rows.
+	NoContentMessage = "Query executed successfully and returned no content."
+
+	emptyResultMessageKey = "emptyResultMessage"
+)
+
+// EmptyResult is the configured message returned in place of a result without
+// data. It is a distinct type so that tools built on top of other tools can
+// tell it apart from other strings.
+type EmptyResult string
+
+// IsEmptyResult reports whether res holds no data: nil, an empty list or map,
+// ZeroRowsMessage or an EmptyResult, possibly wrapped in a PrettyResult.
+// NoContentMessage isn't an empty result, as the statement, e.g. a DDL
+// statement, succeeded without being meant to return rows.
+func IsEmptyResult(res any) bool {
+	switch r := res.(type) {
+	case nil, EmptyResult:
+		return true
+	case string:
+		return r == ZeroRowsMessage
+	case PrettyResult:
+		return IsEmptyResult(r.Result)
+	}
+	v := reflect.ValueOf(res)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+	return false
+}
+
+// ExtractEmptyResultMessage removes the `emptyResultMessage` field from a raw
+// tool config, so that the remaining config can be decoded strictly by the
+// tool kind. An empty string is returned if it is not set.
+func ExtractEmptyResultMessage(toolName string, v map[string]any) (string, error) {
+	raw, ok := v[emptyResultMessageKey]
+	if !ok {
+		return "", nil
+	}
+	delete(v, emptyResultMessageKey)
+	msg, ok := raw.(string)
+	if !ok || msg == "" {
+		return "", fmt.Errorf("%q must be a non-empty string for tool %q", emptyResultMessageKey, toolName)
+	}
+	return msg, nil
+}
+
+// EmptyResultConfig wraps a ToolConfig with the message returned for results
+// without data.
+type EmptyResultConfig = WrapperConfig[EmptyResultOption]
+
+// validate interface
+var _ ToolReferencingConfig = EmptyResultConfig{}
+
+// EmptyResultOption is the option of an EmptyResultConfig.
+type EmptyResultOption struct {
+	Message string
+}
+
+func (o EmptyResultOption) wrap(_ ToolConfig, t Tool) (Tool, error) {
+	return NewEmptyResultTool(t, o.Message), nil
+}
+
+// EmptyResultTool wraps a Tool so that results without data are replaced by
+// Message. Results already replaced by an inner EmptyResultTool are kept, so
+// a tool's own message takes precedence over the server default.
+type EmptyResultTool struct {
+	Tool
+	Message string
+}
+
+// validate interface
+var _ WrapperTool = EmptyResultTool{}
+
+func (t EmptyResultTool) Unwrap() Tool {
+	return t.Tool
+}
+
+// NewEmptyResultTool returns t wrapped to return msg for results without data.
+func NewEmptyResultTool(t Tool, msg string) EmptyResultTool {
+	return EmptyResultTool{Tool: t, Message: msg}
+}
+
+func (t EmptyResultTool) Invoke(ctx context.Context, params ParamValues, accessToken AccessToken) (any, error) {
+	res, err := t.Tool.Invoke(ctx, params, accessToken)
+	if err != nil {
+		return nil, err
+	}
+	return t.replaceEmpty(res), nil
+}
+
+// replaceEmpty returns Message in place of res if it holds no data, keeping the
+// statement of a ResolvedStatementResult.
+func (t EmptyResultTool) replaceEmpty(res any) any {
+	switch r := res.(type) {
+	case EmptyResult:
+		return r
+	case ResolvedStatementResult:
+		r.Result = t.replaceEmpty(r.Result)
+		return r
+	}
+	if IsEmptyResult(res) {
+		return EmptyResult(t.Message)
+	}
+	return res
+}
@@ -0,0 +1,173 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// resultTool returns a fixed result.
+type resultTool struct {
+	res any
+}
+
+func (t resultTool) Invoke(context.Context, tools.ParamValues, tools.AccessToken) (any, error) {
+	return t.res, nil
+}
+
+func (t resultTool) ParseParams(map[string]any, map[string]map[string]any) (tools.ParamValues, error) {
+	return nil, nil
+}
+
+func (t resultTool) Manifest() tools.Manifest {
+	return tools.Manifest{}
+}
+
+func (t resultTool) McpManifest() tools.McpManifest {
+	return tools.McpManifest{}
+}
+
+func (t resultTool) Authorized([]string) bool {
+	return true
+}
+
+func (t resultTool) RequiresClientAuthorization() bool {
+	return false
+}
+
+func TestEmptyResultTool(t *testing.T) {
+	const msg = "No data found."
+	rows := []any{map[string]any{"id": 1}}
+
+	tcs := []struct {
+		desc string
+		res  any
+		want any
+	}{
+		{
+			desc: "bigquery query without rows",
+			res:  tools.ZeroRowsMessage,
+			want: tools.EmptyResult(msg),
+		},
+		{
+			desc: "bigquery ddl statement",
+			res:  tools.NoContentMessage,
+			want: tools.NoContentMessage,
+		},
+		{
+			desc: "null result",
+			res:  nil,
+			want: tools.EmptyResult(msg),
+		},
+		{
+			desc: "empty list of rows",
+			res:  []any{},
+			want: tools.EmptyResult(msg),
+		},
+		{
+			desc: "empty map",
+			res:  map[string]any{},
+			want: tools.EmptyResult(msg),
+		},
+		{
+			desc: "rows",
+			res:  rows,
+			want: rows,
+		},
+		{
+			desc: "other message",
+			res:  "Inserted 1 row.",
+			want: "Inserted 1 row.",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			tool := tools.NewEmptyResultTool(resultTool{res: tc.res}, msg)
+			got, err := tool.Invoke(context.Background(), nil, "")
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect result: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestEmptyResultToolOverride(t *testing.T) {
+	// the server default wraps the tool's own message
+	tool := tools.NewEmptyResultTool(tools.NewEmptyResultTool(resultTool{res: nil}, "Nothing here."), "No data found.")
+	got, err := tool.Invoke(context.Background(), nil, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got != tools.EmptyResult("Nothing here.") {
+		t.Fatalf("the tool's own message should take precedence: got %v", got)
+	}
+}
+
+func TestExtractEmptyResultMessage(t *testing.T) {
+	tcs := []struct {
+		name    string
+		in      map[string]any
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "not set",
+			in:   map[string]any{"kind": "some-kind"},
+			want: "",
+		},
+		{
+			name: "message",
+			in:   map[string]any{"kind": "some-kind", "emptyResultMessage": "No data found."},
+			want: "No data found.",
+		},
+		{
+			name:    "empty message",
+			in:      map[string]any{"kind": "some-kind", "emptyResultMessage": ""},
+			wantErr: true,
+		},
+		{
+			name:    "not a string",
+			in:      map[string]any{"kind": "some-kind", "emptyResultMessage": true},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tools.ExtractEmptyResultMessage("my-tool", tc.in)
+			if err != nil {
+				if tc.wantErr {
+					return
+				}
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if tc.wantErr {
+				t.Fatalf("expected error but got nil")
+			}
+			if got != tc.want {
+				t.Fatalf("incorrect message: got %q, want %q", got, tc.want)
+			}
+			if diff := cmp.Diff(map[string]any{"kind": "some-kind"}, tc.in); diff != "" {
+				t.Fatalf("emptyResultMessage not removed from config: diff %v", diff)
+			}
+		})
+	}
+}
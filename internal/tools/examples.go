@@ -0,0 +1,178 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+const examplesKey = "examples"
+
+// Example is a sample invocation of a tool, surfaced to MCP clients as a
+// few-shot hint.
+type Example struct {
+	Description string         `yaml:"description" json:"description,omitempty"`
+	Args        map[string]any `yaml:"args" json:"args"`
+}
+
+// ExtractExamples removes the `examples` field from a raw tool config, so that
+// the remaining config can be decoded strictly by the tool kind. If the config
+// declares `parameters`, the examples are validated against them.
+func ExtractExamples(ctx context.Context, toolName string, v map[string]any) ([]Example, error) {
+	raw, ok := v[examplesKey]
+	if !ok {
+		return nil, nil
+	}
+	delete(v, examplesKey)
+
+	dec, err := util.NewStrictDecoder(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %q field for tool %q: %w", examplesKey, toolName, err)
+	}
+	var examples []Example
+	if err := dec.DecodeContext(ctx, &examples); err != nil {
+		return nil, fmt.Errorf("invalid %q field for tool %q: %w", examplesKey, toolName, err)
+	}
+
+	if rawParams, ok := v["parameters"]; ok {
+		dec, err := util.NewStrictDecoder(rawParams)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read parameters of tool %q: %w", toolName, err)
+		}
+		var params Parameters
+		if err := dec.DecodeContext(ctx, &params); err != nil {
+			return nil, fmt.Errorf("unable to read parameters of tool %q: %w", toolName, err)
+		}
+		if err := ValidateExamples(examples, params.Manifest()); err != nil {
+			return nil, fmt.Errorf("invalid %q field for tool %q: %w", examplesKey, toolName, err)
+		}
+	}
+	return examples, nil
+}
+
+// ValidateExamples checks that each example's args match the parameters: every
+// arg is a known parameter of the right type, and every required parameter
+// that isn't filled from an auth service is present.
+func ValidateExamples(examples []Example, params []ParameterManifest) error {
+	byName := make(map[string]ParameterManifest, len(params))
+	for _, p := range params {
+		byName[p.Name] = p
+	}
+	for i, ex := range examples {
+		for name, value := range ex.Args {
+			p, ok := byName[name]
+			if !ok {
+				return fmt.Errorf("example %d: unknown parameter %q", i, name)
+			}
+			if err := checkExampleValue(p, value); err != nil {
+				return fmt.Errorf("example %d: parameter %q: %w", i, name, err)
+			}
+		}
+		for _, p := range params {
+			if _, ok := ex.Args[p.Name]; !ok && p.Required && len(p.AuthServices) == 0 {
+				return fmt.Errorf("example %d: missing required parameter %q", i, p.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// checkExampleValue checks that value has the type described by p.
+func checkExampleValue(p ParameterManifest, value any) error {
+	ok := true
+	switch p.Type {
+	case typeString:
+		_, ok = value.(string)
+	case typeInt:
+		switch v := value.(type) {
+		case int, int64, uint64:
+		case float64:
+			ok = v == math.Trunc(v)
+		default:
+			ok = false
+		}
+	case typeFloat:
+		switch value.(type) {
+		case int, int64, uint64, float64:
+		default:
+			ok = false
+		}
+	case typeBool:
+		_, ok = value.(bool)
+	case typeArray:
+		items, isSlice := value.([]any)
+		if !isSlice {
+			ok = false
+			break
+		}
+		if p.Items != nil {
+			for _, item := range items {
+				if err := checkExampleValue(*p.Items, item); err != nil {
+					return fmt.Errorf("array item: %w", err)
+				}
+			}
+		}
+	case "object":
+		_, ok = value.(map[string]any)
+	}
+	if !ok {
+		return fmt.Errorf("expected a value of type %q, got %T", p.Type, value)
+	}
+	return nil
+}
+
+// ExampleConfig wraps a ToolConfig with example invocations.
+type ExampleConfig = WrapperConfig[ExampleOption]
+
+// validate interface
+var _ ToolReferencingConfig = ExampleConfig{}
+
+// ExampleOption is the option of an ExampleConfig.
+type ExampleOption struct {
+	Examples []Example
+}
+
+func (o ExampleOption) wrap(_ ToolConfig, t Tool) (Tool, error) {
+	// Tools without declared parameters can only be checked once their
+	// parameters are known.
+	if err := ValidateExamples(o.Examples, t.Manifest().Parameters); err != nil {
+		return nil, fmt.Errorf("invalid %q field: %w", examplesKey, err)
+	}
+	return ExampleTool{Tool: t, Examples: o.Examples}, nil
+}
+
+// ExampleTool wraps a Tool so that its MCP manifest includes example
+// invocations.
+type ExampleTool struct {
+	Tool
+	Examples []Example
+}
+
+// validate interface
+var _ WrapperTool = ExampleTool{}
+
+func (t ExampleTool) Unwrap() Tool {
+	return t.Tool
+}
+
+func (t ExampleTool) McpManifest() McpManifest {
+	m := t.Tool.McpManifest()
+	m.Examples = t.Examples
+	return m
+}
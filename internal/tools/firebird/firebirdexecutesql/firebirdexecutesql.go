@@ -58,12 +58,16 @@ type Config struct {
 	AuthRequired []string `yaml:"authRequired"`
 }
 
-var _ tools.ToolConfig = Config{}
+var _ tools.StatementConfig = Config{}
 
 func (cfg Config) ToolConfigKind() string {
 	return kind
 }
 
+// TakesStatement marks the tool as one that runs the statement given in its
+// "sql" parameter.
+func (cfg Config) TakesStatement() {}
+
 func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
 	rawS, ok := srcs[cfg.Source]
 	if !ok {
@@ -120,12 +124,13 @@ func (t *Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		return nil, fmt.Errorf("unable to get cast %s", paramsMap["sql"])
 	}
 
-	// Log the query executed for debugging.
+	// Log the query executed for debugging. The statement may hold sensitive
+	// values, so only its type and length are logged.
 	logger, err := util.LoggerFromContext(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("error getting logger: %s", err)
 	}
-	logger.DebugContext(ctx, "executing `%s` tool query: %s", kind, sql)
+	logger.DebugContext(ctx, "executing `%s` tool query: %s", kind, tools.DescribeStatement(sql))
 
 	rows, err := t.Db.QueryContext(ctx, sql)
 	if err != nil {
@@ -81,13 +81,13 @@ func JSONToFirestoreValue(value interface{}, client *firestore.Client) (interfac
 					}
 					return nil, fmt.Errorf("bytes value must be a base64 encoded string")
 				case "timestampValue":
-					// Parse timestamp
+					// Parse timestamp, keeping sub-second precision and normalizing offsets to UTC
 					if str, ok := val.(string); ok {
 						t, err := time.Parse(time.RFC3339Nano, str)
 						if err != nil {
 							return nil, fmt.Errorf("invalid timestamp format: %w", err)
 						}
-						return t, nil
+						return t.UTC(), nil
 					}
 					return nil, fmt.Errorf("timestamp value must be a string")
 				case "geoPointValue":
@@ -145,6 +145,45 @@ func JSONToFirestoreValue(value interface{}, client *firestore.Client) (interfac
 						return strVal, nil
 					}
 					return nil, fmt.Errorf("reference value must be a string")
+				case "serverTimestampValue":
+					// Sentinel for the server's commit time
+					if b, ok := val.(bool); ok && b {
+						return firestore.ServerTimestamp, nil
+					}
+					return nil, fmt.Errorf("server timestamp value must be true")
+				case "incrementValue":
+					// Sentinel for a numeric increment of the existing field value
+					switch num := val.(type) {
+					case float64:
+						if num == float64(int64(num)) {
+							return firestore.Increment(int64(num)), nil
+						}
+						return firestore.Increment(num), nil
+					case int:
+						return firestore.Increment(int64(num)), nil
+					case int64:
+						return firestore.Increment(num), nil
+					}
+					return nil, fmt.Errorf("invalid increment value: %v", val)
+				case "arrayUnionValue", "arrayRemoveValue":
+					// Sentinels for adding or removing array elements
+					if arrayMap, ok := val.(map[string]interface{}); ok {
+						if values, ok := arrayMap["values"].([]interface{}); ok {
+							elems := make([]interface{}, len(values))
+							for i, item := range values {
+								converted, err := JSONToFirestoreValue(item, client)
+								if err != nil {
+									return nil, fmt.Errorf("%s item %d: %w", key, i, err)
+								}
+								elems[i] = converted
+							}
+							if key == "arrayUnionValue" {
+								return firestore.ArrayUnion(elems...), nil
+							}
+							return firestore.ArrayRemove(elems...), nil
+						}
+					}
+					return nil, fmt.Errorf("invalid %s format", key)
 				default:
 					// If not a typed value, treat as regular map
 					return convertPlainMap(v, client)
@@ -172,9 +211,36 @@ func convertPlainMap(m map[string]interface{}, client *firestore.Client) (map[st
 	return result, nil
 }
 
+// ConversionMode selects how FirestoreValueToJSONWithOptions represents values
+type ConversionMode int
+
+const (
+	// PlainMode returns plain values, which are easier to read. References and
+	// geopoints are still wrapped so that they aren't mistaken for strings or maps.
+	PlainMode ConversionMode = iota
+	// TypedMode wraps every value in its typed format (e.g. {"stringValue": "a"}),
+	// so that the output can be passed back to JSONToFirestoreValue
+	TypedMode
+)
+
+// ConverterOptions configures FirestoreValueToJSONWithOptions
+type ConverterOptions struct {
+	Mode ConversionMode
+}
+
 // FirestoreValueToJSON converts a Firestore value to a simplified JSON representation
-// This removes type information and returns plain values
+// This removes type information and returns plain values, except for references and
+// geopoints, which are wrapped in the same typed format accepted by JSONToFirestoreValue
 func FirestoreValueToJSON(value interface{}) interface{} {
+	return FirestoreValueToJSONWithOptions(value, ConverterOptions{Mode: PlainMode})
+}
+
+// FirestoreValueToJSONWithOptions converts a Firestore value to JSON using the given options
+func FirestoreValueToJSONWithOptions(value interface{}, opts ConverterOptions) interface{} {
+	if opts.Mode == TypedMode {
+		return firestoreValueToTypedJSON(value)
+	}
+
 	if value == nil {
 		return nil
 	}
@@ -183,31 +249,80 @@ func FirestoreValueToJSON(value interface{}) interface{} {
 	case time.Time:
 		return v.Format(time.RFC3339Nano)
 	case *latlng.LatLng:
-		return map[string]interface{}{
-			"latitude":  v.Latitude,
-			"longitude": v.Longitude,
-		}
+		return geoPointToJSON(v)
 	case []byte:
 		return base64.StdEncoding.EncodeToString(v)
 	case []interface{}:
 		result := make([]interface{}, len(v))
 		for i, item := range v {
-			result[i] = FirestoreValueToJSON(item)
+			result[i] = FirestoreValueToJSONWithOptions(item, opts)
 		}
 		return result
 	case map[string]interface{}:
 		result := make(map[string]interface{})
 		for k, val := range v {
-			result[k] = FirestoreValueToJSON(val)
+			result[k] = FirestoreValueToJSONWithOptions(val, opts)
 		}
 		return result
 	case *firestore.DocumentRef:
-		return v.Path
+		return map[string]interface{}{
+			"referenceValue": v.Path,
+		}
+	default:
+		return value
+	}
+}
+
+// firestoreValueToTypedJSON wraps a Firestore value and any nested values in their typed format
+func firestoreValueToTypedJSON(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil:
+		return map[string]interface{}{"nullValue": nil}
+	case bool:
+		return map[string]interface{}{"booleanValue": v}
+	case string:
+		return map[string]interface{}{"stringValue": v}
+	case int64:
+		return map[string]interface{}{"integerValue": strconv.FormatInt(v, 10)}
+	case int:
+		return map[string]interface{}{"integerValue": strconv.Itoa(v)}
+	case float64:
+		return map[string]interface{}{"doubleValue": v}
+	case []byte:
+		return map[string]interface{}{"bytesValue": base64.StdEncoding.EncodeToString(v)}
+	case time.Time:
+		return map[string]interface{}{"timestampValue": v.Format(time.RFC3339Nano)}
+	case *latlng.LatLng:
+		return geoPointToJSON(v)
+	case *firestore.DocumentRef:
+		return map[string]interface{}{"referenceValue": v.Path}
+	case []interface{}:
+		values := make([]interface{}, len(v))
+		for i, item := range v {
+			values[i] = firestoreValueToTypedJSON(item)
+		}
+		return map[string]interface{}{"arrayValue": map[string]interface{}{"values": values}}
+	case map[string]interface{}:
+		fields := make(map[string]interface{})
+		for k, val := range v {
+			fields[k] = firestoreValueToTypedJSON(val)
+		}
+		return map[string]interface{}{"mapValue": map[string]interface{}{"fields": fields}}
 	default:
 		return value
 	}
 }
 
+// geoPointToJSON wraps a geopoint in its typed format
+func geoPointToJSON(v *latlng.LatLng) map[string]interface{} {
+	return map[string]interface{}{
+		"geoPointValue": map[string]interface{}{
+			"latitude":  v.Latitude,
+			"longitude": v.Longitude,
+		},
+	}
+}
+
 // isValidDocumentPath checks if a string is a valid Firestore document path
 // Valid paths have an even number of segments (collection/doc/collection/doc...)
 func isValidDocumentPath(path string) bool {
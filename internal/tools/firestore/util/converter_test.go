@@ -18,10 +18,12 @@ import (
 	"bytes"
 	"encoding/base64"
 	"encoding/json"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
 
+	"cloud.google.com/go/firestore"
 	"google.golang.org/genproto/googleapis/type/latlng"
 )
 
@@ -312,6 +314,148 @@ func TestJSONToFirestoreValue_IntegerFromString(t *testing.T) {
 	}
 }
 
+func TestJSONToFirestoreValue_TimestampPrecision(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  time.Time
+	}{
+		{
+			name:  "milliseconds in UTC",
+			input: "2025-01-07T10:00:00.000Z",
+			want:  time.Date(2025, 1, 7, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "nanoseconds in UTC",
+			input: "2025-01-07T10:00:00.123456789Z",
+			want:  time.Date(2025, 1, 7, 10, 0, 0, 123456789, time.UTC),
+		},
+		{
+			name:  "nanoseconds with an offset",
+			input: "2025-01-07T12:30:15.000000001+02:30",
+			want:  time.Date(2025, 1, 7, 10, 0, 15, 1, time.UTC),
+		},
+		{
+			name:  "negative offset crossing midnight",
+			input: "2025-01-06T20:00:00.5-05:00",
+			want:  time.Date(2025, 1, 7, 1, 0, 0, 500000000, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := JSONToFirestoreValue(map[string]interface{}{"timestampValue": tt.input}, nil)
+			if err != nil {
+				t.Fatalf("Failed to convert: %v", err)
+			}
+			got, ok := result.(time.Time)
+			if !ok {
+				t.Fatalf("Result should be time.Time, got %T", result)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestJSONToFirestoreValue_Sentinels(t *testing.T) {
+	tests := []struct {
+		name  string
+		input interface{}
+		want  interface{}
+	}{
+		{
+			name:  "server timestamp",
+			input: map[string]interface{}{"serverTimestampValue": true},
+			want:  firestore.ServerTimestamp,
+		},
+		{
+			name:  "integer increment",
+			input: map[string]interface{}{"incrementValue": float64(5)},
+			want:  firestore.Increment(int64(5)),
+		},
+		{
+			name:  "negative integer increment",
+			input: map[string]interface{}{"incrementValue": -2},
+			want:  firestore.Increment(int64(-2)),
+		},
+		{
+			name:  "float increment",
+			input: map[string]interface{}{"incrementValue": 1.5},
+			want:  firestore.Increment(1.5),
+		},
+		{
+			name: "array union with nested values",
+			input: map[string]interface{}{
+				"arrayUnionValue": map[string]interface{}{
+					"values": []interface{}{
+						map[string]interface{}{"stringValue": "tag"},
+						map[string]interface{}{"integerValue": "7"},
+						map[string]interface{}{"mapValue": map[string]interface{}{
+							"fields": map[string]interface{}{
+								"when": map[string]interface{}{"timestampValue": "2025-01-07T10:00:00Z"},
+							},
+						}},
+					},
+				},
+			},
+			want: firestore.ArrayUnion(
+				"tag",
+				int64(7),
+				map[string]interface{}{"when": time.Date(2025, 1, 7, 10, 0, 0, 0, time.UTC)},
+			),
+		},
+		{
+			name: "array remove with nested values",
+			input: map[string]interface{}{
+				"arrayRemoveValue": map[string]interface{}{
+					"values": []interface{}{
+						map[string]interface{}{"doubleValue": 2.5},
+						map[string]interface{}{"arrayValue": map[string]interface{}{
+							"values": []interface{}{
+								map[string]interface{}{"booleanValue": true},
+							},
+						}},
+						map[string]interface{}{"geoPointValue": map[string]interface{}{
+							"latitude":  34.052235,
+							"longitude": -118.243683,
+						}},
+					},
+				},
+			},
+			want: firestore.ArrayRemove(
+				2.5,
+				[]interface{}{true},
+				&latlng.LatLng{Latitude: 34.052235, Longitude: -118.243683},
+			),
+		},
+		{
+			name: "sentinels inside a plain map",
+			input: map[string]interface{}{
+				"updatedAt": map[string]interface{}{"serverTimestampValue": true},
+				"visits":    map[string]interface{}{"incrementValue": float64(1)},
+			},
+			want: map[string]interface{}{
+				"updatedAt": firestore.ServerTimestamp,
+				"visits":    firestore.Increment(int64(1)),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := JSONToFirestoreValue(tt.input, nil)
+			if err != nil {
+				t.Fatalf("Failed to convert: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %#v, got %#v", tt.want, got)
+			}
+		})
+	}
+}
+
 func TestFirestoreValueToJSON_RoundTrip(t *testing.T) {
 	// Test round-trip conversion
 	original := map[string]interface{}{
@@ -346,6 +490,153 @@ func TestFirestoreValueToJSON_RoundTrip(t *testing.T) {
 	}
 }
 
+func TestFirestoreValueToJSON_ReferenceAndGeoPoint(t *testing.T) {
+	refPath := "projects/my-project/databases/(default)/documents/users/alice"
+	doc := map[string]interface{}{
+		"name":  "Store",
+		"owner": &firestore.DocumentRef{ID: "alice", Path: refPath},
+		"location": &latlng.LatLng{
+			Latitude:  34.052235,
+			Longitude: -118.243683,
+		},
+		"branches": []interface{}{
+			&latlng.LatLng{Latitude: 37.7749, Longitude: -122.4194},
+		},
+	}
+
+	want := map[string]interface{}{
+		"name": "Store",
+		"owner": map[string]interface{}{
+			"referenceValue": refPath,
+		},
+		"location": map[string]interface{}{
+			"geoPointValue": map[string]interface{}{
+				"latitude":  34.052235,
+				"longitude": -118.243683,
+			},
+		},
+		"branches": []interface{}{
+			map[string]interface{}{
+				"geoPointValue": map[string]interface{}{
+					"latitude":  37.7749,
+					"longitude": -122.4194,
+				},
+			},
+		},
+	}
+
+	got := FirestoreValueToJSON(doc)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	// The geopoint output can be converted back into the original value
+	location := got.(map[string]interface{})["location"]
+	roundTrip, err := JSONToFirestoreValue(location, nil)
+	if err != nil {
+		t.Fatalf("Failed to convert geopoint back: %v", err)
+	}
+	if !reflect.DeepEqual(roundTrip, doc["location"]) {
+		t.Errorf("Expected %v, got %v", doc["location"], roundTrip)
+	}
+}
+
+func TestFirestoreValueToJSONWithOptions_Modes(t *testing.T) {
+	created := time.Date(2025, 1, 7, 10, 0, 0, 0, time.UTC)
+	doc := map[string]interface{}{
+		"name":     "Test",
+		"count":    int64(42),
+		"price":    19.99,
+		"active":   true,
+		"notes":    nil,
+		"data":     []byte("hello"),
+		"tags":     []interface{}{"tag1"},
+		"metadata": map[string]interface{}{"created": created},
+		"location": &latlng.LatLng{Latitude: 1.5, Longitude: -2.5},
+	}
+
+	tests := []struct {
+		name string
+		opts ConverterOptions
+		want interface{}
+	}{
+		{
+			name: "plain mode",
+			opts: ConverterOptions{Mode: PlainMode},
+			want: map[string]interface{}{
+				"name":     "Test",
+				"count":    int64(42),
+				"price":    19.99,
+				"active":   true,
+				"notes":    nil,
+				"data":     "aGVsbG8=",
+				"tags":     []interface{}{"tag1"},
+				"metadata": map[string]interface{}{"created": "2025-01-07T10:00:00Z"},
+				"location": map[string]interface{}{
+					"geoPointValue": map[string]interface{}{"latitude": 1.5, "longitude": -2.5},
+				},
+			},
+		},
+		{
+			name: "typed mode",
+			opts: ConverterOptions{Mode: TypedMode},
+			want: map[string]interface{}{
+				"mapValue": map[string]interface{}{
+					"fields": map[string]interface{}{
+						"name":   map[string]interface{}{"stringValue": "Test"},
+						"count":  map[string]interface{}{"integerValue": "42"},
+						"price":  map[string]interface{}{"doubleValue": 19.99},
+						"active": map[string]interface{}{"booleanValue": true},
+						"notes":  map[string]interface{}{"nullValue": nil},
+						"data":   map[string]interface{}{"bytesValue": "aGVsbG8="},
+						"tags": map[string]interface{}{
+							"arrayValue": map[string]interface{}{
+								"values": []interface{}{
+									map[string]interface{}{"stringValue": "tag1"},
+								},
+							},
+						},
+						"metadata": map[string]interface{}{
+							"mapValue": map[string]interface{}{
+								"fields": map[string]interface{}{
+									"created": map[string]interface{}{"timestampValue": "2025-01-07T10:00:00Z"},
+								},
+							},
+						},
+						"location": map[string]interface{}{
+							"geoPointValue": map[string]interface{}{"latitude": 1.5, "longitude": -2.5},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FirestoreValueToJSONWithOptions(doc, tt.opts)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	// The default conversion is plain mode
+	if got := FirestoreValueToJSON(doc); !reflect.DeepEqual(got, tests[0].want) {
+		t.Errorf("Expected FirestoreValueToJSON to use plain mode, got %v", got)
+	}
+
+	// Typed output can be converted back into the original document
+	typed := FirestoreValueToJSONWithOptions(doc, ConverterOptions{Mode: TypedMode})
+	roundTrip, err := JSONToFirestoreValue(typed, nil)
+	if err != nil {
+		t.Fatalf("Failed to convert typed output back: %v", err)
+	}
+	if !reflect.DeepEqual(roundTrip, doc) {
+		t.Errorf("Expected round trip to return %v, got %v", doc, roundTrip)
+	}
+}
+
 func TestJSONToFirestoreValue_InvalidFormats(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -395,6 +686,42 @@ func TestJSONToFirestoreValue_InvalidFormats(t *testing.T) {
 			wantErr: true,
 			errMsg:  "invalid map value format",
 		},
+		{
+			name: "invalid array union format",
+			input: map[string]interface{}{
+				"arrayUnionValue": []interface{}{"not-wrapped"},
+			},
+			wantErr: true,
+			errMsg:  "invalid arrayUnionValue format",
+		},
+		{
+			name: "invalid array remove item",
+			input: map[string]interface{}{
+				"arrayRemoveValue": map[string]interface{}{
+					"values": []interface{}{
+						map[string]interface{}{"integerValue": "not-a-number"},
+					},
+				},
+			},
+			wantErr: true,
+			errMsg:  "arrayRemoveValue item 0: invalid integer value",
+		},
+		{
+			name: "invalid increment value",
+			input: map[string]interface{}{
+				"incrementValue": "one",
+			},
+			wantErr: true,
+			errMsg:  "invalid increment value",
+		},
+		{
+			name: "invalid server timestamp value",
+			input: map[string]interface{}{
+				"serverTimestampValue": false,
+			},
+			wantErr: true,
+			errMsg:  "server timestamp value must be true",
+		},
 		{
 			name: "invalid bytes - not base64",
 			input: map[string]interface{}{
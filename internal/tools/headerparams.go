@@ -0,0 +1,62 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+// ApplyHeaderParams sets the arguments of parameters configured with
+// `fromHeader` to the value of their header, so that a gateway can provide
+// them instead of the client. Arguments supplied in data take precedence, and
+// parameters whose header is absent are left to their default. The resulting
+// arguments are returned, since data may be nil.
+func ApplyHeaderParams(m Manifest, data map[string]any, header http.Header) map[string]any {
+	for _, p := range m.Parameters {
+		if p.FromHeader == "" {
+			continue
+		}
+		if _, ok := data[p.Name]; ok {
+			continue
+		}
+		v := header.Get(p.FromHeader)
+		if v == "" {
+			continue
+		}
+		if data == nil {
+			data = make(map[string]any)
+		}
+		data[p.Name] = headerValue(p.Type, v)
+	}
+	return data
+}
+
+// headerValue converts the value of a header to an argument of the given
+// parameter type. Values of non-string parameters are decoded as JSON, e.g.
+// `42` or `["a", "b"]`; values that aren't valid JSON are kept as strings so
+// that parsing the parameter reports the type error.
+func headerValue(typ string, v string) any {
+	if typ == typeString {
+		return v
+	}
+	var out any
+	if err := util.DecodeJSON(strings.NewReader(v), &out); err != nil {
+		return v
+	}
+	return out
+}
@@ -0,0 +1,80 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestApplyHeaderParams(t *testing.T) {
+	tenant := tools.NewStringParameterWithDefault("tenant_id", "public", "the caller's tenant")
+	tenant.FromHeader = "X-Tenant-ID"
+	limit := tools.NewIntParameterWithDefault("limit", 10, "the maximum number of rows")
+	limit.FromHeader = "X-Row-Limit"
+	ps := tools.Parameters{tenant, limit}
+	m := tools.Manifest{Parameters: ps.Manifest()}
+
+	tcs := []struct {
+		name   string
+		data   map[string]any
+		header http.Header
+		want   tools.ParamValues
+	}{
+		{
+			name:   "from header",
+			data:   map[string]any{},
+			header: http.Header{"X-Tenant-Id": []string{"acme"}, "X-Row-Limit": []string{"5"}},
+			want:   tools.ParamValues{{Name: "tenant_id", Value: "acme"}, {Name: "limit", Value: 5}},
+		},
+		{
+			name:   "header absent",
+			data:   nil,
+			header: http.Header{},
+			want:   tools.ParamValues{{Name: "tenant_id", Value: "public"}, {Name: "limit", Value: 10}},
+		},
+		{
+			name:   "explicit argument",
+			data:   map[string]any{"tenant_id": "initech"},
+			header: http.Header{"X-Tenant-Id": []string{"acme"}},
+			want:   tools.ParamValues{{Name: "tenant_id", Value: "initech"}, {Name: "limit", Value: 10}},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			data := tools.ApplyHeaderParams(m, tc.data, tc.header)
+			got, err := tools.ParseParams(ps, data, nil)
+			if err != nil {
+				t.Fatalf("unexpected error from ParseParams: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("ParseParams() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestApplyHeaderParamsInvalidValue(t *testing.T) {
+	limit := tools.NewIntParameter("limit", "the maximum number of rows")
+	limit.FromHeader = "X-Row-Limit"
+	ps := tools.Parameters{limit}
+	data := tools.ApplyHeaderParams(tools.Manifest{Parameters: ps.Manifest()}, nil, http.Header{"X-Row-Limit": []string{"many"}})
+	if _, err := tools.ParseParams(ps, data, nil); err == nil {
+		t.Fatalf("expected an error for a header value of the wrong type")
+	}
+}
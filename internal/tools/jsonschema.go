@@ -0,0 +1,90 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+// JSONSchemaDraft is the JSON Schema dialect used by exported schemas.
+const JSONSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema is a JSON Schema document describing a tool's parameters. Unlike
+// the MCP input schema, it keeps the full structure of nested parameters so that
+// clients can generate typed bindings from it.
+type JSONSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Type                 string                 `json:"type"`
+	Description          string                 `json:"description,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+	AdditionalProperties any                    `json:"additionalProperties,omitempty"`
+	Enum                 []string               `json:"enum,omitempty"`
+}
+
+// JSONSchema returns the JSON Schema document for the parameters.
+func (ps Parameters) JSONSchema() JSONSchema {
+	return NewJSONSchema(ps.Manifest())
+}
+
+// NewJSONSchema returns the JSON Schema document for an object whose properties
+// are the given parameters.
+func NewJSONSchema(params []ParameterManifest) JSONSchema {
+	properties := make(map[string]*JSONSchema, len(params))
+	required := make([]string, 0)
+	for _, p := range params {
+		properties[p.Name] = parameterJSONSchema(p)
+		if p.Required {
+			required = append(required, p.Name)
+		}
+	}
+	return JSONSchema{
+		Schema:               JSONSchemaDraft,
+		Type:                 "object",
+		Properties:           properties,
+		Required:             required,
+		AdditionalProperties: false,
+	}
+}
+
+// parameterJSONSchema returns the JSON Schema for a single parameter.
+func parameterJSONSchema(p ParameterManifest) *JSONSchema {
+	s := &JSONSchema{
+		Type:                 jsonSchemaType(p.Type),
+		Description:          p.Description,
+		AdditionalProperties: p.AdditionalProperties,
+		Enum:                 p.Enum,
+	}
+	if p.Items != nil {
+		s.Items = parameterJSONSchema(*p.Items)
+	}
+	// map parameters describe their values as {"type": <parameter type>}
+	if valueSchema, ok := p.AdditionalProperties.(map[string]any); ok {
+		if t, ok := valueSchema["type"].(string); ok {
+			s.AdditionalProperties = &JSONSchema{Type: jsonSchemaType(t)}
+		}
+	}
+	return s
+}
+
+// jsonSchemaType maps a parameter type to its JSON Schema type.
+func jsonSchemaType(paramType string) string {
+	switch paramType {
+	case typeFloat:
+		return "number"
+	case typeMap:
+		return "object"
+	default:
+		return paramType
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestParametersJSONSchema(t *testing.T) {
+	tcs := []struct {
+		name   string
+		params tools.Parameters
+		want   string
+	}{
+		{
+			name: "array of structs",
+			params: tools.Parameters{
+				tools.NewArrayParameter("rows", "rows to insert", tools.NewMapParameter("row", "a single row", "string")),
+				tools.NewIntParameterWithDefault("limit", 10, "max rows"),
+			},
+			want: `{
+				"$schema": "https://json-schema.org/draft/2020-12/schema",
+				"type": "object",
+				"properties": {
+					"rows": {
+						"type": "array",
+						"description": "rows to insert",
+						"items": {
+							"type": "object",
+							"description": "a single row",
+							"additionalProperties": {"type": "string"}
+						}
+					},
+					"limit": {
+						"type": "integer",
+						"description": "max rows"
+					}
+				},
+				"required": ["rows"],
+				"additionalProperties": false
+			}`,
+		},
+		{
+			name: "nested arrays and generic maps",
+			params: tools.Parameters{
+				tools.NewArrayParameter("matrix", "a matrix", tools.NewArrayParameter("row", "a row", tools.NewFloatParameter("cell", "a cell"))),
+				tools.NewMapParameter("labels", "free-form labels", ""),
+			},
+			want: `{
+				"$schema": "https://json-schema.org/draft/2020-12/schema",
+				"type": "object",
+				"properties": {
+					"matrix": {
+						"type": "array",
+						"description": "a matrix",
+						"items": {
+							"type": "array",
+							"description": "a row",
+							"items": {"type": "number", "description": "a cell"}
+						}
+					},
+					"labels": {
+						"type": "object",
+						"description": "free-form labels",
+						"additionalProperties": true
+					}
+				},
+				"required": ["matrix", "labels"],
+				"additionalProperties": false
+			}`,
+		},
+		{
+			name: "allowed values",
+			params: tools.Parameters{
+				tools.NewStringParameterWithAllowedValues("table", "the table", []string{"hotels", "flights"}),
+				tools.NewArrayParameter("tables", "the tables", tools.NewStringParameterWithAllowedValues("table", "a table", []string{"hotels", "flights"})),
+			},
+			want: `{
+				"$schema": "https://json-schema.org/draft/2020-12/schema",
+				"type": "object",
+				"properties": {
+					"table": {
+						"type": "string",
+						"description": "the table",
+						"enum": ["hotels", "flights"]
+					},
+					"tables": {
+						"type": "array",
+						"description": "the tables",
+						"items": {
+							"type": "string",
+							"description": "a table",
+							"enum": ["hotels", "flights"]
+						}
+					}
+				},
+				"required": ["table", "tables"],
+				"additionalProperties": false
+			}`,
+		},
+		{
+			name:   "no parameters",
+			params: tools.Parameters{},
+			want: `{
+				"$schema": "https://json-schema.org/draft/2020-12/schema",
+				"type": "object",
+				"additionalProperties": false
+			}`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := json.Marshal(tc.params.JSONSchema())
+			if err != nil {
+				t.Fatalf("unable to marshal schema: %s", err)
+			}
+			var got, want any
+			if err := json.Unmarshal(b, &got); err != nil {
+				t.Fatalf("unable to unmarshal schema: %s", err)
+			}
+			if err := json.Unmarshal([]byte(tc.want), &want); err != nil {
+				t.Fatalf("unable to unmarshal wanted schema: %s", err)
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Fatalf("incorrect schema: diff %v", diff)
+			}
+		})
+	}
+}
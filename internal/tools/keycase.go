@@ -0,0 +1,247 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+const keyCaseKey = "keyCase"
+
+// The casings that the keys of result rows can be converted to.
+const (
+	KeyCaseNone  = "none"
+	KeyCaseCamel = "camel"
+	KeyCaseSnake = "snake"
+)
+
+var keyCases = []string{KeyCaseNone, KeyCaseCamel, KeyCaseSnake}
+
+// RowsResult is implemented by results that hold rows along with other
+// fields, so that tools wrapping other tools can transform the rows.
+type RowsResult interface {
+	// ResultRows returns the rows of the result.
+	ResultRows() []any
+	// WithRows returns a copy of the result holding rows instead.
+	WithRows(rows []any) any
+}
+
+// ExtractKeyCase removes the `keyCase` field from a raw tool config, so that
+// the remaining config can be decoded strictly by the tool kind. An empty
+// string is returned if it is not set.
+func ExtractKeyCase(toolName string, v map[string]any) (string, error) {
+	raw, ok := v[keyCaseKey]
+	if !ok {
+		return "", nil
+	}
+	delete(v, keyCaseKey)
+	keyCase, ok := raw.(string)
+	if !ok || !validKeyCase(keyCase) {
+		return "", fmt.Errorf("%q must be one of %q for tool %q", keyCaseKey, keyCases, toolName)
+	}
+	return keyCase, nil
+}
+
+func validKeyCase(keyCase string) bool {
+	for _, c := range keyCases {
+		if keyCase == c {
+			return true
+		}
+	}
+	return false
+}
+
+// KeyCaseConfig wraps a ToolConfig so that the keys of its result rows are
+// converted to the Case of its option.
+type KeyCaseConfig = WrapperConfig[KeyCaseOption]
+
+// validate interface
+var _ ToolReferencingConfig = KeyCaseConfig{}
+
+// KeyCaseOption is the option of a KeyCaseConfig.
+type KeyCaseOption struct {
+	Case string
+}
+
+func (o KeyCaseOption) wrap(_ ToolConfig, t Tool) (Tool, error) {
+	return NewKeyCaseTool(t, o.Case), nil
+}
+
+// KeyCaseTool wraps a Tool so that the keys of its result rows are converted
+// to Case. Rows are the objects of a list result, or of the rows of a
+// RowsResult; other results are returned as is.
+type KeyCaseTool struct {
+	Tool
+	Case string
+}
+
+// validate interface
+var _ WrapperTool = KeyCaseTool{}
+
+func (t KeyCaseTool) Unwrap() Tool {
+	return t.Tool
+}
+
+// NewKeyCaseTool returns t wrapped to convert the keys of its result rows to
+// keyCase.
+func NewKeyCaseTool(t Tool, keyCase string) KeyCaseTool {
+	return KeyCaseTool{Tool: t, Case: keyCase}
+}
+
+func (t KeyCaseTool) Invoke(ctx context.Context, params ParamValues, accessToken AccessToken) (any, error) {
+	res, err := t.Tool.Invoke(ctx, params, accessToken)
+	if err != nil {
+		return nil, err
+	}
+	if t.Case == KeyCaseNone {
+		return res, nil
+	}
+	return convertResultKeys(res, t.Case)
+}
+
+func convertResultKeys(res any, keyCase string) (any, error) {
+	switch r := res.(type) {
+	case PrettyResult:
+		converted, err := convertResultKeys(r.Result, keyCase)
+		if err != nil {
+			return nil, err
+		}
+		return PrettyResult{Result: converted}, nil
+	case ResolvedStatementResult:
+		converted, err := convertResultKeys(r.Result, keyCase)
+		if err != nil {
+			return nil, err
+		}
+		r.Result = converted
+		return r, nil
+	case RowsResult:
+		rows, err := convertRowKeys(r.ResultRows(), keyCase)
+		if err != nil {
+			return nil, err
+		}
+		return r.WithRows(rows), nil
+	case []any:
+		return convertRowKeys(r, keyCase)
+	case []map[string]any:
+		out := make([]map[string]any, 0, len(r))
+		for _, row := range r {
+			converted, err := convertKeys(row, keyCase)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, converted)
+		}
+		return out, nil
+	}
+	return res, nil
+}
+
+func convertRowKeys(rows []any, keyCase string) ([]any, error) {
+	if rows == nil {
+		return nil, nil
+	}
+	out := make([]any, 0, len(rows))
+	for _, row := range rows {
+		m, ok := row.(map[string]any)
+		if !ok {
+			out = append(out, row)
+			continue
+		}
+		converted, err := convertKeys(m, keyCase)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, converted)
+	}
+	return out, nil
+}
+
+func convertKeys(row map[string]any, keyCase string) (map[string]any, error) {
+	out := make(map[string]any, len(row))
+	from := make(map[string]string, len(row))
+	for key, value := range row {
+		converted := ConvertKeyCase(key, keyCase)
+		if other, ok := from[converted]; ok {
+			return nil, fmt.Errorf("%s case converts both %q and %q to %q", keyCase, other, key, converted)
+		}
+		from[converted] = key
+		out[converted] = value
+	}
+	return out, nil
+}
+
+// ConvertKeyCase converts key to keyCase, e.g. `user_name` to `userName` for
+// KeyCaseCamel and `userName` to `user_name` for KeyCaseSnake. Words are
+// separated by underscores, hyphens, spaces and changes of case. Keys without
+// words, and keys for KeyCaseNone, are returned as is.
+func ConvertKeyCase(key, keyCase string) string {
+	words := splitWords(key)
+	if len(words) == 0 {
+		return key
+	}
+	switch keyCase {
+	case KeyCaseCamel:
+		var b strings.Builder
+		for i, w := range words {
+			w = strings.ToLower(w)
+			if i > 0 {
+				r := []rune(w)
+				r[0] = unicode.ToUpper(r[0])
+				w = string(r)
+			}
+			b.WriteString(w)
+		}
+		return b.String()
+	case KeyCaseSnake:
+		for i, w := range words {
+			words[i] = strings.ToLower(w)
+		}
+		return strings.Join(words, "_")
+	}
+	return key
+}
+
+// splitWords splits key into words, e.g. `HTTPServer_id` into `HTTP`,
+// `Server` and `id`.
+func splitWords(key string) []string {
+	var words []string
+	var word []rune
+	runes := []rune(key)
+	for i, r := range runes {
+		if r == '_' || r == '-' || r == ' ' {
+			if len(word) > 0 {
+				words = append(words, string(word))
+				word = nil
+			}
+			continue
+		}
+		if unicode.IsUpper(r) && len(word) > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				words = append(words, string(word))
+				word = nil
+			}
+		}
+		word = append(word, r)
+	}
+	if len(word) > 0 {
+		words = append(words, string(word))
+	}
+	return words
+}
@@ -0,0 +1,195 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// warningResult is a RowsResult like the results of BigQuery tools.
+type warningResult struct {
+	Rows    []any
+	Warning string
+}
+
+func (r warningResult) ResultRows() []any {
+	return r.Rows
+}
+
+func (r warningResult) WithRows(rows []any) any {
+	r.Rows = rows
+	return r
+}
+
+func TestKeyCaseTool(t *testing.T) {
+	tcs := []struct {
+		desc    string
+		keyCase string
+		res     any
+		want    any
+	}{
+		{
+			desc:    "camel",
+			keyCase: tools.KeyCaseCamel,
+			res:     []any{map[string]any{"user_name": "alice", "id": 1}},
+			want:    []any{map[string]any{"userName": "alice", "id": 1}},
+		},
+		{
+			desc:    "snake",
+			keyCase: tools.KeyCaseSnake,
+			res:     []any{map[string]any{"userName": "alice", "id": 1}},
+			want:    []any{map[string]any{"user_name": "alice", "id": 1}},
+		},
+		{
+			desc:    "none",
+			keyCase: tools.KeyCaseNone,
+			res:     []any{map[string]any{"user_name": "alice", "userId": 1}},
+			want:    []any{map[string]any{"user_name": "alice", "userId": 1}},
+		},
+		{
+			desc:    "typed rows",
+			keyCase: tools.KeyCaseCamel,
+			res:     []map[string]any{{"user_name": "alice"}},
+			want:    []map[string]any{{"userName": "alice"}},
+		},
+		{
+			desc:    "rows with a warning",
+			keyCase: tools.KeyCaseCamel,
+			res:     warningResult{Rows: []any{map[string]any{"user_name": "alice"}}, Warning: "incomplete"},
+			want:    warningResult{Rows: []any{map[string]any{"userName": "alice"}}, Warning: "incomplete"},
+		},
+		{
+			desc:    "pretty rows",
+			keyCase: tools.KeyCaseCamel,
+			res:     tools.PrettyResult{Result: []any{map[string]any{"user_name": "alice"}}},
+			want:    tools.PrettyResult{Result: []any{map[string]any{"userName": "alice"}}},
+		},
+		{
+			desc:    "message",
+			keyCase: tools.KeyCaseCamel,
+			res:     tools.ZeroRowsMessage,
+			want:    tools.ZeroRowsMessage,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			tool := tools.NewKeyCaseTool(resultTool{res: tc.res}, tc.keyCase)
+			got, err := tool.Invoke(context.Background(), nil, "")
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect result: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestKeyCaseToolClash(t *testing.T) {
+	tool := tools.NewKeyCaseTool(resultTool{res: []any{map[string]any{"user_name": "alice", "userName": "bob"}}}, tools.KeyCaseCamel)
+	if _, err := tool.Invoke(context.Background(), nil, ""); err == nil {
+		t.Fatalf("expected an error for keys that convert to the same key")
+	}
+}
+
+func TestConvertKeyCase(t *testing.T) {
+	tcs := []struct {
+		in    string
+		camel string
+		snake string
+	}{
+		{in: "user_name", camel: "userName", snake: "user_name"},
+		{in: "userName", camel: "userName", snake: "user_name"},
+		{in: "UserName", camel: "userName", snake: "user_name"},
+		{in: "USER_NAME", camel: "userName", snake: "user_name"},
+		{in: "user-name", camel: "userName", snake: "user_name"},
+		{in: "HTTPServer", camel: "httpServer", snake: "http_server"},
+		{in: "address_line1", camel: "addressLine1", snake: "address_line1"},
+		{in: "id", camel: "id", snake: "id"},
+		{in: "_", camel: "_", snake: "_"},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.in, func(t *testing.T) {
+			if got := tools.ConvertKeyCase(tc.in, tools.KeyCaseCamel); got != tc.camel {
+				t.Errorf("incorrect camel case: got %q, want %q", got, tc.camel)
+			}
+			if got := tools.ConvertKeyCase(tc.in, tools.KeyCaseSnake); got != tc.snake {
+				t.Errorf("incorrect snake case: got %q, want %q", got, tc.snake)
+			}
+			if got := tools.ConvertKeyCase(tc.in, tools.KeyCaseNone); got != tc.in {
+				t.Errorf("none should leave the key untouched: got %q", got)
+			}
+		})
+	}
+}
+
+func TestExtractKeyCase(t *testing.T) {
+	tcs := []struct {
+		name    string
+		in      map[string]any
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "not set",
+			in:   map[string]any{"kind": "some-kind"},
+			want: "",
+		},
+		{
+			name: "camel",
+			in:   map[string]any{"kind": "some-kind", "keyCase": "camel"},
+			want: tools.KeyCaseCamel,
+		},
+		{
+			name: "none",
+			in:   map[string]any{"kind": "some-kind", "keyCase": "none"},
+			want: tools.KeyCaseNone,
+		},
+		{
+			name:    "unknown case",
+			in:      map[string]any{"kind": "some-kind", "keyCase": "kebab"},
+			wantErr: true,
+		},
+		{
+			name:    "not a string",
+			in:      map[string]any{"kind": "some-kind", "keyCase": true},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tools.ExtractKeyCase("my-tool", tc.in)
+			if err != nil {
+				if tc.wantErr {
+					return
+				}
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if tc.wantErr {
+				t.Fatalf("expected error but got nil")
+			}
+			if got != tc.want {
+				t.Fatalf("incorrect key case: got %q, want %q", got, tc.want)
+			}
+			if diff := cmp.Diff(map[string]any{"kind": "some-kind"}, tc.in); diff != "" {
+				t.Fatalf("keyCase not removed from config: diff %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,236 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// descriptionsKey is the tool and parameter config field holding translated
+// descriptions keyed by locale.
+const descriptionsKey = "descriptions"
+
+// Localization holds the translated descriptions of a tool for one locale.
+type Localization struct {
+	Description string
+	// Parameters maps parameter names to their translated descriptions.
+	Parameters map[string]string
+}
+
+// Localizations maps locales (e.g. "fr" or "pt-BR") to translated descriptions.
+type Localizations map[string]Localization
+
+// ExtractLocalizations removes the `descriptions` fields from a raw tool
+// config and its parameters, and returns them as Localizations. The fields
+// are removed so that the remaining config can be decoded strictly by the
+// tool kind. A nil Localizations is returned if no translations are set.
+func ExtractLocalizations(toolName string, v map[string]any) (Localizations, error) {
+	var l Localizations
+	add := func(locale string, set func(*Localization)) {
+		if l == nil {
+			l = make(Localizations)
+		}
+		entry := l[locale]
+		set(&entry)
+		l[locale] = entry
+	}
+
+	descs, err := popDescriptions(v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %q field for tool %q: %w", descriptionsKey, toolName, err)
+	}
+	for locale, desc := range descs {
+		add(locale, func(e *Localization) { e.Description = desc })
+	}
+
+	rawParams, _ := v["parameters"].([]any)
+	for _, rawParam := range rawParams {
+		p, ok := rawParam.(map[string]any)
+		if !ok {
+			continue
+		}
+		descs, err := popDescriptions(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %q field for parameter %v of tool %q: %w", descriptionsKey, p["name"], toolName, err)
+		}
+		name, _ := p["name"].(string)
+		for locale, desc := range descs {
+			add(locale, func(e *Localization) {
+				if e.Parameters == nil {
+					e.Parameters = make(map[string]string)
+				}
+				e.Parameters[name] = desc
+			})
+		}
+	}
+	return l, nil
+}
+
+// popDescriptions removes and returns the `descriptions` field of m.
+func popDescriptions(m map[string]any) (map[string]string, error) {
+	raw, ok := m[descriptionsKey]
+	if !ok {
+		return nil, nil
+	}
+	delete(m, descriptionsKey)
+	rawMap, ok := raw.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("must be a map of locale to description")
+	}
+	descs := make(map[string]string, len(rawMap))
+	for locale, d := range rawMap {
+		desc, ok := d.(string)
+		if !ok {
+			return nil, fmt.Errorf("description for locale %q must be a string", locale)
+		}
+		descs[locale] = desc
+	}
+	return descs, nil
+}
+
+// LocalizedConfig wraps a ToolConfig with translated descriptions.
+type LocalizedConfig = WrapperConfig[Localizations]
+
+// validate interface
+var _ ToolReferencingConfig = LocalizedConfig{}
+
+func (l Localizations) wrap(_ ToolConfig, t Tool) (Tool, error) {
+	return LocalizedTool{Tool: t, Localizations: l}, nil
+}
+
+// LocalizedTool wraps a Tool with translated descriptions. Manifest and
+// McpManifest return the base descriptions; use LocalizeManifest and
+// LocalizeMcpManifest to select a locale, which find the LocalizedTool through
+// the wrappers around it.
+type LocalizedTool struct {
+	Tool
+	Localizations Localizations
+}
+
+// validate interface
+var _ WrapperTool = LocalizedTool{}
+
+func (t LocalizedTool) Unwrap() Tool {
+	return t.Tool
+}
+
+// lookup returns the translations for the first of the preferred locales that
+// the tool supports. A locale also matches a translation for its base
+// language, e.g. "fr-CA" falls back to "fr".
+func (l Localizations) lookup(locales []string) (Localization, bool) {
+	for _, locale := range locales {
+		base, _, _ := strings.Cut(locale, "-")
+		for _, candidate := range []string{locale, base} {
+			for k, v := range l {
+				if strings.EqualFold(k, candidate) {
+					return v, true
+				}
+			}
+		}
+	}
+	return Localization{}, false
+}
+
+// LocalizeManifest returns the manifest of t with descriptions translated to
+// the first supported locale in locales. Descriptions without a translation
+// keep their base value.
+func LocalizeManifest(t Tool, locales []string) Manifest {
+	m := t.Manifest()
+	lt, ok := findWrapper[LocalizedTool](t)
+	if !ok {
+		return m
+	}
+	loc, ok := lt.Localizations.lookup(locales)
+	if !ok {
+		return m
+	}
+	if loc.Description != "" {
+		m.Description = loc.Description
+	}
+	params := make([]ParameterManifest, len(m.Parameters))
+	for i, p := range m.Parameters {
+		if desc, ok := loc.Parameters[p.Name]; ok {
+			p.Description = desc
+		}
+		params[i] = p
+	}
+	m.Parameters = params
+	return m
+}
+
+// LocalizeMcpManifest returns the MCP manifest of t with descriptions
+// translated to the first supported locale in locales. Descriptions without a
+// translation keep their base value.
+func LocalizeMcpManifest(t Tool, locales []string) McpManifest {
+	m := t.McpManifest()
+	lt, ok := findWrapper[LocalizedTool](t)
+	if !ok {
+		return m
+	}
+	loc, ok := lt.Localizations.lookup(locales)
+	if !ok {
+		return m
+	}
+	if loc.Description != "" {
+		m.Description = loc.Description
+	}
+	props := make(map[string]ParameterMcpManifest, len(m.InputSchema.Properties))
+	for name, p := range m.InputSchema.Properties {
+		if desc, ok := loc.Parameters[name]; ok {
+			p.Description = desc
+		}
+		props[name] = p
+	}
+	m.InputSchema.Properties = props
+	return m
+}
+
+// ParseAcceptLanguage returns the locales of an Accept-Language header value
+// ordered by preference. Wildcards and locales with a zero weight are skipped.
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		locale string
+		q      float64
+	}
+	var ws []weighted
+	for _, part := range strings.Split(header, ",") {
+		locale, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		locale = strings.TrimSpace(locale)
+		if locale == "" || locale == "*" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+		ws = append(ws, weighted{locale: locale, q: q})
+	}
+	sort.SliceStable(ws, func(i, j int) bool { return ws[i].q > ws[j].q })
+	locales := make([]string, 0, len(ws))
+	for _, w := range ws {
+		locales = append(locales, w.locale)
+	}
+	return locales
+}
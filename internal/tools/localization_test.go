@@ -0,0 +1,166 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestExtractLocalizations(t *testing.T) {
+	tcs := []struct {
+		name    string
+		in      map[string]any
+		want    tools.Localizations
+		wantCfg map[string]any
+		wantErr bool
+	}{
+		{
+			name:    "no descriptions",
+			in:      map[string]any{"kind": "some-kind", "description": "base"},
+			want:    nil,
+			wantCfg: map[string]any{"kind": "some-kind", "description": "base"},
+		},
+		{
+			name: "tool and parameter descriptions",
+			in: map[string]any{
+				"kind":         "some-kind",
+				"description":  "base",
+				"descriptions": map[string]any{"fr": "base fr", "es": "base es"},
+				"parameters": []any{
+					map[string]any{"name": "city", "type": "string", "description": "city", "descriptions": map[string]any{"fr": "ville"}},
+					map[string]any{"name": "id", "type": "integer", "description": "id"},
+				},
+			},
+			want: tools.Localizations{
+				"fr": {Description: "base fr", Parameters: map[string]string{"city": "ville"}},
+				"es": {Description: "base es"},
+			},
+			wantCfg: map[string]any{
+				"kind":        "some-kind",
+				"description": "base",
+				"parameters": []any{
+					map[string]any{"name": "city", "type": "string", "description": "city"},
+					map[string]any{"name": "id", "type": "integer", "description": "id"},
+				},
+			},
+		},
+		{
+			name:    "descriptions not a map",
+			in:      map[string]any{"kind": "some-kind", "descriptions": "fr"},
+			wantErr: true,
+		},
+		{
+			name:    "description not a string",
+			in:      map[string]any{"kind": "some-kind", "descriptions": map[string]any{"fr": 1}},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tools.ExtractLocalizations("my-tool", tc.in)
+			if err != nil {
+				if tc.wantErr {
+					return
+				}
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if tc.wantErr {
+				t.Fatalf("expected error but got nil")
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect localizations: diff %v", diff)
+			}
+			if diff := cmp.Diff(tc.wantCfg, tc.in); diff != "" {
+				t.Fatalf("descriptions not removed from config: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tcs := []struct {
+		name string
+		in   string
+		want []string
+	}{
+		{
+			name: "empty",
+			in:   "",
+			want: []string{},
+		},
+		{
+			name: "single locale",
+			in:   "fr-CA",
+			want: []string{"fr-CA"},
+		},
+		{
+			name: "ordered by weight",
+			in:   "en;q=0.5, fr-CA, fr;q=0.9",
+			want: []string{"fr-CA", "fr", "en"},
+		},
+		{
+			name: "skips wildcard and zero weight",
+			in:   "de;q=0, *;q=0.1, es",
+			want: []string{"es"},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tools.ParseAcceptLanguage(tc.in)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect locales: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestLocalizeManifestThroughWrappers(t *testing.T) {
+	base := paramsTool{params: tools.Parameters{
+		tools.NewIntParameter("limit", "The maximum number of rows."),
+	}}
+	localized := tools.LocalizedTool{Tool: base, Localizations: tools.Localizations{
+		"fr": {Description: "french", Parameters: map[string]string{"limit": "Le nombre maximum de lignes."}},
+	}}
+
+	tcs := []struct {
+		desc string
+		tool tools.Tool
+	}{
+		{desc: "localized tool", tool: localized},
+		{desc: "statement length outside", tool: tools.NewStatementLengthTool(localized, 100)},
+		{desc: "pretty print outside", tool: tools.NewPrettyPrintTool(localized)},
+		{desc: "several wrappers outside", tool: tools.NewStatementLengthTool(tools.NewEmptyResultTool(tools.NewPrettyPrintTool(localized), "no rows"), 100)},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			m := tools.LocalizeManifest(tc.tool, []string{"fr-CA"})
+			if m.Description != "french" {
+				t.Fatalf("incorrect description: got %q, want %q", m.Description, "french")
+			}
+			if got := m.Parameters[0].Description; got != "Le nombre maximum de lignes." {
+				t.Fatalf("incorrect parameter description: got %q", got)
+			}
+			if got := tools.LocalizeMcpManifest(tc.tool, []string{"fr"}).Description; got != "french" {
+				t.Fatalf("incorrect MCP description: got %q, want %q", got, "french")
+			}
+			if got := tools.LocalizeManifest(tc.tool, []string{"de"}).Description; got != "" {
+				t.Fatalf("unsupported locale should keep the base description, got %q", got)
+			}
+		})
+	}
+}
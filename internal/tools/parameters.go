@@ -16,9 +16,12 @@ package tools
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	"encoding/json"
 	"fmt"
+	"reflect"
+	"regexp"
 	"slices"
 	"strings"
 	"text/template"
@@ -35,6 +38,9 @@ const (
 	typeMap    = "map"
 )
 
+// RedactedValue replaces the value of a sensitive parameter in logs.
+const RedactedValue = "***"
+
 // ParamValues is an ordered list of ParamValue
 type ParamValues []ParamValue
 
@@ -42,6 +48,22 @@ type ParamValues []ParamValue
 type ParamValue struct {
 	Name  string
 	Value any
+	// Sensitive values are redacted when the ParamValues are formatted.
+	Sensitive bool
+}
+
+// String formats the values for logging, with the values of sensitive
+// parameters replaced by RedactedValue.
+func (p ParamValues) String() string {
+	values := make([]string, 0, len(p))
+	for _, v := range p {
+		if v.Sensitive {
+			values = append(values, fmt.Sprintf("%s=%s", v.Name, RedactedValue))
+			continue
+		}
+		values = append(values, fmt.Sprintf("%s=%v", v.Name, v.Value))
+	}
+	return "[" + strings.Join(values, " ") + "]"
 }
 
 // AsSlice returns a slice of the Param's values (in order).
@@ -54,6 +76,25 @@ func (p ParamValues) AsSlice() []any {
 	return params
 }
 
+// AsOrderedSlice returns the values of the given Parameters in the order they
+// are declared, as necessary for positional binding. Values for parameters not
+// in ps (e.g. template parameters) are omitted.
+func (p ParamValues) AsOrderedSlice(ps Parameters) ([]any, error) {
+	values := make(map[string]any, len(p))
+	for _, v := range p {
+		values[v.Name] = v.Value
+	}
+	params := make([]any, 0, len(ps))
+	for _, param := range ps {
+		v, ok := values[param.GetName()]
+		if !ok {
+			return nil, fmt.Errorf("missing parameter %s", param.GetName())
+		}
+		params = append(params, v)
+	}
+	return params, nil
+}
+
 // AsMap returns a map of ParamValue's names to values.
 func (p ParamValues) AsMap() map[string]interface{} {
 	params := make(map[string]interface{})
@@ -100,7 +141,7 @@ func parseFromAuthService(paramAuthServices []ParamAuthService, claimsMap map[st
 			// not validated for this authservice, skip to the next one
 			continue
 		}
-		v, ok := claims[a.Field]
+		v, ok := lookupClaim(claims, a.Field)
 		if !ok {
 			// claims do not contain specified field
 			return nil, fmt.Errorf("no field named %s in claims", a.Field)
@@ -110,6 +151,51 @@ func parseFromAuthService(paramAuthServices []ParamAuthService, claimsMap map[st
 	return nil, fmt.Errorf("missing or invalid authentication header: %w", ErrUnauthorized)
 }
 
+// lookupClaim returns the claim named by field. A field that is not a
+// top-level claim is treated as a dot-separated path into nested claims, so
+// "org.id" reads the "id" key of the "org" claim. Top-level claims are checked
+// first because claim names, such as namespaced custom claims, may contain dots.
+func lookupClaim(claims map[string]any, field string) (any, bool) {
+	if v, ok := claims[field]; ok {
+		return v, true
+	}
+	path := strings.Split(field, ".")
+	if len(path) < 2 {
+		return nil, false
+	}
+	var v any = claims
+	for _, key := range path {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		v, ok = m[key]
+		if !ok {
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// checkClaimMatch rejects a request that supplies a value for an
+// authenticated parameter that differs from the value taken from the claim,
+// e.g. a caller passing another tenant's org id.
+func checkClaimMatch(p Parameter, data map[string]any, claimV any) error {
+	name := p.GetName()
+	v, ok := data[name]
+	if !ok {
+		return nil
+	}
+	supplied, err := p.Parse(v)
+	if err != nil {
+		return fmt.Errorf("unable to parse value for %q: %w", name, err)
+	}
+	if !reflect.DeepEqual(supplied, claimV) {
+		return fmt.Errorf("value for parameter %q does not match the authenticated claim: %w", name, ErrUnauthorized)
+	}
+	return nil
+}
+
 // CheckParamRequired checks if a parameter is required based on the required and default field.
 func CheckParamRequired(required bool, defaultV any) bool {
 	return required && defaultV == nil
@@ -147,7 +233,12 @@ func ParseParams(ps Parameters, data map[string]any, claimsMap map[string]map[st
 				return nil, fmt.Errorf("unable to parse value for %q: %w", name, err)
 			}
 		}
-		params = append(params, ParamValue{Name: name, Value: newV})
+		if len(paramAuthServices) != 0 && p.GetMatchClaim() {
+			if err := checkClaimMatch(p, data, newV); err != nil {
+				return nil, err
+			}
+		}
+		params = append(params, ParamValue{Name: name, Value: newV, Sensitive: p.GetSensitive()})
 	}
 	return params, nil
 }
@@ -191,6 +282,10 @@ func ResolveTemplateParams(templateParams Parameters, originalStatement string,
 		return "", fmt.Errorf("error getting template params %s", err)
 	}
 
+	if err := checkIdentifiers(templateParams, templateParamsMap); err != nil {
+		return "", err
+	}
+
 	funcMap := template.FuncMap{
 		"array": ConvertArrayParamToString,
 	}
@@ -208,6 +303,46 @@ func ResolveTemplateParams(templateParams Parameters, originalStatement string,
 	return modifiedStatement, nil
 }
 
+// identifierRegex matches SQL identifiers made of letters, digits,
+// underscores and dots, with backticked parts that may also hold hyphens,
+// e.g. `my-project`.dataset.table.
+var identifierRegex = regexp.MustCompile("^(?:[A-Za-z0-9_.]|`[A-Za-z0-9_.-]+`)+$")
+
+// checkIdentifiers returns an error if the value of an identifier template
+// parameter, or of an item of an array of identifiers, isn't an identifier.
+func checkIdentifiers(templateParams Parameters, values map[string]any) error {
+	for _, p := range templateParams {
+		switch p := p.(type) {
+		case *StringParameter:
+			if p.Identifier {
+				if err := checkIdentifier(p.Name, values[p.Name]); err != nil {
+					return err
+				}
+			}
+		case *ArrayParameter:
+			items, ok := p.Items.(*StringParameter)
+			if !ok || !items.Identifier {
+				continue
+			}
+			arr, _ := values[p.Name].([]any)
+			for _, v := range arr {
+				if err := checkIdentifier(p.Name, v); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func checkIdentifier(name string, v any) error {
+	s, ok := v.(string)
+	if !ok || !identifierRegex.MatchString(s) {
+		return fmt.Errorf("invalid value %q for template parameter %q: must be an identifier of letters, digits, underscores, dots and backticks", fmt.Sprint(v), name)
+	}
+	return nil
+}
+
 // ProcessParameters concatenate templateParameters and parameters from a tool.
 // It returns a list of concatenated parameters, concatenated Toolbox manifest, and concatenated MCP Manifest.
 func ProcessParameters(templateParams Parameters, params Parameters) (Parameters, []ParameterManifest, McpToolsSchema, error) {
@@ -236,6 +371,8 @@ type Parameter interface {
 	GetDefault() any
 	GetRequired() bool
 	GetAuthServices() []ParamAuthService
+	GetMatchClaim() bool
+	GetSensitive() bool
 	Parse(any) (any, error)
 	Manifest() ParameterManifest
 	McpManifest() ParameterMcpManifest
@@ -300,7 +437,15 @@ func parseParamFromDelayedUnmarshaler(ctx context.Context, u *util.DelayedUnmars
 			a.AuthServices = append(a.AuthServices, a.AuthSources...)
 			a.AuthSources = nil
 		}
-		return a, nil
+		if a.AllowedValues != nil && len(a.AllowedValues) == 0 {
+			return nil, fmt.Errorf("allowedValues of parameter %q must not be empty", a.Name)
+		}
+		if a.Default != nil {
+			if _, err := a.Parse(*a.Default); err != nil {
+				return nil, fmt.Errorf("invalid default for parameter %q: %w", a.Name, err)
+			}
+		}
+		return parseExample(a, &a.CommonParameter)
 	case typeInt:
 		a := &IntParameter{}
 		if err := dec.DecodeContext(ctx, a); err != nil {
@@ -311,7 +456,7 @@ func parseParamFromDelayedUnmarshaler(ctx context.Context, u *util.DelayedUnmars
 			a.AuthServices = append(a.AuthServices, a.AuthSources...)
 			a.AuthSources = nil
 		}
-		return a, nil
+		return parseExample(a, &a.CommonParameter)
 	case typeFloat:
 		a := &FloatParameter{}
 		if err := dec.DecodeContext(ctx, a); err != nil {
@@ -322,7 +467,7 @@ func parseParamFromDelayedUnmarshaler(ctx context.Context, u *util.DelayedUnmars
 			a.AuthServices = append(a.AuthServices, a.AuthSources...)
 			a.AuthSources = nil
 		}
-		return a, nil
+		return parseExample(a, &a.CommonParameter)
 	case typeBool:
 		a := &BooleanParameter{}
 		if err := dec.DecodeContext(ctx, a); err != nil {
@@ -333,7 +478,7 @@ func parseParamFromDelayedUnmarshaler(ctx context.Context, u *util.DelayedUnmars
 			a.AuthServices = append(a.AuthServices, a.AuthSources...)
 			a.AuthSources = nil
 		}
-		return a, nil
+		return parseExample(a, &a.CommonParameter)
 	case typeArray:
 		a := &ArrayParameter{}
 		if err := dec.DecodeContext(ctx, a); err != nil {
@@ -344,7 +489,7 @@ func parseParamFromDelayedUnmarshaler(ctx context.Context, u *util.DelayedUnmars
 			a.AuthServices = append(a.AuthServices, a.AuthSources...)
 			a.AuthSources = nil
 		}
-		return a, nil
+		return parseExample(a, &a.CommonParameter)
 	case typeMap:
 		a := &MapParameter{}
 		if err := dec.DecodeContext(ctx, a); err != nil {
@@ -355,11 +500,34 @@ func parseParamFromDelayedUnmarshaler(ctx context.Context, u *util.DelayedUnmars
 			a.AuthServices = append(a.AuthServices, a.AuthSources...)
 			a.AuthSources = nil
 		}
-		return a, nil
+		return parseExample(a, &a.CommonParameter)
 	}
 	return nil, fmt.Errorf("%q is not valid type for a parameter", t)
 }
 
+// parseExample verifies that the example of p, if any, is a valid value of
+// its type. The example is parsed like a value sent by a client, and replaced
+// by the parsed value.
+func parseExample(p Parameter, c *CommonParameter) (Parameter, error) {
+	if c.Example == nil {
+		return p, nil
+	}
+	b, err := json.Marshal(c.Example)
+	if err != nil {
+		return nil, fmt.Errorf("invalid example for parameter %q: %w", c.Name, err)
+	}
+	var v any
+	if err := util.DecodeJSON(bytes.NewReader(b), &v); err != nil {
+		return nil, fmt.Errorf("invalid example for parameter %q: %w", c.Name, err)
+	}
+	example, err := p.Parse(v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid example for parameter %q: %w", c.Name, err)
+	}
+	c.Example = example
+	return p, nil
+}
+
 func (ps Parameters) Manifest() []ParameterManifest {
 	rtn := make([]ParameterManifest, 0, len(ps))
 	for _, p := range ps {
@@ -397,6 +565,9 @@ type ParameterManifest struct {
 	AuthServices         []string           `json:"authSources"`
 	Items                *ParameterManifest `json:"items,omitempty"`
 	AdditionalProperties any                `json:"additionalProperties,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	// FromHeader isn't part of the manifest served to clients.
+	FromHeader string `json:"-"`
 }
 
 // ParameterMcpManifest represents properties when served as part of a ToolMcpManifest.
@@ -405,6 +576,8 @@ type ParameterMcpManifest struct {
 	Description          string                `json:"description"`
 	Items                *ParameterMcpManifest `json:"items,omitempty"`
 	AdditionalProperties any                   `json:"additionalProperties,omitempty"`
+	Examples             []any                 `json:"examples,omitempty"`
+	Enum                 []string              `json:"enum,omitempty"`
 }
 
 // CommonParameter are default fields that are emebdding in most Parameter implementations. Embedding this stuct will give the object Name() and Type() functions.
@@ -415,6 +588,17 @@ type CommonParameter struct {
 	Required     *bool              `yaml:"required"`
 	AuthServices []ParamAuthService `yaml:"authServices"`
 	AuthSources  []ParamAuthService `yaml:"authSources"` // Deprecated: Kept for compatibility.
+	// MatchClaim rejects requests that supply a value for an authenticated
+	// parameter that differs from the value of its claim.
+	MatchClaim bool `yaml:"matchClaim"`
+	// Sensitive redacts the value of the parameter in logs.
+	Sensitive bool `yaml:"sensitive"`
+	// FromHeader names a request header that provides the value of the
+	// parameter when the request doesn't.
+	FromHeader string `yaml:"fromHeader"`
+	// Example is a value of the parameter shown to the model in the MCP
+	// input schema, e.g. the expected format of a date.
+	Example any `yaml:"example"`
 }
 
 // GetName returns the name specified for the Parameter.
@@ -436,14 +620,33 @@ func (p *CommonParameter) GetRequired() bool {
 	return *p.Required
 }
 
+// GetMatchClaim returns whether a supplied value must match the claim.
+func (p *CommonParameter) GetMatchClaim() bool {
+	return p.MatchClaim
+}
+
+// GetSensitive returns whether the value is redacted in logs.
+func (p *CommonParameter) GetSensitive() bool {
+	return p.Sensitive
+}
+
 // McpManifest returns the MCP manifest for the Parameter.
 func (p *CommonParameter) McpManifest() ParameterMcpManifest {
 	return ParameterMcpManifest{
 		Type:        p.Type,
 		Description: p.Desc,
+		Examples:    p.examples(),
 	}
 }
 
+// examples returns the JSON Schema examples of the Parameter.
+func (p *CommonParameter) examples() []any {
+	if p.Example == nil {
+		return nil
+	}
+	return []any{p.Example}
+}
+
 // ParseTypeError is a custom error for incorrectly typed Parameters.
 type ParseTypeError struct {
 	Name  string
@@ -498,6 +701,34 @@ func NewStringParameterWithRequired(name string, desc string, required bool) *St
 	}
 }
 
+// NewIdentifierParameter is a convenience function for initializing a
+// StringParameter whose values must be SQL identifiers.
+func NewIdentifierParameter(name string, desc string) *StringParameter {
+	return &StringParameter{
+		CommonParameter: CommonParameter{
+			Name:         name,
+			Type:         typeString,
+			Desc:         desc,
+			AuthServices: nil,
+		},
+		Identifier: true,
+	}
+}
+
+// NewStringParameterWithAllowedValues is a convenience function for
+// initializing a StringParameter that only accepts the given values.
+func NewStringParameterWithAllowedValues(name string, desc string, allowedValues []string) *StringParameter {
+	return &StringParameter{
+		CommonParameter: CommonParameter{
+			Name:         name,
+			Type:         typeString,
+			Desc:         desc,
+			AuthServices: nil,
+		},
+		AllowedValues: allowedValues,
+	}
+}
+
 // NewStringParameterWithAuth is a convenience function for initializing a StringParameter with a list of ParamAuthService.
 func NewStringParameterWithAuth(name string, desc string, authServices []ParamAuthService) *StringParameter {
 	return &StringParameter{
@@ -516,6 +747,12 @@ var _ Parameter = &StringParameter{}
 type StringParameter struct {
 	CommonParameter `yaml:",inline"`
 	Default         *string `yaml:"default"`
+	// Identifier restricts the values of a template parameter to SQL
+	// identifiers, e.g. table names, so that they can't inject SQL.
+	Identifier bool `yaml:"identifier"`
+	// AllowedValues, if set, is the list of the only values the parameter
+	// accepts, e.g. the tables a template parameter may name.
+	AllowedValues []string `yaml:"allowedValues"`
 }
 
 // Parse casts the value "v" as a "string".
@@ -524,6 +761,9 @@ func (p *StringParameter) Parse(v any) (any, error) {
 	if !ok {
 		return nil, &ParseTypeError{p.Name, p.Type, v}
 	}
+	if p.AllowedValues != nil && !slices.Contains(p.AllowedValues, newV) {
+		return nil, fmt.Errorf("value %q is not allowed for parameter %q, must be one of %q", newV, p.Name, p.AllowedValues)
+	}
 	return newV, nil
 }
 
@@ -552,6 +792,19 @@ func (p *StringParameter) Manifest() ParameterManifest {
 		Required:     r,
 		Description:  p.Desc,
 		AuthServices: authNames,
+		Enum:         p.AllowedValues,
+		FromHeader:   p.FromHeader,
+	}
+}
+
+// McpManifest returns the MCP manifest for the StringParameter, which lists
+// its allowed values, if any, as the JSON Schema enum.
+func (p *StringParameter) McpManifest() ParameterMcpManifest {
+	return ParameterMcpManifest{
+		Type:        p.Type,
+		Description: p.Desc,
+		Examples:    p.examples(),
+		Enum:        p.AllowedValues,
 	}
 }
 
@@ -659,6 +912,7 @@ func (p *IntParameter) Manifest() ParameterManifest {
 		Required:     r,
 		Description:  p.Desc,
 		AuthServices: authNames,
+		FromHeader:   p.FromHeader,
 	}
 }
 
@@ -764,6 +1018,7 @@ func (p *FloatParameter) Manifest() ParameterManifest {
 		Required:     r,
 		Description:  p.Desc,
 		AuthServices: authNames,
+		FromHeader:   p.FromHeader,
 	}
 }
 
@@ -773,6 +1028,7 @@ func (p *FloatParameter) McpManifest() ParameterMcpManifest {
 	return ParameterMcpManifest{
 		Type:        "number",
 		Description: p.Desc,
+		Examples:    p.examples(),
 	}
 }
 
@@ -867,6 +1123,7 @@ func (p *BooleanParameter) Manifest() ParameterManifest {
 		Required:     r,
 		Description:  p.Desc,
 		AuthServices: authNames,
+		FromHeader:   p.FromHeader,
 	}
 }
 
@@ -926,11 +1183,15 @@ func NewArrayParameterWithAuth(name string, desc string, items Parameter, authSe
 
 var _ Parameter = &ArrayParameter{}
 
-// ArrayParameter is a parameter representing the "array" type.
+// ArrayParameter is a parameter representing the "array" type. If Dedupe is
+// set, repeated elements are removed when parsing, keeping the first. If Sort
+// is set, elements are sorted in ascending order when parsing.
 type ArrayParameter struct {
 	CommonParameter `yaml:",inline"`
 	Default         *[]any    `yaml:"default"`
 	Items           Parameter `yaml:"items"`
+	Dedupe          bool      `yaml:"dedupe"`
+	Sort            bool      `yaml:"sort"`
 }
 
 func (p *ArrayParameter) UnmarshalYAML(ctx context.Context, unmarshal func(interface{}) error) error {
@@ -938,12 +1199,16 @@ func (p *ArrayParameter) UnmarshalYAML(ctx context.Context, unmarshal func(inter
 		CommonParameter `yaml:",inline"`
 		Default         *[]any                  `yaml:"default"`
 		Items           util.DelayedUnmarshaler `yaml:"items"`
+		Dedupe          bool                    `yaml:"dedupe"`
+		Sort            bool                    `yaml:"sort"`
 	}
 	if err := unmarshal(&rawItem); err != nil {
 		return err
 	}
 	p.CommonParameter = rawItem.CommonParameter
 	p.Default = rawItem.Default
+	p.Dedupe = rawItem.Dedupe
+	p.Sort = rawItem.Sort
 	i, err := parseParamFromDelayedUnmarshaler(ctx, &rawItem.Items)
 	if err != nil {
 		return fmt.Errorf("unable to parse 'items' field: %w", err)
@@ -951,6 +1216,9 @@ func (p *ArrayParameter) UnmarshalYAML(ctx context.Context, unmarshal func(inter
 	if i.GetAuthServices() != nil && len(i.GetAuthServices()) != 0 {
 		return fmt.Errorf("nested items should not have auth services")
 	}
+	if p.Sort && !slices.Contains([]string{typeString, typeInt, typeFloat, typeBool}, i.GetType()) {
+		return fmt.Errorf("'sort' is only supported for arrays of %s, %s, %s or %s items", typeString, typeInt, typeFloat, typeBool)
+	}
 	p.Items = i
 
 	return nil
@@ -969,9 +1237,58 @@ func (p *ArrayParameter) Parse(v any) (any, error) {
 		}
 		rtn = append(rtn, val)
 	}
+	if p.Dedupe {
+		rtn = dedupeElements(rtn)
+	}
+	if p.Sort {
+		slices.SortStableFunc(rtn, compareElements)
+	}
 	return rtn, nil
 }
 
+// dedupeElements removes repeated elements, keeping the first of each.
+func dedupeElements(elems []any) []any {
+	seen := make(map[any]bool)
+	out := make([]any, 0, len(elems))
+	for _, e := range elems {
+		if e == nil || !reflect.TypeOf(e).Comparable() {
+			// maps and nested arrays can't be map keys
+			if !slices.ContainsFunc(out, func(o any) bool { return reflect.DeepEqual(o, e) }) {
+				out = append(out, e)
+			}
+			continue
+		}
+		if !seen[e] {
+			seen[e] = true
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// compareElements orders the parsed elements of string, integer, float and
+// boolean arrays. false sorts before true.
+func compareElements(a, b any) int {
+	switch a := a.(type) {
+	case string:
+		return cmp.Compare(a, b.(string))
+	case int:
+		return cmp.Compare(a, b.(int))
+	case float64:
+		return cmp.Compare(a, b.(float64))
+	case bool:
+		switch {
+		case a == b.(bool):
+			return 0
+		case a:
+			return 1
+		default:
+			return -1
+		}
+	}
+	return 0
+}
+
 func (p *ArrayParameter) GetAuthServices() []ParamAuthService {
 	return p.AuthServices
 }
@@ -1005,6 +1322,7 @@ func (p *ArrayParameter) Manifest() ParameterManifest {
 		Description:  p.Desc,
 		AuthServices: authNames,
 		Items:        &items,
+		FromHeader:   p.FromHeader,
 	}
 }
 
@@ -1020,6 +1338,7 @@ func (p *ArrayParameter) McpManifest() ParameterMcpManifest {
 		Type:        p.Type,
 		Description: p.Desc,
 		Items:       &items,
+		Examples:    p.examples(),
 	}
 }
 
@@ -1206,6 +1525,7 @@ func (p *MapParameter) Manifest() ParameterManifest {
 		Description:          p.Desc,
 		AuthServices:         authNames,
 		AdditionalProperties: additionalProperties,
+		FromHeader:           p.FromHeader,
 	}
 }
 
@@ -1228,5 +1548,6 @@ func (p *MapParameter) McpManifest() ParameterMcpManifest {
 		Type:                 "object",
 		Description:          p.Desc,
 		AdditionalProperties: additionalProperties,
+		Examples:             p.examples(),
 	}
 }
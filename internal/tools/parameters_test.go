@@ -16,13 +16,17 @@ package tools_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"math"
 	"strings"
 	"testing"
 
 	"github.com/goccy/go-yaml"
 	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/log"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 )
@@ -64,6 +68,34 @@ func TestParametersMarshal(t *testing.T) {
 				tools.NewStringParameterWithRequired("my_string", "this param is a string", false),
 			},
 		},
+		{
+			name: "identifier",
+			in: []map[string]any{
+				{
+					"name":        "my_table",
+					"type":        "string",
+					"description": "this param is an identifier",
+					"identifier":  true,
+				},
+			},
+			want: tools.Parameters{
+				tools.NewIdentifierParameter("my_table", "this param is an identifier"),
+			},
+		},
+		{
+			name: "string with allowed values",
+			in: []map[string]any{
+				{
+					"name":          "my_table",
+					"type":          "string",
+					"description":   "this param is a table",
+					"allowedValues": []string{"hotels", "flights"},
+				},
+			},
+			want: tools.Parameters{
+				tools.NewStringParameterWithAllowedValues("my_table", "this param is a table", []string{"hotels", "flights"}),
+			},
+		},
 		{
 			name: "int",
 			in: []map[string]any{
@@ -351,6 +383,31 @@ func TestParametersMarshal(t *testing.T) {
 				tools.NewMapParameter("my_generic_map", "this param is a generic map", ""),
 			},
 		},
+		{
+			name: "array with dedupe and sort",
+			in: []map[string]any{
+				{
+					"name":        "my_array",
+					"type":        "array",
+					"description": "this param is an array of strings",
+					"dedupe":      true,
+					"sort":        true,
+					"items": map[string]string{
+						"name":        "my_string",
+						"type":        "string",
+						"description": "string item",
+					},
+				},
+			},
+			want: tools.Parameters{
+				func() *tools.ArrayParameter {
+					p := tools.NewArrayParameter("my_array", "this param is an array of strings", tools.NewStringParameter("my_string", "string item"))
+					p.Dedupe = true
+					p.Sort = true
+					return p
+				}(),
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.name, func(t *testing.T) {
@@ -717,6 +774,34 @@ func TestParametersParse(t *testing.T) {
 				"my_string": 4,
 			},
 		},
+		{
+			name: "allowed string",
+			params: tools.Parameters{
+				tools.NewStringParameterWithAllowedValues("my_table", "this param is a table", []string{"hotels", "flights"}),
+			},
+			in: map[string]any{
+				"my_table": "flights",
+			},
+			want: tools.ParamValues{tools.ParamValue{Name: "my_table", Value: "flights"}},
+		},
+		{
+			name: "string not allowed",
+			params: tools.Parameters{
+				tools.NewStringParameterWithAllowedValues("my_table", "this param is a table", []string{"hotels", "flights"}),
+			},
+			in: map[string]any{
+				"my_table": "users",
+			},
+		},
+		{
+			name: "array item not allowed",
+			params: tools.Parameters{
+				tools.NewArrayParameter("my_tables", "this param is an array of tables", tools.NewStringParameterWithAllowedValues("my_table", "a table", []string{"hotels", "flights"})),
+			},
+			in: map[string]any{
+				"my_tables": []any{"hotels", "users"},
+			},
+		},
 		{
 			name: "int",
 			params: tools.Parameters{
@@ -901,6 +986,43 @@ func TestParametersParse(t *testing.T) {
 			in:   map[string]any{},
 			want: tools.ParamValues{tools.ParamValue{Name: "my_map_not_required", Value: nil}},
 		},
+		{
+			name: "array deduped",
+			params: tools.Parameters{
+				&tools.ArrayParameter{
+					CommonParameter: tools.CommonParameter{Name: "my_array", Type: "array", Desc: "an array"},
+					Items:           tools.NewIntParameter("my_int", "int item"),
+					Dedupe:          true,
+				},
+			},
+			in:   map[string]any{"my_array": []any{3, 1, 3, 2, 1}},
+			want: tools.ParamValues{tools.ParamValue{Name: "my_array", Value: []any{3, 1, 2}}},
+		},
+		{
+			name: "array sorted",
+			params: tools.Parameters{
+				&tools.ArrayParameter{
+					CommonParameter: tools.CommonParameter{Name: "my_array", Type: "array", Desc: "an array"},
+					Items:           tools.NewStringParameter("my_string", "string item"),
+					Sort:            true,
+				},
+			},
+			in:   map[string]any{"my_array": []any{"pear", "apple", "fig", "apple"}},
+			want: tools.ParamValues{tools.ParamValue{Name: "my_array", Value: []any{"apple", "apple", "fig", "pear"}}},
+		},
+		{
+			name: "array deduped and sorted",
+			params: tools.Parameters{
+				&tools.ArrayParameter{
+					CommonParameter: tools.CommonParameter{Name: "my_array", Type: "array", Desc: "an array"},
+					Items:           tools.NewFloatParameter("my_float", "float item"),
+					Dedupe:          true,
+					Sort:            true,
+				},
+			},
+			in:   map[string]any{"my_array": []any{2.5, 1.5, 2.5, 0.5}},
+			want: tools.ParamValues{tools.ParamValue{Name: "my_array", Value: []any{0.5, 1.5, 2.5}}},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.name, func(t *testing.T) {
@@ -1070,6 +1192,32 @@ func TestAuthParametersParse(t *testing.T) {
 			claimsMap: map[string]map[string]any{"my-google-auth-service": {"auth_field": map[string]any{"authed_key": "authed_val"}}},
 			want:      tools.ParamValues{tools.ParamValue{Name: "my_map", Value: map[string]any{"authed_key": "authed_val"}}},
 		},
+		{
+			name: "nested claim",
+			params: tools.Parameters{
+				tools.NewStringParameterWithAuth("org_id", "the caller's organization", []tools.ParamAuthService{{Name: "my-google-auth-service", Field: "org.id"}}),
+			},
+			in:        map[string]any{"org_id": "other-org"},
+			claimsMap: map[string]map[string]any{"my-google-auth-service": {"org": map[string]any{"id": "my-org"}}},
+			want:      tools.ParamValues{tools.ParamValue{Name: "org_id", Value: "my-org"}},
+		},
+		{
+			name: "claim name containing a dot",
+			params: tools.Parameters{
+				tools.NewStringParameterWithAuth("role", "the caller's role", []tools.ParamAuthService{{Name: "my-google-auth-service", Field: "https://example.com/role"}}),
+			},
+			in:        map[string]any{},
+			claimsMap: map[string]map[string]any{"my-google-auth-service": {"https://example.com/role": "admin"}},
+			want:      tools.ParamValues{tools.ParamValue{Name: "role", Value: "admin"}},
+		},
+		{
+			name: "expect nested claim error",
+			params: tools.Parameters{
+				tools.NewStringParameterWithAuth("org_id", "the caller's organization", []tools.ParamAuthService{{Name: "my-google-auth-service", Field: "org.id"}}),
+			},
+			in:        map[string]any{},
+			claimsMap: map[string]map[string]any{"my-google-auth-service": {"org": map[string]any{"name": "my-org"}}},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.name, func(t *testing.T) {
@@ -1103,6 +1251,57 @@ func TestAuthParametersParse(t *testing.T) {
 	}
 }
 
+func TestMatchClaimParametersParse(t *testing.T) {
+	orgID := &tools.StringParameter{
+		CommonParameter: tools.CommonParameter{
+			Name:         "org_id",
+			Type:         "string",
+			Desc:         "the caller's organization",
+			AuthServices: []tools.ParamAuthService{{Name: "my-google-auth-service", Field: "org_id"}},
+			MatchClaim:   true,
+		},
+	}
+	claimsMap := map[string]map[string]any{"my-google-auth-service": {"org_id": "my-org"}}
+	want := tools.ParamValues{tools.ParamValue{Name: "org_id", Value: "my-org"}}
+
+	tcs := []struct {
+		name    string
+		in      map[string]any
+		wantErr bool
+	}{
+		{
+			name: "matching value",
+			in:   map[string]any{"org_id": "my-org"},
+		},
+		{
+			name: "no value supplied",
+			in:   map[string]any{},
+		},
+		{
+			name:    "mismatched value",
+			in:      map[string]any{"org_id": "other-org"},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tools.ParseParams(tools.Parameters{orgID}, tc.in, claimsMap)
+			if tc.wantErr {
+				if !errors.Is(err, tools.ErrUnauthorized) {
+					t.Fatalf("expected an unauthorized error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error from ParseParams: %s", err)
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Fatalf("ParseParams() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestParamValues(t *testing.T) {
 	tcs := []struct {
 		name              string
@@ -1159,6 +1358,82 @@ func TestParamValues(t *testing.T) {
 	}
 }
 
+func TestParamValuesAsOrderedSlice(t *testing.T) {
+	tcs := []struct {
+		name    string
+		in      tools.ParamValues
+		params  tools.Parameters
+		want    []any
+		wantErr bool
+	}{
+		{
+			name: "declaration order",
+			in: tools.ParamValues{
+				tools.ParamValue{Name: "third", Value: "c"},
+				tools.ParamValue{Name: "first", Value: "a"},
+				tools.ParamValue{Name: "second", Value: 2},
+			},
+			params: tools.Parameters{
+				tools.NewStringParameter("first", "first positional parameter"),
+				tools.NewIntParameter("second", "second positional parameter"),
+				tools.NewStringParameter("third", "third positional parameter"),
+			},
+			want: []any{"a", 2, "c"},
+		},
+		{
+			name: "repeated values",
+			in: tools.ParamValues{
+				tools.ParamValue{Name: "low", Value: 1},
+				tools.ParamValue{Name: "high", Value: 1},
+			},
+			params: tools.Parameters{
+				tools.NewIntParameter("low", "lower bound"),
+				tools.NewIntParameter("high", "upper bound"),
+			},
+			want: []any{1, 1},
+		},
+		{
+			name: "ignores undeclared values",
+			in: tools.ParamValues{
+				tools.ParamValue{Name: "tableName", Value: "my_table"},
+				tools.ParamValue{Name: "id", Value: 1},
+			},
+			params: tools.Parameters{
+				tools.NewIntParameter("id", "the id"),
+			},
+			want: []any{1},
+		},
+		{
+			name: "missing value",
+			in: tools.ParamValues{
+				tools.ParamValue{Name: "first", Value: "a"},
+			},
+			params: tools.Parameters{
+				tools.NewStringParameter("first", "first positional parameter"),
+				tools.NewStringParameter("second", "second positional parameter"),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.in.AsOrderedSlice(tc.params)
+			if err != nil {
+				if tc.wantErr {
+					return
+				}
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if tc.wantErr {
+				t.Fatalf("expected error but got nil")
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect AsOrderedSlice: diff %v", diff)
+			}
+		})
+	}
+}
+
 func TestParamManifest(t *testing.T) {
 	tcs := []struct {
 		name string
@@ -1170,6 +1445,11 @@ func TestParamManifest(t *testing.T) {
 			in:   tools.NewStringParameter("foo-string", "bar"),
 			want: tools.ParameterManifest{Name: "foo-string", Type: "string", Required: true, Description: "bar", AuthServices: []string{}},
 		},
+		{
+			name: "string with allowed values",
+			in:   tools.NewStringParameterWithAllowedValues("foo-string", "bar", []string{"a", "b"}),
+			want: tools.ParameterManifest{Name: "foo-string", Type: "string", Required: true, Description: "bar", AuthServices: []string{}, Enum: []string{"a", "b"}},
+		},
 		{
 			name: "int",
 			in:   tools.NewIntParameter("foo-int", "bar"),
@@ -1319,6 +1599,11 @@ func TestParamMcpManifest(t *testing.T) {
 			in:   tools.NewStringParameter("foo-string", "bar"),
 			want: tools.ParameterMcpManifest{Type: "string", Description: "bar"},
 		},
+		{
+			name: "string with allowed values",
+			in:   tools.NewStringParameterWithAllowedValues("foo-string", "bar", []string{"a", "b"}),
+			want: tools.ParameterMcpManifest{Type: "string", Description: "bar", Enum: []string{"a", "b"}},
+		},
 		{
 			name: "int",
 			in:   tools.NewIntParameter("foo-int", "bar"),
@@ -1467,6 +1752,31 @@ func TestFailParametersUnmarshal(t *testing.T) {
 			},
 			err: "unable to parse as \"string\": Key: 'CommonParameter.Desc' Error:Field validation for 'Desc' failed on the 'required' tag",
 		},
+		{
+			name: "empty allowed values",
+			in: []map[string]any{
+				{
+					"name":          "my_table",
+					"type":          "string",
+					"description":   "this param is a table",
+					"allowedValues": []string{},
+				},
+			},
+			err: "allowedValues of parameter \"my_table\" must not be empty",
+		},
+		{
+			name: "default not allowed",
+			in: []map[string]any{
+				{
+					"name":          "my_table",
+					"type":          "string",
+					"description":   "this param is a table",
+					"allowedValues": []string{"hotels", "flights"},
+					"default":       "users",
+				},
+			},
+			err: "invalid default for parameter \"my_table\": value \"users\" is not allowed for parameter \"my_table\", must be one of [\"hotels\" \"flights\"]",
+		},
 		{
 			name: "array parameter missing items",
 			in: []map[string]any{
@@ -1506,6 +1816,23 @@ func TestFailParametersUnmarshal(t *testing.T) {
 			},
 			err: "unsupported valueType \"not-a-real-type\" for map parameter",
 		},
+		{
+			name: "sort array of maps",
+			in: []map[string]any{
+				{
+					"name":        "my_array",
+					"type":        "array",
+					"description": "this param is an array of maps",
+					"sort":        true,
+					"items": map[string]string{
+						"name":        "my_map",
+						"type":        "map",
+						"description": "map item",
+					},
+				},
+			},
+			err: "'sort' is only supported for arrays of string, integer, float or boolean items",
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.name, func(t *testing.T) {
@@ -1529,6 +1856,123 @@ func TestFailParametersUnmarshal(t *testing.T) {
 	}
 }
 
+func TestParameterExamples(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := []map[string]any{
+		{
+			"name":        "start_date",
+			"type":        "string",
+			"description": "the first day, as YYYY-MM-DD",
+			"example":     "2024-01-31",
+		},
+		{
+			"name":        "limit",
+			"type":        "integer",
+			"description": "the maximum number of rows",
+			"example":     10,
+		},
+		{
+			"name":        "ids",
+			"type":        "array",
+			"description": "the ids to look up",
+			"items": map[string]any{
+				"name":        "id",
+				"type":        "integer",
+				"description": "an id",
+			},
+			"example": []any{1, 2},
+		},
+		{
+			"name":        "name",
+			"type":        "string",
+			"description": "a name without an example",
+		},
+	}
+	data, err := yaml.Marshal(in)
+	if err != nil {
+		t.Fatalf("unable to marshal input to yaml: %s", err)
+	}
+	var params tools.Parameters
+	if err := yaml.UnmarshalContext(ctx, data, &params); err != nil {
+		t.Fatalf("unable to unmarshal: %s", err)
+	}
+
+	got := params.McpManifest().Properties
+	want := map[string][]any{
+		"start_date": {"2024-01-31"},
+		"limit":      {10},
+		"ids":        {[]any{1, 2}},
+		"name":       nil,
+	}
+	for name, examples := range want {
+		if diff := cmp.Diff(examples, got[name].Examples); diff != "" {
+			t.Fatalf("incorrect examples for %q: diff %v", name, diff)
+		}
+	}
+
+	// examples are listed as JSON Schema examples
+	b, err := json.Marshal(got["start_date"])
+	if err != nil {
+		t.Fatalf("unable to marshal manifest: %s", err)
+	}
+	if !strings.Contains(string(b), `"examples":["2024-01-31"]`) {
+		t.Fatalf("examples missing from the input schema: %s", b)
+	}
+}
+
+func TestFailParameterExamples(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		name string
+		in   map[string]any
+	}{
+		{
+			name: "string example for integer",
+			in:   map[string]any{"name": "limit", "type": "integer", "description": "a limit", "example": "ten"},
+		},
+		{
+			name: "float example for integer",
+			in:   map[string]any{"name": "limit", "type": "integer", "description": "a limit", "example": 1.5},
+		},
+		{
+			name: "integer example for string",
+			in:   map[string]any{"name": "name", "type": "string", "description": "a name", "example": 1},
+		},
+		{
+			name: "invalid element",
+			in: map[string]any{
+				"name":        "ids",
+				"type":        "array",
+				"description": "some ids",
+				"items":       map[string]any{"name": "id", "type": "integer", "description": "an id"},
+				"example":     []any{1, "two"},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := yaml.Marshal([]map[string]any{tc.in})
+			if err != nil {
+				t.Fatalf("unable to marshal input to yaml: %s", err)
+			}
+			var got tools.Parameters
+			err = yaml.UnmarshalContext(ctx, data, &got)
+			if err == nil {
+				t.Fatalf("expect parsing to fail")
+			}
+			if !strings.Contains(err.Error(), "invalid example for parameter") {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		})
+	}
+}
+
 // ... (Remaining test functions do not involve parameter definitions and need no changes)
 
 func TestConvertArrayParamToString(t *testing.T) {
@@ -1767,6 +2211,28 @@ func TestResolveTemplateParameters(t *testing.T) {
 			},
 			want: "SELECT * FROM hotels WHERE name = $1",
 		},
+		{
+			name: "backticked identifier",
+			templateParams: tools.Parameters{
+				tools.NewIdentifierParameter("tableName", "this is an identifier template parameter"),
+			},
+			statement: "SELECT * FROM {{.tableName}}",
+			in: map[string]any{
+				"tableName": "`my-project.my_dataset.hotels`",
+			},
+			want: "SELECT * FROM `my-project.my_dataset.hotels`",
+		},
+		{
+			name: "array of identifiers",
+			templateParams: tools.Parameters{
+				tools.NewArrayParameter("columnNames", "this is an array template parameter", tools.NewIdentifierParameter("column", "a column")),
+			},
+			statement: "SELECT {{array .columnNames}} FROM hotels",
+			in: map[string]any{
+				"columnNames": []any{"id", "hotels.name"},
+			},
+			want: "SELECT id, hotels.name FROM hotels",
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.name, func(t *testing.T) {
@@ -1828,6 +2294,39 @@ func TestFailResolveTemplateParameters(t *testing.T) {
 			},
 			err: "error executing go template template: statement:1:16: executing \"statement\" at <.tableName>: tableName is not a method but has arguments",
 		},
+		{
+			name: "injected identifier",
+			templateParams: tools.Parameters{
+				tools.NewIdentifierParameter("tableName", "this is an identifier template parameter"),
+			},
+			statement: "SELECT * FROM {{.tableName}}",
+			in: map[string]any{
+				"tableName": "hotels;DROP TABLE hotels",
+			},
+			err: "invalid value \"hotels;DROP TABLE hotels\" for template parameter \"tableName\": must be an identifier of letters, digits, underscores, dots and backticks",
+		},
+		{
+			name: "injected identifier in backticks",
+			templateParams: tools.Parameters{
+				tools.NewIdentifierParameter("tableName", "this is an identifier template parameter"),
+			},
+			statement: "SELECT * FROM {{.tableName}}",
+			in: map[string]any{
+				"tableName": "`hotels`;DROP TABLE `hotels`",
+			},
+			err: "invalid value \"`hotels`;DROP TABLE `hotels`\" for template parameter \"tableName\": must be an identifier of letters, digits, underscores, dots and backticks",
+		},
+		{
+			name: "injected identifier in an array",
+			templateParams: tools.Parameters{
+				tools.NewArrayParameter("columnNames", "this is an array template parameter", tools.NewIdentifierParameter("column", "a column")),
+			},
+			statement: "SELECT {{array .columnNames}} FROM hotels",
+			in: map[string]any{
+				"columnNames": []any{"id", "name FROM hotels; --"},
+			},
+			err: "invalid value \"name FROM hotels; --\" for template parameter \"columnNames\": must be an identifier of letters, digits, underscores, dots and backticks",
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.name, func(t *testing.T) {
@@ -1881,3 +2380,42 @@ func TestCheckParamRequired(t *testing.T) {
 		})
 	}
 }
+
+func TestSensitiveParamValues(t *testing.T) {
+	ps := tools.Parameters{
+		tools.NewStringParameter("name", "the customer's name"),
+		&tools.StringParameter{
+			CommonParameter: tools.CommonParameter{
+				Name:      "email",
+				Type:      "string",
+				Desc:      "the customer's email",
+				Sensitive: true,
+			},
+		},
+	}
+	params, err := tools.ParseParams(ps, map[string]any{"name": "Alice", "email": "alice@example.com"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error from ParseParams: %s", err)
+	}
+	want := tools.ParamValues{
+		{Name: "name", Value: "Alice"},
+		{Name: "email", Value: "alice@example.com", Sensitive: true},
+	}
+	if diff := cmp.Diff(want, params); diff != "" {
+		t.Fatalf("ParseParams() mismatch (-want +got):\n%s", diff)
+	}
+
+	var buf bytes.Buffer
+	logger, err := log.NewStdLogger(&buf, &buf, "DEBUG")
+	if err != nil {
+		t.Fatalf("unable to create logger: %s", err)
+	}
+	logger.DebugContext(context.Background(), fmt.Sprintf("invocation params: %s", params))
+	got := buf.String()
+	if !strings.Contains(got, "invocation params: [name=Alice email=***]") {
+		t.Fatalf("unexpected log output: %q", got)
+	}
+	if strings.Contains(got, "alice@example.com") {
+		t.Fatalf("sensitive value was logged: %q", got)
+	}
+}
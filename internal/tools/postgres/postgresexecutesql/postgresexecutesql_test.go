@@ -21,6 +21,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/genai-toolbox/internal/server"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/tools/postgres/postgresexecutesql"
 )
 
@@ -47,12 +48,14 @@ func TestParseFromYamlExecuteSql(t *testing.T) {
 						- other-auth-service
 			`,
 			want: server.ToolConfigs{
-				"example_tool": postgresexecutesql.Config{
-					Name:         "example_tool",
-					Kind:         "postgres-execute-sql",
-					Source:       "my-instance",
-					Description:  "some description",
-					AuthRequired: []string{"my-google-auth-service", "other-auth-service"},
+				"example_tool": tools.StatementLengthConfig{
+					ToolConfig: postgresexecutesql.Config{
+						Name:         "example_tool",
+						Kind:         "postgres-execute-sql",
+						Source:       "my-instance",
+						Description:  "some description",
+						AuthRequired: []string{"my-google-auth-service", "other-auth-service"},
+					},
 				},
 			},
 		},
@@ -0,0 +1,110 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+const prettyPrintKey = "prettyPrint"
+
+// PrettyResult marks a tool result to be serialized as indented JSON.
+type PrettyResult struct {
+	Result any
+}
+
+// MarshalJSON serializes the wrapped result, so that tools built on top of
+// other tools see it unchanged.
+func (r PrettyResult) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.Result)
+}
+
+// UnwrapPrettyResult returns the result wrapped by a PrettyResult and whether
+// it should be indented. Other results are returned as is.
+func UnwrapPrettyResult(res any) (any, bool) {
+	if r, ok := res.(PrettyResult); ok {
+		return r.Result, true
+	}
+	return res, false
+}
+
+// MarshalResult serializes a tool result as JSON, indented if indent is true.
+func MarshalResult(res any, indent bool) ([]byte, error) {
+	if indent {
+		return json.MarshalIndent(res, "", "  ")
+	}
+	return json.Marshal(res)
+}
+
+// ExtractPrettyPrint removes the `prettyPrint` field from a raw tool config, so
+// that the remaining config can be decoded strictly by the tool kind.
+func ExtractPrettyPrint(toolName string, v map[string]any) (bool, error) {
+	raw, ok := v[prettyPrintKey]
+	if !ok {
+		return false, nil
+	}
+	delete(v, prettyPrintKey)
+	pretty, ok := raw.(bool)
+	if !ok {
+		return false, fmt.Errorf("%q must be a boolean for tool %q", prettyPrintKey, toolName)
+	}
+	return pretty, nil
+}
+
+// PrettyPrintConfig wraps a ToolConfig so that its results are serialized as
+// indented JSON.
+type PrettyPrintConfig = WrapperConfig[PrettyPrintOption]
+
+// validate interface
+var _ ToolReferencingConfig = PrettyPrintConfig{}
+
+// PrettyPrintOption is the option of a PrettyPrintConfig, which has no
+// settings.
+type PrettyPrintOption struct{}
+
+func (PrettyPrintOption) wrap(_ ToolConfig, t Tool) (Tool, error) {
+	return NewPrettyPrintTool(t), nil
+}
+
+// PrettyPrintTool wraps a Tool so that its results are returned as
+// PrettyResults.
+type PrettyPrintTool struct {
+	Tool
+}
+
+// validate interface
+var _ WrapperTool = PrettyPrintTool{}
+
+func (t PrettyPrintTool) Unwrap() Tool {
+	return t.Tool
+}
+
+// NewPrettyPrintTool returns t wrapped to have its results indented.
+func NewPrettyPrintTool(t Tool) PrettyPrintTool {
+	return PrettyPrintTool{Tool: t}
+}
+
+func (t PrettyPrintTool) Invoke(ctx context.Context, params ParamValues, accessToken AccessToken) (any, error) {
+	res, err := t.Tool.Invoke(ctx, params, accessToken)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := res.(PrettyResult); ok {
+		return res, nil
+	}
+	return PrettyResult{Result: res}, nil
+}
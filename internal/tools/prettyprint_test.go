@@ -0,0 +1,135 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestPrettyPrintTool(t *testing.T) {
+	rows := []any{map[string]any{"id": 1, "name": "Alice"}}
+
+	tcs := []struct {
+		desc   string
+		tool   tools.Tool
+		indent bool
+	}{
+		{
+			desc:   "compact by default",
+			tool:   resultTool{res: rows},
+			indent: false,
+		},
+		{
+			desc:   "pretty print",
+			tool:   tools.NewPrettyPrintTool(resultTool{res: rows}),
+			indent: true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := tc.tool.Invoke(context.Background(), nil, "")
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			res, indent := tools.UnwrapPrettyResult(res)
+			if indent != tc.indent {
+				t.Fatalf("incorrect indent: got %t, want %t", indent, tc.indent)
+			}
+			if diff := cmp.Diff(rows, res); diff != "" {
+				t.Fatalf("incorrect result: diff %v", diff)
+			}
+			got, err := tools.MarshalResult(res, indent)
+			if err != nil {
+				t.Fatalf("unable to marshal result: %s", err)
+			}
+			if strings.Contains(string(got), "\n  ") != tc.indent {
+				t.Fatalf("unexpected indentation in %q", got)
+			}
+		})
+	}
+}
+
+func TestPrettyResultMarshalJSON(t *testing.T) {
+	// tools built on top of a pretty printed tool see the result unchanged
+	got, err := json.Marshal(tools.PrettyResult{Result: []any{"a", "b"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if string(got) != `["a","b"]` {
+		t.Fatalf("unexpected JSON: got %s", got)
+	}
+}
+
+func TestPrettyResultIsEmpty(t *testing.T) {
+	tool := tools.NewEmptyResultTool(tools.NewPrettyPrintTool(resultTool{res: []any{}}), "No data found.")
+	got, err := tool.Invoke(context.Background(), nil, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got != tools.EmptyResult("No data found.") {
+		t.Fatalf("expected the empty result message, got %v", got)
+	}
+}
+
+func TestExtractPrettyPrint(t *testing.T) {
+	tcs := []struct {
+		name    string
+		in      map[string]any
+		want    bool
+		wantErr bool
+	}{
+		{
+			name: "not set",
+			in:   map[string]any{"kind": "some-kind"},
+			want: false,
+		},
+		{
+			name: "enabled",
+			in:   map[string]any{"kind": "some-kind", "prettyPrint": true},
+			want: true,
+		},
+		{
+			name:    "not a boolean",
+			in:      map[string]any{"kind": "some-kind", "prettyPrint": "yes"},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tools.ExtractPrettyPrint("my-tool", tc.in)
+			if err != nil {
+				if tc.wantErr {
+					return
+				}
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if tc.wantErr {
+				t.Fatalf("expected error but got nil")
+			}
+			if got != tc.want {
+				t.Fatalf("incorrect value: got %t, want %t", got, tc.want)
+			}
+			if diff := cmp.Diff(map[string]any{"kind": "some-kind"}, tc.in); diff != "" {
+				t.Fatalf("prettyPrint not removed from config: diff %v", diff)
+			}
+		})
+	}
+}
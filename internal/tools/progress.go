@@ -0,0 +1,44 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import "context"
+
+// ProgressFunc reports a step of a long-running invocation, e.g. that its
+// job was submitted, to the client.
+type ProgressFunc func(message string)
+
+type progressKey struct{}
+
+// WithProgress returns a context whose invocations report their progress to
+// f. The MCP server sets it for tool calls with a progress token.
+func WithProgress(ctx context.Context, f ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, f)
+}
+
+// ProgressRequested reports whether the client asked for the progress of the
+// invocation.
+func ProgressRequested(ctx context.Context) bool {
+	_, ok := ctx.Value(progressKey{}).(ProgressFunc)
+	return ok
+}
+
+// ReportProgress reports a step of the invocation to the client, if it asked
+// for progress. Tools call it at milestones of long-running operations.
+func ReportProgress(ctx context.Context, message string) {
+	if f, ok := ctx.Value(progressKey{}).(ProgressFunc); ok {
+		f(message)
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestReportProgress(t *testing.T) {
+	// without a ProgressFunc, progress is dropped
+	if tools.ProgressRequested(context.Background()) {
+		t.Fatalf("progress should not be requested by default")
+	}
+	tools.ReportProgress(context.Background(), "job submitted")
+
+	var got []string
+	ctx := tools.WithProgress(context.Background(), func(message string) {
+		got = append(got, message)
+	})
+	if !tools.ProgressRequested(ctx) {
+		t.Fatalf("progress should be requested")
+	}
+	tools.ReportProgress(ctx, "job submitted")
+	tools.ReportProgress(ctx, "job complete")
+	if diff := cmp.Diff([]string{"job submitted", "job complete"}, got); diff != "" {
+		t.Fatalf("incorrect progress: diff %v", diff)
+	}
+}
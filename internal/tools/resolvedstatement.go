@@ -0,0 +1,137 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+const includeResolvedStatementKey = "includeResolvedStatement"
+
+// StatementResolver is implemented by tools whose statement is a template, so
+// that the statement run for an invocation can be shown.
+type StatementResolver interface {
+	// ResolveStatement returns the statement with the template parameters
+	// substituted by their values in params.
+	ResolveStatement(params ParamValues) (string, error)
+}
+
+// ResolvedStatementResult is the result of a tool along with the statement it
+// ran.
+type ResolvedStatementResult struct {
+	Result            any    `json:"result"`
+	ResolvedStatement string `json:"resolvedStatement"`
+}
+
+// ExtractIncludeResolvedStatement removes the `includeResolvedStatement` field
+// from a raw tool config, so that the remaining config can be decoded strictly
+// by the tool kind.
+func ExtractIncludeResolvedStatement(toolName string, v map[string]any) (bool, error) {
+	raw, ok := v[includeResolvedStatementKey]
+	if !ok {
+		return false, nil
+	}
+	delete(v, includeResolvedStatementKey)
+	include, ok := raw.(bool)
+	if !ok {
+		return false, fmt.Errorf("%q must be a boolean for tool %q", includeResolvedStatementKey, toolName)
+	}
+	return include, nil
+}
+
+// ResolvedStatementConfig wraps a ToolConfig so that its results include the
+// resolved statement. The tool must be a StatementResolver.
+type ResolvedStatementConfig = WrapperConfig[ResolvedStatementOption]
+
+// validate interface
+var _ ToolReferencingConfig = ResolvedStatementConfig{}
+
+// ResolvedStatementOption is the option of a ResolvedStatementConfig, which
+// has no settings.
+type ResolvedStatementOption struct{}
+
+func (ResolvedStatementOption) wrap(c ToolConfig, t Tool) (Tool, error) {
+	if _, ok := t.(StatementResolver); !ok {
+		return nil, fmt.Errorf("%q is not supported by tools of kind %q", includeResolvedStatementKey, c.ToolConfigKind())
+	}
+	return NewResolvedStatementTool(t), nil
+}
+
+// ResolvedStatementTool wraps a StatementResolver so that its results are
+// returned as ResolvedStatementResults. The values of the parameters are
+// redacted from the statement.
+type ResolvedStatementTool struct {
+	Tool
+}
+
+// validate interface
+var _ WrapperTool = ResolvedStatementTool{}
+
+func (t ResolvedStatementTool) Unwrap() Tool {
+	return t.Tool
+}
+
+// NewResolvedStatementTool returns t wrapped to include the resolved statement
+// in its results. t must be a StatementResolver.
+func NewResolvedStatementTool(t Tool) ResolvedStatementTool {
+	return ResolvedStatementTool{Tool: t}
+}
+
+func (t ResolvedStatementTool) Invoke(ctx context.Context, params ParamValues, accessToken AccessToken) (any, error) {
+	// resolved before the tool runs, so that a failure can't discard the
+	// results of a query that already ran
+	stmt, err := resolveRedacted(t.Tool.(StatementResolver), params)
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve statement: %w", err)
+	}
+	res, err := t.Tool.Invoke(ctx, params, accessToken)
+	if err != nil {
+		return nil, err
+	}
+	return ResolvedStatementResult{Result: res, ResolvedStatement: stmt}, nil
+}
+
+// resolveRedacted resolves the statement of r with the value of each
+// parameter, or of each of its items, replaced by RedactedValue. The values
+// are first replaced by placeholders that pass the checks of identifier
+// parameters, which are then replaced in the resolved statement.
+func resolveRedacted(r StatementResolver, params ParamValues) (string, error) {
+	var oldnew []string
+	placeholder := func() string {
+		p := fmt.Sprintf("__redacted_%d__", len(oldnew)/2)
+		oldnew = append(oldnew, p, RedactedValue)
+		return p
+	}
+	masked := make(ParamValues, 0, len(params))
+	for _, p := range params {
+		if items, ok := p.Value.([]any); ok {
+			redacted := make([]any, len(items))
+			for i := range items {
+				redacted[i] = placeholder()
+			}
+			p.Value = redacted
+		} else if p.Value != nil {
+			p.Value = placeholder()
+		}
+		masked = append(masked, p)
+	}
+	stmt, err := r.ResolveStatement(masked)
+	if err != nil {
+		return "", err
+	}
+	return strings.NewReplacer(oldnew...).Replace(stmt), nil
+}
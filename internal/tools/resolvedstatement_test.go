@@ -0,0 +1,199 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// templateTool is a StatementResolver like the tools with template parameters.
+type templateTool struct {
+	resultTool
+	templateParams tools.Parameters
+	statement      string
+}
+
+func (t templateTool) ResolveStatement(params tools.ParamValues) (string, error) {
+	return tools.ResolveTemplateParams(t.templateParams, t.statement, params.AsMap())
+}
+
+func TestResolvedStatementTool(t *testing.T) {
+	rows := []any{map[string]any{"id": 1}}
+	tool := templateTool{
+		resultTool: resultTool{res: rows},
+		templateParams: tools.Parameters{
+			tools.NewStringParameter("tableName", "some description"),
+			tools.NewArrayParameter("columnNames", "some description", tools.NewStringParameter("column", "some description")),
+		},
+		statement: "SELECT {{array .columnNames}} FROM {{.tableName}} WHERE id = $1",
+	}
+	tcs := []struct {
+		desc   string
+		params tools.ParamValues
+		want   any
+	}{
+		{
+			desc: "values are redacted",
+			params: tools.ParamValues{
+				{Name: "id", Value: 1},
+				{Name: "tableName", Value: "flights"},
+				{Name: "columnNames", Value: []any{"id", "name"}},
+			},
+			want: tools.ResolvedStatementResult{
+				Result:            rows,
+				ResolvedStatement: "SELECT ***, *** FROM *** WHERE id = $1",
+			},
+		},
+		{
+			desc: "sensitive values are redacted",
+			params: tools.ParamValues{
+				{Name: "id", Value: 1, Sensitive: true},
+				{Name: "tableName", Value: "secret_flights", Sensitive: true},
+				{Name: "columnNames", Value: []any{"id", "name"}, Sensitive: true},
+			},
+			want: tools.ResolvedStatementResult{
+				Result:            rows,
+				ResolvedStatement: "SELECT ***, *** FROM *** WHERE id = $1",
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := tools.NewResolvedStatementTool(tool).Invoke(context.Background(), tc.params, "")
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect result: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestResolvedStatementToolSensitiveIdentifier(t *testing.T) {
+	rows := []any{map[string]any{"id": 1}}
+	tableName := tools.NewStringParameter("tableName", "some description")
+	tableName.Identifier = true
+	tableName.Sensitive = true
+	tool := templateTool{
+		resultTool:     resultTool{res: rows},
+		templateParams: tools.Parameters{tableName},
+		statement:      "SELECT * FROM {{.tableName}}",
+	}
+	params := tools.ParamValues{{Name: "tableName", Value: "`my-project`.secret.flights", Sensitive: true}}
+	got, err := tools.NewResolvedStatementTool(tool).Invoke(context.Background(), params, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := tools.ResolvedStatementResult{Result: rows, ResolvedStatement: "SELECT * FROM ***"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("incorrect result: diff %v", diff)
+	}
+}
+
+// uninvokableTool fails the test if it is invoked.
+type uninvokableTool struct {
+	templateTool
+	t *testing.T
+}
+
+func (u uninvokableTool) Invoke(context.Context, tools.ParamValues, tools.AccessToken) (any, error) {
+	u.t.Fatalf("tool invoked although its statement can't be resolved")
+	return nil, nil
+}
+
+func TestResolvedStatementToolResolvesFirst(t *testing.T) {
+	tool := uninvokableTool{
+		templateTool: templateTool{
+			templateParams: tools.Parameters{tools.NewStringParameter("tableName", "some description")},
+			statement:      "SELECT * FROM {{.tableName}",
+		},
+		t: t,
+	}
+	params := tools.ParamValues{{Name: "tableName", Value: "flights"}}
+	if _, err := tools.NewResolvedStatementTool(tool).Invoke(context.Background(), params, ""); err == nil {
+		t.Fatalf("expected an error for a statement that can't be resolved")
+	}
+}
+
+func TestResolvedStatementToolEmptyResult(t *testing.T) {
+	const msg = "No data found."
+	tool := templateTool{
+		resultTool:     resultTool{res: []any{}},
+		templateParams: tools.Parameters{tools.NewStringParameter("tableName", "some description")},
+		statement:      "SELECT * FROM {{.tableName}}",
+	}
+	params := tools.ParamValues{{Name: "tableName", Value: "flights"}}
+	got, err := tools.NewEmptyResultTool(tools.NewResolvedStatementTool(tool), msg).Invoke(context.Background(), params, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := tools.ResolvedStatementResult{
+		Result:            tools.EmptyResult(msg),
+		ResolvedStatement: "SELECT * FROM ***",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("incorrect result: diff %v", diff)
+	}
+}
+
+func TestExtractIncludeResolvedStatement(t *testing.T) {
+	tcs := []struct {
+		name    string
+		in      map[string]any
+		want    bool
+		wantErr bool
+	}{
+		{
+			name: "not set",
+			in:   map[string]any{"kind": "some-kind"},
+			want: false,
+		},
+		{
+			name: "enabled",
+			in:   map[string]any{"kind": "some-kind", "includeResolvedStatement": true},
+			want: true,
+		},
+		{
+			name:    "not a boolean",
+			in:      map[string]any{"kind": "some-kind", "includeResolvedStatement": "yes"},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tools.ExtractIncludeResolvedStatement("my-tool", tc.in)
+			if err != nil {
+				if tc.wantErr {
+					return
+				}
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if tc.wantErr {
+				t.Fatalf("expected error but got nil")
+			}
+			if got != tc.want {
+				t.Fatalf("incorrect value: got %v, want %v", got, tc.want)
+			}
+			if diff := cmp.Diff(map[string]any{"kind": "some-kind"}, tc.in); diff != "" {
+				t.Fatalf("includeResolvedStatement not removed from config: diff %v", diff)
+			}
+		})
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/genai-toolbox/internal/server"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/tools/spanner/spannerexecutesql"
 )
 
@@ -44,13 +45,15 @@ func TestParseFromYamlExecuteSql(t *testing.T) {
 					description: some description
 			`,
 			want: server.ToolConfigs{
-				"example_tool": spannerexecutesql.Config{
-					Name:         "example_tool",
-					Kind:         "spanner-execute-sql",
-					Source:       "my-spanner-instance",
-					Description:  "some description",
-					AuthRequired: []string{},
-					ReadOnly:     false,
+				"example_tool": tools.StatementLengthConfig{
+					ToolConfig: spannerexecutesql.Config{
+						Name:         "example_tool",
+						Kind:         "spanner-execute-sql",
+						Source:       "my-spanner-instance",
+						Description:  "some description",
+						AuthRequired: []string{},
+						ReadOnly:     false,
+					},
 				},
 			},
 		},
@@ -65,13 +68,15 @@ func TestParseFromYamlExecuteSql(t *testing.T) {
 					readOnly: true
 			`,
 			want: server.ToolConfigs{
-				"example_tool": spannerexecutesql.Config{
-					Name:         "example_tool",
-					Kind:         "spanner-execute-sql",
-					Source:       "my-spanner-instance",
-					Description:  "some description",
-					AuthRequired: []string{},
-					ReadOnly:     true,
+				"example_tool": tools.StatementLengthConfig{
+					ToolConfig: spannerexecutesql.Config{
+						Name:         "example_tool",
+						Kind:         "spanner-execute-sql",
+						Source:       "my-spanner-instance",
+						Description:  "some description",
+						AuthRequired: []string{},
+						ReadOnly:     true,
+					},
 				},
 			},
 		},
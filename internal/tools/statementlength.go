@@ -0,0 +1,151 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// StatementParameter is the parameter that execute-sql tools take the
+	// statement to run in.
+	StatementParameter = "sql"
+	// DefaultMaxStatementLength is the default limit, in characters, of the
+	// statements that tools are asked to run.
+	DefaultMaxStatementLength = 100000
+
+	maxStatementLengthKey = "maxStatementLength"
+)
+
+// ExtractMaxStatementLength removes the `maxStatementLength` field from a raw
+// tool config, so that the remaining config can be decoded strictly by the
+// tool kind. Zero is returned if it is not set.
+func ExtractMaxStatementLength(toolName string, v map[string]any) (int, error) {
+	raw, ok := v[maxStatementLengthKey]
+	if !ok {
+		return 0, nil
+	}
+	delete(v, maxStatementLengthKey)
+
+	var length int
+	switch n := raw.(type) {
+	case int:
+		length = n
+	case int64:
+		length = int(n)
+	case uint64:
+		length = int(n)
+	case float64:
+		if n != float64(int(n)) {
+			return 0, fmt.Errorf("%q must be an integer for tool %q", maxStatementLengthKey, toolName)
+		}
+		length = int(n)
+	default:
+		return 0, fmt.Errorf("%q must be an integer for tool %q", maxStatementLengthKey, toolName)
+	}
+	if length <= 0 {
+		return 0, fmt.Errorf("%q must be positive for tool %q", maxStatementLengthKey, toolName)
+	}
+	return length, nil
+}
+
+// StatementConfig is implemented by the configs of tools that run a statement
+// written by the caller in their StatementParameter, such as execute-sql
+// tools. Only these tools are limited in the length of their statements.
+type StatementConfig interface {
+	ToolConfig
+	TakesStatement()
+}
+
+// StatementLengthConfig wraps the ToolConfig of a tool that takes a statement
+// with the maximum length of that statement.
+type StatementLengthConfig = WrapperConfig[StatementLengthOption]
+
+// validate interface
+var _ ToolReferencingConfig = StatementLengthConfig{}
+
+// StatementLengthOption is the option of a StatementLengthConfig. A MaxLength
+// of zero stands for the server default.
+type StatementLengthOption struct {
+	MaxLength int
+}
+
+func (o StatementLengthOption) wrap(_ ToolConfig, t Tool) (Tool, error) {
+	if o.MaxLength == 0 {
+		return t, nil
+	}
+	return NewStatementLengthTool(t, o.MaxLength), nil
+}
+
+// StatementLengthTool wraps a Tool so that ParseParams fails for statements
+// in the StatementParameter longer than MaxLength characters, before the tool
+// makes any call to its source.
+type StatementLengthTool struct {
+	Tool
+	MaxLength int
+}
+
+// validate interface
+var _ WrapperTool = StatementLengthTool{}
+
+func (t StatementLengthTool) Unwrap() Tool {
+	return t.Tool
+}
+
+// NewStatementLengthTool returns t wrapped to reject statements longer than
+// maxLength characters.
+func NewStatementLengthTool(t Tool, maxLength int) StatementLengthTool {
+	return StatementLengthTool{Tool: t, MaxLength: maxLength}
+}
+
+func (t StatementLengthTool) ParseParams(data map[string]any, claims map[string]map[string]any) (ParamValues, error) {
+	if stmt, ok := data[StatementParameter].(string); ok {
+		if n := utf8.RuneCountInString(stmt); n > t.MaxLength {
+			return nil, fmt.Errorf("statement is %d characters long, which exceeds the maximum of %d", n, t.MaxLength)
+		}
+	}
+	return t.Tool.ParseParams(data, claims)
+}
+
+// statementKeywords are the first keywords that DescribeStatement reports as
+// the type of a statement.
+var statementKeywords = map[string]bool{
+	"ALTER": true, "BEGIN": true, "CALL": true, "COMMIT": true, "CREATE": true,
+	"DECLARE": true, "DELETE": true, "DESCRIBE": true, "DROP": true, "EXEC": true,
+	"EXECUTE": true, "EXPLAIN": true, "GRANT": true, "INSERT": true, "MERGE": true,
+	"PRAGMA": true, "REPLACE": true, "REVOKE": true, "ROLLBACK": true, "SELECT": true,
+	"SET": true, "SHOW": true, "TRUNCATE": true, "UPDATE": true, "UPSERT": true,
+	"USE": true, "VALUES": true, "WITH": true,
+}
+
+// DescribeStatement describes a statement by its type and length, so that
+// tools can log the statements they run without the values they may hold. The
+// type is the first keyword of the statement if it is a known SQL keyword,
+// and OTHER otherwise.
+func DescribeStatement(statement string) string {
+	first := strings.TrimLeft(statement, "( \t\r\n")
+	end := strings.IndexFunc(first, func(r rune) bool { return !unicode.IsLetter(r) })
+	if end >= 0 {
+		first = first[:end]
+	}
+	statementType := strings.ToUpper(first)
+	if !statementKeywords[statementType] {
+		statementType = "OTHER"
+	}
+	return fmt.Sprintf("%s statement of %d characters", statementType, utf8.RuneCountInString(statement))
+}
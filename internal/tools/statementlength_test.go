@@ -0,0 +1,151 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestStatementLengthTool(t *testing.T) {
+	inner := paramsTool{params: tools.Parameters{tools.NewStringParameter("sql", "the statement to run")}}
+	tool := tools.NewStatementLengthTool(inner, 20)
+
+	tcs := []struct {
+		desc    string
+		sql     string
+		wantErr bool
+	}{
+		{
+			desc: "under the limit",
+			sql:  "SELECT 1",
+		},
+		{
+			desc: "at the limit",
+			sql:  "SELECT 'abcdefghijk'",
+		},
+		{
+			desc: "multi-byte characters count once",
+			sql:  "SELECT 'ééééééééé'",
+		},
+		{
+			desc:    "over the limit",
+			sql:     "SELECT " + strings.Repeat("1 + ", 10) + "1",
+			wantErr: true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := tool.ParseParams(map[string]any{"sql": tc.sql}, nil)
+			if err != nil {
+				if tc.wantErr {
+					if !strings.Contains(err.Error(), "exceeds the maximum of 20") {
+						t.Fatalf("unexpected error: %s", err)
+					}
+					return
+				}
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if tc.wantErr {
+				t.Fatalf("expected error but got nil")
+			}
+			want := tools.ParamValues{{Name: "sql", Value: tc.sql}}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Fatalf("incorrect params: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestStatementLengthToolWithoutStatement(t *testing.T) {
+	// tools without a sql parameter are unaffected
+	inner := paramsTool{params: tools.Parameters{tools.NewStringParameter("name", "a name")}}
+	tool := tools.NewStatementLengthTool(inner, 5)
+	if _, err := tool.ParseParams(map[string]any{"name": "a long name"}, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+func TestExtractMaxStatementLength(t *testing.T) {
+	tcs := []struct {
+		name    string
+		in      map[string]any
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "not set",
+			in:   map[string]any{"kind": "some-kind"},
+			want: 0,
+		},
+		{
+			name: "length",
+			in:   map[string]any{"kind": "some-kind", "maxStatementLength": uint64(5000)},
+			want: 5000,
+		},
+		{
+			name:    "zero",
+			in:      map[string]any{"kind": "some-kind", "maxStatementLength": uint64(0)},
+			wantErr: true,
+		},
+		{
+			name:    "not an integer",
+			in:      map[string]any{"kind": "some-kind", "maxStatementLength": "long"},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tools.ExtractMaxStatementLength("my-tool", tc.in)
+			if err != nil {
+				if tc.wantErr {
+					return
+				}
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if tc.wantErr {
+				t.Fatalf("expected error but got nil")
+			}
+			if got != tc.want {
+				t.Fatalf("incorrect length: got %d, want %d", got, tc.want)
+			}
+			if diff := cmp.Diff(map[string]any{"kind": "some-kind"}, tc.in); diff != "" {
+				t.Fatalf("maxStatementLength not removed from config: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestDescribeStatement(t *testing.T) {
+	tcs := []struct {
+		statement string
+		want      string
+	}{
+		{statement: "SELECT * FROM users", want: "SELECT statement of 19 characters"},
+		{statement: "  (select 1)", want: "SELECT statement of 12 characters"},
+		{statement: "insert into t values ('é')", want: "INSERT statement of 26 characters"},
+		{statement: "-- secret\nSELECT 1", want: "OTHER statement of 18 characters"},
+		{statement: "hunter2", want: "OTHER statement of 7 characters"},
+		{statement: "", want: "OTHER statement of 0 characters"},
+	}
+	for _, tc := range tcs {
+		if got := tools.DescribeStatement(tc.statement); got != tc.want {
+			t.Errorf("DescribeStatement(%q) = %q, want %q", tc.statement, got, tc.want)
+		}
+	}
+}
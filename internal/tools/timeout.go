@@ -0,0 +1,115 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// invokeTimeoutKey is not `timeout`, since some tool kinds already use that
+// field for their own settings.
+const invokeTimeoutKey = "invokeTimeout"
+
+// ErrToolTimeout is returned when an invocation doesn't complete within the
+// tool's invoke timeout.
+var ErrToolTimeout = errors.New("tool invocation timed out")
+
+// ExtractInvokeTimeout removes the `invokeTimeout` field from a raw tool
+// config, so that the remaining config can be decoded strictly by the tool
+// kind. Zero is returned if it is not set.
+func ExtractInvokeTimeout(toolName string, v map[string]any) (time.Duration, error) {
+	raw, ok := v[invokeTimeoutKey]
+	if !ok {
+		return 0, nil
+	}
+	delete(v, invokeTimeoutKey)
+
+	s, ok := raw.(string)
+	if !ok {
+		return 0, fmt.Errorf("%q must be a duration string, e.g. \"30s\", for tool %q", invokeTimeoutKey, toolName)
+	}
+	timeout, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %q for tool %q: %w", invokeTimeoutKey, toolName, err)
+	}
+	if timeout <= 0 {
+		return 0, fmt.Errorf("%q must be positive for tool %q", invokeTimeoutKey, toolName)
+	}
+	return timeout, nil
+}
+
+// TimeoutConfig wraps a ToolConfig with an invoke timeout.
+type TimeoutConfig = WrapperConfig[TimeoutOption]
+
+// validate interface
+var _ ToolReferencingConfig = TimeoutConfig{}
+
+// TimeoutOption is the option of a TimeoutConfig.
+type TimeoutOption struct {
+	Timeout time.Duration
+}
+
+func (o TimeoutOption) wrap(_ ToolConfig, t Tool) (Tool, error) {
+	return NewTimeoutTool(t, o.Timeout), nil
+}
+
+// TimeoutTool wraps a Tool so that invocations are cancelled after Timeout. The
+// caller gets ErrToolTimeout as soon as the timeout fires, even if the tool
+// doesn't stop when its context is cancelled.
+type TimeoutTool struct {
+	Tool
+	Timeout time.Duration
+}
+
+// validate interface
+var _ WrapperTool = TimeoutTool{}
+
+func (t TimeoutTool) Unwrap() Tool {
+	return t.Tool
+}
+
+// NewTimeoutTool returns t wrapped with the given invoke timeout.
+func NewTimeoutTool(t Tool, timeout time.Duration) TimeoutTool {
+	return TimeoutTool{Tool: t, Timeout: timeout}
+}
+
+func (t TimeoutTool) Invoke(ctx context.Context, params ParamValues, accessToken AccessToken) (any, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.Timeout)
+	defer cancel()
+
+	type result struct {
+		res any
+		err error
+	}
+	// buffered, so that the invocation can finish after the timeout fired
+	done := make(chan result, 1)
+	go func() {
+		res, err := t.Tool.Invoke(ctx, params, accessToken)
+		done <- result{res, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.res, r.err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%w after %s", ErrToolTimeout, t.Timeout)
+		}
+		return nil, ctx.Err()
+	}
+}
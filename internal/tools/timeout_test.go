@@ -0,0 +1,113 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestTimeoutTool(t *testing.T) {
+	tool := tools.NewTimeoutTool(newCountingTool(10*time.Millisecond), time.Second)
+	got, err := tool.Invoke(context.Background(), nil, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got != "ok" {
+		t.Fatalf("unexpected result: got %v, want %q", got, "ok")
+	}
+}
+
+func TestTimeoutToolExpired(t *testing.T) {
+	// countingTool sleeps without watching its context
+	tool := tools.NewTimeoutTool(newCountingTool(time.Second), 20*time.Millisecond)
+
+	start := time.Now()
+	_, err := tool.Invoke(context.Background(), nil, "")
+	if !errors.Is(err, tools.ErrToolTimeout) {
+		t.Fatalf("expected a timeout error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("invocation did not return promptly after the timeout, took %s", elapsed)
+	}
+}
+
+func TestTimeoutToolCancelled(t *testing.T) {
+	tool := tools.NewTimeoutTool(newCountingTool(time.Second), time.Minute)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+	if _, err := tool.Invoke(ctx, nil, ""); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected a cancellation error, got %v", err)
+	}
+}
+
+func TestExtractInvokeTimeout(t *testing.T) {
+	tcs := []struct {
+		name    string
+		in      map[string]any
+		want    time.Duration
+		wantErr bool
+	}{
+		{
+			name: "not set",
+			in:   map[string]any{"kind": "some-kind"},
+			want: 0,
+		},
+		{
+			name: "duration",
+			in:   map[string]any{"kind": "some-kind", "invokeTimeout": "30s"},
+			want: 30 * time.Second,
+		},
+		{
+			name:    "not a duration",
+			in:      map[string]any{"kind": "some-kind", "invokeTimeout": "soon"},
+			wantErr: true,
+		},
+		{
+			name:    "negative",
+			in:      map[string]any{"kind": "some-kind", "invokeTimeout": "-1s"},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tools.ExtractInvokeTimeout("my-tool", tc.in)
+			if err != nil {
+				if tc.wantErr {
+					return
+				}
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if tc.wantErr {
+				t.Fatalf("expected error but got nil")
+			}
+			if got != tc.want {
+				t.Fatalf("incorrect timeout: got %s, want %s", got, tc.want)
+			}
+			if diff := cmp.Diff(map[string]any{"kind": "some-kind"}, tc.in); diff != "" {
+				t.Fatalf("invokeTimeout not removed from config: diff %v", diff)
+			}
+		})
+	}
+}
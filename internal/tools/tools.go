@@ -65,6 +65,34 @@ type ToolConfig interface {
 	Initialize(map[string]sources.Source) (Tool, error)
 }
 
+// ToolReferencingConfig is implemented by tool configs that are built on top of
+// other configured tools. The tools it references are initialized first and
+// passed to InitializeWithTools.
+type ToolReferencingConfig interface {
+	ToolConfig
+	ReferencedTools() []string
+	InitializeWithTools(map[string]sources.Source, map[string]Tool) (Tool, error)
+}
+
+// ReferencedTools returns the names of the tools that c references, if any.
+func ReferencedTools(c ToolConfig) []string {
+	rc, ok := c.(ToolReferencingConfig)
+	if !ok {
+		return nil
+	}
+	return rc.ReferencedTools()
+}
+
+// InitializeWithTools initializes c with the tools it references, falling back
+// to Initialize for configs that do not reference other tools.
+func InitializeWithTools(c ToolConfig, srcs map[string]sources.Source, tls map[string]Tool) (Tool, error) {
+	rc, ok := c.(ToolReferencingConfig)
+	if !ok {
+		return c.Initialize(srcs)
+	}
+	return rc.InitializeWithTools(srcs, tls)
+}
+
 type AccessToken string
 
 func (token AccessToken) ParseBearerToken() (string, error) {
@@ -99,6 +127,8 @@ type McpManifest struct {
 	Description string `json:"description,omitempty"`
 	// A JSON Schema object defining the expected parameters for the tool.
 	InputSchema McpToolsSchema `json:"inputSchema,omitempty"`
+	// Example invocations of the tool, given to the model as few-shot hints.
+	Examples []Example `json:"examples,omitempty"`
 }
 
 var ErrUnauthorized = errors.New("unauthorized")
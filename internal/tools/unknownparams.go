@@ -0,0 +1,94 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"fmt"
+	"sort"
+)
+
+const disallowUnknownFieldsKey = "disallowUnknownFields"
+
+// ExtractDisallowUnknownFields removes the `disallowUnknownFields` field from a
+// raw tool config, so that the remaining config can be decoded strictly by the
+// tool kind.
+func ExtractDisallowUnknownFields(toolName string, v map[string]any) (bool, error) {
+	raw, ok := v[disallowUnknownFieldsKey]
+	if !ok {
+		return false, nil
+	}
+	delete(v, disallowUnknownFieldsKey)
+	disallow, ok := raw.(bool)
+	if !ok {
+		return false, fmt.Errorf("%q must be a boolean for tool %q", disallowUnknownFieldsKey, toolName)
+	}
+	return disallow, nil
+}
+
+// DisallowUnknownConfig wraps a ToolConfig so that arguments that aren't
+// parameters of the tool are rejected.
+type DisallowUnknownConfig = WrapperConfig[DisallowUnknownOption]
+
+// validate interface
+var _ ToolReferencingConfig = DisallowUnknownConfig{}
+
+// DisallowUnknownOption is the option of a DisallowUnknownConfig, which has no
+// settings.
+type DisallowUnknownOption struct{}
+
+func (DisallowUnknownOption) wrap(_ ToolConfig, t Tool) (Tool, error) {
+	return NewDisallowUnknownTool(t), nil
+}
+
+// DisallowUnknownTool wraps a Tool so that ParseParams fails for arguments
+// that aren't parameters of the tool, instead of ignoring them.
+type DisallowUnknownTool struct {
+	Tool
+	params map[string]bool
+}
+
+// validate interface
+var _ WrapperTool = DisallowUnknownTool{}
+
+func (t DisallowUnknownTool) Unwrap() Tool {
+	return t.Tool
+}
+
+// NewDisallowUnknownTool returns t wrapped to reject unknown arguments.
+func NewDisallowUnknownTool(t Tool) DisallowUnknownTool {
+	params := make(map[string]bool)
+	for _, p := range t.Manifest().Parameters {
+		params[p.Name] = true
+	}
+	return DisallowUnknownTool{Tool: t, params: params}
+}
+
+func (t DisallowUnknownTool) ParseParams(data map[string]any, claims map[string]map[string]any) (ParamValues, error) {
+	var unknown []string
+	for name := range data {
+		if !t.params[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	switch len(unknown) {
+	case 0:
+		return t.Tool.ParseParams(data, claims)
+	case 1:
+		return nil, fmt.Errorf("unknown parameter %q", unknown[0])
+	default:
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("unknown parameters %q", unknown)
+	}
+}
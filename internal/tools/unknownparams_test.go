@@ -0,0 +1,114 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestDisallowUnknownTool(t *testing.T) {
+	tool := tools.NewDisallowUnknownTool(paramsTool{params: tools.Parameters{
+		tools.NewIntParameterWithRequired("limit", "The maximum number of rows.", false),
+		tools.NewStringParameterWithRequired("name", "The name to search for.", false),
+	}})
+
+	tcs := []struct {
+		desc    string
+		in      map[string]any
+		want    tools.ParamValues
+		wantErr string
+	}{
+		{
+			desc: "known parameters",
+			in:   map[string]any{"limit": 10, "name": "foo"},
+			want: tools.ParamValues{{Name: "limit", Value: 10}, {Name: "name", Value: "foo"}},
+		},
+		{
+			desc:    "unknown parameter",
+			in:      map[string]any{"limit": 10, "nmae": "foo"},
+			wantErr: `unknown parameter "nmae"`,
+		},
+		{
+			desc:    "several unknown parameters",
+			in:      map[string]any{"limit": 10, "nmae": "foo", "offset": 5},
+			wantErr: `unknown parameters ["nmae" "offset"]`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := tool.ParseParams(tc.in, nil)
+			if tc.wantErr != "" {
+				if err == nil || err.Error() != tc.wantErr {
+					t.Fatalf("incorrect error: got %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect params: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestExtractDisallowUnknownFields(t *testing.T) {
+	tcs := []struct {
+		name    string
+		in      map[string]any
+		want    bool
+		wantErr bool
+	}{
+		{
+			name: "not set",
+			in:   map[string]any{"kind": "some-kind"},
+			want: false,
+		},
+		{
+			name: "enabled",
+			in:   map[string]any{"kind": "some-kind", "disallowUnknownFields": true},
+			want: true,
+		},
+		{
+			name:    "not a boolean",
+			in:      map[string]any{"kind": "some-kind", "disallowUnknownFields": "yes"},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tools.ExtractDisallowUnknownFields("my-tool", tc.in)
+			if err != nil {
+				if tc.wantErr {
+					return
+				}
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if tc.wantErr {
+				t.Fatalf("expected error but got nil")
+			}
+			if got != tc.want {
+				t.Fatalf("incorrect value: got %t, want %t", got, tc.want)
+			}
+			if diff := cmp.Diff(map[string]any{"kind": "some-kind"}, tc.in); diff != "" {
+				t.Fatalf("disallowUnknownFields not removed from config: diff %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,279 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/utility/stepcommon"
+)
+
+const kind string = "branch"
+
+// operators are the comparisons a condition can make.
+var operators = []string{"==", "!=", ">", ">=", "<", "<="}
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+// Condition compares a field of the first row of an output with Value. If
+// Field is empty, the number of rows of the output is compared instead.
+type Condition struct {
+	Field    string `yaml:"field"`
+	Operator string `yaml:"operator" validate:"required"`
+	Value    any    `yaml:"value"`
+}
+
+type Config struct {
+	Name         string          `yaml:"name" validate:"required"`
+	Kind         string          `yaml:"kind" validate:"required"`
+	Description  string          `yaml:"description" validate:"required"`
+	Tool         string          `yaml:"tool" validate:"required"`
+	Condition    Condition       `yaml:"condition" validate:"required"`
+	Then         stepcommon.Step `yaml:"then" validate:"required"`
+	Else         stepcommon.Step `yaml:"else" validate:"required"`
+	AuthRequired []string        `yaml:"authRequired"`
+}
+
+// validate interface
+var _ tools.ToolReferencingConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+func (cfg Config) Initialize(_ map[string]sources.Source) (tools.Tool, error) {
+	return nil, fmt.Errorf("%q tools must be initialized with the tools they reference", kind)
+}
+
+func (cfg Config) ReferencedTools() []string {
+	return []string{cfg.Tool, cfg.Then.Tool, cfg.Else.Tool}
+}
+
+func (cfg Config) InitializeWithTools(_ map[string]sources.Source, tls map[string]tools.Tool) (tools.Tool, error) {
+	if err := cfg.Condition.validate(); err != nil {
+		return nil, fmt.Errorf("invalid condition: %w", err)
+	}
+	condTool, ok := tls[cfg.Tool]
+	if !ok {
+		return nil, fmt.Errorf("no tool named %q configured", cfg.Tool)
+	}
+	thenStep, err := stepcommon.Bind(cfg.Then, tls)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %q branch: %w", "then", err)
+	}
+	elseStep, err := stepcommon.Bind(cfg.Else, tls)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %q branch: %w", "else", err)
+	}
+
+	mcpManifest := tools.McpManifest{
+		Name:        cfg.Name,
+		Description: cfg.Description,
+		InputSchema: condTool.McpManifest().InputSchema,
+	}
+
+	t := Tool{
+		Name:         cfg.Name,
+		Kind:         kind,
+		AuthRequired: cfg.AuthRequired,
+		Condition:    cfg.Condition,
+		condName:     cfg.Tool,
+		condTool:     condTool,
+		thenStep:     thenStep,
+		elseStep:     elseStep,
+		manifest:     tools.Manifest{Description: cfg.Description, Parameters: condTool.Manifest().Parameters, AuthRequired: cfg.AuthRequired},
+		mcpManifest:  mcpManifest,
+	}
+	return t, nil
+}
+
+// validate checks that the operator is known and that the value can be
+// compared with it.
+func (c Condition) validate() error {
+	if !slices.Contains(operators, c.Operator) {
+		return fmt.Errorf("operator %q must be one of %q", c.Operator, operators)
+	}
+	_, isNumber := toFloat(c.Value)
+	if c.Field == "" && !isNumber {
+		return fmt.Errorf("value %v must be a number to compare with the row count", c.Value)
+	}
+	if _, isString := c.Value.(string); !isNumber && !isString && c.Operator != "==" && c.Operator != "!=" {
+		return fmt.Errorf("value %v must be a number or a string to use operator %q", c.Value, c.Operator)
+	}
+	return nil
+}
+
+// evaluate reports whether the condition holds for the given output rows. It
+// is false if the output has no rows to read the field from.
+func (c Condition) evaluate(rows []map[string]any) (bool, error) {
+	var v any = len(rows)
+	if c.Field != "" {
+		if len(rows) == 0 {
+			return false, nil
+		}
+		var ok bool
+		v, ok = rows[0][c.Field]
+		if !ok {
+			return false, fmt.Errorf("field %q not found in the output", c.Field)
+		}
+	}
+
+	if a, ok := toFloat(v); ok {
+		if b, ok := toFloat(c.Value); ok {
+			return compare(a, b, c.Operator), nil
+		}
+	}
+	if a, ok := v.(string); ok {
+		if b, ok := c.Value.(string); ok {
+			return compare(a, b, c.Operator), nil
+		}
+	}
+	switch c.Operator {
+	case "==":
+		return reflect.DeepEqual(v, c.Value), nil
+	case "!=":
+		return !reflect.DeepEqual(v, c.Value), nil
+	default:
+		return false, fmt.Errorf("unable to compare %v with %v using %q", v, c.Value, c.Operator)
+	}
+}
+
+func compare[T float64 | string](a, b T, operator string) bool {
+	switch operator {
+	case "==":
+		return a == b
+	case "!=":
+		return a != b
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	case "<":
+		return a < b
+	default:
+		return a <= b
+	}
+}
+
+// toFloat converts the numbers found in configs and tool outputs to float64.
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float64:
+		return n, true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name         string    `yaml:"name"`
+	Kind         string    `yaml:"kind"`
+	AuthRequired []string  `yaml:"authRequired"`
+	Condition    Condition `yaml:"condition"`
+
+	condName    string
+	condTool    tools.Tool
+	thenStep    stepcommon.BoundStep
+	elseStep    stepcommon.BoundStep
+	manifest    tools.Manifest
+	mcpManifest tools.McpManifest
+}
+
+func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
+	out, err := t.condTool.Invoke(ctx, params, accessToken)
+	if err != nil {
+		return nil, fmt.Errorf("tool %q failed: %w", t.condName, err)
+	}
+	rows, err := stepcommon.OutputRows(out)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the output of tool %q: %w", t.condName, err)
+	}
+	ok, err := t.Condition.evaluate(rows)
+	if err != nil {
+		return nil, fmt.Errorf("unable to evaluate condition: %w", err)
+	}
+
+	s := t.elseStep
+	if ok {
+		s = t.thenStep
+	}
+	stepParams, err := s.ParseParams(out)
+	if err != nil {
+		return nil, fmt.Errorf("invalid inputs for tool %q: %w", s.Name, err)
+	}
+	res, err := s.Tool.Invoke(ctx, stepParams, accessToken)
+	if err != nil {
+		return nil, fmt.Errorf("tool %q failed: %w", s.Name, err)
+	}
+	return res, nil
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return t.condTool.ParseParams(data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+// Authorized requires the caller to be authorized for the branch and for every
+// tool it may run.
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices) &&
+		t.condTool.Authorized(verifiedAuthServices) &&
+		t.thenStep.Tool.Authorized(verifiedAuthServices) &&
+		t.elseStep.Tool.Authorized(verifiedAuthServices)
+}
+
+func (t Tool) RequiresClientAuthorization() bool {
+	return t.condTool.RequiresClientAuthorization() ||
+		t.thenStep.Tool.RequiresClientAuthorization() ||
+		t.elseStep.Tool.RequiresClientAuthorization()
+}
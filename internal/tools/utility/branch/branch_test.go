@@ -0,0 +1,270 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_test
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/sources/sqlite"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/sqlite/sqlitesql"
+	"github.com/googleapis/genai-toolbox/internal/tools/utility/branch"
+	"github.com/googleapis/genai-toolbox/internal/tools/utility/stepcommon"
+	_ "modernc.org/sqlite"
+)
+
+func TestParseFromYamlBranch(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		want server.ToolConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			tools:
+				example_tool:
+					kind: branch
+					description: some description
+					tool: find_user
+					condition:
+						operator: ">"
+						value: 0
+					then:
+						tool: list_orders
+						inputs:
+							user_id: id
+					else:
+						tool: list_users
+			`,
+			want: server.ToolConfigs{
+				"example_tool": branch.Config{
+					Name:         "example_tool",
+					Kind:         "branch",
+					Description:  "some description",
+					Tool:         "find_user",
+					Condition:    branch.Condition{Operator: ">", Value: uint64(0)},
+					Then:         stepcommon.Step{Tool: "list_orders", Inputs: map[string]string{"user_id": "id"}},
+					Else:         stepcommon.Step{Tool: "list_users"},
+					AuthRequired: []string{},
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			// Parse contents
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Tools); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+}
+
+// setUpSQLTools returns three sqlite-sql tools: find_user looks up the id of a
+// user by name, list_orders lists the orders of a user id and list_users lists
+// the names of all users.
+func setUpSQLTools(t *testing.T) map[string]tools.Tool {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("unable to open in-memory database: %s", err)
+	}
+	// every connection to :memory: opens a new database
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	setup := `
+	CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT);
+	CREATE TABLE orders (id INTEGER PRIMARY KEY, user_id INTEGER, item TEXT);
+	INSERT INTO users (id, name) VALUES (1, 'Alice'), (2, 'Bob');
+	INSERT INTO orders (id, user_id, item) VALUES (1, 1, 'book'), (2, 2, 'pen'), (3, 1, 'lamp');`
+	if _, err := db.Exec(setup); err != nil {
+		t.Fatalf("unable to set up database: %s", err)
+	}
+
+	srcs := map[string]sources.Source{"my-sqlite": &sqlite.Source{Name: "my-sqlite", Kind: sqlite.SourceKind, Db: db}}
+	cfgs := []sqlitesql.Config{
+		{
+			Name:        "find_user",
+			Kind:        "sqlite-sql",
+			Source:      "my-sqlite",
+			Description: "Find a user by name.",
+			Statement:   "SELECT id FROM users WHERE name = ?",
+			Parameters:  tools.Parameters{tools.NewStringParameter("name", "The name of the user.")},
+		},
+		{
+			Name:        "list_orders",
+			Kind:        "sqlite-sql",
+			Source:      "my-sqlite",
+			Description: "List the orders of a user.",
+			Statement:   "SELECT item FROM orders WHERE user_id = ? ORDER BY id",
+			Parameters:  tools.Parameters{tools.NewIntParameter("user_id", "The id of the user.")},
+		},
+		{
+			Name:        "list_users",
+			Kind:        "sqlite-sql",
+			Source:      "my-sqlite",
+			Description: "List all users.",
+			Statement:   "SELECT name FROM users ORDER BY id",
+		},
+	}
+	tls := make(map[string]tools.Tool)
+	for _, cfg := range cfgs {
+		tool, err := cfg.Initialize(srcs)
+		if err != nil {
+			t.Fatalf("unable to initialize tool %q: %s", cfg.Name, err)
+		}
+		tls[cfg.Name] = tool
+	}
+	return tls
+}
+
+func TestInvokeBranch(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tls := setUpSQLTools(t)
+	orders := []any{map[string]any{"item": "book"}, map[string]any{"item": "lamp"}}
+	users := []any{map[string]any{"name": "Alice"}, map[string]any{"name": "Bob"}}
+
+	tcs := []struct {
+		desc      string
+		condition branch.Condition
+		name      string
+		want      any
+	}{
+		{
+			desc:      "row count true branch",
+			condition: branch.Condition{Operator: ">", Value: 0},
+			name:      "Alice",
+			want:      orders,
+		},
+		{
+			desc:      "row count false branch",
+			condition: branch.Condition{Operator: ">", Value: 0},
+			name:      "Carol",
+			want:      users,
+		},
+		{
+			desc:      "field true branch",
+			condition: branch.Condition{Field: "id", Operator: "==", Value: 1},
+			name:      "Alice",
+			want:      orders,
+		},
+		{
+			desc:      "field false branch",
+			condition: branch.Condition{Field: "id", Operator: "==", Value: 1},
+			name:      "Bob",
+			want:      users,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			cfg := branch.Config{
+				Name:        "user_orders_or_users",
+				Kind:        "branch",
+				Description: "List the orders of a user, or all users if the user does not exist.",
+				Tool:        "find_user",
+				Condition:   tc.condition,
+				Then:        stepcommon.Step{Tool: "list_orders", Inputs: map[string]string{"user_id": "id"}},
+				Else:        stepcommon.Step{Tool: "list_users"},
+			}
+			tool, err := cfg.InitializeWithTools(nil, tls)
+			if err != nil {
+				t.Fatalf("unable to initialize branch: %s", err)
+			}
+
+			params, err := tool.ParseParams(map[string]any{"name": tc.name}, nil)
+			if err != nil {
+				t.Fatalf("unable to parse params: %s", err)
+			}
+			got, err := tool.Invoke(ctx, params, "")
+			if err != nil {
+				t.Fatalf("unable to invoke branch: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect result: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestInitializeBranchErrors(t *testing.T) {
+	tls := setUpSQLTools(t)
+	tcs := []struct {
+		desc      string
+		condition branch.Condition
+		then      stepcommon.Step
+		err       string
+	}{
+		{
+			desc:      "unknown operator",
+			condition: branch.Condition{Operator: "~", Value: 0},
+			then:      stepcommon.Step{Tool: "list_users"},
+			err:       `operator "~" must be one of`,
+		},
+		{
+			desc:      "row count compared with a string",
+			condition: branch.Condition{Operator: ">", Value: "zero"},
+			then:      stepcommon.Step{Tool: "list_users"},
+			err:       "must be a number to compare with the row count",
+		},
+		{
+			desc:      "branch missing required input",
+			condition: branch.Condition{Operator: ">", Value: 0},
+			then:      stepcommon.Step{Tool: "list_orders"},
+			err:       `required parameter "user_id" of step "list_orders" is not set in its inputs`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			cfg := branch.Config{
+				Name:        "user_orders_or_users",
+				Kind:        "branch",
+				Description: "List the orders of a user, or all users if the user does not exist.",
+				Tool:        "find_user",
+				Condition:   tc.condition,
+				Then:        tc.then,
+				Else:        stepcommon.Step{Tool: "list_users"},
+			}
+			_, err := cfg.InitializeWithTools(nil, tls)
+			if err == nil {
+				t.Fatalf("expected error %q, got nil", tc.err)
+			}
+			if !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("unexpected error: got %q, want it to contain %q", err, tc.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package connectivitycheck
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+const kind string = "connectivity-check"
+
+const sourceKey = "source"
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type Config struct {
+	Name        string `yaml:"name" validate:"required"`
+	Kind        string `yaml:"kind" validate:"required"`
+	Description string `yaml:"description" validate:"required"`
+	// Sources limits the sources that can be checked. All sources that
+	// support connectivity checks can be checked if it is empty.
+	Sources      []string `yaml:"sources"`
+	AuthRequired []string `yaml:"authRequired"`
+}
+
+var _ tools.ToolConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	names := cfg.Sources
+	if len(names) == 0 {
+		for name, src := range srcs {
+			if _, ok := src.(sources.Pinger); ok {
+				names = append(names, name)
+			}
+		}
+	}
+
+	pingers := make(map[string]sources.Pinger, len(names))
+	kinds := make(map[string]string, len(names))
+	for _, name := range names {
+		src, ok := srcs[name]
+		if !ok {
+			return nil, fmt.Errorf("no source named %q configured", name)
+		}
+		p, ok := src.(sources.Pinger)
+		if !ok {
+			return nil, fmt.Errorf("source %q of kind %q does not support connectivity checks", name, src.SourceKind())
+		}
+		pingers[name] = p
+		kinds[name] = src.SourceKind()
+	}
+	if len(pingers) == 0 {
+		return nil, fmt.Errorf("no sources that support connectivity checks are configured for tool %q", cfg.Name)
+	}
+	sortedNames := make([]string, 0, len(pingers))
+	for name := range pingers {
+		sortedNames = append(sortedNames, name)
+	}
+	sort.Strings(sortedNames)
+
+	sourceParameter := tools.NewStringParameter(sourceKey, fmt.Sprintf("The name of the source to check. One of: %s.", strings.Join(sortedNames, ", ")))
+	parameters := tools.Parameters{sourceParameter}
+
+	mcpManifest := tools.McpManifest{
+		Name:        cfg.Name,
+		Description: cfg.Description,
+		InputSchema: parameters.McpManifest(),
+	}
+
+	t := Tool{
+		Name:         cfg.Name,
+		Kind:         kind,
+		Parameters:   parameters,
+		AuthRequired: cfg.AuthRequired,
+		Pingers:      pingers,
+		Kinds:        kinds,
+		manifest:     tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:  mcpManifest,
+	}
+	return t, nil
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name         string
+	Kind         string
+	Parameters   tools.Parameters
+	AuthRequired []string
+	Pingers      map[string]sources.Pinger
+	Kinds        map[string]string
+	manifest     tools.Manifest
+	mcpManifest  tools.McpManifest
+}
+
+// Result is the outcome of checking the connectivity of a source.
+type Result struct {
+	Source  string `json:"source"`
+	Kind    string `json:"kind"`
+	Success bool   `json:"success"`
+	Latency string `json:"latency"`
+	Error   string `json:"error,omitempty"`
+}
+
+func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
+	name, ok := params.AsMap()[sourceKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("unable to get cast %s", sourceKey)
+	}
+	p, ok := t.Pingers[name]
+	if !ok {
+		return nil, fmt.Errorf("source %q can't be checked by this tool", name)
+	}
+
+	// failures are part of the result rather than errors, since reporting them
+	// is the point of the tool
+	start := time.Now()
+	err := p.Ping(ctx)
+	res := Result{
+		Source:  name,
+		Kind:    t.Kinds[name],
+		Success: err == nil,
+		Latency: time.Since(start).Round(time.Millisecond).String(),
+	}
+	if err != nil {
+		res.Error = err.Error()
+	}
+	return res, nil
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.Parameters, data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
+
+func (t Tool) RequiresClientAuthorization() bool {
+	return false
+}
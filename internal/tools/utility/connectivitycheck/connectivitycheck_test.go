@@ -0,0 +1,220 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package connectivitycheck_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/sources/bigquery"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+
+	"github.com/googleapis/genai-toolbox/internal/tools/utility/connectivitycheck"
+)
+
+func TestParseFromYamlConnectivityCheck(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		want server.ToolConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			tools:
+				example_tool:
+					kind: connectivity-check
+					description: some description
+			`,
+			want: server.ToolConfigs{
+				"example_tool": connectivitycheck.Config{
+					Name:         "example_tool",
+					Kind:         "connectivity-check",
+					Description:  "some description",
+					AuthRequired: []string{},
+				},
+			},
+		},
+		{
+			desc: "with sources",
+			in: `
+			tools:
+				example_tool:
+					kind: connectivity-check
+					description: some description
+					sources:
+						- my-bigquery
+					authRequired:
+						- my-google-auth-service
+			`,
+			want: server.ToolConfigs{
+				"example_tool": connectivitycheck.Config{
+					Name:         "example_tool",
+					Kind:         "connectivity-check",
+					Description:  "some description",
+					Sources:      []string{"my-bigquery"},
+					AuthRequired: []string{"my-google-auth-service"},
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			// Parse contents
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Tools); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+}
+
+// pingSource is a source whose Ping returns err.
+type pingSource struct {
+	err error
+}
+
+func (s pingSource) SourceKind() string {
+	return "ping-source"
+}
+
+func (s pingSource) Ping(context.Context) error {
+	return s.err
+}
+
+// plainSource is a source that can't be pinged.
+type plainSource struct{}
+
+func (s plainSource) SourceKind() string {
+	return "plain-source"
+}
+
+func TestInvoke(t *testing.T) {
+	srcs := map[string]sources.Source{
+		// client OAuth sources have no credentials of their own to check
+		"my-bigquery": &bigquery.Source{Name: "my-bigquery", Kind: "bigquery", UseClientOAuth: true},
+		"my-db":       pingSource{},
+		"my-broken":   pingSource{err: errors.New("connection refused")},
+		"my-plain":    plainSource{},
+	}
+	cfg := connectivitycheck.Config{Name: "check", Kind: "connectivity-check", Description: "some description"}
+	tool, err := cfg.Initialize(srcs)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	tcs := []struct {
+		desc    string
+		source  string
+		want    connectivitycheck.Result
+		wantErr bool
+	}{
+		{
+			desc:   "bigquery",
+			source: "my-bigquery",
+			want:   connectivitycheck.Result{Source: "my-bigquery", Kind: "bigquery", Success: true},
+		},
+		{
+			desc:   "healthy",
+			source: "my-db",
+			want:   connectivitycheck.Result{Source: "my-db", Kind: "ping-source", Success: true},
+		},
+		{
+			desc:   "unhealthy",
+			source: "my-broken",
+			want:   connectivitycheck.Result{Source: "my-broken", Kind: "ping-source", Error: "connection refused"},
+		},
+		{
+			desc:    "source without connectivity checks",
+			source:  "my-plain",
+			wantErr: true,
+		},
+		{
+			desc:    "unknown source",
+			source:  "my-imaginary-source",
+			wantErr: true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			params, err := tool.ParseParams(map[string]any{"source": tc.source}, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			got, err := tool.Invoke(context.Background(), params, "")
+			if err != nil {
+				if tc.wantErr {
+					return
+				}
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if tc.wantErr {
+				t.Fatalf("expected error but got nil")
+			}
+			if diff := cmp.Diff(tc.want, got, cmpopts.IgnoreFields(connectivitycheck.Result{}, "Latency")); diff != "" {
+				t.Fatalf("incorrect result: diff %v", diff)
+			}
+			if got.(connectivitycheck.Result).Latency == "" {
+				t.Fatalf("latency not reported")
+			}
+		})
+	}
+}
+
+func TestInitializeErrors(t *testing.T) {
+	srcs := map[string]sources.Source{
+		"my-plain": plainSource{},
+	}
+	tcs := []struct {
+		desc string
+		cfg  connectivitycheck.Config
+	}{
+		{
+			desc: "no sources to check",
+			cfg:  connectivitycheck.Config{Name: "check"},
+		},
+		{
+			desc: "source without connectivity checks",
+			cfg:  connectivitycheck.Config{Name: "check", Sources: []string{"my-plain"}},
+		},
+		{
+			desc: "unknown source",
+			cfg:  connectivitycheck.Config{Name: "check", Sources: []string{"my-imaginary-source"}},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			if _, err := tc.cfg.Initialize(srcs); err == nil {
+				t.Fatalf("expected error but got nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sequence
+
+import (
+	"context"
+	"fmt"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/utility/stepcommon"
+)
+
+const kind string = "sequence"
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type Config struct {
+	Name                      string            `yaml:"name" validate:"required"`
+	Kind                      string            `yaml:"kind" validate:"required"`
+	Description               string            `yaml:"description" validate:"required"`
+	Steps                     []stepcommon.Step `yaml:"steps" validate:"required"`
+	ReturnIntermediateOutputs bool              `yaml:"returnIntermediateOutputs"`
+	AuthRequired              []string          `yaml:"authRequired"`
+}
+
+// validate interface
+var _ tools.ToolReferencingConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+func (cfg Config) Initialize(_ map[string]sources.Source) (tools.Tool, error) {
+	return nil, fmt.Errorf("%q tools must be initialized with the tools they reference", kind)
+}
+
+func (cfg Config) ReferencedTools() []string {
+	names := make([]string, 0, len(cfg.Steps))
+	for _, s := range cfg.Steps {
+		names = append(names, s.Tool)
+	}
+	return names
+}
+
+func (cfg Config) InitializeWithTools(_ map[string]sources.Source, tls map[string]tools.Tool) (tools.Tool, error) {
+	if len(cfg.Steps) == 0 {
+		return nil, fmt.Errorf("at least one step is required")
+	}
+
+	// The first step receives the parameters of the sequence itself.
+	if len(cfg.Steps[0].Inputs) > 0 {
+		return nil, fmt.Errorf("the first step %q cannot have inputs, it takes the parameters of the sequence", cfg.Steps[0].Tool)
+	}
+	first, ok := tls[cfg.Steps[0].Tool]
+	if !ok {
+		return nil, fmt.Errorf("no tool named %q configured", cfg.Steps[0].Tool)
+	}
+	steps := make([]stepcommon.BoundStep, 0, len(cfg.Steps)-1)
+	for _, s := range cfg.Steps[1:] {
+		bs, err := stepcommon.Bind(s, tls)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, bs)
+	}
+
+	mcpManifest := tools.McpManifest{
+		Name:        cfg.Name,
+		Description: cfg.Description,
+		InputSchema: first.McpManifest().InputSchema,
+	}
+
+	t := Tool{
+		Name:                      cfg.Name,
+		Kind:                      kind,
+		AuthRequired:              cfg.AuthRequired,
+		ReturnIntermediateOutputs: cfg.ReturnIntermediateOutputs,
+		first:                     first,
+		firstName:                 cfg.Steps[0].Tool,
+		steps:                     steps,
+		manifest:                  tools.Manifest{Description: cfg.Description, Parameters: first.Manifest().Parameters, AuthRequired: cfg.AuthRequired},
+		mcpManifest:               mcpManifest,
+	}
+	return t, nil
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name                      string   `yaml:"name"`
+	Kind                      string   `yaml:"kind"`
+	AuthRequired              []string `yaml:"authRequired"`
+	ReturnIntermediateOutputs bool     `yaml:"returnIntermediateOutputs"`
+
+	first       tools.Tool
+	firstName   string
+	steps       []stepcommon.BoundStep
+	manifest    tools.Manifest
+	mcpManifest tools.McpManifest
+}
+
+func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
+	out, err := t.first.Invoke(ctx, params, accessToken)
+	if err != nil {
+		return nil, fmt.Errorf("step 1 (%q) failed: %w", t.firstName, err)
+	}
+	outputs := []any{map[string]any{"tool": t.firstName, "output": out}}
+	for i, s := range t.steps {
+		stepParams, err := s.ParseParams(out)
+		if err != nil {
+			return nil, fmt.Errorf("invalid inputs for step %d (%q): %w", i+2, s.Name, err)
+		}
+		out, err = s.Tool.Invoke(ctx, stepParams, accessToken)
+		if err != nil {
+			return nil, fmt.Errorf("step %d (%q) failed: %w", i+2, s.Name, err)
+		}
+		outputs = append(outputs, map[string]any{"tool": s.Name, "output": out})
+	}
+	if t.ReturnIntermediateOutputs {
+		return outputs, nil
+	}
+	return out, nil
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return t.first.ParseParams(data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+// Authorized requires the caller to be authorized for the sequence and for
+// every tool it runs.
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	if !tools.IsAuthorized(t.AuthRequired, verifiedAuthServices) {
+		return false
+	}
+	if !t.first.Authorized(verifiedAuthServices) {
+		return false
+	}
+	for _, s := range t.steps {
+		if !s.Tool.Authorized(verifiedAuthServices) {
+			return false
+		}
+	}
+	return true
+}
+
+func (t Tool) RequiresClientAuthorization() bool {
+	if t.first.RequiresClientAuthorization() {
+		return true
+	}
+	for _, s := range t.steps {
+		if s.Tool.RequiresClientAuthorization() {
+			return true
+		}
+	}
+	return false
+}
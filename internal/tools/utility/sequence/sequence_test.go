@@ -0,0 +1,256 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sequence_test
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/sources/sqlite"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/sqlite/sqlitesql"
+	"github.com/googleapis/genai-toolbox/internal/tools/utility/sequence"
+	"github.com/googleapis/genai-toolbox/internal/tools/utility/stepcommon"
+	_ "modernc.org/sqlite"
+)
+
+func TestParseFromYamlSequence(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		want server.ToolConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			tools:
+				example_tool:
+					kind: sequence
+					description: some description
+					steps:
+						- tool: find_user
+						- tool: list_orders
+						  inputs:
+								user_id: id
+					returnIntermediateOutputs: true
+			`,
+			want: server.ToolConfigs{
+				"example_tool": sequence.Config{
+					Name:        "example_tool",
+					Kind:        "sequence",
+					Description: "some description",
+					Steps: []stepcommon.Step{
+						{Tool: "find_user"},
+						{Tool: "list_orders", Inputs: map[string]string{"user_id": "id"}},
+					},
+					ReturnIntermediateOutputs: true,
+					AuthRequired:              []string{},
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			// Parse contents
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Tools); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+}
+
+// setUpSQLTools returns two sqlite-sql tools: find_user looks up the id of a
+// user by name, and list_orders lists the orders of a user id.
+func setUpSQLTools(t *testing.T) map[string]tools.Tool {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("unable to open in-memory database: %s", err)
+	}
+	// every connection to :memory: opens a new database
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	setup := `
+	CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT);
+	CREATE TABLE orders (id INTEGER PRIMARY KEY, user_id INTEGER, item TEXT);
+	INSERT INTO users (id, name) VALUES (1, 'Alice'), (2, 'Bob');
+	INSERT INTO orders (id, user_id, item) VALUES (1, 1, 'book'), (2, 2, 'pen'), (3, 1, 'lamp');`
+	if _, err := db.Exec(setup); err != nil {
+		t.Fatalf("unable to set up database: %s", err)
+	}
+
+	srcs := map[string]sources.Source{"my-sqlite": &sqlite.Source{Name: "my-sqlite", Kind: sqlite.SourceKind, Db: db}}
+	cfgs := []sqlitesql.Config{
+		{
+			Name:        "find_user",
+			Kind:        "sqlite-sql",
+			Source:      "my-sqlite",
+			Description: "Find a user by name.",
+			Statement:   "SELECT id FROM users WHERE name = ?",
+			Parameters:  tools.Parameters{tools.NewStringParameter("name", "The name of the user.")},
+		},
+		{
+			Name:        "list_orders",
+			Kind:        "sqlite-sql",
+			Source:      "my-sqlite",
+			Description: "List the orders of a user.",
+			Statement:   "SELECT item FROM orders WHERE user_id = ? ORDER BY id",
+			Parameters:  tools.Parameters{tools.NewIntParameter("user_id", "The id of the user.")},
+		},
+	}
+	tls := make(map[string]tools.Tool)
+	for _, cfg := range cfgs {
+		tool, err := cfg.Initialize(srcs)
+		if err != nil {
+			t.Fatalf("unable to initialize tool %q: %s", cfg.Name, err)
+		}
+		tls[cfg.Name] = tool
+	}
+	return tls
+}
+
+func TestInvokeSequence(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tls := setUpSQLTools(t)
+	steps := []stepcommon.Step{
+		{Tool: "find_user"},
+		{Tool: "list_orders", Inputs: map[string]string{"user_id": "id"}},
+	}
+
+	tcs := []struct {
+		desc             string
+		returnAllOutputs bool
+		want             any
+	}{
+		{
+			desc: "final output",
+			want: []any{
+				map[string]any{"item": "book"},
+				map[string]any{"item": "lamp"},
+			},
+		},
+		{
+			desc:             "intermediate outputs",
+			returnAllOutputs: true,
+			want: []any{
+				map[string]any{"tool": "find_user", "output": []any{map[string]any{"id": int64(1)}}},
+				map[string]any{"tool": "list_orders", "output": []any{
+					map[string]any{"item": "book"},
+					map[string]any{"item": "lamp"},
+				}},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			cfg := sequence.Config{
+				Name:                      "user_orders",
+				Kind:                      "sequence",
+				Description:               "List the orders of a user by name.",
+				Steps:                     steps,
+				ReturnIntermediateOutputs: tc.returnAllOutputs,
+			}
+			tool, err := cfg.InitializeWithTools(nil, tls)
+			if err != nil {
+				t.Fatalf("unable to initialize sequence: %s", err)
+			}
+			if diff := cmp.Diff(tls["find_user"].Manifest().Parameters, tool.Manifest().Parameters); diff != "" {
+				t.Fatalf("sequence should take the parameters of its first step: diff %v", diff)
+			}
+
+			params, err := tool.ParseParams(map[string]any{"name": "Alice"}, nil)
+			if err != nil {
+				t.Fatalf("unable to parse params: %s", err)
+			}
+			got, err := tool.Invoke(ctx, params, "")
+			if err != nil {
+				t.Fatalf("unable to invoke sequence: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect result: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestInitializeSequenceErrors(t *testing.T) {
+	tls := setUpSQLTools(t)
+	tcs := []struct {
+		desc  string
+		steps []stepcommon.Step
+		err   string
+	}{
+		{
+			desc:  "unknown tool",
+			steps: []stepcommon.Step{{Tool: "find_user"}, {Tool: "imaginary_tool"}},
+			err:   `no tool named "imaginary_tool" configured`,
+		},
+		{
+			desc:  "inputs on first step",
+			steps: []stepcommon.Step{{Tool: "find_user", Inputs: map[string]string{"name": "name"}}},
+			err:   `the first step "find_user" cannot have inputs`,
+		},
+		{
+			desc:  "required parameter not set",
+			steps: []stepcommon.Step{{Tool: "find_user"}, {Tool: "list_orders"}},
+			err:   `required parameter "user_id" of step "list_orders" is not set in its inputs`,
+		},
+		{
+			desc: "unknown parameter",
+			steps: []stepcommon.Step{
+				{Tool: "find_user"},
+				{Tool: "list_orders", Inputs: map[string]string{"user_id": "id", "limit": "id"}},
+			},
+			err: `step "list_orders" has no parameter "limit"`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			cfg := sequence.Config{
+				Name:        "user_orders",
+				Kind:        "sequence",
+				Description: "List the orders of a user by name.",
+				Steps:       tc.steps,
+			}
+			_, err := cfg.InitializeWithTools(nil, tls)
+			if err == nil {
+				t.Fatalf("expected error %q, got nil", tc.err)
+			}
+			if !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("unexpected error: got %q, want it to contain %q", err, tc.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package stepcommon holds the helpers shared by tools that run other tools
+// as steps, feeding the output of one tool into the next.
+package stepcommon
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+// Step is a tool run by another tool. Inputs maps the parameters of the tool
+// to fields of the output of the previous step.
+type Step struct {
+	Tool   string            `yaml:"tool" validate:"required"`
+	Inputs map[string]string `yaml:"inputs"`
+}
+
+// BoundStep is a Step bound to its initialized tool.
+type BoundStep struct {
+	Name string
+	Tool tools.Tool
+
+	inputs     map[string]string
+	paramTypes map[string]string
+}
+
+// Bind looks up the tool of s in tls and checks that its inputs set every
+// required parameter of the tool.
+func Bind(s Step, tls map[string]tools.Tool) (BoundStep, error) {
+	t, ok := tls[s.Tool]
+	if !ok {
+		return BoundStep{}, fmt.Errorf("no tool named %q configured", s.Tool)
+	}
+	paramTypes := make(map[string]string)
+	for _, p := range t.Manifest().Parameters {
+		if len(p.AuthServices) > 0 {
+			return BoundStep{}, fmt.Errorf("step %q has authenticated parameter %q, which is only supported in the first step", s.Tool, p.Name)
+		}
+		paramTypes[p.Name] = p.Type
+		if _, ok := s.Inputs[p.Name]; p.Required && !ok {
+			return BoundStep{}, fmt.Errorf("required parameter %q of step %q is not set in its inputs", p.Name, s.Tool)
+		}
+	}
+	for param := range s.Inputs {
+		if _, ok := paramTypes[param]; !ok {
+			return BoundStep{}, fmt.Errorf("step %q has no parameter %q", s.Tool, param)
+		}
+	}
+	return BoundStep{Name: s.Tool, Tool: t, inputs: s.Inputs, paramTypes: paramTypes}, nil
+}
+
+// ParseParams builds the parameters of s from the output of the previous step.
+// Array parameters collect the field from every row of the output, other
+// parameters take it from the first row.
+func (s BoundStep) ParseParams(prev any) (tools.ParamValues, error) {
+	rows, err := OutputRows(prev)
+	if err != nil {
+		return nil, err
+	}
+	data := make(map[string]any, len(s.inputs))
+	for param, field := range s.inputs {
+		if s.paramTypes[param] == "array" {
+			values := make([]any, 0, len(rows))
+			for _, row := range rows {
+				v, ok := row[field]
+				if !ok {
+					return nil, fmt.Errorf("field %q not found in the output of the previous step", field)
+				}
+				values = append(values, v)
+			}
+			data[param] = values
+			continue
+		}
+		if len(rows) == 0 {
+			return nil, fmt.Errorf("the previous step returned no rows to read field %q from", field)
+		}
+		v, ok := rows[0][field]
+		if !ok {
+			return nil, fmt.Errorf("field %q not found in the output of the previous step", field)
+		}
+		data[param] = v
+	}
+	return s.Tool.ParseParams(data, nil)
+}
+
+// OutputRows converts the output of a tool into rows. The output is round
+// tripped through JSON, so that the rows hold the same values a client would
+// send back to the next tool. Outputs without data, and outputs of statements
+// that don't return rows, have no rows.
+func OutputRows(out any) ([]map[string]any, error) {
+	if tools.IsEmptyResult(out) || out == tools.NoContentMessage {
+		return nil, nil
+	}
+	b, err := json.Marshal(out)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal output: %w", err)
+	}
+	var v any
+	if err := util.DecodeJSON(bytes.NewReader(b), &v); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal output: %w", err)
+	}
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case map[string]any:
+		return []map[string]any{v}, nil
+	case []any:
+		rows := make([]map[string]any, 0, len(v))
+		for _, r := range v {
+			row, ok := r.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("output row %v is not an object", r)
+			}
+			rows = append(rows, row)
+		}
+		return rows, nil
+	default:
+		return nil, fmt.Errorf("output %v is not a list of rows", v)
+	}
+}
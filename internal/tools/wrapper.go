@@ -0,0 +1,74 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"github.com/googleapis/genai-toolbox/internal/sources"
+)
+
+// toolWrapper is the Option of a WrapperConfig.
+type toolWrapper interface {
+	// wrap returns t, the tool initialized from c, wrapped as the option
+	// says.
+	wrap(c ToolConfig, t Tool) (Tool, error)
+}
+
+// WrapperConfig wraps a ToolConfig with a per-tool option, such as a timeout
+// or a result cache. The tool of ToolConfig is initialized along with the
+// tools it references, then wrapped as Option says. The kind of a
+// WrapperConfig is that of the ToolConfig it wraps.
+type WrapperConfig[O toolWrapper] struct {
+	ToolConfig
+	Option O
+}
+
+func (c WrapperConfig[O]) Initialize(srcs map[string]sources.Source) (Tool, error) {
+	return c.InitializeWithTools(srcs, nil)
+}
+
+func (c WrapperConfig[O]) ReferencedTools() []string {
+	return ReferencedTools(c.ToolConfig)
+}
+
+func (c WrapperConfig[O]) InitializeWithTools(srcs map[string]sources.Source, tls map[string]Tool) (Tool, error) {
+	t, err := InitializeWithTools(c.ToolConfig, srcs, tls)
+	if err != nil {
+		return nil, err
+	}
+	return c.Option.wrap(c.ToolConfig, t)
+}
+
+// WrapperTool is implemented by the tools that wrap another tool for a
+// per-tool option or a server default. Unwrap returns the wrapped tool, so
+// that wrappers further in can be found whatever the order of the options.
+type WrapperTool interface {
+	Tool
+	Unwrap() Tool
+}
+
+// findWrapper returns the first tool of type T among t and the tools it wraps.
+func findWrapper[T Tool](t Tool) (T, bool) {
+	for {
+		if found, ok := t.(T); ok {
+			return found, true
+		}
+		w, ok := t.(WrapperTool)
+		if !ok {
+			var zero T
+			return zero, false
+		}
+		t = w.Unwrap()
+	}
+}
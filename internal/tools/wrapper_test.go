@@ -0,0 +1,60 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestWrapperConfig(t *testing.T) {
+	tool := resultTool{res: "ok"}
+	inner := tools.AliasConfig{Name: "old_name", Tool: "new_name"}
+	cfg := tools.TimeoutConfig{ToolConfig: inner, Option: tools.TimeoutOption{Timeout: time.Second}}
+
+	// the wrapper is transparent to the kind and references of the config
+	if cfg.ToolConfigKind() != inner.ToolConfigKind() {
+		t.Fatalf("incorrect kind: got %q, want %q", cfg.ToolConfigKind(), inner.ToolConfigKind())
+	}
+	if diff := cmp.Diff([]string{"new_name"}, tools.ReferencedTools(cfg)); diff != "" {
+		t.Fatalf("incorrect referenced tools: diff %v", diff)
+	}
+
+	got, err := tools.InitializeWithTools(cfg, nil, map[string]tools.Tool{"new_name": tool})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := tools.NewTimeoutTool(tool, time.Second)
+	if got != want {
+		t.Fatalf("incorrect tool: got %v, want %v", got, want)
+	}
+
+	if _, err := cfg.Initialize(nil); err == nil {
+		t.Fatalf("expected error for an unknown tool but got nil")
+	}
+}
+
+func TestWrapperConfigRejectsTool(t *testing.T) {
+	inner := tools.AliasConfig{Name: "old_name", Tool: "new_name"}
+	cfg := tools.ResolvedStatementConfig{ToolConfig: inner}
+	_, err := tools.InitializeWithTools(cfg, nil, map[string]tools.Tool{"new_name": resultTool{}})
+	if err == nil || !strings.Contains(err.Error(), `"includeResolvedStatement" is not supported`) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
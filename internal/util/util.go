@@ -103,9 +103,14 @@ type contextKey string
 // userAgentKey is the key used to store userAgent within context
 const userAgentKey contextKey = "userAgent"
 
-// WithUserAgent adds a user agent into the context as a value
-func WithUserAgent(ctx context.Context, versionString string) context.Context {
+// WithUserAgent adds a user agent into the context as a value. A non-empty
+// suffix is appended to the user agent, so that traffic from a specific
+// deployment can be told apart.
+func WithUserAgent(ctx context.Context, versionString string, suffix string) context.Context {
 	userAgent := "genai-toolbox/" + versionString
+	if suffix != "" {
+		userAgent += " " + suffix
+	}
 	return context.WithValue(ctx, userAgentKey, userAgent)
 }
 
@@ -162,3 +167,22 @@ func InstrumentationFromContext(ctx context.Context) (*telemetry.Instrumentation
 	}
 	return nil, fmt.Errorf("unable to retrieve instrumentation")
 }
+
+// defaultAuthRequiredKey is the key used to store the default authRequired
+// list of tools within context
+const defaultAuthRequiredKey contextKey = "defaultAuthRequired"
+
+// WithDefaultAuthRequired adds the auth services required by tools that don't
+// set `authRequired` into the context as a value
+func WithDefaultAuthRequired(ctx context.Context, authRequired []string) context.Context {
+	return context.WithValue(ctx, defaultAuthRequiredKey, authRequired)
+}
+
+// DefaultAuthRequiredFromContext retrieves the default authRequired list of
+// tools, which is empty if none is set
+func DefaultAuthRequiredFromContext(ctx context.Context) []string {
+	if authRequired, ok := ctx.Value(defaultAuthRequiredKey).([]string); ok {
+		return authRequired
+	}
+	return nil
+}
@@ -20,18 +20,23 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"os"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
 	"testing"
 	"time"
 
 	bigqueryapi "cloud.google.com/go/bigquery"
+	"github.com/apache/arrow/go/v15/arrow/ipc"
+	"github.com/google/go-cmp/cmp"
 	"github.com/google/uuid"
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigquerycommon"
 	"github.com/googleapis/genai-toolbox/tests"
 	"golang.org/x/oauth2/google"
 	"google.golang.org/api/googleapi"
@@ -43,6 +48,12 @@ var (
 	BigquerySourceKind = "bigquery"
 	BigqueryToolKind   = "bigquery-sql"
 	BigqueryProject    = os.Getenv("BIGQUERY_PROJECT")
+	// BigqueryEmbeddingModel is a remote embedding model, as `dataset.model`,
+	// used by the bigquery-generate-embedding tests.
+	BigqueryEmbeddingModel = os.Getenv("BIGQUERY_EMBEDDING_MODEL")
+	// BigquerySecondProject is a second project the caller can run jobs in,
+	// used by the allowedProjects tests.
+	BigquerySecondProject = os.Getenv("BIGQUERY_SECOND_PROJECT")
 )
 
 func getBigQueryVars(t *testing.T) map[string]any {
@@ -119,6 +130,12 @@ func TestBigQueryToolEndpoints(t *testing.T) {
 		strings.ReplaceAll(uuid.New().String(), "-", ""),
 	)
 
+	tableNameVectorSearch := fmt.Sprintf("`%s.%s.vector_search_table_%s`",
+		BigqueryProject,
+		datasetName,
+		strings.ReplaceAll(uuid.New().String(), "-", ""),
+	)
+
 	// set up data for param tool
 	createParamTableStmt, insertParamTableStmt, paramToolStmt, idParamToolStmt, nameParamToolStmt, arrayToolStmt, paramTestParams := getBigQueryParamToolInfo(tableNameParam)
 	teardownTable1 := setupBigQueryTable(t, ctx, client, createParamTableStmt, insertParamTableStmt, datasetName, tableNameParam, paramTestParams)
@@ -144,11 +161,18 @@ func TestBigQueryToolEndpoints(t *testing.T) {
 	teardownTable5 := setupBigQueryTable(t, ctx, client, createAnalyzeContributionTableStmt, insertAnalyzeContributionTableStmt, datasetName, tableNameAnalyzeContribution, analyzeContributionTestParams)
 	defer teardownTable5(t)
 
+	// set up data for vector search tool
+	createVectorSearchTableStmt, insertVectorSearchTableStmt, vectorSearchTestParams := getBigQueryVectorSearchToolInfo(tableNameVectorSearch)
+	teardownTable6 := setupBigQueryTable(t, ctx, client, createVectorSearchTableStmt, insertVectorSearchTableStmt, datasetName, tableNameVectorSearch, vectorSearchTestParams)
+	defer teardownTable6(t)
+
 	// Write config into a file and pass it to command
 	toolsFile := tests.GetToolsConfig(sourceConfig, BigqueryToolKind, paramToolStmt, idParamToolStmt, nameParamToolStmt, arrayToolStmt, authToolStmt)
 	toolsFile = addClientAuthSourceConfig(t, toolsFile)
+	toolsFile = addBigQueryQueryTagConfig(t, toolsFile)
 	toolsFile = addBigQuerySqlToolConfig(t, toolsFile, dataTypeToolStmt, arrayDataTypeToolStmt)
 	toolsFile = addBigQueryPrebuiltToolsConfig(t, toolsFile)
+	toolsFile = addBigQuerySavedQueryToolConfig(t, toolsFile, tableNameParam)
 	tmplSelectCombined, tmplSelectFilterCombined := getBigQueryTmplToolStatement()
 	toolsFile = tests.AddTemplateParamConfig(t, toolsFile, BigqueryToolKind, tmplSelectCombined, tmplSelectFilterCombined, "")
 
@@ -192,13 +216,34 @@ func TestBigQueryToolEndpoints(t *testing.T) {
 
 	runBigQueryExecuteSqlToolInvokeTest(t, select1Want, invokeParamWant, tableNameParam, ddlWant)
 	runBigQueryExecuteSqlToolInvokeDryRunTest(t, datasetName)
+	runBigQueryExecuteSqlToolInvokeAsyncTest(t)
+	runBigQueryExecuteSqlScriptErrorTest(t)
 	runBigQueryForecastToolInvokeTest(t, tableNameForecast)
+	runBigQueryQueryExternalToolInvokeTest(t)
 	runBigQueryAnalyzeContributionToolInvokeTest(t, tableNameAnalyzeContribution)
+	runBigQueryVectorSearchToolInvokeTest(t, tableNameVectorSearch)
 	runBigQueryDataTypeTests(t)
+	runBigQueryReadOnlyToolInvokeTest(t, tableNameParam)
+	runBigQueryConnectionPropertiesToolInvokeTest(t)
+	runBigQueryArrowToolInvokeTest(t)
+	runBigQueryIncludeStatsToolInvokeTest(t)
+	runBigQueryQueryTagTest(t, ctx, client)
+	runBigQueryExecuteSqlDeniedStatementTypesTest(t, tableNameParam)
+	runBigQueryRowAccessPolicyWarningTest(t, ctx, client, datasetName)
+	runBigQueryRunSavedQueryToolInvokeTest(t)
 	runBigQueryListDatasetToolInvokeTest(t, datasetName)
+	runBigQueryListDatasetIdsPagingTest(t, datasetName)
 	runBigQueryGetDatasetInfoToolInvokeTest(t, datasetName, datasetInfoWant)
 	runBigQueryListTableIdsToolInvokeTest(t, datasetName, tableName)
+	runBigQueryListTableIdsWithMetadataToolInvokeTest(t, datasetName, tableName)
+	runBigQueryListTableIdsTypesFilterTest(t, ctx, client, datasetName, tableName)
+	runBigQueryListTableIdsPagingTest(t, datasetName)
 	runBigQueryGetTableInfoToolInvokeTest(t, datasetName, tableName, tableInfoWant)
+	runBigQueryGetTableInfoPartitionedTest(t, ctx, client, datasetName)
+	runBigQueryListColumnsToolInvokeTest(t, datasetName, tableName)
+	runBigQueryTableStorageToolInvokeTest(t, datasetName, tableName)
+	runBigQueryProfileTableToolInvokeTest(t, datasetName, tableName)
+	runBigQueryLoadFromGCSToolInvokeTest(t, ctx, client, datasetName)
 	runBigQueryConversationalAnalyticsInvokeTest(t, datasetName, tableName, dataInsightsWant)
 	runBigQuerySearchCatalogToolInvokeTest(t, datasetName, tableName)
 }
@@ -269,6 +314,31 @@ func TestBigQueryToolWithDatasetRestriction(t *testing.T) {
 			"source":      "my-instance",
 			"description": "Tool to list table within a dataset",
 		},
+		"list-columns-restricted": map[string]any{
+			"kind":        "bigquery-list-columns",
+			"source":      "my-instance",
+			"description": "Tool to list table columns",
+		},
+		"table-storage-restricted": map[string]any{
+			"kind":        "bigquery-table-storage",
+			"source":      "my-instance",
+			"description": "Tool to show table storage statistics",
+		},
+		"profile-table-restricted": map[string]any{
+			"kind":        "bigquery-profile-table",
+			"source":      "my-instance",
+			"description": "Tool to profile a table",
+		},
+		"load-from-gcs-restricted": map[string]any{
+			"kind":        "bigquery-load-from-gcs",
+			"source":      "my-instance",
+			"description": "Tool to load files from Cloud Storage into a table",
+		},
+		"vector-search-restricted": map[string]any{
+			"kind":        "bigquery-vector-search",
+			"source":      "my-instance",
+			"description": "Tool to find the nearest rows to an embedding",
+		},
 	}
 
 	// Create config file
@@ -297,409 +367,1962 @@ func TestBigQueryToolWithDatasetRestriction(t *testing.T) {
 	// Run tests
 	runListTableIdsWithRestriction(t, allowedDatasetName1, disallowedDatasetName, allowedTableName1, allowedForecastTableName1)
 	runListTableIdsWithRestriction(t, allowedDatasetName2, disallowedDatasetName, allowedTableName2, allowedForecastTableName2)
+	runTableToolWithRestriction(t, "list-columns-restricted", allowedDatasetName1, disallowedDatasetName, allowedTableName1, disallowedTableName)
+	runTableToolWithRestriction(t, "table-storage-restricted", allowedDatasetName1, disallowedDatasetName, allowedTableName1, disallowedTableName)
+	runTableToolWithRestriction(t, "profile-table-restricted", allowedDatasetName1, disallowedDatasetName, allowedTableName1, disallowedTableName)
+	runLoadFromGCSWithRestriction(t, disallowedDatasetName)
+	runVectorSearchWithRestriction(t, disallowedDatasetName, disallowedTableName)
 }
 
-// getBigQueryParamToolInfo returns statements and param for my-tool for bigquery kind
-func getBigQueryParamToolInfo(tableName string) (string, string, string, string, string, string, []bigqueryapi.QueryParameter) {
-	createStatement := fmt.Sprintf(`
-		CREATE TABLE IF NOT EXISTS %s (id INT64, name STRING);`, tableName)
-	insertStatement := fmt.Sprintf(`
-		INSERT INTO %s (id, name) VALUES (?, ?), (?, ?), (?, ?), (?, NULL);`, tableName)
-	toolStatement := fmt.Sprintf(`SELECT * FROM %s WHERE id = ? OR name = ? ORDER BY id;`, tableName)
-	idToolStatement := fmt.Sprintf(`SELECT * FROM %s WHERE id = ? ORDER BY id;`, tableName)
-	nameToolStatement := fmt.Sprintf(`SELECT * FROM %s WHERE name = ? ORDER BY id;`, tableName)
-	arrayToolStatememt := fmt.Sprintf(`SELECT * FROM %s WHERE id IN UNNEST(@idArray) AND name IN UNNEST(@nameArray) ORDER BY id;`, tableName)
-	params := []bigqueryapi.QueryParameter{
-		{Value: int64(1)}, {Value: "Alice"},
-		{Value: int64(2)}, {Value: "Jane"},
-		{Value: int64(3)}, {Value: "Sid"},
-		{Value: int64(4)},
+func runVectorSearchWithRestriction(t *testing.T, disallowedDatasetName, disallowedTableName string) {
+	body := bytes.NewBuffer([]byte(fmt.Sprintf(`{"table": "%s.%s", "column_to_search": "embedding", "query_embedding": [1, 0]}`, disallowedDatasetName, disallowedTableName)))
+	req, err := http.NewRequest(http.MethodPost, "http://127.0.0.1:5000/api/tool/vector-search-restricted/invoke", body)
+	if err != nil {
+		t.Fatalf("unable to create request: %s", err)
 	}
-	return createStatement, insertStatement, toolStatement, idToolStatement, nameToolStatement, arrayToolStatememt, params
-}
-
-// getBigQueryAuthToolInfo returns statements and param of my-auth-tool for bigquery kind
-func getBigQueryAuthToolInfo(tableName string) (string, string, string, []bigqueryapi.QueryParameter) {
-	createStatement := fmt.Sprintf(`
-		CREATE TABLE IF NOT EXISTS %s (id INT64, name STRING, email STRING)`, tableName)
-	insertStatement := fmt.Sprintf(`
-		INSERT INTO %s (id, name, email) VALUES (?, ?, ?), (?, ?, ?)`, tableName)
-	toolStatement := fmt.Sprintf(`
-		SELECT name FROM %s WHERE email = ?`, tableName)
-	params := []bigqueryapi.QueryParameter{
-		{Value: int64(1)}, {Value: "Alice"}, {Value: tests.ServiceAccountEmail},
-		{Value: int64(2)}, {Value: "Jane"}, {Value: "janedoe@gmail.com"},
+	req.Header.Add("Content-type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("unable to send request: %s", err)
 	}
-	return createStatement, insertStatement, toolStatement, params
-}
+	defer resp.Body.Close()
 
-// getBigQueryDataTypeTestInfo returns statements and params for data type tests.
-func getBigQueryDataTypeTestInfo(tableName string) (string, string, string, string, []bigqueryapi.QueryParameter) {
-	createStatement := fmt.Sprintf(`
-		CREATE TABLE IF NOT EXISTS %s (id INT64, int_val INT64, string_val STRING, float_val FLOAT64, bool_val BOOL);`, tableName)
-	insertStatement := fmt.Sprintf(`
-		INSERT INTO %s (id, int_val, string_val, float_val, bool_val) VALUES (?, ?, ?, ?, ?), (?, ?, ?, ?, ?), (?, ?, ?, ?, ?);`, tableName)
-	toolStatement := fmt.Sprintf(`SELECT * FROM %s WHERE int_val = ? AND string_val = ? AND float_val = ? AND bool_val = ?;`, tableName)
-	arrayToolStatement := fmt.Sprintf(`SELECT * FROM %s WHERE int_val IN UNNEST(@int_array) AND string_val IN UNNEST(@string_array) AND float_val IN UNNEST(@float_array) AND bool_val IN UNNEST(@bool_array) ORDER BY id;`, tableName)
-	params := []bigqueryapi.QueryParameter{
-		{Value: int64(1)}, {Value: int64(123)}, {Value: "hello"}, {Value: 3.14}, {Value: true},
-		{Value: int64(2)}, {Value: int64(-456)}, {Value: "world"}, {Value: -0.55}, {Value: false},
-		{Value: int64(3)}, {Value: int64(789)}, {Value: "test"}, {Value: 100.1}, {Value: true},
+	bodyBytes, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("unexpected status code: got %d, want %d. Body: %s", resp.StatusCode, http.StatusBadRequest, string(bodyBytes))
 	}
-	return createStatement, insertStatement, toolStatement, arrayToolStatement, params
-}
-
-// getBigQueryForecastToolInfo returns statements and params for the forecast tool.
-func getBigQueryForecastToolInfo(tableName string) (string, string, []bigqueryapi.QueryParameter) {
-	createStatement := fmt.Sprintf(`
-		CREATE TABLE IF NOT EXISTS %s (ts TIMESTAMP, data FLOAT64, id STRING);`, tableName)
-	insertStatement := fmt.Sprintf(`
-		INSERT INTO %s (ts, data, id) VALUES
-		(?, ?, ?), (?, ?, ?), (?, ?, ?), 
-		(?, ?, ?), (?, ?, ?), (?, ?, ?);`, tableName)
-	params := []bigqueryapi.QueryParameter{
-		{Value: "2025-01-01T00:00:00Z"}, {Value: 10.0}, {Value: "a"},
-		{Value: "2025-01-01T01:00:00Z"}, {Value: 11.0}, {Value: "a"},
-		{Value: "2025-01-01T02:00:00Z"}, {Value: 12.0}, {Value: "a"},
-		{Value: "2025-01-01T00:00:00Z"}, {Value: 20.0}, {Value: "b"},
-		{Value: "2025-01-01T01:00:00Z"}, {Value: 21.0}, {Value: "b"},
-		{Value: "2025-01-01T02:00:00Z"}, {Value: 22.0}, {Value: "b"},
+	wantInError := fmt.Sprintf("access denied to dataset '%s'", disallowedDatasetName)
+	if !strings.Contains(string(bodyBytes), wantInError) {
+		t.Errorf("unexpected error message: got %q, want to contain %q", string(bodyBytes), wantInError)
 	}
-	return createStatement, insertStatement, params
 }
 
-// getBigQueryAnalyzeContributionToolInfo returns statements and params for the analyze-contribution tool.
-func getBigQueryAnalyzeContributionToolInfo(tableName string) (string, string, []bigqueryapi.QueryParameter) {
-	createStatement := fmt.Sprintf(`
-		CREATE TABLE IF NOT EXISTS %s (dim1 STRING, dim2 STRING, is_test BOOL, metric FLOAT64);`, tableName)
-	insertStatement := fmt.Sprintf(`
-		INSERT INTO %s (dim1, dim2, is_test, metric) VALUES 
-		(?, ?, ?, ?), (?, ?, ?, ?), (?, ?, ?, ?), (?, ?, ?, ?);`, tableName)
-	params := []bigqueryapi.QueryParameter{
-		{Value: "a"}, {Value: "x"}, {Value: true}, {Value: 100.0},
-		{Value: "a"}, {Value: "x"}, {Value: false}, {Value: 110.0},
-		{Value: "a"}, {Value: "y"}, {Value: true}, {Value: 120.0},
-		{Value: "a"}, {Value: "y"}, {Value: false}, {Value: 100.0},
-		{Value: "b"}, {Value: "x"}, {Value: true}, {Value: 40.0},
-		{Value: "b"}, {Value: "x"}, {Value: false}, {Value: 100.0},
-		{Value: "b"}, {Value: "y"}, {Value: true}, {Value: 60.0},
-		{Value: "b"}, {Value: "y"}, {Value: false}, {Value: 60.0},
+func TestBigQueryValidateOnLoad(t *testing.T) {
+	sourceConfig := getBigQueryVars(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	tcs := []struct {
+		name      string
+		statement string
+		wantErr   bool
+	}{
+		{
+			name:      "valid statement starts",
+			statement: "SELECT @id AS id",
+		},
+		{
+			name:      "malformed statement fails startup",
+			statement: "SELEC @id AS id",
+			wantErr:   true,
+		},
 	}
-	return createStatement, insertStatement, params
-}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			config := map[string]any{
+				"sources": map[string]any{
+					"my-instance": sourceConfig,
+				},
+				"tools": map[string]any{
+					"my-validated-tool": map[string]any{
+						"kind":           "bigquery-sql",
+						"source":         "my-instance",
+						"description":    "Tool with a statement validated on load",
+						"statement":      tc.statement,
+						"validateOnLoad": true,
+						"parameters": []map[string]any{
+							{"name": "id", "type": "integer", "description": "an id"},
+						},
+					},
+				},
+			}
 
-// getBigQueryTmplToolStatement returns statements for template parameter test cases for bigquery kind
-func getBigQueryTmplToolStatement() (string, string) {
-	tmplSelectCombined := "SELECT * FROM {{.tableName}} WHERE id = ? ORDER BY id"
-	tmplSelectFilterCombined := "SELECT * FROM {{.tableName}} WHERE {{.columnFilter}} = ? ORDER BY id"
-	return tmplSelectCombined, tmplSelectFilterCombined
+			cmd, cleanup, err := tests.StartCmd(ctx, config)
+			if err != nil {
+				t.Fatalf("command initialization returned an error: %s", err)
+			}
+			defer cleanup()
+			defer cmd.Close()
+
+			waitCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+			defer cancel()
+			if tc.wantErr {
+				err := cmd.Wait(waitCtx)
+				if err == nil || !strings.Contains(err.Error(), `invalid statement for tool "my-validated-tool"`) {
+					t.Fatalf("expected toolbox to fail on the malformed statement, got: %v", err)
+				}
+				return
+			}
+			out, err := testutils.WaitForString(waitCtx, regexp.MustCompile(`Server ready to serve`), cmd.Out)
+			if err != nil {
+				t.Logf("toolbox command logs: \n%s", out)
+				t.Fatalf("toolbox didn't start successfully: %s", err)
+			}
+		})
+	}
 }
 
-func setupBigQueryTable(t *testing.T, ctx context.Context, client *bigqueryapi.Client, createStatement, insertStatement, datasetName string, tableName string, params []bigqueryapi.QueryParameter) func(*testing.T) {
-	// Create dataset
-	dataset := client.Dataset(datasetName)
-	_, err := dataset.Metadata(ctx)
-
-	if err != nil {
-		apiErr, ok := err.(*googleapi.Error)
-		if !ok || apiErr.Code != 404 {
-			t.Fatalf("Failed to check dataset %q existence: %v", datasetName, err)
-		}
-		metadataToCreate := &bigqueryapi.DatasetMetadata{Name: datasetName}
-		if err := dataset.Create(ctx, metadataToCreate); err != nil {
-			t.Fatalf("Failed to create dataset %q: %v", datasetName, err)
-		}
+func TestBigQueryGenerateEmbedding(t *testing.T) {
+	if BigqueryEmbeddingModel == "" {
+		t.Skip("'BIGQUERY_EMBEDDING_MODEL' not set")
 	}
+	sourceConfig := getBigQueryVars(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
 
-	// Create table
-	createJob, err := client.Query(createStatement).Run(ctx)
+	config := map[string]any{
+		"sources": map[string]any{
+			"my-instance": sourceConfig,
+		},
+		"tools": map[string]any{
+			"my-generate-embedding-tool": map[string]any{
+				"kind":        "bigquery-generate-embedding",
+				"source":      "my-instance",
+				"description": "Tool to generate text embeddings",
+			},
+		},
+	}
 
+	// Start server
+	cmd, cleanup, err := tests.StartCmd(ctx, config)
 	if err != nil {
-		t.Fatalf("Failed to start create table job for %s: %v", tableName, err)
+		t.Fatalf("command initialization returned an error: %s", err)
 	}
-	createStatus, err := createJob.Wait(ctx)
+	defer cleanup()
+
+	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	out, err := testutils.WaitForString(waitCtx, regexp.MustCompile(`Server ready to serve`), cmd.Out)
 	if err != nil {
-		t.Fatalf("Failed to wait for create table job for %s: %v", tableName, err)
-	}
-	if err := createStatus.Err(); err != nil {
-		t.Fatalf("Create table job for %s failed: %v", tableName, err)
+		t.Logf("toolbox command logs: \n%s", out)
+		t.Fatalf("toolbox didn't start successfully: %s", err)
 	}
 
-	if len(params) > 0 {
-		// Insert test data
-		insertQuery := client.Query(insertStatement)
-		insertQuery.Parameters = params
-		insertJob, err := insertQuery.Run(ctx)
-		if err != nil {
-			t.Fatalf("Failed to start insert job for %s: %v", tableName, err)
-		}
-		insertStatus, err := insertJob.Wait(ctx)
-		if err != nil {
-			t.Fatalf("Failed to wait for insert job for %s: %v", tableName, err)
-		}
-		if err := insertStatus.Err(); err != nil {
-			t.Fatalf("Insert job for %s failed: %v", tableName, err)
-		}
+	tcs := []struct {
+		name        string
+		requestBody map[string]any
+		wantRows    int
+		isErr       bool
+	}{
+		{
+			name:        "embed text",
+			requestBody: map[string]any{"model": BigqueryEmbeddingModel, "text": "wireless headphones"},
+			wantRows:    1,
+		},
+		{
+			name:        "embed query",
+			requestBody: map[string]any{"model": BigqueryEmbeddingModel, "input_data": "SELECT 'red' AS content UNION ALL SELECT 'blue' AS content"},
+			wantRows:    2,
+		},
+		{
+			name:        "invalid model reference",
+			requestBody: map[string]any{"model": "not a model", "text": "wireless headphones"},
+			isErr:       true,
+		},
+		{
+			name:        "both text and input data",
+			requestBody: map[string]any{"model": BigqueryEmbeddingModel, "text": "red", "input_data": "SELECT 'blue' AS content"},
+			isErr:       true,
+		},
+		{
+			name:        "neither text nor input data",
+			requestBody: map[string]any{"model": BigqueryEmbeddingModel},
+			isErr:       true,
+		},
 	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			reqBody, err := json.Marshal(tc.requestBody)
+			if err != nil {
+				t.Fatalf("unable to marshal request body: %s", err)
+			}
+			req, err := http.NewRequest(http.MethodPost, "http://127.0.0.1:5000/api/tool/my-generate-embedding-tool/invoke", bytes.NewBuffer(reqBody))
+			if err != nil {
+				t.Fatalf("unable to create request: %s", err)
+			}
+			req.Header.Add("Content-type", "application/json")
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("unable to send request: %s", err)
+			}
+			defer resp.Body.Close()
 
-	return func(t *testing.T) {
-		// tear down table
-		dropSQL := fmt.Sprintf("drop table %s", tableName)
-		dropJob, err := client.Query(dropSQL).Run(ctx)
-		if err != nil {
-			t.Errorf("Failed to start drop table job for %s: %v", tableName, err)
-			return
-		}
-		dropStatus, err := dropJob.Wait(ctx)
-		if err != nil {
-			t.Errorf("Failed to wait for drop table job for %s: %v", tableName, err)
-			return
-		}
-		if err := dropStatus.Err(); err != nil {
-			t.Errorf("Error dropping table %s: %v", tableName, err)
-		}
-
-		// tear down dataset
-		datasetToTeardown := client.Dataset(datasetName)
-		tablesIterator := datasetToTeardown.Tables(ctx)
-		_, err = tablesIterator.Next()
+			bodyBytes, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("unable to read response body: %s", err)
+			}
+			if tc.isErr {
+				if resp.StatusCode == http.StatusOK {
+					t.Fatalf("expected an error, got 200: %s", string(bodyBytes))
+				}
+				return
+			}
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("response status code is not 200, got %d: %s", resp.StatusCode, string(bodyBytes))
+			}
 
-		if err == iterator.Done {
-			if err := datasetToTeardown.Delete(ctx); err != nil {
-				t.Errorf("Failed to delete dataset %s: %v", datasetName, err)
+			var body map[string]interface{}
+			if err := json.Unmarshal(bodyBytes, &body); err != nil {
+				t.Fatalf("error parsing response body")
 			}
-		} else if err != nil {
-			t.Errorf("Failed to list tables in dataset %s to check emptiness: %v.", datasetName, err)
-		}
+			got, ok := body["result"].(string)
+			if !ok {
+				t.Fatalf("unable to find result in response body")
+			}
+			var rows []map[string]any
+			if err := json.Unmarshal([]byte(got), &rows); err != nil {
+				t.Fatalf("unable to parse result %q: %s", got, err)
+			}
+			if len(rows) != tc.wantRows {
+				t.Fatalf("unexpected number of rows: got %d, want %d", len(rows), tc.wantRows)
+			}
+			for _, row := range rows {
+				embedding, ok := row["ml_generate_embedding_result"].([]any)
+				if !ok || len(embedding) == 0 {
+					t.Errorf("expected a non-empty embedding in row %v", row)
+				}
+			}
+		})
 	}
 }
 
-func addBigQueryPrebuiltToolsConfig(t *testing.T, config map[string]any) map[string]any {
-	tools, ok := config["tools"].(map[string]any)
-	if !ok {
-		t.Fatalf("unable to get tools from config")
+func TestBigQueryMLPredict(t *testing.T) {
+	sourceConfig := getBigQueryVars(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	client, err := initBigQueryConnection(BigqueryProject)
+	if err != nil {
+		t.Fatalf("unable to create BigQuery client: %s", err)
 	}
-	tools["my-exec-sql-tool"] = map[string]any{
-		"kind":        "bigquery-execute-sql",
-		"source":      "my-instance",
-		"description": "Tool to execute sql",
+
+	datasetName := fmt.Sprintf("temp_toolbox_test_%s", strings.ReplaceAll(uuid.New().String(), "-", ""))
+	dataset := client.Dataset(datasetName)
+	if err := dataset.Create(ctx, &bigqueryapi.DatasetMetadata{Name: datasetName}); err != nil {
+		t.Fatalf("failed to create dataset %q: %s", datasetName, err)
 	}
-	tools["my-auth-exec-sql-tool"] = map[string]any{
-		"kind":        "bigquery-execute-sql",
-		"source":      "my-instance",
-		"description": "Tool to execute sql",
-		"authRequired": []string{
-			"my-google-auth",
-		},
+	defer func() {
+		if err := dataset.DeleteWithContents(ctx); err != nil {
+			t.Errorf("failed to delete dataset %q: %s", datasetName, err)
+		}
+	}()
+
+	// a linear regression of label = 2 * x
+	model := fmt.Sprintf("%s.linear_model", datasetName)
+	createModelStmt := fmt.Sprintf("CREATE MODEL `%s.%s` OPTIONS (model_type = 'linear_reg', input_label_cols = ['label']) AS "+
+		"SELECT x, 2 * x AS label FROM UNNEST(GENERATE_ARRAY(1, 20)) AS x", BigqueryProject, model)
+	job, err := client.Query(createModelStmt).Run(ctx)
+	if err != nil {
+		t.Fatalf("failed to start create model job: %s", err)
 	}
-	tools["my-client-auth-exec-sql-tool"] = map[string]any{
-		"kind":        "bigquery-execute-sql",
-		"source":      "my-client-auth-source",
-		"description": "Tool to execute sql",
+	status, err := job.Wait(ctx)
+	if err != nil {
+		t.Fatalf("failed to wait for create model job: %s", err)
 	}
-	tools["my-forecast-tool"] = map[string]any{
-		"kind":        "bigquery-forecast",
-		"source":      "my-instance",
-		"description": "Tool to forecast time series data.",
+	if err := status.Err(); err != nil {
+		t.Fatalf("create model job failed: %s", err)
 	}
-	tools["my-auth-forecast-tool"] = map[string]any{
-		"kind":        "bigquery-forecast",
-		"source":      "my-instance",
-		"description": "Tool to forecast time series data with auth.",
-		"authRequired": []string{
-			"my-google-auth",
+
+	config := map[string]any{
+		"sources": map[string]any{
+			"my-instance": sourceConfig,
 		},
-	}
-	tools["my-client-auth-forecast-tool"] = map[string]any{
-		"kind":        "bigquery-forecast",
-		"source":      "my-client-auth-source",
-		"description": "Tool to forecast time series data with auth.",
-	}
-	tools["my-analyze-contribution-tool"] = map[string]any{
-		"kind":        "bigquery-analyze-contribution",
-		"source":      "my-instance",
-		"description": "Tool to analyze contribution.",
-	}
-	tools["my-auth-analyze-contribution-tool"] = map[string]any{
-		"kind":        "bigquery-analyze-contribution",
-		"source":      "my-instance",
-		"description": "Tool to analyze contribution with auth.",
-		"authRequired": []string{
-			"my-google-auth",
+		"tools": map[string]any{
+			"my-ml-predict-tool": map[string]any{
+				"kind":        "bigquery-ml-predict",
+				"source":      "my-instance",
+				"description": "Tool to run predictions with a model",
+			},
 		},
 	}
-	tools["my-client-auth-analyze-contribution-tool"] = map[string]any{
-		"kind":        "bigquery-analyze-contribution",
-		"source":      "my-client-auth-source",
-		"description": "Tool to analyze contribution with auth.",
+
+	// Start server
+	cmd, cleanup, err := tests.StartCmd(ctx, config)
+	if err != nil {
+		t.Fatalf("command initialization returned an error: %s", err)
 	}
-	tools["my-list-dataset-ids-tool"] = map[string]any{
-		"kind":        "bigquery-list-dataset-ids",
-		"source":      "my-instance",
-		"description": "Tool to list dataset",
+	defer cleanup()
+
+	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	out, err := testutils.WaitForString(waitCtx, regexp.MustCompile(`Server ready to serve`), cmd.Out)
+	if err != nil {
+		t.Logf("toolbox command logs: \n%s", out)
+		t.Fatalf("toolbox didn't start successfully: %s", err)
 	}
-	tools["my-auth-list-dataset-ids-tool"] = map[string]any{
-		"kind":        "bigquery-list-dataset-ids",
-		"source":      "my-instance",
-		"description": "Tool to list dataset",
-		"authRequired": []string{
-			"my-google-auth",
+
+	tcs := []struct {
+		name        string
+		requestBody map[string]any
+		wantRows    int
+		isErr       bool
+	}{
+		{
+			name:        "predict inline rows",
+			requestBody: map[string]any{"model": model, "rows": `[{"x": 3}, {"x": 4.5}, {"x": 10}]`},
+			wantRows:    3,
 		},
-	}
-	tools["my-client-auth-list-dataset-ids-tool"] = map[string]any{
-		"kind":        "bigquery-list-dataset-ids",
-		"source":      "my-client-auth-source",
-		"description": "Tool to list dataset",
-	}
-	tools["my-get-dataset-info-tool"] = map[string]any{
-		"kind":        "bigquery-get-dataset-info",
-		"source":      "my-instance",
-		"description": "Tool to show dataset metadata",
-	}
-	tools["my-auth-get-dataset-info-tool"] = map[string]any{
-		"kind":        "bigquery-get-dataset-info",
-		"source":      "my-instance",
-		"description": "Tool to show dataset metadata",
-		"authRequired": []string{
-			"my-google-auth",
+		{
+			name:        "predict query",
+			requestBody: map[string]any{"model": model, "input_data": "SELECT x FROM UNNEST([1, 2]) AS x"},
+			wantRows:    2,
 		},
-	}
-	tools["my-client-auth-get-dataset-info-tool"] = map[string]any{
-		"kind":        "bigquery-get-dataset-info",
-		"source":      "my-client-auth-source",
-		"description": "Tool to show dataset metadata",
-	}
-	tools["my-list-table-ids-tool"] = map[string]any{
-		"kind":        "bigquery-list-table-ids",
-		"source":      "my-instance",
-		"description": "Tool to list table within a dataset",
-	}
-	tools["my-auth-list-table-ids-tool"] = map[string]any{
-		"kind":        "bigquery-list-table-ids",
-		"source":      "my-instance",
-		"description": "Tool to list table within a dataset",
-		"authRequired": []string{
-			"my-google-auth",
+		{
+			name:        "invalid model reference",
+			requestBody: map[string]any{"model": "not a model", "rows": `[{"x": 3}]`},
+			isErr:       true,
 		},
-	}
-	tools["my-client-auth-list-table-ids-tool"] = map[string]any{
-		"kind":        "bigquery-list-table-ids",
-		"source":      "my-client-auth-source",
-		"description": "Tool to list table within a dataset",
-	}
-	tools["my-get-table-info-tool"] = map[string]any{
-		"kind":        "bigquery-get-table-info",
-		"source":      "my-instance",
-		"description": "Tool to show dataset metadata",
-	}
-	tools["my-auth-get-table-info-tool"] = map[string]any{
-		"kind":        "bigquery-get-table-info",
-		"source":      "my-instance",
-		"description": "Tool to show dataset metadata",
-		"authRequired": []string{
-			"my-google-auth",
+		{
+			name:        "invalid rows",
+			requestBody: map[string]any{"model": model, "rows": `{"x": 3}`},
+			isErr:       true,
 		},
-	}
-	tools["my-client-auth-get-table-info-tool"] = map[string]any{
-		"kind":        "bigquery-get-table-info",
-		"source":      "my-client-auth-source",
-		"description": "Tool to show dataset metadata",
-	}
-	tools["my-conversational-analytics-tool"] = map[string]any{
-		"kind":        "bigquery-conversational-analytics",
-		"source":      "my-instance",
-		"description": "Tool to ask BigQuery conversational analytics",
-	}
-	tools["my-auth-conversational-analytics-tool"] = map[string]any{
-		"kind":        "bigquery-conversational-analytics",
-		"source":      "my-instance",
-		"description": "Tool to ask BigQuery conversational analytics",
-		"authRequired": []string{
-			"my-google-auth",
+		{
+			name:        "both input data and rows",
+			requestBody: map[string]any{"model": model, "input_data": "SELECT 1 AS x", "rows": `[{"x": 3}]`},
+			isErr:       true,
 		},
-	}
-	tools["my-client-auth-conversational-analytics-tool"] = map[string]any{
-		"kind":        "bigquery-conversational-analytics",
-		"source":      "my-client-auth-source",
-		"description": "Tool to ask BigQuery conversational analytics",
-	}
-	tools["my-search-catalog-tool"] = map[string]any{
-		"kind":        "bigquery-search-catalog",
-		"source":      "my-instance",
-		"description": "Tool to search the BiqQuery catalog",
-	}
-	tools["my-auth-search-catalog-tool"] = map[string]any{
-		"kind":        "bigquery-search-catalog",
-		"source":      "my-instance",
-		"description": "Tool to search the BiqQuery catalog",
-		"authRequired": []string{
-			"my-google-auth",
+		{
+			name:        "neither input data nor rows",
+			requestBody: map[string]any{"model": model},
+			isErr:       true,
 		},
 	}
-	tools["my-client-auth-search-catalog-tool"] = map[string]any{
-		"kind":        "bigquery-search-catalog",
-		"source":      "my-client-auth-source",
-		"description": "Tool to search the BiqQuery catalog",
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			reqBody, err := json.Marshal(tc.requestBody)
+			if err != nil {
+				t.Fatalf("unable to marshal request body: %s", err)
+			}
+			req, err := http.NewRequest(http.MethodPost, "http://127.0.0.1:5000/api/tool/my-ml-predict-tool/invoke", bytes.NewBuffer(reqBody))
+			if err != nil {
+				t.Fatalf("unable to create request: %s", err)
+			}
+			req.Header.Add("Content-type", "application/json")
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("unable to send request: %s", err)
+			}
+			defer resp.Body.Close()
+
+			bodyBytes, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("unable to read response body: %s", err)
+			}
+			if tc.isErr {
+				if resp.StatusCode == http.StatusOK {
+					t.Fatalf("expected an error, got 200: %s", string(bodyBytes))
+				}
+				return
+			}
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("response status code is not 200, got %d: %s", resp.StatusCode, string(bodyBytes))
+			}
+
+			var body map[string]interface{}
+			if err := json.Unmarshal(bodyBytes, &body); err != nil {
+				t.Fatalf("error parsing response body")
+			}
+			got, ok := body["result"].(string)
+			if !ok {
+				t.Fatalf("unable to find result in response body")
+			}
+			var rows []map[string]any
+			if err := json.Unmarshal([]byte(got), &rows); err != nil {
+				t.Fatalf("unable to parse result %q: %s", got, err)
+			}
+			if len(rows) != tc.wantRows {
+				t.Fatalf("unexpected number of rows: got %d, want %d", len(rows), tc.wantRows)
+			}
+			for _, row := range rows {
+				x, okX := row["x"].(float64)
+				predicted, okPredicted := row["predicted_label"].(float64)
+				if !okX || !okPredicted {
+					t.Fatalf("expected x and predicted_label in row %v", row)
+				}
+				if math.Abs(predicted-2*x) > 0.5 {
+					t.Errorf("unexpected prediction for x = %v: got %v, want about %v", x, predicted, 2*x)
+				}
+			}
+		})
 	}
-	config["tools"] = tools
-	return config
 }
 
-func addClientAuthSourceConfig(t *testing.T, config map[string]any) map[string]any {
-	sources, ok := config["sources"].(map[string]any)
-	if !ok {
-		t.Fatalf("unable to get sources from config")
-	}
-	sources["my-client-auth-source"] = map[string]any{
-		"kind":           BigquerySourceKind,
-		"project":        BigqueryProject,
-		"useClientOAuth": true,
+func TestBigQueryAllowedProjects(t *testing.T) {
+	if BigquerySecondProject == "" {
+		t.Skip("'BIGQUERY_SECOND_PROJECT' not set")
 	}
-	config["sources"] = sources
-	return config
-}
+	sourceConfig := getBigQueryVars(t)
+	sourceConfig["allowedProjects"] = []string{BigquerySecondProject}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
 
-func addBigQuerySqlToolConfig(t *testing.T, config map[string]any, toolStatement, arrayToolStatement string) map[string]any {
-	tools, ok := config["tools"].(map[string]any)
-	if !ok {
-		t.Fatalf("unable to get tools from config")
+	// a table in the second project for the metadata tools
+	secondClient, err := initBigQueryConnection(BigquerySecondProject)
+	if err != nil {
+		t.Fatalf("unable to create BigQuery client for the second project: %s", err)
 	}
-	tools["my-scalar-datatype-tool"] = map[string]any{
-		"kind":        "bigquery-sql",
-		"source":      "my-instance",
-		"description": "Tool to test various scalar data types.",
-		"statement":   toolStatement,
-		"parameters": []any{
-			map[string]any{"name": "int_val", "type": "integer", "description": "an integer value"},
-			map[string]any{"name": "string_val", "type": "string", "description": "a string value"},
-			map[string]any{"name": "float_val", "type": "float", "description": "a float value"},
-			map[string]any{"name": "bool_val", "type": "boolean", "description": "a boolean value"},
+	datasetName := fmt.Sprintf("temp_toolbox_test_%s", strings.ReplaceAll(uuid.New().String(), "-", ""))
+	tableName := fmt.Sprintf("projects_table_%s", strings.ReplaceAll(uuid.New().String(), "-", ""))
+	fullTableName := fmt.Sprintf("`%s.%s.%s`", BigquerySecondProject, datasetName, tableName)
+	teardownTable := setupBigQueryTable(t, ctx, secondClient, fmt.Sprintf("CREATE TABLE %s (id INT64)", fullTableName), "", datasetName, fullTableName, nil)
+	defer teardownTable(t)
+
+	config := map[string]any{
+		"sources": map[string]any{
+			"my-instance": sourceConfig,
+		},
+		"tools": map[string]any{
+			"my-exec-sql-tool": map[string]any{
+				"kind":        "bigquery-execute-sql",
+				"source":      "my-instance",
+				"description": "Tool to execute sql",
+			},
+			"my-list-dataset-ids-tool": map[string]any{
+				"kind":        "bigquery-list-dataset-ids",
+				"source":      "my-instance",
+				"description": "Tool to list datasets",
+			},
+			"my-list-columns-tool": map[string]any{
+				"kind":        "bigquery-list-columns",
+				"source":      "my-instance",
+				"description": "Tool to list columns",
+			},
+			"my-saved-query-tool": map[string]any{
+				"kind":        "bigquery-run-saved-query",
+				"source":      "my-instance",
+				"description": "Tool to run saved queries",
+				"queries": map[string]any{
+					"project_id": map[string]any{"statement": "SELECT @@project_id AS project_id"},
+				},
+			},
 		},
 	}
-	tools["my-array-datatype-tool"] = map[string]any{
-		"kind":        "bigquery-sql",
-		"source":      "my-instance",
-		"description": "Tool to test various array data types.",
-		"statement":   arrayToolStatement,
-		"parameters": []any{
-			map[string]any{"name": "int_array", "type": "array", "description": "an array of integer values", "items": map[string]any{"name": "item", "type": "integer", "description": "desc"}},
+
+	// Start server
+	cmd, cleanup, err := tests.StartCmd(ctx, config)
+	if err != nil {
+		t.Fatalf("command initialization returned an error: %s", err)
+	}
+	defer cleanup()
+
+	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	out, err := testutils.WaitForString(waitCtx, regexp.MustCompile(`Server ready to serve`), cmd.Out)
+	if err != nil {
+		t.Logf("toolbox command logs: \n%s", out)
+		t.Fatalf("toolbox didn't start successfully: %s", err)
+	}
+
+	// @@project_id is the project the query job runs in
+	const sql = "SELECT @@project_id AS project_id"
+	tcs := []struct {
+		name        string
+		toolName    string
+		requestBody map[string]any
+		want        string
+		isErr       bool
+	}{
+		{
+			name:        "query in the source project by default",
+			toolName:    "my-exec-sql-tool",
+			requestBody: map[string]any{"sql": sql},
+			want:        fmt.Sprintf(`[{"project_id":"%s"}]`, BigqueryProject),
+		},
+		{
+			name:        "query in the source project",
+			toolName:    "my-exec-sql-tool",
+			requestBody: map[string]any{"sql": sql, "project": BigqueryProject},
+			want:        fmt.Sprintf(`[{"project_id":"%s"}]`, BigqueryProject),
+		},
+		{
+			name:        "query in an allowed project",
+			toolName:    "my-exec-sql-tool",
+			requestBody: map[string]any{"sql": sql, "project": BigquerySecondProject},
+			want:        fmt.Sprintf(`[{"project_id":"%s"}]`, BigquerySecondProject),
+		},
+		{
+			name:        "query in a project that is not allowed",
+			toolName:    "my-exec-sql-tool",
+			requestBody: map[string]any{"sql": sql, "project": "not-an-allowed-project"},
+			isErr:       true,
+		},
+		{
+			name:        "list columns in an allowed project",
+			toolName:    "my-list-columns-tool",
+			requestBody: map[string]any{"project": BigquerySecondProject, "dataset": datasetName, "table": tableName},
+			want:        `[{"column_name":"id","data_type":"INT64","mode":"NULLABLE","ordinal_position":1}]`,
+		},
+		{
+			name:        "list columns in a project that is not allowed",
+			toolName:    "my-list-columns-tool",
+			requestBody: map[string]any{"project": "not-an-allowed-project", "dataset": datasetName, "table": tableName},
+			isErr:       true,
+		},
+		{
+			name:        "run a saved query in an allowed project",
+			toolName:    "my-saved-query-tool",
+			requestBody: map[string]any{"name": "project_id", "project": BigquerySecondProject},
+			want:        fmt.Sprintf(`[{"project_id":"%s"}]`, BigquerySecondProject),
+		},
+		{
+			name:        "list datasets in a project that is not allowed",
+			toolName:    "my-list-dataset-ids-tool",
+			requestBody: map[string]any{"project": "not-an-allowed-project"},
+			isErr:       true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			reqBody, err := json.Marshal(tc.requestBody)
+			if err != nil {
+				t.Fatalf("unable to marshal request body: %s", err)
+			}
+			req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("http://127.0.0.1:5000/api/tool/%s/invoke", tc.toolName), bytes.NewBuffer(reqBody))
+			if err != nil {
+				t.Fatalf("unable to create request: %s", err)
+			}
+			req.Header.Add("Content-type", "application/json")
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("unable to send request: %s", err)
+			}
+			defer resp.Body.Close()
+
+			bodyBytes, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("unable to read response body: %s", err)
+			}
+			if tc.isErr {
+				if resp.StatusCode == http.StatusOK {
+					t.Fatalf("expected an error, got 200: %s", string(bodyBytes))
+				}
+				return
+			}
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("response status code is not 200, got %d: %s", resp.StatusCode, string(bodyBytes))
+			}
+
+			var body map[string]interface{}
+			if err := json.Unmarshal(bodyBytes, &body); err != nil {
+				t.Fatalf("error parsing response body")
+			}
+			got, ok := body["result"].(string)
+			if !ok {
+				t.Fatalf("unable to find result in response body")
+			}
+			if got != tc.want {
+				t.Fatalf("unexpected value: got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func runLoadFromGCSWithRestriction(t *testing.T, disallowedDatasetName string) {
+	body := bytes.NewBuffer([]byte(fmt.Sprintf(`{"source_uris": ["gs://cloud-samples-data/bigquery/us-states/us-states.csv"], "dataset": "%s", "table": "loaded_table"}`, disallowedDatasetName)))
+	req, err := http.NewRequest(http.MethodPost, "http://127.0.0.1:5000/api/tool/load-from-gcs-restricted/invoke", body)
+	if err != nil {
+		t.Fatalf("unable to create request: %s", err)
+	}
+	req.Header.Add("Content-type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("unable to send request: %s", err)
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("unexpected status code: got %d, want %d. Body: %s", resp.StatusCode, http.StatusBadRequest, string(bodyBytes))
+	}
+	wantInError := fmt.Sprintf("access denied to dataset '%s'", disallowedDatasetName)
+	if !strings.Contains(string(bodyBytes), wantInError) {
+		t.Errorf("unexpected error message: got %q, want to contain %q", string(bodyBytes), wantInError)
+	}
+}
+
+// getBigQueryParamToolInfo returns statements and param for my-tool for bigquery kind
+func getBigQueryParamToolInfo(tableName string) (string, string, string, string, string, string, []bigqueryapi.QueryParameter) {
+	createStatement := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (id INT64, name STRING);`, tableName)
+	insertStatement := fmt.Sprintf(`
+		INSERT INTO %s (id, name) VALUES (?, ?), (?, ?), (?, ?), (?, NULL);`, tableName)
+	toolStatement := fmt.Sprintf(`SELECT * FROM %s WHERE id = ? OR name = ? ORDER BY id;`, tableName)
+	idToolStatement := fmt.Sprintf(`SELECT * FROM %s WHERE id = ? ORDER BY id;`, tableName)
+	nameToolStatement := fmt.Sprintf(`SELECT * FROM %s WHERE name = ? ORDER BY id;`, tableName)
+	arrayToolStatememt := fmt.Sprintf(`SELECT * FROM %s WHERE id IN UNNEST(@idArray) AND name IN UNNEST(@nameArray) ORDER BY id;`, tableName)
+	params := []bigqueryapi.QueryParameter{
+		{Value: int64(1)}, {Value: "Alice"},
+		{Value: int64(2)}, {Value: "Jane"},
+		{Value: int64(3)}, {Value: "Sid"},
+		{Value: int64(4)},
+	}
+	return createStatement, insertStatement, toolStatement, idToolStatement, nameToolStatement, arrayToolStatememt, params
+}
+
+// getBigQueryAuthToolInfo returns statements and param of my-auth-tool for bigquery kind
+func getBigQueryAuthToolInfo(tableName string) (string, string, string, []bigqueryapi.QueryParameter) {
+	createStatement := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (id INT64, name STRING, email STRING)`, tableName)
+	insertStatement := fmt.Sprintf(`
+		INSERT INTO %s (id, name, email) VALUES (?, ?, ?), (?, ?, ?)`, tableName)
+	toolStatement := fmt.Sprintf(`
+		SELECT name FROM %s WHERE email = ?`, tableName)
+	params := []bigqueryapi.QueryParameter{
+		{Value: int64(1)}, {Value: "Alice"}, {Value: tests.ServiceAccountEmail},
+		{Value: int64(2)}, {Value: "Jane"}, {Value: "janedoe@gmail.com"},
+	}
+	return createStatement, insertStatement, toolStatement, params
+}
+
+// getBigQueryDataTypeTestInfo returns statements and params for data type tests.
+func getBigQueryDataTypeTestInfo(tableName string) (string, string, string, string, []bigqueryapi.QueryParameter) {
+	createStatement := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (id INT64, int_val INT64, string_val STRING, float_val FLOAT64, bool_val BOOL);`, tableName)
+	insertStatement := fmt.Sprintf(`
+		INSERT INTO %s (id, int_val, string_val, float_val, bool_val) VALUES (?, ?, ?, ?, ?), (?, ?, ?, ?, ?), (?, ?, ?, ?, ?);`, tableName)
+	toolStatement := fmt.Sprintf(`SELECT * FROM %s WHERE int_val = ? AND string_val = ? AND float_val = ? AND bool_val = ?;`, tableName)
+	arrayToolStatement := fmt.Sprintf(`SELECT * FROM %s WHERE int_val IN UNNEST(@int_array) AND string_val IN UNNEST(@string_array) AND float_val IN UNNEST(@float_array) AND bool_val IN UNNEST(@bool_array) ORDER BY id;`, tableName)
+	params := []bigqueryapi.QueryParameter{
+		{Value: int64(1)}, {Value: int64(123)}, {Value: "hello"}, {Value: 3.14}, {Value: true},
+		{Value: int64(2)}, {Value: int64(-456)}, {Value: "world"}, {Value: -0.55}, {Value: false},
+		{Value: int64(3)}, {Value: int64(789)}, {Value: "test"}, {Value: 100.1}, {Value: true},
+	}
+	return createStatement, insertStatement, toolStatement, arrayToolStatement, params
+}
+
+// getBigQueryForecastToolInfo returns statements and params for the forecast tool.
+func getBigQueryForecastToolInfo(tableName string) (string, string, []bigqueryapi.QueryParameter) {
+	createStatement := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (ts TIMESTAMP, data FLOAT64, id STRING);`, tableName)
+	insertStatement := fmt.Sprintf(`
+		INSERT INTO %s (ts, data, id) VALUES
+		(?, ?, ?), (?, ?, ?), (?, ?, ?), 
+		(?, ?, ?), (?, ?, ?), (?, ?, ?);`, tableName)
+	params := []bigqueryapi.QueryParameter{
+		{Value: "2025-01-01T00:00:00Z"}, {Value: 10.0}, {Value: "a"},
+		{Value: "2025-01-01T01:00:00Z"}, {Value: 11.0}, {Value: "a"},
+		{Value: "2025-01-01T02:00:00Z"}, {Value: 12.0}, {Value: "a"},
+		{Value: "2025-01-01T00:00:00Z"}, {Value: 20.0}, {Value: "b"},
+		{Value: "2025-01-01T01:00:00Z"}, {Value: 21.0}, {Value: "b"},
+		{Value: "2025-01-01T02:00:00Z"}, {Value: 22.0}, {Value: "b"},
+	}
+	return createStatement, insertStatement, params
+}
+
+// getBigQueryVectorSearchToolInfo returns statements and params for the vector-search tool.
+func getBigQueryVectorSearchToolInfo(tableName string) (string, string, []bigqueryapi.QueryParameter) {
+	createStatement := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (id INT64, name STRING, embedding ARRAY<FLOAT64>);`, tableName)
+	insertStatement := fmt.Sprintf(`
+		INSERT INTO %s (id, name, embedding) VALUES
+		(?, ?, ?), (?, ?, ?), (?, ?, ?);`, tableName)
+	params := []bigqueryapi.QueryParameter{
+		{Value: 1}, {Value: "east"}, {Value: []float64{1, 0}},
+		{Value: 2}, {Value: "north"}, {Value: []float64{0, 1}},
+		{Value: 3}, {Value: "mostly east"}, {Value: []float64{0.9, 0.1}},
+	}
+	return createStatement, insertStatement, params
+}
+
+// getBigQueryAnalyzeContributionToolInfo returns statements and params for the analyze-contribution tool.
+func getBigQueryAnalyzeContributionToolInfo(tableName string) (string, string, []bigqueryapi.QueryParameter) {
+	createStatement := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (dim1 STRING, dim2 STRING, is_test BOOL, metric FLOAT64);`, tableName)
+	insertStatement := fmt.Sprintf(`
+		INSERT INTO %s (dim1, dim2, is_test, metric) VALUES 
+		(?, ?, ?, ?), (?, ?, ?, ?), (?, ?, ?, ?), (?, ?, ?, ?);`, tableName)
+	params := []bigqueryapi.QueryParameter{
+		{Value: "a"}, {Value: "x"}, {Value: true}, {Value: 100.0},
+		{Value: "a"}, {Value: "x"}, {Value: false}, {Value: 110.0},
+		{Value: "a"}, {Value: "y"}, {Value: true}, {Value: 120.0},
+		{Value: "a"}, {Value: "y"}, {Value: false}, {Value: 100.0},
+		{Value: "b"}, {Value: "x"}, {Value: true}, {Value: 40.0},
+		{Value: "b"}, {Value: "x"}, {Value: false}, {Value: 100.0},
+		{Value: "b"}, {Value: "y"}, {Value: true}, {Value: 60.0},
+		{Value: "b"}, {Value: "y"}, {Value: false}, {Value: 60.0},
+	}
+	return createStatement, insertStatement, params
+}
+
+// getBigQueryTmplToolStatement returns statements for template parameter test cases for bigquery kind
+func getBigQueryTmplToolStatement() (string, string) {
+	tmplSelectCombined := "SELECT * FROM {{.tableName}} WHERE id = ? ORDER BY id"
+	tmplSelectFilterCombined := "SELECT * FROM {{.tableName}} WHERE {{.columnFilter}} = ? ORDER BY id"
+	return tmplSelectCombined, tmplSelectFilterCombined
+}
+
+func setupBigQueryTable(t *testing.T, ctx context.Context, client *bigqueryapi.Client, createStatement, insertStatement, datasetName string, tableName string, params []bigqueryapi.QueryParameter) func(*testing.T) {
+	// Create dataset
+	dataset := client.Dataset(datasetName)
+	_, err := dataset.Metadata(ctx)
+
+	if err != nil {
+		apiErr, ok := err.(*googleapi.Error)
+		if !ok || apiErr.Code != 404 {
+			t.Fatalf("Failed to check dataset %q existence: %v", datasetName, err)
+		}
+		metadataToCreate := &bigqueryapi.DatasetMetadata{Name: datasetName}
+		if err := dataset.Create(ctx, metadataToCreate); err != nil {
+			t.Fatalf("Failed to create dataset %q: %v", datasetName, err)
+		}
+	}
+
+	// Create table
+	createJob, err := client.Query(createStatement).Run(ctx)
+
+	if err != nil {
+		t.Fatalf("Failed to start create table job for %s: %v", tableName, err)
+	}
+	createStatus, err := createJob.Wait(ctx)
+	if err != nil {
+		t.Fatalf("Failed to wait for create table job for %s: %v", tableName, err)
+	}
+	if err := createStatus.Err(); err != nil {
+		t.Fatalf("Create table job for %s failed: %v", tableName, err)
+	}
+
+	if len(params) > 0 {
+		// Insert test data
+		insertQuery := client.Query(insertStatement)
+		insertQuery.Parameters = params
+		insertJob, err := insertQuery.Run(ctx)
+		if err != nil {
+			t.Fatalf("Failed to start insert job for %s: %v", tableName, err)
+		}
+		insertStatus, err := insertJob.Wait(ctx)
+		if err != nil {
+			t.Fatalf("Failed to wait for insert job for %s: %v", tableName, err)
+		}
+		if err := insertStatus.Err(); err != nil {
+			t.Fatalf("Insert job for %s failed: %v", tableName, err)
+		}
+	}
+
+	return func(t *testing.T) {
+		// tear down table
+		dropSQL := fmt.Sprintf("drop table %s", tableName)
+		dropJob, err := client.Query(dropSQL).Run(ctx)
+		if err != nil {
+			t.Errorf("Failed to start drop table job for %s: %v", tableName, err)
+			return
+		}
+		dropStatus, err := dropJob.Wait(ctx)
+		if err != nil {
+			t.Errorf("Failed to wait for drop table job for %s: %v", tableName, err)
+			return
+		}
+		if err := dropStatus.Err(); err != nil {
+			t.Errorf("Error dropping table %s: %v", tableName, err)
+		}
+
+		// tear down dataset
+		datasetToTeardown := client.Dataset(datasetName)
+		tablesIterator := datasetToTeardown.Tables(ctx)
+		_, err = tablesIterator.Next()
+
+		if err == iterator.Done {
+			if err := datasetToTeardown.Delete(ctx); err != nil {
+				t.Errorf("Failed to delete dataset %s: %v", datasetName, err)
+			}
+		} else if err != nil {
+			t.Errorf("Failed to list tables in dataset %s to check emptiness: %v.", datasetName, err)
+		}
+	}
+}
+
+// addBigQuerySavedQueryToolConfig adds a bigquery-run-saved-query tool with
+// saved queries over the given table.
+func addBigQuerySavedQueryToolConfig(t *testing.T, config map[string]any, tableName string) map[string]any {
+	tools, ok := config["tools"].(map[string]any)
+	if !ok {
+		t.Fatalf("unable to get tools from config")
+	}
+	tools["my-saved-query-tool"] = map[string]any{
+		"kind":        "bigquery-run-saved-query",
+		"source":      "my-instance",
+		"description": "Tool to run approved queries",
+		"queries": map[string]any{
+			"get_by_id": map[string]any{
+				"description": "Gets the rows with the given id.",
+				"statement":   fmt.Sprintf("SELECT id, name FROM %s WHERE id = @id ORDER BY id;", tableName),
+				"parameters": []any{
+					map[string]any{
+						"name":        "id",
+						"type":        "integer",
+						"description": "The id to look up.",
+					},
+				},
+			},
+			"count_rows": map[string]any{
+				"description": "Counts the rows in the table.",
+				"statement":   fmt.Sprintf("SELECT COUNT(*) AS total FROM %s;", tableName),
+			},
+		},
+	}
+	config["tools"] = tools
+	return config
+}
+
+func addBigQueryPrebuiltToolsConfig(t *testing.T, config map[string]any) map[string]any {
+	tools, ok := config["tools"].(map[string]any)
+	if !ok {
+		t.Fatalf("unable to get tools from config")
+	}
+	tools["my-exec-sql-tool"] = map[string]any{
+		"kind":        "bigquery-execute-sql",
+		"source":      "my-instance",
+		"description": "Tool to execute sql",
+	}
+	tools["my-auth-exec-sql-tool"] = map[string]any{
+		"kind":        "bigquery-execute-sql",
+		"source":      "my-instance",
+		"description": "Tool to execute sql",
+		"authRequired": []string{
+			"my-google-auth",
+		},
+	}
+	tools["my-client-auth-exec-sql-tool"] = map[string]any{
+		"kind":        "bigquery-execute-sql",
+		"source":      "my-client-auth-source",
+		"description": "Tool to execute sql",
+	}
+	tools["my-denied-exec-sql-tool"] = map[string]any{
+		"kind":                 "bigquery-execute-sql",
+		"source":               "my-instance",
+		"description":          "Tool to execute sql",
+		"deniedStatementTypes": []string{"DROP_TABLE"},
+	}
+	tools["my-row-access-exec-sql-tool"] = map[string]any{
+		"kind":                  "bigquery-execute-sql",
+		"source":                "my-instance",
+		"description":           "Tool to execute sql",
+		"warnOnRowAccessPolicy": true,
+	}
+	tools["my-vector-search-tool"] = map[string]any{
+		"kind":        "bigquery-vector-search",
+		"source":      "my-instance",
+		"description": "Tool to find the nearest rows to an embedding.",
+	}
+	tools["my-forecast-tool"] = map[string]any{
+		"kind":        "bigquery-forecast",
+		"source":      "my-instance",
+		"description": "Tool to forecast time series data.",
+	}
+	tools["my-auth-forecast-tool"] = map[string]any{
+		"kind":        "bigquery-forecast",
+		"source":      "my-instance",
+		"description": "Tool to forecast time series data with auth.",
+		"authRequired": []string{
+			"my-google-auth",
+		},
+	}
+	tools["my-client-auth-forecast-tool"] = map[string]any{
+		"kind":        "bigquery-forecast",
+		"source":      "my-client-auth-source",
+		"description": "Tool to forecast time series data with auth.",
+	}
+	tools["my-query-external-tool"] = map[string]any{
+		"kind":        "bigquery-query-external",
+		"source":      "my-instance",
+		"description": "Tool to query external data in Cloud Storage.",
+	}
+	tools["my-client-auth-query-external-tool"] = map[string]any{
+		"kind":        "bigquery-query-external",
+		"source":      "my-client-auth-source",
+		"description": "Tool to query external data in Cloud Storage with auth.",
+	}
+	tools["my-analyze-contribution-tool"] = map[string]any{
+		"kind":        "bigquery-analyze-contribution",
+		"source":      "my-instance",
+		"description": "Tool to analyze contribution.",
+	}
+	tools["my-auth-analyze-contribution-tool"] = map[string]any{
+		"kind":        "bigquery-analyze-contribution",
+		"source":      "my-instance",
+		"description": "Tool to analyze contribution with auth.",
+		"authRequired": []string{
+			"my-google-auth",
+		},
+	}
+	tools["my-client-auth-analyze-contribution-tool"] = map[string]any{
+		"kind":        "bigquery-analyze-contribution",
+		"source":      "my-client-auth-source",
+		"description": "Tool to analyze contribution with auth.",
+	}
+	tools["my-list-dataset-ids-tool"] = map[string]any{
+		"kind":        "bigquery-list-dataset-ids",
+		"source":      "my-instance",
+		"description": "Tool to list dataset",
+	}
+	tools["my-auth-list-dataset-ids-tool"] = map[string]any{
+		"kind":        "bigquery-list-dataset-ids",
+		"source":      "my-instance",
+		"description": "Tool to list dataset",
+		"authRequired": []string{
+			"my-google-auth",
+		},
+	}
+	tools["my-client-auth-list-dataset-ids-tool"] = map[string]any{
+		"kind":        "bigquery-list-dataset-ids",
+		"source":      "my-client-auth-source",
+		"description": "Tool to list dataset",
+	}
+	tools["my-get-dataset-info-tool"] = map[string]any{
+		"kind":        "bigquery-get-dataset-info",
+		"source":      "my-instance",
+		"description": "Tool to show dataset metadata",
+	}
+	tools["my-auth-get-dataset-info-tool"] = map[string]any{
+		"kind":        "bigquery-get-dataset-info",
+		"source":      "my-instance",
+		"description": "Tool to show dataset metadata",
+		"authRequired": []string{
+			"my-google-auth",
+		},
+	}
+	tools["my-client-auth-get-dataset-info-tool"] = map[string]any{
+		"kind":        "bigquery-get-dataset-info",
+		"source":      "my-client-auth-source",
+		"description": "Tool to show dataset metadata",
+	}
+	tools["my-list-table-ids-tool"] = map[string]any{
+		"kind":        "bigquery-list-table-ids",
+		"source":      "my-instance",
+		"description": "Tool to list table within a dataset",
+	}
+	tools["my-auth-list-table-ids-tool"] = map[string]any{
+		"kind":        "bigquery-list-table-ids",
+		"source":      "my-instance",
+		"description": "Tool to list table within a dataset",
+		"authRequired": []string{
+			"my-google-auth",
+		},
+	}
+	tools["my-client-auth-list-table-ids-tool"] = map[string]any{
+		"kind":        "bigquery-list-table-ids",
+		"source":      "my-client-auth-source",
+		"description": "Tool to list table within a dataset",
+	}
+	tools["my-list-table-ids-with-metadata-tool"] = map[string]any{
+		"kind":            "bigquery-list-table-ids",
+		"source":          "my-instance",
+		"description":     "Tool to list table within a dataset",
+		"includeMetadata": true,
+	}
+	tools["my-get-table-info-tool"] = map[string]any{
+		"kind":        "bigquery-get-table-info",
+		"source":      "my-instance",
+		"description": "Tool to show dataset metadata",
+	}
+	tools["my-auth-get-table-info-tool"] = map[string]any{
+		"kind":        "bigquery-get-table-info",
+		"source":      "my-instance",
+		"description": "Tool to show dataset metadata",
+		"authRequired": []string{
+			"my-google-auth",
+		},
+	}
+	tools["my-client-auth-get-table-info-tool"] = map[string]any{
+		"kind":        "bigquery-get-table-info",
+		"source":      "my-client-auth-source",
+		"description": "Tool to show dataset metadata",
+	}
+	tools["my-list-columns-tool"] = map[string]any{
+		"kind":        "bigquery-list-columns",
+		"source":      "my-instance",
+		"description": "Tool to list table columns",
+	}
+	tools["my-client-auth-list-columns-tool"] = map[string]any{
+		"kind":        "bigquery-list-columns",
+		"source":      "my-client-auth-source",
+		"description": "Tool to list table columns",
+	}
+	tools["my-table-storage-tool"] = map[string]any{
+		"kind":        "bigquery-table-storage",
+		"source":      "my-instance",
+		"description": "Tool to show table storage statistics",
+	}
+	tools["my-client-auth-table-storage-tool"] = map[string]any{
+		"kind":        "bigquery-table-storage",
+		"source":      "my-client-auth-source",
+		"description": "Tool to show table storage statistics",
+	}
+	tools["my-profile-table-tool"] = map[string]any{
+		"kind":        "bigquery-profile-table",
+		"source":      "my-instance",
+		"description": "Tool to profile a table",
+	}
+	tools["my-client-auth-profile-table-tool"] = map[string]any{
+		"kind":        "bigquery-profile-table",
+		"source":      "my-client-auth-source",
+		"description": "Tool to profile a table",
+	}
+	tools["my-load-from-gcs-tool"] = map[string]any{
+		"kind":        "bigquery-load-from-gcs",
+		"source":      "my-instance",
+		"description": "Tool to load files from Cloud Storage into a table",
+	}
+	tools["my-client-auth-load-from-gcs-tool"] = map[string]any{
+		"kind":        "bigquery-load-from-gcs",
+		"source":      "my-client-auth-source",
+		"description": "Tool to load files from Cloud Storage into a table",
+	}
+	tools["my-conversational-analytics-tool"] = map[string]any{
+		"kind":        "bigquery-conversational-analytics",
+		"source":      "my-instance",
+		"description": "Tool to ask BigQuery conversational analytics",
+	}
+	tools["my-auth-conversational-analytics-tool"] = map[string]any{
+		"kind":        "bigquery-conversational-analytics",
+		"source":      "my-instance",
+		"description": "Tool to ask BigQuery conversational analytics",
+		"authRequired": []string{
+			"my-google-auth",
+		},
+	}
+	tools["my-client-auth-conversational-analytics-tool"] = map[string]any{
+		"kind":        "bigquery-conversational-analytics",
+		"source":      "my-client-auth-source",
+		"description": "Tool to ask BigQuery conversational analytics",
+	}
+	tools["my-search-catalog-tool"] = map[string]any{
+		"kind":        "bigquery-search-catalog",
+		"source":      "my-instance",
+		"description": "Tool to search the BiqQuery catalog",
+	}
+	tools["my-auth-search-catalog-tool"] = map[string]any{
+		"kind":        "bigquery-search-catalog",
+		"source":      "my-instance",
+		"description": "Tool to search the BiqQuery catalog",
+		"authRequired": []string{
+			"my-google-auth",
+		},
+	}
+	tools["my-client-auth-search-catalog-tool"] = map[string]any{
+		"kind":        "bigquery-search-catalog",
+		"source":      "my-client-auth-source",
+		"description": "Tool to search the BiqQuery catalog",
+	}
+	config["tools"] = tools
+	return config
+}
+
+func addClientAuthSourceConfig(t *testing.T, config map[string]any) map[string]any {
+	sources, ok := config["sources"].(map[string]any)
+	if !ok {
+		t.Fatalf("unable to get sources from config")
+	}
+	sources["my-client-auth-source"] = map[string]any{
+		"kind":           BigquerySourceKind,
+		"project":        BigqueryProject,
+		"useClientOAuth": true,
+	}
+	config["sources"] = sources
+	return config
+}
+
+// bigqueryQueryTag is the queryTag of my-tagged-instance.
+const bigqueryQueryTag = "toolbox-integration-test"
+
+func addBigQueryQueryTagConfig(t *testing.T, config map[string]any) map[string]any {
+	sources, ok := config["sources"].(map[string]any)
+	if !ok {
+		t.Fatalf("unable to get sources from config")
+	}
+	sources["my-tagged-instance"] = map[string]any{
+		"kind":     BigquerySourceKind,
+		"project":  BigqueryProject,
+		"queryTag": bigqueryQueryTag,
+	}
+	tools, ok := config["tools"].(map[string]any)
+	if !ok {
+		t.Fatalf("unable to get tools from config")
+	}
+	tools["my-tagged-tool"] = map[string]any{
+		"kind":        "bigquery-sql",
+		"source":      "my-tagged-instance",
+		"description": "Tool whose query jobs are labeled with the source's query tag.",
+		"statement":   "SELECT 1",
+	}
+	config["sources"] = sources
+	config["tools"] = tools
+	return config
+}
+
+func addBigQuerySqlToolConfig(t *testing.T, config map[string]any, toolStatement, arrayToolStatement string) map[string]any {
+	tools, ok := config["tools"].(map[string]any)
+	if !ok {
+		t.Fatalf("unable to get tools from config")
+	}
+	tools["my-scalar-datatype-tool"] = map[string]any{
+		"kind":        "bigquery-sql",
+		"source":      "my-instance",
+		"description": "Tool to test various scalar data types.",
+		"statement":   toolStatement,
+		"parameters": []any{
+			map[string]any{"name": "int_val", "type": "integer", "description": "an integer value"},
+			map[string]any{"name": "string_val", "type": "string", "description": "a string value"},
+			map[string]any{"name": "float_val", "type": "float", "description": "a float value"},
+			map[string]any{"name": "bool_val", "type": "boolean", "description": "a boolean value"},
+		},
+	}
+	tools["my-array-datatype-tool"] = map[string]any{
+		"kind":        "bigquery-sql",
+		"source":      "my-instance",
+		"description": "Tool to test various array data types.",
+		"statement":   arrayToolStatement,
+		"parameters": []any{
+			map[string]any{"name": "int_array", "type": "array", "description": "an array of integer values", "items": map[string]any{"name": "item", "type": "integer", "description": "desc"}},
 			map[string]any{"name": "string_array", "type": "array", "description": "an array of string values", "items": map[string]any{"name": "item", "type": "string", "description": "desc"}},
 			map[string]any{"name": "float_array", "type": "array", "description": "an array of float values", "items": map[string]any{"name": "item", "type": "float", "description": "desc"}},
 			map[string]any{"name": "bool_array", "type": "array", "description": "an array of boolean values", "items": map[string]any{"name": "item", "type": "boolean", "description": "desc"}},
 		},
 	}
-	tools["my-client-auth-tool"] = map[string]any{
-		"kind":        "bigquery-sql",
-		"source":      "my-client-auth-source",
-		"description": "Tool to test client authorization.",
-		"statement":   "SELECT 1",
+	repeatedStatement := "SELECT * FROM (SELECT 1 AS id, ['a', 'b'] AS tags, [STRUCT('x' AS key, 1 AS value), STRUCT('y' AS key, 2 AS value)] AS attrs " +
+		"UNION ALL SELECT 2, ARRAY<STRING>[], ARRAY<STRUCT<key STRING, value INT64>>[]) ORDER BY id"
+	tools["my-repeated-datatype-tool"] = map[string]any{
+		"kind":        "bigquery-sql",
+		"source":      "my-instance",
+		"description": "Tool to test REPEATED data types.",
+		"statement":   repeatedStatement,
+	}
+	tools["my-repeated-null-datatype-tool"] = map[string]any{
+		"kind":        "bigquery-sql",
+		"source":      "my-instance",
+		"description": "Tool to test REPEATED data types with empty arrays as null.",
+		"statement":   repeatedStatement,
+		"emptyArrays": "null",
+	}
+	tools["my-positional-param-tool"] = map[string]any{
+		"kind":        "bigquery-sql",
+		"source":      "my-instance",
+		"description": "Tool to test positional parameter binding.",
+		"statement":   "SELECT ? AS first_val, ? AS second_val, ? AS third_val, ? AS fourth_val",
+		"parameters": []any{
+			map[string]any{"name": "first", "type": "string", "description": "the first value"},
+			map[string]any{"name": "second", "type": "integer", "description": "the second value"},
+			map[string]any{"name": "third", "type": "string", "description": "the third value"},
+			map[string]any{"name": "fourth", "type": "integer", "description": "the fourth value"},
+		},
+	}
+	tools["my-read-only-tool"] = map[string]any{
+		"kind":        "bigquery-sql",
+		"source":      "my-instance",
+		"description": "Tool to test that read-only tools reject DML.",
+		"readOnly":    true,
+		"statement":   "{{.sql}}",
+		"templateParameters": []any{
+			map[string]any{"name": "sql", "type": "string", "description": "the statement to run"},
+		},
+	}
+	tools["my-time-zone-tool"] = map[string]any{
+		"kind":        "bigquery-sql",
+		"source":      "my-instance",
+		"description": "Tool to test connection properties.",
+		// CURRENT_DATE() and CURRENT_DATETIME() use the session time zone, so
+		// they only match the New York values if time_zone was applied. The
+		// hours always differ from UTC, unlike the dates.
+		"statement": "SELECT CURRENT_DATE() = CURRENT_DATE('America/New_York') " +
+			"AND EXTRACT(HOUR FROM CURRENT_DATETIME()) = EXTRACT(HOUR FROM CURRENT_DATETIME('America/New_York')) AS in_zone",
+		"connectionProperties": map[string]any{
+			"time_zone": "America/New_York",
+		},
+	}
+	tools["my-arrow-tool"] = map[string]any{
+		"kind":         "bigquery-sql",
+		"source":       "my-instance",
+		"description":  "Tool to test Arrow results.",
+		"arrowResults": true,
+		"statement": "SELECT id, name, score FROM UNNEST([" +
+			"STRUCT(1 AS id, 'Alice' AS name, 1.5 AS score), STRUCT(2, 'Jane', 2.5), STRUCT(3, 'Sid', 3.5)]) ORDER BY id",
+	}
+	tools["my-stats-tool"] = map[string]any{
+		"kind":         "bigquery-sql",
+		"source":       "my-instance",
+		"description":  "Tool to test query statistics.",
+		"includeStats": true,
+		"statement":    "SELECT 1",
+	}
+	tools["my-exec-sql-stats-tool"] = map[string]any{
+		"kind":         "bigquery-execute-sql",
+		"source":       "my-instance",
+		"description":  "Tool to test query statistics.",
+		"includeStats": true,
+	}
+	tools["my-client-auth-tool"] = map[string]any{
+		"kind":        "bigquery-sql",
+		"source":      "my-client-auth-source",
+		"description": "Tool to test client authorization.",
+		"statement":   "SELECT 1",
+	}
+	config["tools"] = tools
+	return config
+}
+
+func runBigQueryExecuteSqlToolInvokeTest(t *testing.T, select1Want, invokeParamWant, tableNameParam, ddlWant string) {
+	// Get ID token
+	idToken, err := tests.GetGoogleIdToken(tests.ClientId)
+	if err != nil {
+		t.Fatalf("error getting Google ID token: %s", err)
+	}
+
+	// Get access token
+	accessToken, err := sources.GetIAMAccessToken(t.Context())
+	if err != nil {
+		t.Fatalf("error getting access token from ADC: %s", err)
+	}
+	accessToken = "Bearer " + accessToken
+
+	// Test tool invoke endpoint
+	invokeTcs := []struct {
+		name          string
+		api           string
+		requestHeader map[string]string
+		requestBody   io.Reader
+		want          string
+		isErr         bool
+	}{
+		{
+			name:          "invoke my-exec-sql-tool without body",
+			api:           "http://127.0.0.1:5000/api/tool/my-exec-sql-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   bytes.NewBuffer([]byte(`{}`)),
+			isErr:         true,
+		},
+		{
+			name:          "invoke my-exec-sql-tool",
+			api:           "http://127.0.0.1:5000/api/tool/my-exec-sql-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   bytes.NewBuffer([]byte(`{"sql":"SELECT 1"}`)),
+			want:          select1Want,
+			isErr:         false,
+		},
+		{
+			name:          "invoke my-exec-sql-tool create table",
+			api:           "http://127.0.0.1:5000/api/tool/my-exec-sql-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   bytes.NewBuffer([]byte(`{"sql":"CREATE TABLE t (id SERIAL PRIMARY KEY, name TEXT)"}`)),
+			want:          ddlWant,
+			isErr:         true,
+		},
+		{
+			name:          "invoke my-exec-sql-tool with data present in table",
+			api:           "http://127.0.0.1:5000/api/tool/my-exec-sql-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf("{\"sql\":\"SELECT * FROM %s WHERE id = 3 OR name = 'Alice' ORDER BY id\"}", tableNameParam))),
+			want:          invokeParamWant,
+			isErr:         false,
+		},
+		{
+			name:          "invoke my-exec-sql-tool with no matching rows",
+			api:           "http://127.0.0.1:5000/api/tool/my-exec-sql-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf("{\"sql\":\"SELECT * FROM %s WHERE id = 999\"}", tableNameParam))),
+			want:          `"The query returned 0 rows."`,
+			isErr:         false,
+		},
+		{
+			name:          "invoke my-exec-sql-tool with multi-statement script",
+			api:           "http://127.0.0.1:5000/api/tool/my-exec-sql-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf("{\"sql\":\"SELECT 1; SELECT * FROM %s WHERE id = 3 OR name = 'Alice' ORDER BY id\"}", tableNameParam))),
+			want:          invokeParamWant,
+			isErr:         false,
+		},
+		{
+			name:          "invoke my-exec-sql-tool drop table",
+			api:           "http://127.0.0.1:5000/api/tool/my-exec-sql-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   bytes.NewBuffer([]byte(`{"sql":"DROP TABLE t"}`)),
+			want:          ddlWant,
+			isErr:         true,
+		},
+		{
+			name:          "invoke my-exec-sql-tool insert entry",
+			api:           "http://127.0.0.1:5000/api/tool/my-exec-sql-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf("{\"sql\":\"INSERT INTO %s (id, name) VALUES (4, 'test_name')\"}", tableNameParam))),
+			want:          `{"affectedRows":1}`,
+			isErr:         false,
+		},
+		{
+			name:          "invoke my-exec-sql-tool insert multiple entries",
+			api:           "http://127.0.0.1:5000/api/tool/my-exec-sql-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf("{\"sql\":\"INSERT INTO %s (id, name) VALUES (5, 'test_name'), (6, 'test_name')\"}", tableNameParam))),
+			want:          `{"affectedRows":2}`,
+			isErr:         false,
+		},
+		{
+			name:          "invoke my-exec-sql-tool update entries",
+			api:           "http://127.0.0.1:5000/api/tool/my-exec-sql-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf("{\"sql\":\"UPDATE %s SET name = 'updated_name' WHERE name = 'test_name'\"}", tableNameParam))),
+			want:          `{"affectedRows":3}`,
+			isErr:         false,
+		},
+		{
+			name:          "invoke my-exec-sql-tool delete entries",
+			api:           "http://127.0.0.1:5000/api/tool/my-exec-sql-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf("{\"sql\":\"DELETE FROM %s WHERE name = 'updated_name'\"}", tableNameParam))),
+			want:          `{"affectedRows":3}`,
+			isErr:         false,
+		},
+		{
+			name:          "invoke my-exec-sql-tool delete with no matching rows",
+			api:           "http://127.0.0.1:5000/api/tool/my-exec-sql-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf("{\"sql\":\"DELETE FROM %s WHERE id = 999\"}", tableNameParam))),
+			want:          `{"affectedRows":0}`,
+			isErr:         false,
+		},
+		{
+			name:          "invoke my-exec-sql-tool without body",
+			api:           "http://127.0.0.1:5000/api/tool/my-exec-sql-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   bytes.NewBuffer([]byte(`{}`)),
+			isErr:         true,
+		},
+		{
+			name:          "Invoke my-auth-exec-sql-tool with auth token",
+			api:           "http://127.0.0.1:5000/api/tool/my-auth-exec-sql-tool/invoke",
+			requestHeader: map[string]string{"my-google-auth_token": idToken},
+			requestBody:   bytes.NewBuffer([]byte(`{"sql":"SELECT 1"}`)),
+			isErr:         false,
+			want:          select1Want,
+		},
+		{
+			name:          "Invoke my-auth-exec-sql-tool with invalid auth token",
+			api:           "http://127.0.0.1:5000/api/tool/my-auth-exec-sql-tool/invoke",
+			requestHeader: map[string]string{"my-google-auth_token": "INVALID_TOKEN"},
+			requestBody:   bytes.NewBuffer([]byte(`{"sql":"SELECT 1"}`)),
+			isErr:         true,
+		},
+		{
+			name:          "Invoke my-auth-exec-sql-tool without auth token",
+			api:           "http://127.0.0.1:5000/api/tool/my-auth-exec-sql-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   bytes.NewBuffer([]byte(`{"sql":"SELECT 1"}`)),
+			isErr:         true,
+		},
+		{
+			name:          "Invoke my-client-auth-exec-sql-tool with auth token",
+			api:           "http://127.0.0.1:5000/api/tool/my-client-auth-exec-sql-tool/invoke",
+			requestHeader: map[string]string{"Authorization": accessToken},
+			requestBody:   bytes.NewBuffer([]byte(`{"sql":"SELECT 1"}`)),
+			want:          "[{\"f0_\":1}]",
+			isErr:         false,
+		},
+		{
+			name:          "Invoke my-client-auth-exec-sql-tool without auth token",
+			api:           "http://127.0.0.1:5000/api/tool/my-client-auth-exec-sql-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   bytes.NewBuffer([]byte(`{"sql":"SELECT 1"}`)),
+			isErr:         true,
+		},
+		{
+
+			name:          "Invoke my-client-auth-exec-sql-tool with invalid auth token",
+			api:           "http://127.0.0.1:5000/api/tool/my-client-auth-exec-sql-tool/invoke",
+			requestHeader: map[string]string{"Authorization": "Bearer invalid-token"},
+			requestBody:   bytes.NewBuffer([]byte(`{"sql":"SELECT 1"}`)),
+			isErr:         true,
+		},
+	}
+	for _, tc := range invokeTcs {
+		t.Run(tc.name, func(t *testing.T) {
+			// Send Tool invocation request
+			req, err := http.NewRequest(http.MethodPost, tc.api, tc.requestBody)
+			if err != nil {
+				t.Fatalf("unable to create request: %s", err)
+			}
+			req.Header.Add("Content-type", "application/json")
+			for k, v := range tc.requestHeader {
+				req.Header.Add(k, v)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("unable to send request: %s", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				if tc.isErr {
+					return
+				}
+				bodyBytes, _ := io.ReadAll(resp.Body)
+				t.Fatalf("response status code is not 200, got %d: %s", resp.StatusCode, string(bodyBytes))
+			}
+
+			// Check response body
+			var body map[string]interface{}
+			err = json.NewDecoder(resp.Body).Decode(&body)
+			if err != nil {
+				t.Fatalf("error parsing response body")
+			}
+
+			got, ok := body["result"].(string)
+			if !ok {
+				t.Fatalf("unable to find result in response body")
+			}
+
+			if got != tc.want {
+				t.Fatalf("unexpected value: got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func runBigQueryExecuteSqlToolInvokeDryRunTest(t *testing.T, datasetName string) {
+	// Get ID token
+	idToken, err := tests.GetGoogleIdToken(tests.ClientId)
+	if err != nil {
+		t.Fatalf("error getting Google ID token: %s", err)
+	}
+
+	newTableName := fmt.Sprintf("%s.new_dry_run_table_%s", datasetName, strings.ReplaceAll(uuid.New().String(), "-", ""))
+
+	// Test tool invoke endpoint
+	invokeTcs := []struct {
+		name          string
+		api           string
+		requestHeader map[string]string
+		requestBody   io.Reader
+		want          string
+		isErr         bool
+	}{
+		{
+			name:          "invoke my-exec-sql-tool with dryRun",
+			api:           "http://127.0.0.1:5000/api/tool/my-exec-sql-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   bytes.NewBuffer([]byte(`{"sql":"SELECT 1", "dry_run": true}`)),
+			want:          `\"statementType\": \"SELECT\"`,
+			isErr:         false,
+		},
+		{
+			name:          "invoke my-exec-sql-tool with dryRun create table",
+			api:           "http://127.0.0.1:5000/api/tool/my-exec-sql-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf(`{"sql":"CREATE TABLE %s (id INT64, name STRING)", "dry_run": true}`, newTableName))),
+			want:          `\"statementType\": \"CREATE_TABLE\"`,
+			isErr:         false,
+		},
+		{
+			name:          "invoke my-exec-sql-tool with dryRun execute immediate",
+			api:           "http://127.0.0.1:5000/api/tool/my-exec-sql-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf(`{"sql":"EXECUTE IMMEDIATE \"CREATE TABLE %s (id INT64, name STRING)\"", "dry_run": true}`, newTableName))),
+			want:          `\"statementType\": \"SCRIPT\"`,
+			isErr:         false,
+		},
+		{
+			name:          "Invoke my-auth-exec-sql-tool with dryRun and auth token",
+			api:           "http://127.0.0.1:5000/api/tool/my-auth-exec-sql-tool/invoke",
+			requestHeader: map[string]string{"my-google-auth_token": idToken},
+			requestBody:   bytes.NewBuffer([]byte(`{"sql":"SELECT 1", "dry_run": true}`)),
+			isErr:         false,
+			want:          `\"statementType\": \"SELECT\"`,
+		},
+		{
+			name:          "Invoke my-auth-exec-sql-tool with dryRun and invalid auth token",
+			api:           "http://127.0.0.1:5000/api/tool/my-auth-exec-sql-tool/invoke",
+			requestHeader: map[string]string{"my-google-auth_token": "INVALID_TOKEN"},
+			requestBody:   bytes.NewBuffer([]byte(`{"sql":"SELECT 1","dry_run": true}`)),
+			isErr:         true,
+		},
+		{
+			name:          "Invoke my-auth-exec-sql-tool with dryRun and without auth token",
+			api:           "http://127.0.0.1:5000/api/tool/my-auth-exec-sql-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   bytes.NewBuffer([]byte(`{"sql":"SELECT 1", "dry_run": true}`)),
+			isErr:         true,
+		},
+	}
+	for _, tc := range invokeTcs {
+		t.Run(tc.name, func(t *testing.T) {
+			// Send Tool invocation request
+			req, err := http.NewRequest(http.MethodPost, tc.api, tc.requestBody)
+			if err != nil {
+				t.Fatalf("unable to create request: %s", err)
+			}
+			req.Header.Add("Content-type", "application/json")
+			for k, v := range tc.requestHeader {
+				req.Header.Add(k, v)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("unable to send request: %s", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				if tc.isErr {
+					return
+				}
+				bodyBytes, _ := io.ReadAll(resp.Body)
+				t.Fatalf("response status code is not 200, got %d: %s", resp.StatusCode, string(bodyBytes))
+			}
+
+			// Check response body
+			var body map[string]interface{}
+			err = json.NewDecoder(resp.Body).Decode(&body)
+			if err != nil {
+				t.Fatalf("error parsing response body")
+			}
+
+			got, ok := body["result"].(string)
+			if !ok {
+				t.Fatalf("unable to find result in response body")
+			}
+
+			if !strings.Contains(got, tc.want) {
+				t.Fatalf("expected %q to contain %q, but it did not", got, tc.want)
+			}
+		})
+	}
+}
+
+func runBigQueryExecuteSqlToolInvokeAsyncTest(t *testing.T) {
+	// A cross join large enough that the query takes well over the time we
+	// allow an async invocation to return in.
+	slowQuery := "SELECT COUNT(*) FROM UNNEST(GENERATE_ARRAY(1, 1000000)) AS a CROSS JOIN UNNEST(GENERATE_ARRAY(1, 5000)) AS b WHERE MOD(a + b, 7) = 0"
+	requestBody := bytes.NewBuffer([]byte(fmt.Sprintf(`{"sql":"%s", "async": true}`, slowQuery)))
+
+	start := time.Now()
+	req, err := http.NewRequest(http.MethodPost, "http://127.0.0.1:5000/api/tool/my-exec-sql-tool/invoke", requestBody)
+	if err != nil {
+		t.Fatalf("unable to create request: %s", err)
+	}
+	req.Header.Add("Content-type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("unable to send request: %s", err)
+	}
+	defer resp.Body.Close()
+	elapsed := time.Since(start)
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		t.Fatalf("response status code is not 200, got %d: %s", resp.StatusCode, string(bodyBytes))
+	}
+	if elapsed > 15*time.Second {
+		t.Fatalf("async invocation took %s, expected it to return without waiting for the query", elapsed)
+	}
+
+	var body map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("error parsing response body")
+	}
+	got, ok := body["result"].(string)
+	if !ok {
+		t.Fatalf("unable to find result in response body")
+	}
+	var job map[string]string
+	if err := json.Unmarshal([]byte(got), &job); err != nil {
+		t.Fatalf("unable to parse async result %q: %s", got, err)
+	}
+	if job["jobId"] == "" {
+		t.Fatalf("expected a job ID in async result, got %q", got)
+	}
+	if job["projectId"] != BigqueryProject {
+		t.Fatalf("unexpected project ID in async result: got %q, want %q", job["projectId"], BigqueryProject)
+	}
+}
+
+func runBigQueryExecuteSqlScriptErrorTest(t *testing.T) {
+	// The second statement passes the dry run but fails when the script runs.
+	script := "SELECT 1; SELECT ERROR('second statement failed');"
+	reqBody, err := json.Marshal(map[string]any{"sql": script})
+	if err != nil {
+		t.Fatalf("unable to marshal request body: %s", err)
+	}
+	req, err := http.NewRequest(http.MethodPost, "http://127.0.0.1:5000/api/tool/my-exec-sql-tool/invoke", bytes.NewBuffer(reqBody))
+	if err != nil {
+		t.Fatalf("unable to create request: %s", err)
+	}
+	req.Header.Add("Content-type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("unable to send request: %s", err)
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unable to read response body: %s", err)
+	}
+	if resp.StatusCode == http.StatusOK {
+		t.Fatalf("expected the script to fail, got 200: %s", string(bodyBytes))
+	}
+	// both the position of the failed statement and the detailed error from
+	// the job's error stream are reported
+	for _, want := range []string{"at [2:", "second statement failed", "all job errors"} {
+		if !strings.Contains(string(bodyBytes), want) {
+			t.Fatalf("expected error %q to contain %q", string(bodyBytes), want)
+		}
+	}
+}
+
+func runBigQueryForecastToolInvokeTest(t *testing.T, tableName string) {
+	idToken, err := tests.GetGoogleIdToken(tests.ClientId)
+	if err != nil {
+		t.Fatalf("error getting Google ID token: %s", err)
+	}
+
+	// Get access token
+	accessToken, err := sources.GetIAMAccessToken(t.Context())
+	if err != nil {
+		t.Fatalf("error getting access token from ADC: %s", err)
+	}
+	accessToken = "Bearer " + accessToken
+
+	historyDataTable := strings.ReplaceAll(tableName, "`", "")
+	historyDataQuery := fmt.Sprintf("SELECT ts, data, id FROM %s", tableName)
+
+	invokeTcs := []struct {
+		name          string
+		api           string
+		requestHeader map[string]string
+		requestBody   io.Reader
+		want          string
+		isErr         bool
+	}{
+		{
+			name:          "invoke my-forecast-tool without required params",
+			api:           "http://127.0.0.1:5000/api/tool/my-forecast-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf(`{"history_data": "%s"}`, historyDataTable))),
+			isErr:         true,
+		},
+		{
+			name:          "invoke my-forecast-tool with table",
+			api:           "http://127.0.0.1:5000/api/tool/my-forecast-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf(`{"history_data": "%s", "timestamp_col": "ts", "data_col": "data"}`, historyDataTable))),
+			want:          `"forecast_timestamp"`,
+			isErr:         false,
+		},
+		{
+			name:          "invoke my-forecast-tool with query and horizon",
+			api:           "http://127.0.0.1:5000/api/tool/my-forecast-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf(`{"history_data": "%s", "timestamp_col": "ts", "data_col": "data", "horizon": 5}`, historyDataQuery))),
+			want:          `"forecast_timestamp"`,
+			isErr:         false,
+		},
+		{
+			name:          "invoke my-forecast-tool with id_cols",
+			api:           "http://127.0.0.1:5000/api/tool/my-forecast-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf(`{"history_data": "%s", "timestamp_col": "ts", "data_col": "data", "id_cols": ["id"]}`, historyDataTable))),
+			want:          `"id"`,
+			isErr:         false,
+		},
+		{
+			name:          "invoke my-auth-forecast-tool with auth token",
+			api:           "http://127.0.0.1:5000/api/tool/my-auth-forecast-tool/invoke",
+			requestHeader: map[string]string{"my-google-auth_token": idToken},
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf(`{"history_data": "%s", "timestamp_col": "ts", "data_col": "data"}`, historyDataTable))),
+			want:          `"forecast_timestamp"`,
+			isErr:         false,
+		},
+		{
+			name:          "invoke my-auth-forecast-tool with invalid auth token",
+			api:           "http://127.0.0.1:5000/api/tool/my-auth-forecast-tool/invoke",
+			requestHeader: map[string]string{"my-google-auth_token": "INVALID_TOKEN"},
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf(`{"history_data": "%s", "timestamp_col": "ts", "data_col": "data"}`, historyDataTable))),
+			isErr:         true,
+		},
+		{
+			name:          "Invoke my-client-auth-forecast-tool with auth token",
+			api:           "http://127.0.0.1:5000/api/tool/my-client-auth-forecast-tool/invoke",
+			requestHeader: map[string]string{"Authorization": accessToken},
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf(`{"history_data": "%s", "timestamp_col": "ts", "data_col": "data"}`, historyDataTable))),
+			want:          `"forecast_timestamp"`,
+			isErr:         false,
+		},
+		{
+			name:          "Invoke my-client-auth-forecast-tool without auth token",
+			api:           "http://127.0.0.1:5000/api/tool/my-client-auth-forecast-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf(`{"history_data": "%s", "timestamp_col": "ts", "data_col": "data"}`, historyDataTable))),
+			isErr:         true,
+		},
+		{
+
+			name:          "Invoke my-client-auth-forecast-tool with invalid auth token",
+			api:           "http://127.0.0.1:5000/api/tool/my-client-auth-forecast-tool/invoke",
+			requestHeader: map[string]string{"Authorization": "Bearer invalid-token"},
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf(`{"history_data": "%s", "timestamp_col": "ts", "data_col": "data"}`, historyDataTable))),
+			isErr:         true,
+		},
+	}
+	for _, tc := range invokeTcs {
+		t.Run(tc.name, func(t *testing.T) {
+			// Send Tool invocation request
+			req, err := http.NewRequest(http.MethodPost, tc.api, tc.requestBody)
+			if err != nil {
+				t.Fatalf("unable to create request: %s", err)
+			}
+			req.Header.Add("Content-type", "application/json")
+			for k, v := range tc.requestHeader {
+				req.Header.Add(k, v)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("unable to send request: %s", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				if tc.isErr {
+					return
+				}
+				bodyBytes, _ := io.ReadAll(resp.Body)
+				t.Fatalf("response status code is not 200, got %d: %s", resp.StatusCode, string(bodyBytes))
+			}
+
+			// Check response body
+			var body map[string]interface{}
+			err = json.NewDecoder(resp.Body).Decode(&body)
+			if err != nil {
+				t.Fatalf("error parsing response body")
+			}
+
+			got, ok := body["result"].(string)
+			if !ok {
+				t.Fatalf("unable to find result in response body")
+			}
+
+			if !strings.Contains(got, tc.want) {
+				t.Fatalf("expected %q to contain %q, but it did not", got, tc.want)
+			}
+		})
+	}
+}
+
+func runBigQueryQueryExternalToolInvokeTest(t *testing.T) {
+	// Get access token
+	accessToken, err := sources.GetIAMAccessToken(t.Context())
+	if err != nil {
+		t.Fatalf("error getting access token from ADC: %s", err)
+	}
+	accessToken = "Bearer " + accessToken
+
+	// A small public CSV with a header row and the columns name, post_abbr.
+	sourceURI := "gs://cloud-samples-data/bigquery/us-states/us-states.csv"
+	sql := "SELECT name, post_abbr FROM us_states WHERE post_abbr = 'WA'"
+	want := `[{"name":"Washington","post_abbr":"WA"}]`
+
+	invokeTcs := []struct {
+		name          string
+		api           string
+		requestHeader map[string]string
+		requestBody   io.Reader
+		want          string
+		isErr         bool
+	}{
+		{
+			name:          "invoke my-query-external-tool without required params",
+			api:           "http://127.0.0.1:5000/api/tool/my-query-external-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf(`{"sql": "%s"}`, sql))),
+			isErr:         true,
+		},
+		{
+			name:          "invoke my-query-external-tool with schema",
+			api:           "http://127.0.0.1:5000/api/tool/my-query-external-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf(`{"sql": "%s", "source_uri": "%s", "table_name": "us_states", "schema": "name:STRING,post_abbr:STRING", "skip_leading_rows": 1}`, sql, sourceURI))),
+			want:          want,
+			isErr:         false,
+		},
+		{
+			name:          "invoke my-query-external-tool with empty result",
+			api:           "http://127.0.0.1:5000/api/tool/my-query-external-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf(`{"sql": "SELECT name FROM us_states WHERE post_abbr = 'XX'", "source_uri": "%s", "table_name": "us_states", "schema": "name:STRING,post_abbr:STRING", "skip_leading_rows": 1}`, sourceURI))),
+			want:          `"The query returned 0 rows."`,
+			isErr:         false,
+		},
+		{
+			name:          "invoke my-query-external-tool with unsupported format",
+			api:           "http://127.0.0.1:5000/api/tool/my-query-external-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf(`{"sql": "%s", "source_uri": "%s", "table_name": "us_states", "source_format": "XLSX"}`, sql, sourceURI))),
+			isErr:         true,
+		},
+		{
+			name:          "invoke my-query-external-tool with invalid schema",
+			api:           "http://127.0.0.1:5000/api/tool/my-query-external-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf(`{"sql": "%s", "source_uri": "%s", "table_name": "us_states", "schema": "name"}`, sql, sourceURI))),
+			isErr:         true,
+		},
+		{
+			name:          "Invoke my-client-auth-query-external-tool with auth token",
+			api:           "http://127.0.0.1:5000/api/tool/my-client-auth-query-external-tool/invoke",
+			requestHeader: map[string]string{"Authorization": accessToken},
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf(`{"sql": "%s", "source_uri": "%s", "table_name": "us_states", "schema": "name:STRING,post_abbr:STRING", "skip_leading_rows": 1}`, sql, sourceURI))),
+			want:          want,
+			isErr:         false,
+		},
+		{
+			name:          "Invoke my-client-auth-query-external-tool without auth token",
+			api:           "http://127.0.0.1:5000/api/tool/my-client-auth-query-external-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf(`{"sql": "%s", "source_uri": "%s", "table_name": "us_states"}`, sql, sourceURI))),
+			isErr:         true,
+		},
+	}
+	for _, tc := range invokeTcs {
+		t.Run(tc.name, func(t *testing.T) {
+			// Send Tool invocation request
+			req, err := http.NewRequest(http.MethodPost, tc.api, tc.requestBody)
+			if err != nil {
+				t.Fatalf("unable to create request: %s", err)
+			}
+			req.Header.Add("Content-type", "application/json")
+			for k, v := range tc.requestHeader {
+				req.Header.Add(k, v)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("unable to send request: %s", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				if tc.isErr {
+					return
+				}
+				bodyBytes, _ := io.ReadAll(resp.Body)
+				t.Fatalf("response status code is not 200, got %d: %s", resp.StatusCode, string(bodyBytes))
+			}
+
+			// Check response body
+			var body map[string]interface{}
+			err = json.NewDecoder(resp.Body).Decode(&body)
+			if err != nil {
+				t.Fatalf("error parsing response body")
+			}
+
+			got, ok := body["result"].(string)
+			if !ok {
+				t.Fatalf("unable to find result in response body")
+			}
+
+			if got != tc.want {
+				t.Fatalf("unexpected value: got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func runBigQueryVectorSearchToolInvokeTest(t *testing.T, tableName string) {
+	table := strings.ReplaceAll(tableName, "`", "")
+	tcs := []struct {
+		name        string
+		requestBody string
+		wantNames   []string
+		isErr       bool
+	}{
+		{
+			name:        "search by embedding",
+			requestBody: fmt.Sprintf(`{"table": "%s", "column_to_search": "embedding", "query_embedding": [1, 0], "top_k": 3}`, table),
+			wantNames:   []string{"east", "mostly east", "north"},
+		},
+		{
+			name:        "search with top_k",
+			requestBody: fmt.Sprintf(`{"table": "%s", "column_to_search": "embedding", "query_embedding": [0, 1], "top_k": 1, "distance_type": "cosine"}`, table),
+			wantNames:   []string{"north"},
+		},
+		{
+			name:        "search without a query",
+			requestBody: fmt.Sprintf(`{"table": "%s", "column_to_search": "embedding"}`, table),
+			isErr:       true,
+		},
+		{
+			name:        "search with text but no model",
+			requestBody: fmt.Sprintf(`{"table": "%s", "column_to_search": "embedding", "query_text": "east"}`, table),
+			isErr:       true,
+		},
+		{
+			name:        "search with an invalid column name",
+			requestBody: fmt.Sprintf(`{"table": "%s", "column_to_search": "embedding) --", "query_embedding": [1, 0]}`, table),
+			isErr:       true,
+		},
+		{
+			name:        "search with an unknown distance type",
+			requestBody: fmt.Sprintf(`{"table": "%s", "column_to_search": "embedding", "query_embedding": [1, 0], "distance_type": "MANHATTAN"}`, table),
+			isErr:       true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, "http://127.0.0.1:5000/api/tool/my-vector-search-tool/invoke", bytes.NewBufferString(tc.requestBody))
+			if err != nil {
+				t.Fatalf("unable to create request: %s", err)
+			}
+			req.Header.Add("Content-type", "application/json")
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("unable to send request: %s", err)
+			}
+			defer resp.Body.Close()
+
+			bodyBytes, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("unable to read response body: %s", err)
+			}
+			if tc.isErr {
+				if resp.StatusCode == http.StatusOK {
+					t.Fatalf("expected an error, got 200: %s", string(bodyBytes))
+				}
+				return
+			}
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("response status code is not 200, got %d: %s", resp.StatusCode, string(bodyBytes))
+			}
+
+			var body map[string]interface{}
+			if err := json.Unmarshal(bodyBytes, &body); err != nil {
+				t.Fatalf("error parsing response body")
+			}
+			got, ok := body["result"].(string)
+			if !ok {
+				t.Fatalf("unable to find result in response body")
+			}
+			var rows []map[string]any
+			if err := json.Unmarshal([]byte(got), &rows); err != nil {
+				t.Fatalf("unable to parse result %q: %s", got, err)
+			}
+			var names []string
+			lastDistance := -1.0
+			for _, row := range rows {
+				if _, ok := row["embedding"]; ok {
+					t.Errorf("expected the searched column to be left out of row %v", row)
+				}
+				distance, ok := row["distance"].(float64)
+				if !ok {
+					t.Fatalf("expected a distance in row %v", row)
+				}
+				if distance < lastDistance {
+					t.Errorf("rows are not ordered by distance: %v", rows)
+				}
+				lastDistance = distance
+				names = append(names, fmt.Sprint(row["name"]))
+			}
+			if !slices.Equal(names, tc.wantNames) {
+				t.Fatalf("unexpected rows: got %v, want %v", names, tc.wantNames)
+			}
+		})
 	}
-	config["tools"] = tools
-	return config
 }
 
-func runBigQueryExecuteSqlToolInvokeTest(t *testing.T, select1Want, invokeParamWant, tableNameParam, ddlWant string) {
-	// Get ID token
+func runBigQueryAnalyzeContributionToolInvokeTest(t *testing.T, tableName string) {
 	idToken, err := tests.GetGoogleIdToken(tests.ClientId)
 	if err != nil {
 		t.Fatalf("error getting Google ID token: %s", err)
@@ -712,7 +2335,8 @@ func runBigQueryExecuteSqlToolInvokeTest(t *testing.T, select1Want, invokeParamW
 	}
 	accessToken = "Bearer " + accessToken
 
-	// Test tool invoke endpoint
+	dataTable := strings.ReplaceAll(tableName, "`", "")
+
 	invokeTcs := []struct {
 		name          string
 		api           string
@@ -722,110 +2346,56 @@ func runBigQueryExecuteSqlToolInvokeTest(t *testing.T, select1Want, invokeParamW
 		isErr         bool
 	}{
 		{
-			name:          "invoke my-exec-sql-tool without body",
-			api:           "http://127.0.0.1:5000/api/tool/my-exec-sql-tool/invoke",
-			requestHeader: map[string]string{},
-			requestBody:   bytes.NewBuffer([]byte(`{}`)),
-			isErr:         true,
-		},
-		{
-			name:          "invoke my-exec-sql-tool",
-			api:           "http://127.0.0.1:5000/api/tool/my-exec-sql-tool/invoke",
-			requestHeader: map[string]string{},
-			requestBody:   bytes.NewBuffer([]byte(`{"sql":"SELECT 1"}`)),
-			want:          select1Want,
-			isErr:         false,
-		},
-		{
-			name:          "invoke my-exec-sql-tool create table",
-			api:           "http://127.0.0.1:5000/api/tool/my-exec-sql-tool/invoke",
-			requestHeader: map[string]string{},
-			requestBody:   bytes.NewBuffer([]byte(`{"sql":"CREATE TABLE t (id SERIAL PRIMARY KEY, name TEXT)"}`)),
-			want:          ddlWant,
-			isErr:         true,
-		},
-		{
-			name:          "invoke my-exec-sql-tool with data present in table",
-			api:           "http://127.0.0.1:5000/api/tool/my-exec-sql-tool/invoke",
-			requestHeader: map[string]string{},
-			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf("{\"sql\":\"SELECT * FROM %s WHERE id = 3 OR name = 'Alice' ORDER BY id\"}", tableNameParam))),
-			want:          invokeParamWant,
-			isErr:         false,
-		},
-		{
-			name:          "invoke my-exec-sql-tool with no matching rows",
-			api:           "http://127.0.0.1:5000/api/tool/my-exec-sql-tool/invoke",
-			requestHeader: map[string]string{},
-			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf("{\"sql\":\"SELECT * FROM %s WHERE id = 999\"}", tableNameParam))),
-			want:          `"The query returned 0 rows."`,
-			isErr:         false,
-		},
-		{
-			name:          "invoke my-exec-sql-tool drop table",
-			api:           "http://127.0.0.1:5000/api/tool/my-exec-sql-tool/invoke",
+			name:          "invoke my-analyze-contribution-tool without required params",
+			api:           "http://127.0.0.1:5000/api/tool/my-analyze-contribution-tool/invoke",
 			requestHeader: map[string]string{},
-			requestBody:   bytes.NewBuffer([]byte(`{"sql":"DROP TABLE t"}`)),
-			want:          ddlWant,
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf(`{"input_data": "%s"}`, dataTable))),
 			isErr:         true,
 		},
 		{
-			name:          "invoke my-exec-sql-tool insert entry",
-			api:           "http://127.0.0.1:5000/api/tool/my-exec-sql-tool/invoke",
+			name:          "invoke my-analyze-contribution-tool with table",
+			api:           "http://127.0.0.1:5000/api/tool/my-analyze-contribution-tool/invoke",
 			requestHeader: map[string]string{},
-			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf("{\"sql\":\"INSERT INTO %s (id, name) VALUES (4, 'test_name')\"}", tableNameParam))),
-			want:          ddlWant,
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf(`{"input_data": "%s", "contribution_metric": "SUM(metric)", "is_test_col": "is_test", "dimension_id_cols": ["dim1", "dim2"]}`, dataTable))),
+			want:          `"relative_difference"`,
 			isErr:         false,
 		},
 		{
-			name:          "invoke my-exec-sql-tool without body",
-			api:           "http://127.0.0.1:5000/api/tool/my-exec-sql-tool/invoke",
-			requestHeader: map[string]string{},
-			requestBody:   bytes.NewBuffer([]byte(`{}`)),
-			isErr:         true,
-		},
-		{
-			name:          "Invoke my-auth-exec-sql-tool with auth token",
-			api:           "http://127.0.0.1:5000/api/tool/my-auth-exec-sql-tool/invoke",
+			name:          "invoke my-auth-analyze-contribution-tool with auth token",
+			api:           "http://127.0.0.1:5000/api/tool/my-auth-analyze-contribution-tool/invoke",
 			requestHeader: map[string]string{"my-google-auth_token": idToken},
-			requestBody:   bytes.NewBuffer([]byte(`{"sql":"SELECT 1"}`)),
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf(`{"input_data": "%s", "contribution_metric": "SUM(metric)", "is_test_col": "is_test", "dimension_id_cols": ["dim1", "dim2"]}`, dataTable))),
+			want:          `"relative_difference"`,
 			isErr:         false,
-			want:          select1Want,
 		},
 		{
-			name:          "Invoke my-auth-exec-sql-tool with invalid auth token",
-			api:           "http://127.0.0.1:5000/api/tool/my-auth-exec-sql-tool/invoke",
+			name:          "invoke my-auth-analyze-contribution-tool with invalid auth token",
+			api:           "http://127.0.0.1:5000/api/tool/my-auth-analyze-contribution-tool/invoke",
 			requestHeader: map[string]string{"my-google-auth_token": "INVALID_TOKEN"},
-			requestBody:   bytes.NewBuffer([]byte(`{"sql":"SELECT 1"}`)),
-			isErr:         true,
-		},
-		{
-			name:          "Invoke my-auth-exec-sql-tool without auth token",
-			api:           "http://127.0.0.1:5000/api/tool/my-auth-exec-sql-tool/invoke",
-			requestHeader: map[string]string{},
-			requestBody:   bytes.NewBuffer([]byte(`{"sql":"SELECT 1"}`)),
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf(`{"input_data": "%s", "contribution_metric": "SUM(metric)", "is_test_col": "is_test", "dimension_id_cols": ["dim1", "dim2"]}`, dataTable))),
 			isErr:         true,
 		},
 		{
-			name:          "Invoke my-client-auth-exec-sql-tool with auth token",
-			api:           "http://127.0.0.1:5000/api/tool/my-client-auth-exec-sql-tool/invoke",
+			name:          "Invoke my-client-auth-analyze-contribution-tool with auth token",
+			api:           "http://127.0.0.1:5000/api/tool/my-client-auth-analyze-contribution-tool/invoke",
 			requestHeader: map[string]string{"Authorization": accessToken},
-			requestBody:   bytes.NewBuffer([]byte(`{"sql":"SELECT 1"}`)),
-			want:          "[{\"f0_\":1}]",
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf(`{"input_data": "%s", "contribution_metric": "SUM(metric)", "is_test_col": "is_test", "dimension_id_cols": ["dim1", "dim2"]}`, dataTable))),
+			want:          `"relative_difference"`,
 			isErr:         false,
 		},
 		{
-			name:          "Invoke my-client-auth-exec-sql-tool without auth token",
-			api:           "http://127.0.0.1:5000/api/tool/my-client-auth-exec-sql-tool/invoke",
+			name:          "Invoke my-client-auth-analyze-contribution-tool without auth token",
+			api:           "http://127.0.0.1:5000/api/tool/my-client-auth-analyze-contribution-tool/invoke",
 			requestHeader: map[string]string{},
-			requestBody:   bytes.NewBuffer([]byte(`{"sql":"SELECT 1"}`)),
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf(`{"input_data": "%s", "contribution_metric": "SUM(metric)", "is_test_col": "is_test", "dimension_id_cols": ["dim1", "dim2"]}`, dataTable))),
 			isErr:         true,
 		},
 		{
 
-			name:          "Invoke my-client-auth-exec-sql-tool with invalid auth token",
-			api:           "http://127.0.0.1:5000/api/tool/my-client-auth-exec-sql-tool/invoke",
+			name:          "Invoke my-client-auth-analyze-contribution-tool with invalid auth token",
+			api:           "http://127.0.0.1:5000/api/tool/my-client-auth-analyze-contribution-tool/invoke",
 			requestHeader: map[string]string{"Authorization": "Bearer invalid-token"},
-			requestBody:   bytes.NewBuffer([]byte(`{"sql":"SELECT 1"}`)),
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf(`{"input_data": "%s", "contribution_metric": "SUM(metric)", "is_test_col": "is_test", "dimension_id_cols": ["dim1", "dim2"]}`, dataTable))),
 			isErr:         true,
 		},
 	}
@@ -866,22 +2436,14 @@ func runBigQueryExecuteSqlToolInvokeTest(t *testing.T, select1Want, invokeParamW
 				t.Fatalf("unable to find result in response body")
 			}
 
-			if got != tc.want {
-				t.Fatalf("unexpected value: got %q, want %q", got, tc.want)
+			if !strings.Contains(got, tc.want) {
+				t.Fatalf("expected %q to contain %q, but it did not", got, tc.want)
 			}
 		})
 	}
 }
 
-func runBigQueryExecuteSqlToolInvokeDryRunTest(t *testing.T, datasetName string) {
-	// Get ID token
-	idToken, err := tests.GetGoogleIdToken(tests.ClientId)
-	if err != nil {
-		t.Fatalf("error getting Google ID token: %s", err)
-	}
-
-	newTableName := fmt.Sprintf("%s.new_dry_run_table_%s", datasetName, strings.ReplaceAll(uuid.New().String(), "-", ""))
-
+func runBigQueryDataTypeTests(t *testing.T) {
 	// Test tool invoke endpoint
 	invokeTcs := []struct {
 		name          string
@@ -892,50 +2454,51 @@ func runBigQueryExecuteSqlToolInvokeDryRunTest(t *testing.T, datasetName string)
 		isErr         bool
 	}{
 		{
-			name:          "invoke my-exec-sql-tool with dryRun",
-			api:           "http://127.0.0.1:5000/api/tool/my-exec-sql-tool/invoke",
+			name:          "invoke my-scalar-datatype-tool with values",
+			api:           "http://127.0.0.1:5000/api/tool/my-scalar-datatype-tool/invoke",
 			requestHeader: map[string]string{},
-			requestBody:   bytes.NewBuffer([]byte(`{"sql":"SELECT 1", "dry_run": true}`)),
-			want:          `\"statementType\": \"SELECT\"`,
+			requestBody:   bytes.NewBuffer([]byte(`{"int_val": 123, "string_val": "hello", "float_val": 3.14, "bool_val": true}`)),
+			want:          `[{"bool_val":true,"float_val":3.14,"id":1,"int_val":123,"string_val":"hello"}]`,
 			isErr:         false,
 		},
 		{
-			name:          "invoke my-exec-sql-tool with dryRun create table",
-			api:           "http://127.0.0.1:5000/api/tool/my-exec-sql-tool/invoke",
+			name:          "invoke my-scalar-datatype-tool with missing params",
+			api:           "http://127.0.0.1:5000/api/tool/my-scalar-datatype-tool/invoke",
 			requestHeader: map[string]string{},
-			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf(`{"sql":"CREATE TABLE %s (id INT64, name STRING)", "dry_run": true}`, newTableName))),
-			want:          `\"statementType\": \"CREATE_TABLE\"`,
-			isErr:         false,
+			requestBody:   bytes.NewBuffer([]byte(`{"int_val": 123}`)),
+			isErr:         true,
 		},
 		{
-			name:          "invoke my-exec-sql-tool with dryRun execute immediate",
-			api:           "http://127.0.0.1:5000/api/tool/my-exec-sql-tool/invoke",
+			name:          "invoke my-positional-param-tool",
+			api:           "http://127.0.0.1:5000/api/tool/my-positional-param-tool/invoke",
 			requestHeader: map[string]string{},
-			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf(`{"sql":"EXECUTE IMMEDIATE \"CREATE TABLE %s (id INT64, name STRING)\"", "dry_run": true}`, newTableName))),
-			want:          `\"statementType\": \"SCRIPT\"`,
+			requestBody:   bytes.NewBuffer([]byte(`{"fourth": 4, "third": "c", "second": 2, "first": "a"}`)),
+			want:          `[{"first_val":"a","fourth_val":4,"second_val":2,"third_val":"c"}]`,
 			isErr:         false,
 		},
 		{
-			name:          "Invoke my-auth-exec-sql-tool with dryRun and auth token",
-			api:           "http://127.0.0.1:5000/api/tool/my-auth-exec-sql-tool/invoke",
-			requestHeader: map[string]string{"my-google-auth_token": idToken},
-			requestBody:   bytes.NewBuffer([]byte(`{"sql":"SELECT 1", "dry_run": true}`)),
+			name:          "invoke my-array-datatype-tool",
+			api:           "http://127.0.0.1:5000/api/tool/my-array-datatype-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   bytes.NewBuffer([]byte(`{"int_array": [123, 789], "string_array": ["hello", "test"], "float_array": [3.14, 100.1], "bool_array": [true]}`)),
+			want:          `[{"bool_val":true,"float_val":3.14,"id":1,"int_val":123,"string_val":"hello"},{"bool_val":true,"float_val":100.1,"id":3,"int_val":789,"string_val":"test"}]`,
 			isErr:         false,
-			want:          `\"statementType\": \"SELECT\"`,
 		},
 		{
-			name:          "Invoke my-auth-exec-sql-tool with dryRun and invalid auth token",
-			api:           "http://127.0.0.1:5000/api/tool/my-auth-exec-sql-tool/invoke",
-			requestHeader: map[string]string{"my-google-auth_token": "INVALID_TOKEN"},
-			requestBody:   bytes.NewBuffer([]byte(`{"sql":"SELECT 1","dry_run": true}`)),
-			isErr:         true,
+			name:          "invoke my-repeated-datatype-tool",
+			api:           "http://127.0.0.1:5000/api/tool/my-repeated-datatype-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   bytes.NewBuffer([]byte(`{}`)),
+			want:          `[{"attrs":[{"key":"x","value":1},{"key":"y","value":2}],"id":1,"tags":["a","b"]},{"attrs":[],"id":2,"tags":[]}]`,
+			isErr:         false,
 		},
 		{
-			name:          "Invoke my-auth-exec-sql-tool with dryRun and without auth token",
-			api:           "http://127.0.0.1:5000/api/tool/my-auth-exec-sql-tool/invoke",
+			name:          "invoke my-repeated-null-datatype-tool",
+			api:           "http://127.0.0.1:5000/api/tool/my-repeated-null-datatype-tool/invoke",
 			requestHeader: map[string]string{},
-			requestBody:   bytes.NewBuffer([]byte(`{"sql":"SELECT 1", "dry_run": true}`)),
-			isErr:         true,
+			requestBody:   bytes.NewBuffer([]byte(`{}`)),
+			want:          `[{"attrs":[{"key":"x","value":1},{"key":"y","value":2}],"id":1,"tags":["a","b"]},{"attrs":null,"id":2,"tags":null}]`,
+			isErr:         false,
 		},
 	}
 	for _, tc := range invokeTcs {
@@ -975,343 +2538,490 @@ func runBigQueryExecuteSqlToolInvokeDryRunTest(t *testing.T, datasetName string)
 				t.Fatalf("unable to find result in response body")
 			}
 
-			if !strings.Contains(got, tc.want) {
-				t.Fatalf("expected %q to contain %q, but it did not", got, tc.want)
+			if got != tc.want {
+				t.Fatalf("unexpected value: got %q, want %q", got, tc.want)
 			}
 		})
 	}
 }
 
-func runBigQueryForecastToolInvokeTest(t *testing.T, tableName string) {
-	idToken, err := tests.GetGoogleIdToken(tests.ClientId)
+func runBigQueryConnectionPropertiesToolInvokeTest(t *testing.T) {
+	req, err := http.NewRequest(http.MethodPost, "http://127.0.0.1:5000/api/tool/my-time-zone-tool/invoke", bytes.NewBuffer([]byte(`{}`)))
 	if err != nil {
-		t.Fatalf("error getting Google ID token: %s", err)
+		t.Fatalf("unable to create request: %s", err)
+	}
+	req.Header.Add("Content-type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("unable to send request: %s", err)
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unable to read response body: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("response status code is not 200, got %d: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(bodyBytes, &body); err != nil {
+		t.Fatalf("error parsing response body")
+	}
+	got, ok := body["result"].(string)
+	if !ok {
+		t.Fatalf("unable to find result in response body")
+	}
+	want := `[{"in_zone":true}]`
+	if got != want {
+		t.Fatalf("unexpected value: got %q, want %q", got, want)
+	}
+}
+
+func runBigQueryQueryTagTest(t *testing.T, ctx context.Context, client *bigqueryapi.Client) {
+	start := time.Now().Add(-time.Minute)
+	resp, bodyBytes := tests.RunRequest(t, http.MethodPost, "http://127.0.0.1:5000/api/tool/my-tagged-tool/invoke", bytes.NewBuffer([]byte(`{}`)), nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("response status code is not 200, got %d: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	// the tool's query job is among the jobs created since the invocation
+	it := client.Jobs(ctx)
+	it.MinCreationTime = start
+	for {
+		job, err := it.Next()
+		if err == iterator.Done {
+			t.Fatalf("no query job labeled %s=%s found", bigquerycommon.QueryTagLabel, bigqueryQueryTag)
+		}
+		if err != nil {
+			t.Fatalf("unable to list jobs: %s", err)
+		}
+		cfg, err := job.Config()
+		if err != nil {
+			t.Fatalf("unable to get config of job %s: %s", job.ID(), err)
+		}
+		q, ok := cfg.(*bigqueryapi.QueryConfig)
+		if !ok {
+			continue
+		}
+		if q.Labels[bigquerycommon.QueryTagLabel] == bigqueryQueryTag {
+			return
+		}
+	}
+}
+
+func runBigQueryIncludeStatsToolInvokeTest(t *testing.T) {
+	tcs := []struct {
+		name string
+		api  string
+		body string
+	}{
+		{
+			name: "bigquery-sql",
+			api:  "http://127.0.0.1:5000/api/tool/my-stats-tool/invoke",
+			body: `{}`,
+		},
+		{
+			name: "bigquery-execute-sql",
+			api:  "http://127.0.0.1:5000/api/tool/my-exec-sql-stats-tool/invoke",
+			body: `{"sql":"SELECT 1"}`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, bodyBytes := tests.RunRequest(t, http.MethodPost, tc.api, bytes.NewBuffer([]byte(tc.body)), nil)
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("response status code is not 200, got %d: %s", resp.StatusCode, string(bodyBytes))
+			}
+
+			var body map[string]any
+			if err := json.Unmarshal(bodyBytes, &body); err != nil {
+				t.Fatalf("error parsing response body: %s", err)
+			}
+			got, ok := body["result"].(string)
+			if !ok {
+				t.Fatalf("unable to find result in response body")
+			}
+			var result struct {
+				Rows  []map[string]any `json:"rows"`
+				Stats map[string]any   `json:"stats"`
+			}
+			if err := json.Unmarshal([]byte(got), &result); err != nil {
+				t.Fatalf("unable to parse result %q: %s", got, err)
+			}
+			if len(result.Rows) != 1 {
+				t.Fatalf("unexpected rows: got %v", result.Rows)
+			}
+			// BI Engine is usually not set up for the test project, so the
+			// query may not be accelerated, but the mode is always reported.
+			switch mode := result.Stats["biEngineMode"]; mode {
+			case "FULL", "PARTIAL", "DISABLED":
+			default:
+				t.Fatalf("unexpected BI Engine mode in stats %v: %v", result.Stats, mode)
+			}
+			if _, ok := result.Stats["totalBytesProcessed"]; !ok {
+				t.Fatalf("expected totalBytesProcessed in stats, got %v", result.Stats)
+			}
+		})
+	}
+}
+
+func runBigQueryArrowToolInvokeTest(t *testing.T) {
+	invoke := func(accept string) (*http.Response, []byte) {
+		req, err := http.NewRequest(http.MethodPost, "http://127.0.0.1:5000/api/tool/my-arrow-tool/invoke", bytes.NewBuffer([]byte(`{}`)))
+		if err != nil {
+			t.Fatalf("unable to create request: %s", err)
+		}
+		req.Header.Add("Content-type", "application/json")
+		if accept != "" {
+			req.Header.Add("Accept", accept)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("unable to send request: %s", err)
+		}
+		defer resp.Body.Close()
+		bodyBytes, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("unable to read response body: %s", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("response status code is not 200, got %d: %s", resp.StatusCode, string(bodyBytes))
+		}
+		return resp, bodyBytes
+	}
+
+	// rows as returned by the JSON path
+	_, bodyBytes := invoke("")
+	var body map[string]any
+	if err := json.Unmarshal(bodyBytes, &body); err != nil {
+		t.Fatalf("error parsing response body")
+	}
+	result, ok := body["result"].(string)
+	if !ok {
+		t.Fatalf("unable to find result in response body")
+	}
+	var want []map[string]any
+	if err := json.Unmarshal([]byte(result), &want); err != nil {
+		t.Fatalf("unable to parse result %q: %s", result, err)
+	}
+
+	resp, stream := invoke("application/vnd.apache.arrow.stream")
+	if got := resp.Header.Get("Content-Type"); got != "application/vnd.apache.arrow.stream" {
+		t.Fatalf("unexpected content type: got %q", got)
+	}
+	reader, err := ipc.NewReader(bytes.NewReader(stream))
+	if err != nil {
+		t.Fatalf("unable to read Arrow stream: %s", err)
+	}
+	defer reader.Release()
+	var arrowRows []map[string]any
+	for reader.Next() {
+		record := reader.Record()
+		for i := 0; i < int(record.NumRows()); i++ {
+			row := make(map[string]any)
+			for j, col := range record.Columns() {
+				row[record.ColumnName(j)] = col.GetOneForMarshal(i)
+			}
+			arrowRows = append(arrowRows, row)
+		}
+	}
+	if err := reader.Err(); err != nil {
+		t.Fatalf("unable to read Arrow stream: %s", err)
 	}
 
-	// Get access token
-	accessToken, err := sources.GetIAMAccessToken(t.Context())
+	// round trip through JSON, so that numbers compare like the JSON path's
+	arrowJSON, err := json.Marshal(arrowRows)
 	if err != nil {
-		t.Fatalf("error getting access token from ADC: %s", err)
+		t.Fatalf("unable to marshal Arrow rows: %s", err)
 	}
-	accessToken = "Bearer " + accessToken
-
-	historyDataTable := strings.ReplaceAll(tableName, "`", "")
-	historyDataQuery := fmt.Sprintf("SELECT ts, data, id FROM %s", tableName)
+	var got []map[string]any
+	if err := json.Unmarshal(arrowJSON, &got); err != nil {
+		t.Fatalf("unable to parse Arrow rows: %s", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("Arrow rows don't match the JSON rows: diff %v", diff)
+	}
+}
 
+func runBigQueryReadOnlyToolInvokeTest(t *testing.T, tableName string) {
 	invokeTcs := []struct {
-		name          string
-		api           string
-		requestHeader map[string]string
-		requestBody   io.Reader
-		want          string
-		isErr         bool
+		name        string
+		sql         string
+		want        string
+		wantErrBody string
 	}{
 		{
-			name:          "invoke my-forecast-tool without required params",
-			api:           "http://127.0.0.1:5000/api/tool/my-forecast-tool/invoke",
-			requestHeader: map[string]string{},
-			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf(`{"history_data": "%s"}`, historyDataTable))),
-			isErr:         true,
-		},
-		{
-			name:          "invoke my-forecast-tool with table",
-			api:           "http://127.0.0.1:5000/api/tool/my-forecast-tool/invoke",
-			requestHeader: map[string]string{},
-			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf(`{"history_data": "%s", "timestamp_col": "ts", "data_col": "data"}`, historyDataTable))),
-			want:          `"forecast_timestamp"`,
-			isErr:         false,
-		},
-		{
-			name:          "invoke my-forecast-tool with query and horizon",
-			api:           "http://127.0.0.1:5000/api/tool/my-forecast-tool/invoke",
-			requestHeader: map[string]string{},
-			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf(`{"history_data": "%s", "timestamp_col": "ts", "data_col": "data", "horizon": 5}`, historyDataQuery))),
-			want:          `"forecast_timestamp"`,
-			isErr:         false,
-		},
-		{
-			name:          "invoke my-forecast-tool with id_cols",
-			api:           "http://127.0.0.1:5000/api/tool/my-forecast-tool/invoke",
-			requestHeader: map[string]string{},
-			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf(`{"history_data": "%s", "timestamp_col": "ts", "data_col": "data", "id_cols": ["id"]}`, historyDataTable))),
-			want:          `"id"`,
-			isErr:         false,
-		},
-		{
-			name:          "invoke my-auth-forecast-tool with auth token",
-			api:           "http://127.0.0.1:5000/api/tool/my-auth-forecast-tool/invoke",
-			requestHeader: map[string]string{"my-google-auth_token": idToken},
-			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf(`{"history_data": "%s", "timestamp_col": "ts", "data_col": "data"}`, historyDataTable))),
-			want:          `"forecast_timestamp"`,
-			isErr:         false,
-		},
-		{
-			name:          "invoke my-auth-forecast-tool with invalid auth token",
-			api:           "http://127.0.0.1:5000/api/tool/my-auth-forecast-tool/invoke",
-			requestHeader: map[string]string{"my-google-auth_token": "INVALID_TOKEN"},
-			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf(`{"history_data": "%s", "timestamp_col": "ts", "data_col": "data"}`, historyDataTable))),
-			isErr:         true,
+			name: "invoke my-read-only-tool with SELECT",
+			sql:  fmt.Sprintf("SELECT id, name FROM %s WHERE id = 1", tableName),
+			want: `[{"id":1,"name":"Alice"}]`,
 		},
 		{
-			name:          "Invoke my-client-auth-forecast-tool with auth token",
-			api:           "http://127.0.0.1:5000/api/tool/my-client-auth-forecast-tool/invoke",
-			requestHeader: map[string]string{"Authorization": accessToken},
-			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf(`{"history_data": "%s", "timestamp_col": "ts", "data_col": "data"}`, historyDataTable))),
-			want:          `"forecast_timestamp"`,
-			isErr:         false,
+			name: "invoke my-read-only-tool with WITH-prefixed SELECT",
+			sql:  "WITH t AS (SELECT 1 AS x) SELECT x FROM t",
+			want: `[{"x":1}]`,
 		},
 		{
-			name:          "Invoke my-client-auth-forecast-tool without auth token",
-			api:           "http://127.0.0.1:5000/api/tool/my-client-auth-forecast-tool/invoke",
-			requestHeader: map[string]string{},
-			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf(`{"history_data": "%s", "timestamp_col": "ts", "data_col": "data"}`, historyDataTable))),
-			isErr:         true,
+			name:        "invoke my-read-only-tool with INSERT",
+			sql:         fmt.Sprintf("INSERT INTO %s (id, name) VALUES (100, 'read_only')", tableName),
+			wantErrBody: `only runs SELECT statements, got statement type \"INSERT\"`,
 		},
 		{
-
-			name:          "Invoke my-client-auth-forecast-tool with invalid auth token",
-			api:           "http://127.0.0.1:5000/api/tool/my-client-auth-forecast-tool/invoke",
-			requestHeader: map[string]string{"Authorization": "Bearer invalid-token"},
-			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf(`{"history_data": "%s", "timestamp_col": "ts", "data_col": "data"}`, historyDataTable))),
-			isErr:         true,
+			name:        "invoke my-read-only-tool with DDL",
+			sql:         fmt.Sprintf("DROP TABLE %s", tableName),
+			wantErrBody: `only runs SELECT statements, got statement type \"DROP_TABLE\"`,
 		},
 	}
 	for _, tc := range invokeTcs {
 		t.Run(tc.name, func(t *testing.T) {
-			// Send Tool invocation request
-			req, err := http.NewRequest(http.MethodPost, tc.api, tc.requestBody)
+			reqBody, err := json.Marshal(map[string]any{"sql": tc.sql})
+			if err != nil {
+				t.Fatalf("unable to marshal request body: %s", err)
+			}
+			req, err := http.NewRequest(http.MethodPost, "http://127.0.0.1:5000/api/tool/my-read-only-tool/invoke", bytes.NewBuffer(reqBody))
 			if err != nil {
 				t.Fatalf("unable to create request: %s", err)
 			}
 			req.Header.Add("Content-type", "application/json")
-			for k, v := range tc.requestHeader {
-				req.Header.Add(k, v)
-			}
 			resp, err := http.DefaultClient.Do(req)
 			if err != nil {
 				t.Fatalf("unable to send request: %s", err)
 			}
 			defer resp.Body.Close()
 
-			if resp.StatusCode != http.StatusOK {
-				if tc.isErr {
-					return
+			bodyBytes, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("unable to read response body: %s", err)
+			}
+			if tc.wantErrBody != "" {
+				if resp.StatusCode == http.StatusOK {
+					t.Fatalf("expected an error, got 200: %s", string(bodyBytes))
 				}
-				bodyBytes, _ := io.ReadAll(resp.Body)
+				if !strings.Contains(string(bodyBytes), tc.wantErrBody) {
+					t.Fatalf("expected error %q to contain %q", string(bodyBytes), tc.wantErrBody)
+				}
+				return
+			}
+			if resp.StatusCode != http.StatusOK {
 				t.Fatalf("response status code is not 200, got %d: %s", resp.StatusCode, string(bodyBytes))
 			}
 
-			// Check response body
 			var body map[string]interface{}
-			err = json.NewDecoder(resp.Body).Decode(&body)
-			if err != nil {
+			if err := json.Unmarshal(bodyBytes, &body); err != nil {
 				t.Fatalf("error parsing response body")
 			}
-
 			got, ok := body["result"].(string)
 			if !ok {
 				t.Fatalf("unable to find result in response body")
 			}
-
-			if !strings.Contains(got, tc.want) {
-				t.Fatalf("expected %q to contain %q, but it did not", got, tc.want)
+			if got != tc.want {
+				t.Fatalf("unexpected value: got %q, want %q", got, tc.want)
 			}
 		})
 	}
 }
 
-func runBigQueryAnalyzeContributionToolInvokeTest(t *testing.T, tableName string) {
-	idToken, err := tests.GetGoogleIdToken(tests.ClientId)
-	if err != nil {
-		t.Fatalf("error getting Google ID token: %s", err)
-	}
-
-	// Get access token
-	accessToken, err := sources.GetIAMAccessToken(t.Context())
-	if err != nil {
-		t.Fatalf("error getting access token from ADC: %s", err)
-	}
-	accessToken = "Bearer " + accessToken
-
-	dataTable := strings.ReplaceAll(tableName, "`", "")
-
+func runBigQueryRunSavedQueryToolInvokeTest(t *testing.T) {
 	invokeTcs := []struct {
 		name          string
-		api           string
-		requestHeader map[string]string
 		requestBody   io.Reader
 		want          string
 		isErr         bool
+		wantErrSubstr string
 	}{
 		{
-			name:          "invoke my-analyze-contribution-tool without required params",
-			api:           "http://127.0.0.1:5000/api/tool/my-analyze-contribution-tool/invoke",
-			requestHeader: map[string]string{},
-			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf(`{"input_data": "%s"}`, dataTable))),
-			isErr:         true,
-		},
-		{
-			name:          "invoke my-analyze-contribution-tool with table",
-			api:           "http://127.0.0.1:5000/api/tool/my-analyze-contribution-tool/invoke",
-			requestHeader: map[string]string{},
-			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf(`{"input_data": "%s", "contribution_metric": "SUM(metric)", "is_test_col": "is_test", "dimension_id_cols": ["dim1", "dim2"]}`, dataTable))),
-			want:          `"relative_difference"`,
-			isErr:         false,
-		},
-		{
-			name:          "invoke my-auth-analyze-contribution-tool with auth token",
-			api:           "http://127.0.0.1:5000/api/tool/my-auth-analyze-contribution-tool/invoke",
-			requestHeader: map[string]string{"my-google-auth_token": idToken},
-			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf(`{"input_data": "%s", "contribution_metric": "SUM(metric)", "is_test_col": "is_test", "dimension_id_cols": ["dim1", "dim2"]}`, dataTable))),
-			want:          `"relative_difference"`,
-			isErr:         false,
-		},
-		{
-			name:          "invoke my-auth-analyze-contribution-tool with invalid auth token",
-			api:           "http://127.0.0.1:5000/api/tool/my-auth-analyze-contribution-tool/invoke",
-			requestHeader: map[string]string{"my-google-auth_token": "INVALID_TOKEN"},
-			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf(`{"input_data": "%s", "contribution_metric": "SUM(metric)", "is_test_col": "is_test", "dimension_id_cols": ["dim1", "dim2"]}`, dataTable))),
-			isErr:         true,
+			name:        "invoke my-saved-query-tool with parameters",
+			requestBody: bytes.NewBuffer([]byte(`{"name": "get_by_id", "parameters": {"id": 3}}`)),
+			want:        `[{"id":3,"name":"Sid"}]`,
 		},
 		{
-			name:          "Invoke my-client-auth-analyze-contribution-tool with auth token",
-			api:           "http://127.0.0.1:5000/api/tool/my-client-auth-analyze-contribution-tool/invoke",
-			requestHeader: map[string]string{"Authorization": accessToken},
-			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf(`{"input_data": "%s", "contribution_metric": "SUM(metric)", "is_test_col": "is_test", "dimension_id_cols": ["dim1", "dim2"]}`, dataTable))),
-			want:          `"relative_difference"`,
-			isErr:         false,
+			name:        "invoke my-saved-query-tool without parameters",
+			requestBody: bytes.NewBuffer([]byte(`{"name": "count_rows"}`)),
+			want:        `[{"total":4}]`,
 		},
 		{
-			name:          "Invoke my-client-auth-analyze-contribution-tool without auth token",
-			api:           "http://127.0.0.1:5000/api/tool/my-client-auth-analyze-contribution-tool/invoke",
-			requestHeader: map[string]string{},
-			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf(`{"input_data": "%s", "contribution_metric": "SUM(metric)", "is_test_col": "is_test", "dimension_id_cols": ["dim1", "dim2"]}`, dataTable))),
+			name:          "invoke my-saved-query-tool with unknown name",
+			requestBody:   bytes.NewBuffer([]byte(`{"name": "drop_table"}`)),
 			isErr:         true,
+			wantErrSubstr: `no saved query named \"drop_table\"`,
 		},
 		{
-
-			name:          "Invoke my-client-auth-analyze-contribution-tool with invalid auth token",
-			api:           "http://127.0.0.1:5000/api/tool/my-client-auth-analyze-contribution-tool/invoke",
-			requestHeader: map[string]string{"Authorization": "Bearer invalid-token"},
-			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf(`{"input_data": "%s", "contribution_metric": "SUM(metric)", "is_test_col": "is_test", "dimension_id_cols": ["dim1", "dim2"]}`, dataTable))),
+			name:          "invoke my-saved-query-tool with missing parameter",
+			requestBody:   bytes.NewBuffer([]byte(`{"name": "get_by_id", "parameters": {}}`)),
 			isErr:         true,
+			wantErrSubstr: `parameter \"id\" is required`,
 		},
 	}
 	for _, tc := range invokeTcs {
 		t.Run(tc.name, func(t *testing.T) {
-			// Send Tool invocation request
-			req, err := http.NewRequest(http.MethodPost, tc.api, tc.requestBody)
+			req, err := http.NewRequest(http.MethodPost, "http://127.0.0.1:5000/api/tool/my-saved-query-tool/invoke", tc.requestBody)
 			if err != nil {
 				t.Fatalf("unable to create request: %s", err)
 			}
 			req.Header.Add("Content-type", "application/json")
-			for k, v := range tc.requestHeader {
-				req.Header.Add(k, v)
-			}
 			resp, err := http.DefaultClient.Do(req)
 			if err != nil {
 				t.Fatalf("unable to send request: %s", err)
 			}
 			defer resp.Body.Close()
 
-			if resp.StatusCode != http.StatusOK {
-				if tc.isErr {
-					return
+			bodyBytes, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("unable to read response body: %s", err)
+			}
+			if tc.isErr {
+				if resp.StatusCode == http.StatusOK {
+					t.Fatalf("expected an error, got 200: %s", string(bodyBytes))
 				}
-				bodyBytes, _ := io.ReadAll(resp.Body)
+				if !strings.Contains(string(bodyBytes), tc.wantErrSubstr) {
+					t.Fatalf("expected error %q to contain %q", string(bodyBytes), tc.wantErrSubstr)
+				}
+				return
+			}
+			if resp.StatusCode != http.StatusOK {
 				t.Fatalf("response status code is not 200, got %d: %s", resp.StatusCode, string(bodyBytes))
 			}
 
-			// Check response body
 			var body map[string]interface{}
-			err = json.NewDecoder(resp.Body).Decode(&body)
-			if err != nil {
+			if err := json.Unmarshal(bodyBytes, &body); err != nil {
 				t.Fatalf("error parsing response body")
 			}
-
 			got, ok := body["result"].(string)
 			if !ok {
 				t.Fatalf("unable to find result in response body")
 			}
-
-			if !strings.Contains(got, tc.want) {
-				t.Fatalf("expected %q to contain %q, but it did not", got, tc.want)
+			if got != tc.want {
+				t.Fatalf("unexpected value: got %q, want %q", got, tc.want)
 			}
 		})
 	}
 }
 
-func runBigQueryDataTypeTests(t *testing.T) {
-	// Test tool invoke endpoint
+func runBigQueryRowAccessPolicyWarningTest(t *testing.T, ctx context.Context, client *bigqueryapi.Client, datasetName string) {
+	tableName := fmt.Sprintf("`%s.%s.row_access_%s`", BigqueryProject, datasetName, strings.ReplaceAll(uuid.New().String(), "-", ""))
+	setupStmts := []string{
+		fmt.Sprintf("CREATE TABLE %s AS SELECT * FROM UNNEST([1, 2, 3]) AS id", tableName),
+		// Every caller can only see the row with id 1.
+		fmt.Sprintf(`CREATE ROW ACCESS POLICY only_one ON %s GRANT TO ("allAuthenticatedUsers") FILTER USING (id = 1)`, tableName),
+	}
+	for _, stmt := range setupStmts {
+		job, err := client.Query(stmt).Run(ctx)
+		if err != nil {
+			t.Fatalf("unable to run %q: %s", stmt, err)
+		}
+		status, err := job.Wait(ctx)
+		if err != nil {
+			t.Fatalf("unable to wait for %q: %s", stmt, err)
+		}
+		if err := status.Err(); err != nil {
+			t.Fatalf("%q failed: %s", stmt, err)
+		}
+	}
+	defer func() {
+		if _, err := client.Query(fmt.Sprintf("DROP TABLE %s", tableName)).Read(ctx); err != nil {
+			t.Errorf("unable to drop table %s: %s", tableName, err)
+		}
+	}()
+
+	reqBody, err := json.Marshal(map[string]any{"sql": fmt.Sprintf("SELECT id FROM %s", tableName)})
+	if err != nil {
+		t.Fatalf("unable to marshal request body: %s", err)
+	}
+	req, err := http.NewRequest(http.MethodPost, "http://127.0.0.1:5000/api/tool/my-row-access-exec-sql-tool/invoke", bytes.NewBuffer(reqBody))
+	if err != nil {
+		t.Fatalf("unable to create request: %s", err)
+	}
+	req.Header.Add("Content-type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("unable to send request: %s", err)
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unable to read response body: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("response status code is not 200, got %d: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(bodyBytes, &body); err != nil {
+		t.Fatalf("error parsing response body")
+	}
+	got, ok := body["result"].(string)
+	if !ok {
+		t.Fatalf("unable to find result in response body")
+	}
+	want := `{"rows":[{"id":1}],"warnings":["the results were filtered by a row access policy, so they may not include every row of the queried tables"]}`
+	if got != want {
+		t.Fatalf("unexpected value: got %q, want %q", got, want)
+	}
+}
+
+func runBigQueryExecuteSqlDeniedStatementTypesTest(t *testing.T, tableName string) {
 	invokeTcs := []struct {
-		name          string
-		api           string
-		requestHeader map[string]string
-		requestBody   io.Reader
-		want          string
-		isErr         bool
+		name        string
+		sql         string
+		want        string
+		wantErrBody string
 	}{
 		{
-			name:          "invoke my-scalar-datatype-tool with values",
-			api:           "http://127.0.0.1:5000/api/tool/my-scalar-datatype-tool/invoke",
-			requestHeader: map[string]string{},
-			requestBody:   bytes.NewBuffer([]byte(`{"int_val": 123, "string_val": "hello", "float_val": 3.14, "bool_val": true}`)),
-			want:          `[{"bool_val":true,"float_val":3.14,"id":1,"int_val":123,"string_val":"hello"}]`,
-			isErr:         false,
-		},
-		{
-			name:          "invoke my-scalar-datatype-tool with missing params",
-			api:           "http://127.0.0.1:5000/api/tool/my-scalar-datatype-tool/invoke",
-			requestHeader: map[string]string{},
-			requestBody:   bytes.NewBuffer([]byte(`{"int_val": 123}`)),
-			isErr:         true,
+			name:        "invoke my-denied-exec-sql-tool with denied DROP TABLE",
+			sql:         fmt.Sprintf("DROP TABLE %s", tableName),
+			wantErrBody: `statement type \"DROP_TABLE\" is not allowed by tool \"my-denied-exec-sql-tool\"`,
 		},
 		{
-			name:          "invoke my-array-datatype-tool",
-			api:           "http://127.0.0.1:5000/api/tool/my-array-datatype-tool/invoke",
-			requestHeader: map[string]string{},
-			requestBody:   bytes.NewBuffer([]byte(`{"int_array": [123, 789], "string_array": ["hello", "test"], "float_array": [3.14, 100.1], "bool_array": [true]}`)),
-			want:          `[{"bool_val":true,"float_val":3.14,"id":1,"int_val":123,"string_val":"hello"},{"bool_val":true,"float_val":100.1,"id":3,"int_val":789,"string_val":"test"}]`,
-			isErr:         false,
+			// also checks that the denied DROP TABLE above was never executed
+			name: "invoke my-denied-exec-sql-tool with allowed SELECT",
+			sql:  fmt.Sprintf("SELECT id, name FROM %s WHERE id = 1", tableName),
+			want: `[{"id":1,"name":"Alice"}]`,
 		},
 	}
 	for _, tc := range invokeTcs {
 		t.Run(tc.name, func(t *testing.T) {
-			// Send Tool invocation request
-			req, err := http.NewRequest(http.MethodPost, tc.api, tc.requestBody)
+			reqBody, err := json.Marshal(map[string]any{"sql": tc.sql})
+			if err != nil {
+				t.Fatalf("unable to marshal request body: %s", err)
+			}
+			req, err := http.NewRequest(http.MethodPost, "http://127.0.0.1:5000/api/tool/my-denied-exec-sql-tool/invoke", bytes.NewBuffer(reqBody))
 			if err != nil {
 				t.Fatalf("unable to create request: %s", err)
 			}
 			req.Header.Add("Content-type", "application/json")
-			for k, v := range tc.requestHeader {
-				req.Header.Add(k, v)
-			}
 			resp, err := http.DefaultClient.Do(req)
 			if err != nil {
 				t.Fatalf("unable to send request: %s", err)
 			}
 			defer resp.Body.Close()
 
-			if resp.StatusCode != http.StatusOK {
-				if tc.isErr {
-					return
+			bodyBytes, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("unable to read response body: %s", err)
+			}
+			if tc.wantErrBody != "" {
+				if resp.StatusCode == http.StatusOK {
+					t.Fatalf("expected an error, got 200: %s", string(bodyBytes))
 				}
-				bodyBytes, _ := io.ReadAll(resp.Body)
+				if !strings.Contains(string(bodyBytes), tc.wantErrBody) {
+					t.Fatalf("expected error %q to contain %q", string(bodyBytes), tc.wantErrBody)
+				}
+				return
+			}
+			if resp.StatusCode != http.StatusOK {
 				t.Fatalf("response status code is not 200, got %d: %s", resp.StatusCode, string(bodyBytes))
 			}
 
-			// Check response body
 			var body map[string]interface{}
-			err = json.NewDecoder(resp.Body).Decode(&body)
-			if err != nil {
+			if err := json.Unmarshal(bodyBytes, &body); err != nil {
 				t.Fatalf("error parsing response body")
 			}
-
 			got, ok := body["result"].(string)
 			if !ok {
 				t.Fatalf("unable to find result in response body")
 			}
-
 			if got != tc.want {
 				t.Fatalf("unexpected value: got %q, want %q", got, tc.want)
 			}
@@ -1670,30 +3380,440 @@ func runBigQueryListTableIdsToolInvokeTest(t *testing.T, datasetName, tablename_
 			name:          "Invoke my-auth-list-table-ids-tool without auth token",
 			api:           "http://127.0.0.1:5000/api/tool/my-auth-list-table-ids-tool/invoke",
 			requestHeader: map[string]string{},
-			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf("{\"dataset\":\"%s\"}", datasetName))),
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf("{\"dataset\":\"%s\"}", datasetName))),
+			isErr:         true,
+		},
+		{
+			name:          "Invoke my-client-auth-list-table-ids-tool with auth token",
+			api:           "http://127.0.0.1:5000/api/tool/my-client-auth-list-table-ids-tool/invoke",
+			requestHeader: map[string]string{"Authorization": accessToken},
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf("{\"dataset\":\"%s\"}", datasetName))),
+			want:          tablename_want,
+			isErr:         false,
+		},
+		{
+			name:          "Invoke my-client-auth-list-table-ids-tool without auth token",
+			api:           "http://127.0.0.1:5000/api/tool/my-client-auth-list-table-ids-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf("{\"dataset\":\"%s\"}", datasetName))),
+			isErr:         true,
+		},
+		{
+
+			name:          "Invoke my-client-auth-list-table-ids-tool with invalid auth token",
+			api:           "http://127.0.0.1:5000/api/tool/my-client-auth-list-table-ids-tool/invoke",
+			requestHeader: map[string]string{"Authorization": "Bearer invalid-token"},
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf("{\"dataset\":\"%s\"}", datasetName))),
+			isErr:         true,
+		},
+	}
+	for _, tc := range invokeTcs {
+		t.Run(tc.name, func(t *testing.T) {
+			// Send Tool invocation request
+			req, err := http.NewRequest(http.MethodPost, tc.api, tc.requestBody)
+			if err != nil {
+				t.Fatalf("unable to create request: %s", err)
+			}
+			req.Header.Add("Content-type", "application/json")
+			for k, v := range tc.requestHeader {
+				req.Header.Add(k, v)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("unable to send request: %s", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				if tc.isErr {
+					return
+				}
+				bodyBytes, _ := io.ReadAll(resp.Body)
+				t.Fatalf("response status code is not 200, got %d: %s", resp.StatusCode, string(bodyBytes))
+			}
+
+			// Check response body
+			var body map[string]interface{}
+			err = json.NewDecoder(resp.Body).Decode(&body)
+			if err != nil {
+				t.Fatalf("error parsing response body")
+			}
+
+			got, ok := body["result"].(string)
+			if !ok {
+				t.Fatalf("unable to find result in response body")
+			}
+
+			if !strings.Contains(got, tc.want) {
+				t.Fatalf("expected %q to contain %q, but it did not", got, tc.want)
+			}
+		})
+	}
+}
+
+func runBigQueryListTableIdsWithMetadataToolInvokeTest(t *testing.T, datasetName, tableName string) {
+	requestBody := bytes.NewBuffer([]byte(fmt.Sprintf(`{"dataset":"%s"}`, datasetName)))
+	req, err := http.NewRequest(http.MethodPost, "http://127.0.0.1:5000/api/tool/my-list-table-ids-with-metadata-tool/invoke", requestBody)
+	if err != nil {
+		t.Fatalf("unable to create request: %s", err)
+	}
+	req.Header.Add("Content-type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("unable to send request: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		t.Fatalf("response status code is not 200, got %d: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var body map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("error parsing response body")
+	}
+	got, ok := body["result"].(string)
+	if !ok {
+		t.Fatalf("unable to find result in response body")
+	}
+	var tables []map[string]any
+	if err := json.Unmarshal([]byte(got), &tables); err != nil {
+		t.Fatalf("unable to parse result %q as a list of tables: %s", got, err)
+	}
+	for _, table := range tables {
+		if table["tableId"] != tableName {
+			continue
+		}
+		if table["type"] != "TABLE" {
+			t.Errorf("unexpected type for table %s: got %v, want %q", tableName, table["type"], "TABLE")
+		}
+		if _, ok := table["numRows"].(float64); !ok {
+			t.Errorf("expected a numeric numRows for table %s, got %v", tableName, table["numRows"])
+		}
+		if creationTime, ok := table["creationTime"].(string); !ok || creationTime == "" {
+			t.Errorf("expected a creationTime for table %s, got %v", tableName, table["creationTime"])
+		}
+		return
+	}
+	t.Fatalf("table %s not found in result %q", tableName, got)
+}
+
+func runBigQueryListTableIdsTypesFilterTest(t *testing.T, ctx context.Context, client *bigqueryapi.Client, datasetName, tableName string) {
+	viewName := "view_" + strings.ReplaceAll(uuid.New().String(), "-", "")
+	view := client.Dataset(datasetName).Table(viewName)
+	viewQuery := fmt.Sprintf("SELECT * FROM `%s.%s.%s`", BigqueryProject, datasetName, tableName)
+	if err := view.Create(ctx, &bigqueryapi.TableMetadata{ViewQuery: viewQuery}); err != nil {
+		t.Fatalf("unable to create view %s: %s", viewName, err)
+	}
+	defer func() {
+		if err := view.Delete(ctx); err != nil {
+			t.Errorf("unable to delete view %s: %s", viewName, err)
+		}
+	}()
+
+	tcs := []struct {
+		name      string
+		types     string
+		want      string
+		notWant   string
+		wantError bool
+	}{
+		{
+			name:    "only tables",
+			types:   `["TABLE"]`,
+			want:    tableName,
+			notWant: viewName,
+		},
+		{
+			name:    "only views",
+			types:   `["VIEW"]`,
+			want:    viewName,
+			notWant: tableName,
+		},
+		{
+			name:  "tables and views",
+			types: `["TABLE", "view"]`,
+			want:  viewName,
+		},
+		{
+			name:      "unknown type",
+			types:     `["INDEX"]`,
+			wantError: true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			requestBody := bytes.NewBuffer([]byte(fmt.Sprintf(`{"dataset":"%s", "types": %s}`, datasetName, tc.types)))
+			req, err := http.NewRequest(http.MethodPost, "http://127.0.0.1:5000/api/tool/my-list-table-ids-tool/invoke", requestBody)
+			if err != nil {
+				t.Fatalf("unable to create request: %s", err)
+			}
+			req.Header.Add("Content-type", "application/json")
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("unable to send request: %s", err)
+			}
+			defer resp.Body.Close()
+
+			bodyBytes, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("unable to read response body: %s", err)
+			}
+			if tc.wantError {
+				if resp.StatusCode == http.StatusOK {
+					t.Fatalf("expected an error, got 200: %s", string(bodyBytes))
+				}
+				return
+			}
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("response status code is not 200, got %d: %s", resp.StatusCode, string(bodyBytes))
+			}
+
+			var body map[string]interface{}
+			if err := json.Unmarshal(bodyBytes, &body); err != nil {
+				t.Fatalf("error parsing response body")
+			}
+			got, ok := body["result"].(string)
+			if !ok {
+				t.Fatalf("unable to find result in response body")
+			}
+			var ids []string
+			if err := json.Unmarshal([]byte(got), &ids); err != nil {
+				t.Fatalf("unable to parse result %q as a list of table ids: %s", got, err)
+			}
+			if !slices.Contains(ids, tc.want) {
+				t.Errorf("expected %s in result %v", tc.want, ids)
+			}
+			if tc.notWant != "" && slices.Contains(ids, tc.notWant) {
+				t.Errorf("did not expect %s in result %v", tc.notWant, ids)
+			}
+		})
+	}
+}
+
+// invokeListIdsTool invokes a listing tool with requestBody and returns the
+// raw result, failing the test on a non-200 response.
+func invokeListIdsTool(t *testing.T, toolName, requestBody string) string {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, "http://127.0.0.1:5000/api/tool/"+toolName+"/invoke", bytes.NewBuffer([]byte(requestBody)))
+	if err != nil {
+		t.Fatalf("unable to create request: %s", err)
+	}
+	req.Header.Add("Content-type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("unable to send request: %s", err)
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unable to read response body: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("response status code is not 200, got %d: %s", resp.StatusCode, string(bodyBytes))
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(bodyBytes, &body); err != nil {
+		t.Fatalf("error parsing response body")
+	}
+	got, ok := body["result"].(string)
+	if !ok {
+		t.Fatalf("unable to find result in response body")
+	}
+	return got
+}
+
+// listIdsPage is the result of a listing tool invoked with a page size.
+type listIdsPage struct {
+	DatasetIds    []string `json:"datasetIds"`
+	TableIds      []string `json:"tableIds"`
+	NextPageToken string   `json:"next_page_token"`
+}
+
+func runBigQueryListDatasetIdsPagingTest(t *testing.T, datasetWant string) {
+	const pageSize = 10
+	var found bool
+	pageToken := ""
+	for page := 0; !found; page++ {
+		got := invokeListIdsTool(t, "my-list-dataset-ids-tool", fmt.Sprintf(`{"page_size": %d, "page_token": %q}`, pageSize, pageToken))
+		var p listIdsPage
+		if err := json.Unmarshal([]byte(got), &p); err != nil {
+			t.Fatalf("unable to parse result %q as a page of dataset ids: %s", got, err)
+		}
+		if len(p.DatasetIds) > pageSize {
+			t.Fatalf("page %d holds %d dataset ids, want at most %d", page, len(p.DatasetIds), pageSize)
+		}
+		found = slices.Contains(p.DatasetIds, datasetWant)
+		if !found && p.NextPageToken == "" {
+			t.Fatalf("dataset %s not found after paging through %d pages", datasetWant, page+1)
+		}
+		pageToken = p.NextPageToken
+	}
+
+	t.Run("page token without page size", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodPost, "http://127.0.0.1:5000/api/tool/my-list-dataset-ids-tool/invoke", bytes.NewBuffer([]byte(`{"page_token": "abc"}`)))
+		if err != nil {
+			t.Fatalf("unable to create request: %s", err)
+		}
+		req.Header.Add("Content-type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("unable to send request: %s", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode == http.StatusOK {
+			t.Fatalf("expected an error for a page token without a page size, got 200")
+		}
+	})
+}
+
+func runBigQueryListTableIdsPagingTest(t *testing.T, datasetName string) {
+	// The dataset holds more tables than a page, so listing it takes several
+	// pages.
+	const pageSize = 2
+	got := invokeListIdsTool(t, "my-list-table-ids-tool", fmt.Sprintf(`{"dataset": %q}`, datasetName))
+	var want []string
+	if err := json.Unmarshal([]byte(got), &want); err != nil {
+		t.Fatalf("unable to parse result %q as a list of table ids: %s", got, err)
+	}
+	if len(want) <= pageSize {
+		t.Fatalf("dataset %s holds %d tables, need more than %d to test paging", datasetName, len(want), pageSize)
+	}
+
+	var paged []string
+	pages := 0
+	pageToken := ""
+	for {
+		got := invokeListIdsTool(t, "my-list-table-ids-tool", fmt.Sprintf(`{"dataset": %q, "page_size": %d, "page_token": %q}`, datasetName, pageSize, pageToken))
+		var p listIdsPage
+		if err := json.Unmarshal([]byte(got), &p); err != nil {
+			t.Fatalf("unable to parse result %q as a page of table ids: %s", got, err)
+		}
+		if len(p.TableIds) > pageSize {
+			t.Fatalf("page %d holds %d table ids, want at most %d", pages, len(p.TableIds), pageSize)
+		}
+		paged = append(paged, p.TableIds...)
+		pages++
+		if p.NextPageToken == "" {
+			break
+		}
+		pageToken = p.NextPageToken
+	}
+	if pages < 2 {
+		t.Errorf("expected several pages, got %d", pages)
+	}
+	slices.Sort(want)
+	slices.Sort(paged)
+	if !slices.Equal(want, paged) {
+		t.Errorf("paged table ids %v, want %v", paged, want)
+	}
+}
+
+func runBigQueryGetTableInfoToolInvokeTest(t *testing.T, datasetName, tableName, tableInfoWant string) {
+	// Get ID token
+	idToken, err := tests.GetGoogleIdToken(tests.ClientId)
+	if err != nil {
+		t.Fatalf("error getting Google ID token: %s", err)
+	}
+
+	// Get access token
+	accessToken, err := sources.GetIAMAccessToken(t.Context())
+	if err != nil {
+		t.Fatalf("error getting access token from ADC: %s", err)
+	}
+	accessToken = "Bearer " + accessToken
+
+	// Test tool invoke endpoint
+	invokeTcs := []struct {
+		name          string
+		api           string
+		requestHeader map[string]string
+		requestBody   io.Reader
+		want          string
+		isErr         bool
+	}{
+		{
+			name:          "invoke my-get-table-info-tool without body",
+			api:           "http://127.0.0.1:5000/api/tool/my-get-table-info-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   bytes.NewBuffer([]byte(`{}`)),
+			isErr:         true,
+		},
+		{
+			name:          "invoke my-get-table-info-tool",
+			api:           "http://127.0.0.1:5000/api/tool/my-get-table-info-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf("{\"dataset\":\"%s\", \"table\":\"%s\"}", datasetName, tableName))),
+			want:          tableInfoWant,
+			isErr:         false,
+		},
+		{
+			name:          "invoke my-auth-get-table-info-tool without body",
+			api:           "http://127.0.0.1:5000/api/tool/my-get-table-info-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   bytes.NewBuffer([]byte(`{}`)),
+			isErr:         true,
+		},
+		{
+			name:          "Invoke my-auth-get-table-info-tool with auth token",
+			api:           "http://127.0.0.1:5000/api/tool/my-auth-get-table-info-tool/invoke",
+			requestHeader: map[string]string{"my-google-auth_token": idToken},
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf("{\"dataset\":\"%s\", \"table\":\"%s\"}", datasetName, tableName))),
+			want:          tableInfoWant,
+			isErr:         false,
+		},
+		{
+			name:          "Invoke my-auth-get-table-info-tool with correct project",
+			api:           "http://127.0.0.1:5000/api/tool/my-auth-get-table-info-tool/invoke",
+			requestHeader: map[string]string{"my-google-auth_token": idToken},
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf("{\"project\":\"%s\", \"dataset\":\"%s\", \"table\":\"%s\"}", BigqueryProject, datasetName, tableName))),
+			want:          tableInfoWant,
+			isErr:         false,
+		},
+		{
+			name:          "Invoke my-auth-get-table-info-tool with non-existent project",
+			api:           "http://127.0.0.1:5000/api/tool/my-auth-get-table-info-tool/invoke",
+			requestHeader: map[string]string{"my-google-auth_token": idToken},
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf("{\"project\":\"%s-%s\", \"dataset\":\"%s\", \"table\":\"%s\"}", BigqueryProject, uuid.NewString(), datasetName, tableName))),
+			isErr:         true,
+		},
+		{
+			name:          "Invoke my-auth-get-table-info-tool with invalid auth token",
+			api:           "http://127.0.0.1:5000/api/tool/my-auth-get-table-info-tool/invoke",
+			requestHeader: map[string]string{"my-google-auth_token": "INVALID_TOKEN"},
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf("{\"dataset\":\"%s\", \"table\":\"%s\"}", datasetName, tableName))),
+			isErr:         true,
+		},
+		{
+			name:          "Invoke my-auth-get-table-info-tool without auth token",
+			api:           "http://127.0.0.1:5000/api/tool/my-auth-get-table-info-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf("{\"dataset\":\"%s\", \"table\":\"%s\"}", datasetName, tableName))),
 			isErr:         true,
 		},
 		{
-			name:          "Invoke my-client-auth-list-table-ids-tool with auth token",
-			api:           "http://127.0.0.1:5000/api/tool/my-client-auth-list-table-ids-tool/invoke",
+			name:          "Invoke my-client-auth-get-table-info-tool with auth token",
+			api:           "http://127.0.0.1:5000/api/tool/my-client-auth-get-table-info-tool/invoke",
 			requestHeader: map[string]string{"Authorization": accessToken},
-			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf("{\"dataset\":\"%s\"}", datasetName))),
-			want:          tablename_want,
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf("{\"dataset\":\"%s\", \"table\":\"%s\"}", datasetName, tableName))),
+			want:          tableInfoWant,
 			isErr:         false,
 		},
 		{
-			name:          "Invoke my-client-auth-list-table-ids-tool without auth token",
-			api:           "http://127.0.0.1:5000/api/tool/my-client-auth-list-table-ids-tool/invoke",
+			name:          "Invoke my-client-auth-get-table-info-tool without auth token",
+			api:           "http://127.0.0.1:5000/api/tool/my-client-auth-get-table-info-tool/invoke",
 			requestHeader: map[string]string{},
-			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf("{\"dataset\":\"%s\"}", datasetName))),
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf("{\"dataset\":\"%s\", \"table\":\"%s\"}", datasetName, tableName))),
 			isErr:         true,
 		},
 		{
 
-			name:          "Invoke my-client-auth-list-table-ids-tool with invalid auth token",
-			api:           "http://127.0.0.1:5000/api/tool/my-client-auth-list-table-ids-tool/invoke",
+			name:          "Invoke my-client-auth-get-table-info-tool with invalid auth token",
+			api:           "http://127.0.0.1:5000/api/tool/my-client-auth-get-table-info-tool/invoke",
 			requestHeader: map[string]string{"Authorization": "Bearer invalid-token"},
-			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf("{\"dataset\":\"%s\"}", datasetName))),
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf("{\"dataset\":\"%s\", \"table\":\"%s\"}", datasetName, tableName))),
 			isErr:         true,
 		},
 	}
@@ -1741,13 +3861,68 @@ func runBigQueryListTableIdsToolInvokeTest(t *testing.T, datasetName, tablename_
 	}
 }
 
-func runBigQueryGetTableInfoToolInvokeTest(t *testing.T, datasetName, tableName, tableInfoWant string) {
-	// Get ID token
-	idToken, err := tests.GetGoogleIdToken(tests.ClientId)
+func runBigQueryGetTableInfoPartitionedTest(t *testing.T, ctx context.Context, client *bigqueryapi.Client, datasetName string) {
+	tableName := fmt.Sprintf("partitioned_%s", strings.ReplaceAll(uuid.New().String(), "-", ""))
+	stmt := fmt.Sprintf(`CREATE TABLE `+"`%s.%s.%s`"+` (order_date DATE, customer_id INT64, amount FLOAT64)
+		PARTITION BY order_date
+		CLUSTER BY customer_id
+		OPTIONS (partition_expiration_days = 30, require_partition_filter = TRUE)`, BigqueryProject, datasetName, tableName)
+	job, err := client.Query(stmt).Run(ctx)
 	if err != nil {
-		t.Fatalf("error getting Google ID token: %s", err)
+		t.Fatalf("unable to run %q: %s", stmt, err)
+	}
+	status, err := job.Wait(ctx)
+	if err != nil {
+		t.Fatalf("unable to wait for %q: %s", stmt, err)
+	}
+	if err := status.Err(); err != nil {
+		t.Fatalf("%q failed: %s", stmt, err)
 	}
+	defer func() {
+		if err := client.DatasetInProject(BigqueryProject, datasetName).Table(tableName).Delete(ctx); err != nil {
+			t.Errorf("unable to drop table %s: %s", tableName, err)
+		}
+	}()
+
+	reqBody := bytes.NewBuffer([]byte(fmt.Sprintf(`{"dataset":%q, "table":%q}`, datasetName, tableName)))
+	req, err := http.NewRequest(http.MethodPost, "http://127.0.0.1:5000/api/tool/my-get-table-info-tool/invoke", reqBody)
+	if err != nil {
+		t.Fatalf("unable to create request: %s", err)
+	}
+	req.Header.Add("Content-type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("unable to send request: %s", err)
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unable to read response body: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("response status code is not 200, got %d: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(bodyBytes, &body); err != nil {
+		t.Fatalf("error parsing response body")
+	}
+	got, ok := body["result"].(string)
+	if !ok {
+		t.Fatalf("unable to find result in response body")
+	}
+	for _, want := range []string{
+		`"partitioning":{"type":"DAY","field":"order_date","expiration":"720h0m0s","requirePartitionFilter":true}`,
+		`"clusteringFields":["customer_id"]`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected %q to contain %q, but it did not", got, want)
+		}
+	}
+}
 
+func runBigQueryListColumnsToolInvokeTest(t *testing.T, datasetName, tableName string) {
 	// Get access token
 	accessToken, err := sources.GetIAMAccessToken(t.Context())
 	if err != nil {
@@ -1755,7 +3930,8 @@ func runBigQueryGetTableInfoToolInvokeTest(t *testing.T, datasetName, tableName,
 	}
 	accessToken = "Bearer " + accessToken
 
-	// Test tool invoke endpoint
+	columnsWant := `[{"column_name":"id","data_type":"INT64","mode":"NULLABLE","ordinal_position":1},{"column_name":"name","data_type":"STRING","mode":"NULLABLE","ordinal_position":2}]`
+
 	invokeTcs := []struct {
 		name          string
 		api           string
@@ -1765,88 +3941,277 @@ func runBigQueryGetTableInfoToolInvokeTest(t *testing.T, datasetName, tableName,
 		isErr         bool
 	}{
 		{
-			name:          "invoke my-get-table-info-tool without body",
-			api:           "http://127.0.0.1:5000/api/tool/my-get-table-info-tool/invoke",
+			name:          "invoke my-list-columns-tool without table",
+			api:           "http://127.0.0.1:5000/api/tool/my-list-columns-tool/invoke",
 			requestHeader: map[string]string{},
-			requestBody:   bytes.NewBuffer([]byte(`{}`)),
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf("{\"dataset\":\"%s\"}", datasetName))),
 			isErr:         true,
 		},
 		{
-			name:          "invoke my-get-table-info-tool",
-			api:           "http://127.0.0.1:5000/api/tool/my-get-table-info-tool/invoke",
+			name:          "invoke my-list-columns-tool",
+			api:           "http://127.0.0.1:5000/api/tool/my-list-columns-tool/invoke",
 			requestHeader: map[string]string{},
 			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf("{\"dataset\":\"%s\", \"table\":\"%s\"}", datasetName, tableName))),
-			want:          tableInfoWant,
+			want:          columnsWant,
 			isErr:         false,
 		},
 		{
-			name:          "invoke my-auth-get-table-info-tool without body",
-			api:           "http://127.0.0.1:5000/api/tool/my-get-table-info-tool/invoke",
+			name:          "invoke my-list-columns-tool with nonexistent table",
+			api:           "http://127.0.0.1:5000/api/tool/my-list-columns-tool/invoke",
 			requestHeader: map[string]string{},
-			requestBody:   bytes.NewBuffer([]byte(`{}`)),
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf("{\"dataset\":\"%s\", \"table\":\"%s\"}", datasetName, "nonexistent_table"))),
 			isErr:         true,
 		},
 		{
-			name:          "Invoke my-auth-get-table-info-tool with auth token",
-			api:           "http://127.0.0.1:5000/api/tool/my-auth-get-table-info-tool/invoke",
-			requestHeader: map[string]string{"my-google-auth_token": idToken},
+			name:          "Invoke my-client-auth-list-columns-tool with auth token",
+			api:           "http://127.0.0.1:5000/api/tool/my-client-auth-list-columns-tool/invoke",
+			requestHeader: map[string]string{"Authorization": accessToken},
 			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf("{\"dataset\":\"%s\", \"table\":\"%s\"}", datasetName, tableName))),
-			want:          tableInfoWant,
+			want:          columnsWant,
 			isErr:         false,
 		},
 		{
-			name:          "Invoke my-auth-get-table-info-tool with correct project",
-			api:           "http://127.0.0.1:5000/api/tool/my-auth-get-table-info-tool/invoke",
-			requestHeader: map[string]string{"my-google-auth_token": idToken},
-			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf("{\"project\":\"%s\", \"dataset\":\"%s\", \"table\":\"%s\"}", BigqueryProject, datasetName, tableName))),
-			want:          tableInfoWant,
-			isErr:         false,
+			name:          "Invoke my-client-auth-list-columns-tool without auth token",
+			api:           "http://127.0.0.1:5000/api/tool/my-client-auth-list-columns-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf("{\"dataset\":\"%s\", \"table\":\"%s\"}", datasetName, tableName))),
+			isErr:         true,
 		},
+	}
+	runBigQueryInvokeContainsTests(t, invokeTcs)
+}
+
+func runBigQueryTableStorageToolInvokeTest(t *testing.T, datasetName, tableName string) {
+	// Get access token
+	accessToken, err := sources.GetIAMAccessToken(t.Context())
+	if err != nil {
+		t.Fatalf("error getting access token from ADC: %s", err)
+	}
+	accessToken = "Bearer " + accessToken
+
+	storageWant := fmt.Sprintf(`"table_name":"%s"`, tableName)
+
+	invokeTcs := []struct {
+		name          string
+		api           string
+		requestHeader map[string]string
+		requestBody   io.Reader
+		want          string
+		isErr         bool
+	}{
 		{
-			name:          "Invoke my-auth-get-table-info-tool with non-existent project",
-			api:           "http://127.0.0.1:5000/api/tool/my-auth-get-table-info-tool/invoke",
-			requestHeader: map[string]string{"my-google-auth_token": idToken},
-			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf("{\"project\":\"%s-%s\", \"dataset\":\"%s\", \"table\":\"%s\"}", BigqueryProject, uuid.NewString(), datasetName, tableName))),
+			name:          "invoke my-table-storage-tool without table",
+			api:           "http://127.0.0.1:5000/api/tool/my-table-storage-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf("{\"dataset\":\"%s\"}", datasetName))),
 			isErr:         true,
 		},
 		{
-			name:          "Invoke my-auth-get-table-info-tool with invalid auth token",
-			api:           "http://127.0.0.1:5000/api/tool/my-auth-get-table-info-tool/invoke",
-			requestHeader: map[string]string{"my-google-auth_token": "INVALID_TOKEN"},
+			name:          "invoke my-table-storage-tool",
+			api:           "http://127.0.0.1:5000/api/tool/my-table-storage-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf("{\"dataset\":\"%s\", \"table\":\"%s\"}", datasetName, tableName))),
+			want:          storageWant,
+			isErr:         false,
+		},
+		{
+			name:          "Invoke my-client-auth-table-storage-tool with auth token",
+			api:           "http://127.0.0.1:5000/api/tool/my-client-auth-table-storage-tool/invoke",
+			requestHeader: map[string]string{"Authorization": accessToken},
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf("{\"dataset\":\"%s\", \"table\":\"%s\"}", datasetName, tableName))),
+			want:          storageWant,
+			isErr:         false,
+		},
+		{
+			name:          "Invoke my-client-auth-table-storage-tool without auth token",
+			api:           "http://127.0.0.1:5000/api/tool/my-client-auth-table-storage-tool/invoke",
+			requestHeader: map[string]string{},
 			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf("{\"dataset\":\"%s\", \"table\":\"%s\"}", datasetName, tableName))),
 			isErr:         true,
 		},
+	}
+	runBigQueryInvokeContainsTests(t, invokeTcs)
+}
+
+func runBigQueryProfileTableToolInvokeTest(t *testing.T, datasetName, tableName string) {
+	// Get access token
+	accessToken, err := sources.GetIAMAccessToken(t.Context())
+	if err != nil {
+		t.Fatalf("error getting access token from ADC: %s", err)
+	}
+	accessToken = "Bearer " + accessToken
+
+	// the param table has ids 1 to 4 and the names Alice, Jane, Sid and NULL
+	profileWant := `{"rowCount":4,"columns":[{"name":"id","type":"INTEGER","nullCount":0,"approxDistinctCount":4,"min":1,"avg":2.5,"max":4},{"name":"name","type":"STRING","nullCount":1,"approxDistinctCount":3}]}`
+
+	invokeTcs := []struct {
+		name          string
+		api           string
+		requestHeader map[string]string
+		requestBody   io.Reader
+		want          string
+		isErr         bool
+	}{
+		{
+			name:          "invoke my-profile-table-tool without table",
+			api:           "http://127.0.0.1:5000/api/tool/my-profile-table-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf("{\"dataset\":\"%s\"}", datasetName))),
+			isErr:         true,
+		},
 		{
-			name:          "Invoke my-auth-get-table-info-tool without auth token",
-			api:           "http://127.0.0.1:5000/api/tool/my-auth-get-table-info-tool/invoke",
+			name:          "invoke my-profile-table-tool",
+			api:           "http://127.0.0.1:5000/api/tool/my-profile-table-tool/invoke",
 			requestHeader: map[string]string{},
 			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf("{\"dataset\":\"%s\", \"table\":\"%s\"}", datasetName, tableName))),
+			want:          profileWant,
+			isErr:         false,
+		},
+		{
+			name:          "invoke my-profile-table-tool with nonexistent table",
+			api:           "http://127.0.0.1:5000/api/tool/my-profile-table-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf("{\"dataset\":\"%s\", \"table\":\"%s\"}", datasetName, "nonexistent_table"))),
 			isErr:         true,
 		},
 		{
-			name:          "Invoke my-client-auth-get-table-info-tool with auth token",
-			api:           "http://127.0.0.1:5000/api/tool/my-client-auth-get-table-info-tool/invoke",
+			name:          "Invoke my-client-auth-profile-table-tool with auth token",
+			api:           "http://127.0.0.1:5000/api/tool/my-client-auth-profile-table-tool/invoke",
 			requestHeader: map[string]string{"Authorization": accessToken},
 			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf("{\"dataset\":\"%s\", \"table\":\"%s\"}", datasetName, tableName))),
-			want:          tableInfoWant,
+			want:          profileWant,
 			isErr:         false,
 		},
 		{
-			name:          "Invoke my-client-auth-get-table-info-tool without auth token",
-			api:           "http://127.0.0.1:5000/api/tool/my-client-auth-get-table-info-tool/invoke",
+			name:          "Invoke my-client-auth-profile-table-tool without auth token",
+			api:           "http://127.0.0.1:5000/api/tool/my-client-auth-profile-table-tool/invoke",
 			requestHeader: map[string]string{},
 			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf("{\"dataset\":\"%s\", \"table\":\"%s\"}", datasetName, tableName))),
 			isErr:         true,
 		},
-		{
+	}
+	runBigQueryInvokeContainsTests(t, invokeTcs)
+}
 
-			name:          "Invoke my-client-auth-get-table-info-tool with invalid auth token",
-			api:           "http://127.0.0.1:5000/api/tool/my-client-auth-get-table-info-tool/invoke",
-			requestHeader: map[string]string{"Authorization": "Bearer invalid-token"},
-			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf("{\"dataset\":\"%s\", \"table\":\"%s\"}", datasetName, tableName))),
+func runBigQueryLoadFromGCSToolInvokeTest(t *testing.T, ctx context.Context, client *bigqueryapi.Client, datasetName string) {
+	// Get access token
+	accessToken, err := sources.GetIAMAccessToken(t.Context())
+	if err != nil {
+		t.Fatalf("error getting access token from ADC: %s", err)
+	}
+	accessToken = "Bearer " + accessToken
+
+	// A small public CSV with a header row, 50 rows and the columns name, post_abbr.
+	sourceURI := "gs://cloud-samples-data/bigquery/us-states/us-states.csv"
+	loadTableName := "load_table_" + strings.ReplaceAll(uuid.New().String(), "-", "")
+	clientAuthLoadTableName := "client_auth_load_table_" + strings.ReplaceAll(uuid.New().String(), "-", "")
+	for _, name := range []string{loadTableName, clientAuthLoadTableName} {
+		table := client.Dataset(datasetName).Table(name)
+		defer func() {
+			// The table is only created by a successful load.
+			_ = table.Delete(ctx)
+		}()
+	}
+
+	loadBody := func(table, extra string) io.Reader {
+		return bytes.NewBuffer([]byte(fmt.Sprintf(`{"source_uris": ["%s"], "dataset": "%s", "table": "%s", "schema": "name:STRING,post_abbr:STRING", "skip_leading_rows": 1%s}`, sourceURI, datasetName, table, extra)))
+	}
+	rowsWant := `"outputRows":50`
+
+	invokeTcs := []struct {
+		name          string
+		api           string
+		requestHeader map[string]string
+		requestBody   io.Reader
+		want          string
+		isErr         bool
+	}{
+		{
+			name:          "invoke my-load-from-gcs-tool without source uris",
+			api:           "http://127.0.0.1:5000/api/tool/my-load-from-gcs-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf(`{"dataset": "%s", "table": "%s"}`, datasetName, loadTableName))),
+			isErr:         true,
+		},
+		{
+			name:          "invoke my-load-from-gcs-tool with a non-gcs uri",
+			api:           "http://127.0.0.1:5000/api/tool/my-load-from-gcs-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf(`{"source_uris": ["https://example.com/data.csv"], "dataset": "%s", "table": "%s"}`, datasetName, loadTableName))),
+			isErr:         true,
+		},
+		{
+			name:          "invoke my-load-from-gcs-tool with an unsupported write disposition",
+			api:           "http://127.0.0.1:5000/api/tool/my-load-from-gcs-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   loadBody(loadTableName, `, "write_disposition": "WRITE_SOMETIMES"`),
+			isErr:         true,
+		},
+		{
+			name:          "invoke my-load-from-gcs-tool into a new table",
+			api:           "http://127.0.0.1:5000/api/tool/my-load-from-gcs-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   loadBody(loadTableName, ""),
+			want:          rowsWant,
+			isErr:         false,
+		},
+		{
+			name:          "invoke my-load-from-gcs-tool with WRITE_EMPTY into a table with data",
+			api:           "http://127.0.0.1:5000/api/tool/my-load-from-gcs-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   loadBody(loadTableName, `, "write_disposition": "WRITE_EMPTY"`),
+			isErr:         true,
+		},
+		{
+			name:          "invoke my-load-from-gcs-tool with WRITE_TRUNCATE",
+			api:           "http://127.0.0.1:5000/api/tool/my-load-from-gcs-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   loadBody(loadTableName, `, "write_disposition": "WRITE_TRUNCATE"`),
+			want:          rowsWant,
+			isErr:         false,
+		},
+		{
+			name:          "Invoke my-client-auth-load-from-gcs-tool with auth token",
+			api:           "http://127.0.0.1:5000/api/tool/my-client-auth-load-from-gcs-tool/invoke",
+			requestHeader: map[string]string{"Authorization": accessToken},
+			requestBody:   loadBody(clientAuthLoadTableName, ""),
+			want:          rowsWant,
+			isErr:         false,
+		},
+		{
+			name:          "Invoke my-client-auth-load-from-gcs-tool without auth token",
+			api:           "http://127.0.0.1:5000/api/tool/my-client-auth-load-from-gcs-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   loadBody(clientAuthLoadTableName, ""),
 			isErr:         true,
 		},
 	}
+	runBigQueryInvokeContainsTests(t, invokeTcs)
+
+	// Check that the rows landed in the table, and that WRITE_TRUNCATE replaced them.
+	it, err := client.Query(fmt.Sprintf("SELECT COUNT(*) AS n FROM `%s.%s.%s`", BigqueryProject, datasetName, loadTableName)).Read(ctx)
+	if err != nil {
+		t.Fatalf("unable to count loaded rows: %s", err)
+	}
+	var row map[string]bigqueryapi.Value
+	if err := it.Next(&row); err != nil {
+		t.Fatalf("unable to read loaded row count: %s", err)
+	}
+	if row["n"] != int64(50) {
+		t.Fatalf("unexpected loaded row count: got %v, want 50", row["n"])
+	}
+}
+
+// runBigQueryInvokeContainsTests sends each invoke request and checks that the
+// result contains the wanted string.
+func runBigQueryInvokeContainsTests(t *testing.T, invokeTcs []struct {
+	name          string
+	api           string
+	requestHeader map[string]string
+	requestBody   io.Reader
+	want          string
+	isErr         bool
+}) {
 	for _, tc := range invokeTcs {
 		t.Run(tc.name, func(t *testing.T) {
 			// Send Tool invocation request
@@ -2142,6 +4507,60 @@ func runListTableIdsWithRestriction(t *testing.T, allowedDatasetName, disallowed
 	}
 }
 
+// runTableToolWithRestriction checks that a tool taking dataset and table
+// parameters rejects tables outside the allowed datasets.
+func runTableToolWithRestriction(t *testing.T, toolName, allowedDatasetName, disallowedDatasetName, allowedTableName, disallowedTableName string) {
+	testCases := []struct {
+		name           string
+		dataset        string
+		table          string
+		wantStatusCode int
+		wantInError    string
+	}{
+		{
+			name:           "invoke on allowed dataset",
+			dataset:        allowedDatasetName,
+			table:          allowedTableName,
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "invoke on disallowed dataset",
+			dataset:        disallowedDatasetName,
+			table:          disallowedTableName,
+			wantStatusCode: http.StatusBadRequest,
+			wantInError:    fmt.Sprintf("access denied to dataset '%s'", disallowedDatasetName),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s %s", toolName, tc.name), func(t *testing.T) {
+			body := bytes.NewBuffer([]byte(fmt.Sprintf(`{"dataset":"%s", "table":"%s"}`, tc.dataset, tc.table)))
+			req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("http://127.0.0.1:5000/api/tool/%s/invoke", toolName), body)
+			if err != nil {
+				t.Fatalf("unable to create request: %s", err)
+			}
+			req.Header.Add("Content-type", "application/json")
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("unable to send request: %s", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tc.wantStatusCode {
+				bodyBytes, _ := io.ReadAll(resp.Body)
+				t.Fatalf("unexpected status code: got %d, want %d. Body: %s", resp.StatusCode, tc.wantStatusCode, string(bodyBytes))
+			}
+
+			if tc.wantInError != "" {
+				bodyBytes, _ := io.ReadAll(resp.Body)
+				if !strings.Contains(string(bodyBytes), tc.wantInError) {
+					t.Errorf("unexpected error message: got %q, want to contain %q", string(bodyBytes), tc.wantInError)
+				}
+			}
+		})
+	}
+}
+
 func runBigQuerySearchCatalogToolInvokeTest(t *testing.T, datasetName string, tableName string) {
 	// Get ID token
 	idToken, err := tests.GetGoogleIdToken(tests.ClientId)